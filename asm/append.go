@@ -0,0 +1,175 @@
+package asm
+
+import (
+	"sort"
+
+	"github.com/llir/llvm/internal/enc"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/pkg/errors"
+	"github.com/rickypai/natsort"
+)
+
+// AppendFromString parses the given LLVM IR assembly fragment (e.g. a function
+// definition or a few global variables) and appends its top-level declarations
+// and definitions to the module m.
+//
+// The fragment is parsed in the context of m, and may thus refer to the type
+// definitions, global variables, functions, comdats, attribute groups and
+// metadata definitions of m. Named metadata definitions of the fragment are
+// merged with those of m.
+//
+// An error is returned if the fragment defines a type, global identifier,
+// comdat, attribute group ID or metadata ID already present in m, or specifies
+// a source filename, data layout or target triple different from that of m. The
+// module m is left unmodified on error.
+func AppendFromString(m *ir.Module, content string) error {
+	gen := newGenerator()
+	root, err := gen.parseAST("", content)
+	if err != nil {
+		return errors.Wrap(err, "unable to parse IR fragment into an AST")
+	}
+	gen.indexBase(m)
+	frag, err := gen.translate(root)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	appendModule(m, frag)
+	return nil
+}
+
+// indexBase indexes the IR top-level entities of the given module, to which the
+// generated module is to be appended.
+func (gen *generator) indexBase(m *ir.Module) {
+	gen.base = m
+	for _, t := range m.TypeDefs {
+		gen.new.typeDefs[t.Name()] = t
+	}
+	for _, def := range m.ComdatDefs {
+		gen.new.comdatDefs[def.Name] = def
+	}
+	for _, g := range m.Globals {
+		gen.new.globals[g.GlobalIdent] = g
+	}
+	for _, alias := range m.Aliases {
+		gen.new.globals[alias.GlobalIdent] = alias
+	}
+	for _, ifunc := range m.IFuncs {
+		gen.new.globals[ifunc.GlobalIdent] = ifunc
+	}
+	for _, f := range m.Funcs {
+		gen.new.globals[f.GlobalIdent] = f
+	}
+	for _, def := range m.AttrGroupDefs {
+		gen.new.attrGroupDefs[def.ID] = def
+	}
+	for _, def := range m.MetadataDefs {
+		gen.new.metadataDefs[def.ID()] = def
+	}
+}
+
+// checkBaseConflicts reports an error if the indexed AST top-level entities
+// conflict with the top-level entities of the module to which the generated
+// module is to be appended.
+//
+// pre-condition: indexed AST top-level entities and IR top-level entities of
+// base module.
+func (gen *generator) checkBaseConflicts() error {
+	props := []struct {
+		name      string
+		base, new string
+	}{
+		{name: "source filename", base: gen.base.SourceFilename, new: gen.m.SourceFilename},
+		{name: "data layout", base: gen.base.DataLayout, new: gen.m.DataLayout},
+		{name: "target triple", base: gen.base.TargetTriple, new: gen.m.TargetTriple},
+	}
+	for _, prop := range props {
+		if len(prop.base) > 0 && len(prop.new) > 0 && prop.base != prop.new {
+			return errors.Errorf("%s mismatch; expected %q, got %q", prop.name, prop.base, prop.new)
+		}
+	}
+	typeNames := make([]string, 0, len(gen.old.typeDefs))
+	for name := range gen.old.typeDefs {
+		typeNames = append(typeNames, name)
+	}
+	natsort.Strings(typeNames)
+	for _, name := range typeNames {
+		if _, ok := gen.new.typeDefs[name]; ok {
+			return errors.Errorf("type identifier %q already present in module", enc.Local(name))
+		}
+	}
+	comdatNames := make([]string, 0, len(gen.old.comdatDefs))
+	for name := range gen.old.comdatDefs {
+		comdatNames = append(comdatNames, name)
+	}
+	natsort.Strings(comdatNames)
+	for _, name := range comdatNames {
+		if _, ok := gen.new.comdatDefs[name]; ok {
+			return errors.Errorf("comdat name %q already present in module", enc.Comdat(name))
+		}
+	}
+	for _, ident := range gen.old.globalOrder {
+		if _, ok := gen.new.globals[ident]; ok {
+			return errors.Errorf("global identifier %q already present in module", ident.Ident())
+		}
+	}
+	attrGroupIDs := make([]int64, 0, len(gen.old.attrGroupDefs))
+	for id := range gen.old.attrGroupDefs {
+		attrGroupIDs = append(attrGroupIDs, id)
+	}
+	sort.Slice(attrGroupIDs, func(i, j int) bool { return attrGroupIDs[i] < attrGroupIDs[j] })
+	for _, id := range attrGroupIDs {
+		if _, ok := gen.new.attrGroupDefs[id]; ok {
+			return errors.Errorf("attribute group ID %q already present in module", enc.AttrGroupID(id))
+		}
+	}
+	metadataIDs := make([]int64, 0, len(gen.old.metadataDefs))
+	for id := range gen.old.metadataDefs {
+		metadataIDs = append(metadataIDs, id)
+	}
+	sort.Slice(metadataIDs, func(i, j int) bool { return metadataIDs[i] < metadataIDs[j] })
+	for _, id := range metadataIDs {
+		if _, ok := gen.new.metadataDefs[id]; ok {
+			return errors.Errorf("metadata ID %q already present in module", enc.MetadataID(id))
+		}
+	}
+	return nil
+}
+
+// appendModule appends the top-level declarations and definitions of the
+// module frag to the module m.
+func appendModule(m, frag *ir.Module) {
+	if len(frag.SourceFilename) > 0 {
+		m.SourceFilename = frag.SourceFilename
+	}
+	if len(frag.DataLayout) > 0 {
+		m.DataLayout = frag.DataLayout
+	}
+	if len(frag.TargetTriple) > 0 {
+		m.TargetTriple = frag.TargetTriple
+	}
+	m.ModuleAsms = append(m.ModuleAsms, frag.ModuleAsms...)
+	m.TypeDefs = append(m.TypeDefs, frag.TypeDefs...)
+	m.ComdatDefs = append(m.ComdatDefs, frag.ComdatDefs...)
+	m.Globals = append(m.Globals, frag.Globals...)
+	m.Aliases = append(m.Aliases, frag.Aliases...)
+	m.IFuncs = append(m.IFuncs, frag.IFuncs...)
+	m.Funcs = append(m.Funcs, frag.Funcs...)
+	m.AttrGroupDefs = append(m.AttrGroupDefs, frag.AttrGroupDefs...)
+	if m.NamedMetadataDefs == nil && len(frag.NamedMetadataDefs) > 0 {
+		m.NamedMetadataDefs = make(map[string]*metadata.NamedDef)
+	}
+	for name, def := range frag.NamedMetadataDefs {
+		if prev, ok := m.NamedMetadataDefs[name]; ok {
+			prev.Nodes = append(prev.Nodes, def.Nodes...)
+			continue
+		}
+		m.NamedMetadataDefs[name] = def
+	}
+	m.MetadataDefs = append(m.MetadataDefs, frag.MetadataDefs...)
+	for _, kind := range frag.MetadataKinds {
+		m.MetadataKindID(kind)
+	}
+	m.UseListOrders = append(m.UseListOrders, frag.UseListOrders...)
+	m.UseListOrderBBs = append(m.UseListOrderBBs, frag.UseListOrderBBs...)
+}
@@ -0,0 +1,66 @@
+package asm
+
+import (
+	"testing"
+)
+
+func TestAppendFromString(t *testing.T) {
+	m, err := ParseString("", `
+%T = type { i32, i8* }
+
+@x = global i32 42
+
+declare i32 @g(i32)
+`)
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	const fragment = `
+@y = global %T { i32 1, i8* null }
+
+define i32 @f() {
+	%1 = load i32, i32* @x
+	%2 = call i32 @g(i32 %1)
+	ret i32 %2
+}
+`
+	if err := AppendFromString(m, fragment); err != nil {
+		t.Fatalf("unable to append IR fragment; %+v", err)
+	}
+	want := `%T = type { i32, i8* }
+
+@x = global i32 42
+@y = global %T { i32 1, i8* null }
+
+declare i32 @g(i32)
+
+define i32 @f() {
+; <label>:0
+	%1 = load i32, i32* @x
+	%2 = call i32 @g(i32 %1)
+	ret i32 %2
+}
+`
+	if got := m.String(); got != want {
+		t.Errorf("module mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+	// Conflicts with existing top-level entities.
+	golden := []string{
+		"%T = type i32",
+		"@x = global i8 0",
+		"declare void @f()",
+		`target triple = "x86_64-unknown-linux-gnu"`,
+		// Syntax error.
+		"define",
+	}
+	m.TargetTriple = "x86_64-pc-linux-gnu"
+	before := m.String()
+	for _, g := range golden {
+		if err := AppendFromString(m, g); err == nil {
+			t.Errorf("expected error for IR fragment %q, got nil", g)
+		}
+		if got := m.String(); got != before {
+			t.Errorf("module modified by erroneous IR fragment %q; expected:\n%s\ngot:\n%s", g, before, got)
+		}
+	}
+}
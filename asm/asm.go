@@ -7,6 +7,7 @@ import (
 	"log"
 	"time"
 
+	"github.com/llir/ll"
 	"github.com/llir/ll/ast"
 	"github.com/llir/llvm/ir"
 	"github.com/pkg/errors"
@@ -53,11 +54,138 @@ func ParseBytes(path string, b []byte) (*ir.Module, error) {
 // for error reporting.
 func ParseString(path, content string) (*ir.Module, error) {
 	parseStart := time.Now()
-	tree, err := ast.Parse(path, content)
+	gen := newGenerator()
+	root, err := gen.parseAST(path, content)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to parse %q into an AST", path)
 	}
 	dbg.Println("parsing into AST took:", time.Since(parseStart))
-	root := ast.ToLlvmNode(tree.Root())
-	return translate(root.(*ast.Module))
+	return gen.translate(root)
+}
+
+// ParseStringCollectErrors parses the given LLVM IR assembly file into an LLVM
+// IR module, reading from content. An optional path to the source file may be
+// specified for error reporting.
+//
+// Contrary to ParseString, translation continues past recoverable semantic
+// errors (e.g. references to unknown identifiers), and all errors encountered
+// are returned, sorted by source position. Each error is of type *Error.
+//
+// Top-level entities which fail to translate are handled as follows in the
+// returned partial module: function definitions are translated as function
+// declarations, global variable definitions as global variable declarations,
+// and alias and IFunc definitions, named metadata definitions, metadata
+// definitions and use-list orders are omitted. References to omitted metadata
+// definitions (e.g. from other metadata nodes) are retained, and printed by
+// metadata ID. Only the first error of each top-level entity is reported.
+//
+// A nil module is returned on syntax errors and non-recoverable semantic errors
+// (e.g. invalid type definitions or redefinitions of identifiers).
+func ParseStringCollectErrors(path, content string) (*ir.Module, []error) {
+	gen := newGenerator()
+	gen.collect = true
+	gen.path = path
+	gen.content = content
+	root, err := gen.parseAST(path, content)
+	if err != nil {
+		return nil, []error{gen.posError(nil, err)}
+	}
+	m, err := gen.translate(root)
+	if err != nil {
+		errs := append(gen.errs, gen.posError(nil, err))
+		sortErrors(errs)
+		return nil, errs
+	}
+	sortErrors(gen.errs)
+	return m, gen.errs
+}
+
+// parseAST parses the given LLVM IR assembly file into an AST, reading from
+// content.
+//
+// Memory attributes (e.g. `memory(read, argmem: readwrite)`) and poison
+// constants, which are not supported by the grammar, are extracted before
+// parsing and recorded in gen.memoryAttrs and gen.poisons respectively. An
+// error is reported for other invalid tokens (e.g. function attributes of
+// recent LLVM releases not yet supported by the grammar, such as mustprogress),
+// which would otherwise be skipped silently by the parser.
+func (gen *generator) parseAST(path, content string) (*ast.Module, error) {
+	var (
+		l ll.Lexer
+		// Source contents with memory attributes and poison constants replaced;
+		// or nil if not present.
+		buf []byte
+		// End offset of the previous token, not part of a memory attribute.
+		prevEnd int
+		// End offset of the previous memory attribute.
+		memEnd int
+	)
+	// replace replaces content[start:end] by s, padded with whitespace to
+	// retain the source position of subsequent tokens.
+	replace := func(start, end int, s string) {
+		if buf == nil {
+			buf = []byte(content)
+		}
+		for i := start; i < end; i++ {
+			switch {
+			case i-start < len(s):
+				buf[i] = s[i-start]
+			case buf[i] != '\n':
+				buf[i] = ' '
+			}
+		}
+	}
+	l.Init(content)
+	for tok := l.Next(); tok != ll.EOI; tok = l.Next() {
+		offset, end := l.Pos()
+		if offset < memEnd {
+			// Skip tokens of memory attribute.
+			continue
+		}
+		if tok == ll.INVALID_TOKEN {
+			switch l.Text() {
+			case "memory":
+				mem, n, err := parseMemoryAttr(content[offset:])
+				if err != nil {
+					return nil, &Error{
+						Line: l.Line(),
+						Col:  column(content, offset),
+						Err:  err,
+					}
+				}
+				memEnd = offset + n
+				gen.memoryAttrs = append(gen.memoryAttrs, &memoryAttr{
+					line:    l.Line(),
+					col:     column(content, offset),
+					offset:  offset,
+					prevEnd: prevEnd,
+					attr:    mem,
+				})
+				replace(offset, memEnd, "")
+				continue
+			case "poison":
+				// Parsed as undef constant, and translated to poison constant.
+				if gen.poisons == nil {
+					gen.poisons = make(map[int]bool)
+				}
+				gen.poisons[offset] = true
+				replace(offset, end, "undef")
+			default:
+				return nil, &Error{
+					Line: l.Line(),
+					Col:  column(content, offset),
+					Err:  errors.Errorf("invalid token %q", l.Text()),
+				}
+			}
+		}
+		prevEnd = end
+	}
+	if buf != nil {
+		content = string(buf)
+	}
+	tree, err := ast.Parse(path, content)
+	if err != nil {
+		return nil, err
+	}
+	return ast.ToLlvmNode(tree.Root()).(*ast.Module), nil
 }
@@ -24,15 +24,27 @@ func TestParseFile(t *testing.T) {
 		path string
 	}{
 		{path: "testdata/hexfloat.ll"},
+		{path: "testdata/hexfloat_types.ll"},
+		// Floating-point literals are printed verbatim.
+		{path: "testdata/float_lit.ll"},
 		{path: "testdata/inst_aggregate.ll"},
 		{path: "testdata/inst_binary.ll"},
 		{path: "testdata/inst_bitwise.ll"},
+		// Calls with explicit function type.
+		{path: "testdata/inst_call_explicit.ll"},
 		{path: "testdata/inst_conversion.ll"},
+		{path: "testdata/inst_eh.ll"},
 		{path: "testdata/inst_memory.ll"},
 		{path: "testdata/inst_other.ll"},
 		{path: "testdata/inst_vector.ll"},
 		{path: "testdata/terminator.ll"},
 
+		// Alignment of memory instructions and global variables.
+		{path: "testdata/align.ll"},
+
+		// Non-default address spaces of allocas, globals and getelementptr.
+		{path: "testdata/addrspace.ll"},
+
 		// DIExpression used in named metdata definition.
 		{path: "testdata/diexpression.ll"},
 
@@ -40,9 +52,46 @@ func TestParseFile(t *testing.T) {
 		// be merged into one.
 		{path: "testdata/multiple_named_metadata_defs.ll"},
 
+		// Custom metadata kinds of metadata attachments.
+		{path: "testdata/metadata_kind.ll"},
+
+		// !range metadata attached to load instruction.
+		{path: "testdata/metadata_range.ll"},
+
+		// !nonnull and !dereferenceable metadata attached to load instructions.
+		{path: "testdata/metadata_load.ll"},
+
+		// dso_local and dso_preemptable in combination with linkage, visibility
+		// and DLL storage class.
+		{path: "testdata/dso_local.ll"},
+
+		// Prefix and prologue data of various constant kinds.
+		{path: "testdata/prefix_prologue.ll"},
+
 		// frem constant expression.
 		{path: "testdata/expr_frem.ll"},
 
+		// icmp, fcmp and select constant expressions.
+		{path: "testdata/expr_other.ll"},
+
+		// Function attributes, both inline and in attribute groups.
+		{path: "testdata/func_attr.ll"},
+
+		// Memory attributes, both inline and in attribute groups.
+		{path: "testdata/func_memory.ll"},
+
+		// Output of clang -O2.
+		{path: "testdata/clang_o2.ll"},
+
+		// Unnamed global variables, aliases and functions.
+		{path: "testdata/unnamed_global.ll"},
+
+		// Calling conventions, both keyword and numeric form (e.g. tailcc is cc 18).
+		{path: "testdata/calling_conv.ll"},
+
+		// Metadata strings with escaped NUL bytes, quotes and UTF-8.
+		{path: "testdata/metadata_string.ll"},
+
 		// LLVM IR compatibility.
 		{path: "../testdata/llvm/test/Bitcode/compatibility.ll"},
 
@@ -0,0 +1,159 @@
+package asm
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/llir/ll/ast"
+	"github.com/llir/llvm/ir"
+	"github.com/pkg/errors"
+)
+
+// ParseStringComments parses the given LLVM IR assembly file into an LLVM IR
+// module, reading from content, and captures the line comments of the file. An
+// optional path to the source file may be specified for error reporting.
+//
+// Each line comment (i.e. a comment on a line of its own) is associated with
+// the global variable, alias, IFunc, function, basic block or instruction
+// directly following it; comments before an unnamed basic block are associated
+// with its first instruction. The returned comments may be printed using
+// ir.Printer.Comments.
+//
+// Comments followed by other entities (e.g. type definitions, metadata
+// definitions or the end of a function body) and comments trailing code on the
+// same line are not captured, and neither are `; <label>:N` comments of
+// unnamed basic blocks, as these are printed by the ir package.
+func ParseStringComments(path, content string) (*ir.Module, ir.Comments, error) {
+	gen := newGenerator()
+	root, err := gen.parseAST(path, content)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "unable to parse %q into an AST", path)
+	}
+	m, err := gen.translate(root)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	return m, gen.comments(root, content), nil
+}
+
+// comment is a line comment of an LLVM IR assembly file.
+type comment struct {
+	// Byte offset of the comment in the source file.
+	offset int
+	// Comment text, without the leading ';' and surrounding whitespace.
+	text string
+}
+
+// commentTarget is an entity with which comments may be associated.
+type commentTarget struct {
+	// Byte offset of the entity in the source file.
+	offset int
+	// Entity with which preceding comments are associated; or nil if preceding
+	// comments are not captured.
+	entity interface{}
+}
+
+// comments returns the line comments of the given source file, associated with
+// the entities of the translated module.
+func (gen *generator) comments(old *ast.Module, content string) ir.Comments {
+	targets := gen.commentTargets(old)
+	sort.SliceStable(targets, func(i, j int) bool {
+		return targets[i].offset < targets[j].offset
+	})
+	comments := make(ir.Comments)
+	for _, c := range lineComments(content) {
+		if strings.HasPrefix(c.text, "<label>:") {
+			continue
+		}
+		i := sort.Search(len(targets), func(i int) bool {
+			return targets[i].offset > c.offset
+		})
+		if i < len(targets) && targets[i].entity != nil {
+			comments.Add(targets[i].entity, c.text)
+		}
+	}
+	return comments
+}
+
+// commentTargets returns the entities with which comments may be associated,
+// based on the AST top-level entities of the given module.
+func (gen *generator) commentTargets(old *ast.Module) []commentTarget {
+	var targets []commentTarget
+	for _, entity := range old.TopLevelEntities() {
+		offset := entity.LlvmNode().Offset()
+		var ident ir.GlobalIdent
+		switch entity := entity.(type) {
+		case *ast.GlobalDecl:
+			ident = globalIdent(entity.Name())
+		case *ast.IndirectSymbolDef:
+			ident = globalIdent(entity.Name())
+		case *ast.FuncDecl:
+			ident = globalIdent(entity.Header().Name())
+		case *ast.FuncDef:
+			ident = globalIdent(entity.Header().Name())
+			f, ok := gen.new.globals[ident].(*ir.Func)
+			if !ok {
+				break
+			}
+			targets = append(targets, bodyCommentTargets(f, entity.Body())...)
+		default:
+			targets = append(targets, commentTarget{offset: offset})
+			continue
+		}
+		targets = append(targets, commentTarget{offset: offset, entity: gen.new.globals[ident]})
+	}
+	return targets
+}
+
+// bodyCommentTargets returns the basic blocks and instructions of the given
+// function with which comments may be associated, based on the AST function
+// body.
+func bodyCommentTargets(f *ir.Func, old ast.FuncBody) []commentTarget {
+	var targets []commentTarget
+	for i, oldBlock := range old.Blocks() {
+		block := f.Blocks[i]
+		if _, ok := oldBlock.Name(); ok {
+			targets = append(targets, commentTarget{offset: oldBlock.Offset(), entity: block})
+		}
+		for j, oldInst := range oldBlock.Insts() {
+			targets = append(targets, commentTarget{offset: oldInst.LlvmNode().Offset(), entity: block.Insts[j]})
+		}
+		targets = append(targets, commentTarget{offset: oldBlock.Term().LlvmNode().Offset(), entity: block.Term})
+	}
+	// Comments at the end of the function body are not captured.
+	end := old.Endoffset() - 1
+	targets = append(targets, commentTarget{offset: end})
+	return targets
+}
+
+// lineComments returns the comments of the given source file which are on lines
+// of their own.
+func lineComments(content string) []comment {
+	var comments []comment
+	inString := false
+	lineStart := true
+	for i := 0; i < len(content); i++ {
+		switch c := content[i]; {
+		case c == '"':
+			inString = !inString
+			lineStart = false
+		case c == '\n':
+			lineStart = true
+		case inString || c == ' ' || c == '\t' || c == '\r':
+			// skip whitespace and string contents.
+		case c == ';':
+			end := strings.IndexByte(content[i:], '\n')
+			if end == -1 {
+				end = len(content) - i
+			}
+			if lineStart {
+				text := strings.TrimSpace(content[i+1 : i+end])
+				comments = append(comments, comment{offset: i, text: text})
+			}
+			i += end - 1
+		default:
+			lineStart = false
+		}
+	}
+	return comments
+}
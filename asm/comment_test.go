@@ -0,0 +1,116 @@
+package asm
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/llir/llvm/ir"
+)
+
+func TestParseStringComments(t *testing.T) {
+	const content = `; ModuleID = 'foo.ll'
+source_filename = "foo.ll"
+
+; KEY: global
+@s = global [3 x i8] c"a;b"
+
+%t = type { i32 }
+
+; KEY: func
+;
+define i32 @f(i32 %a) {
+; KEY: block
+entry:
+	; KEY: add
+	%b = add i32 %a, 1 ; trailing comment
+	br label %0
+
+; <label>:0
+	; KEY: unnamed block
+	%c = mul i32 %b, 2
+	; KEY: term
+	ret i32 %c
+	; end of function body
+}
+
+; KEY: decl
+declare void @g()
+
+; metadata comment
+!0 = !{}
+`
+	const want = `source_filename = "foo.ll"
+
+%t = type { i32 }
+
+; KEY: global
+@s = global [3 x i8] c"a;b"
+
+; KEY: func
+;
+define i32 @f(i32 %a) {
+; KEY: block
+entry:
+	; KEY: add
+	%b = add i32 %a, 1
+	br label %0
+
+; <label>:0
+	; KEY: unnamed block
+	%c = mul i32 %b, 2
+	; KEY: term
+	ret i32 %c
+}
+
+; KEY: decl
+declare void @g()
+
+!0 = !{}
+`
+	m, comments, err := ParseStringComments("foo.ll", content)
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	f := m.Funcs[0]
+	entry := f.Blocks[0]
+	golden := []struct {
+		entity interface{}
+		want   []string
+	}{
+		{entity: m.Globals[0], want: []string{"KEY: global"}},
+		{entity: f, want: []string{"KEY: func", ""}},
+		{entity: entry, want: []string{"KEY: block"}},
+		{entity: entry.Insts[0], want: []string{"KEY: add"}},
+		{entity: entry.Term, want: nil},
+		{entity: f.Blocks[1], want: nil},
+		{entity: f.Blocks[1].Insts[0], want: []string{"KEY: unnamed block"}},
+		{entity: f.Blocks[1].Term, want: []string{"KEY: term"}},
+		{entity: m.Funcs[1], want: []string{"KEY: decl"}},
+	}
+	for _, g := range golden {
+		if got := comments[g.entity]; !reflect.DeepEqual(g.want, got) {
+			t.Errorf("comments mismatch of %T; expected %q, got %q", g.entity, g.want, got)
+		}
+	}
+	if len(comments) != 7 {
+		t.Errorf("number of entities with comments mismatch; expected 7, got %d", len(comments))
+	}
+	p := &ir.Printer{Comments: comments}
+	got := p.Sprint(m)
+	if got != want {
+		t.Errorf("module mismatch; expected %q, got %q", want, got)
+	}
+	// Comments are not printed by default.
+	if s := m.String(); s == got {
+		t.Errorf("expected module without comments, got %q", s)
+	}
+	// Round-trip of printed comments.
+	m2, comments2, err := ParseStringComments("foo.ll", got)
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	p2 := &ir.Printer{Comments: comments2}
+	if got2 := p2.Sprint(m2); got2 != want {
+		t.Errorf("round-trip module mismatch; expected %q, got %q", want, got2)
+	}
+}
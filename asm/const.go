@@ -3,6 +3,7 @@ package asm
 import (
 	"fmt"
 
+	"github.com/llir/ll"
 	"github.com/llir/ll/ast"
 	"github.com/llir/llvm/internal/enc"
 	"github.com/llir/llvm/ir"
@@ -15,6 +16,13 @@ import (
 
 // irConstant translates the AST constant into an equivalent IR constant.
 func (gen *generator) irConstant(t types.Type, old ast.Constant) (constant.Constant, error) {
+	// Limit the nesting depth of constants, to prevent stack overflow on
+	// pathological input.
+	gen.constDepth++
+	defer func() { gen.constDepth-- }()
+	if gen.constDepth > constant.MaxDepth {
+		return nil, newPosError(old, "nesting depth of constant exceeds limit %d", constant.MaxDepth)
+	}
 	switch old := old.(type) {
 	case *ast.BoolConst:
 		return gen.irBoolConst(t, old)
@@ -37,6 +45,9 @@ func (gen *generator) irConstant(t types.Type, old ast.Constant) (constant.Const
 	case *ast.ZeroInitializerConst:
 		return constant.NewZeroInitializer(t), nil
 	case *ast.UndefConst:
+		if gen.poisons[old.Offset()] {
+			return constant.NewPoison(t), nil
+		}
 		return constant.NewUndef(t), nil
 	case *ast.BlockAddressConst:
 		return gen.irBlockAddressConst(t, old)
@@ -44,7 +55,7 @@ func (gen *generator) irConstant(t types.Type, old ast.Constant) (constant.Const
 		ident := globalIdent(*old)
 		c, ok := gen.new.globals[ident]
 		if !ok {
-			return nil, errors.Errorf("unable to locate global identifier %q", ident.Ident())
+			return nil, newPosError(old, "unable to locate global identifier %q", ident.Ident())
 		}
 		return c, nil
 	case ast.ConstantExpr:
@@ -151,6 +162,20 @@ func (gen *generator) irStructConst(t types.Type, old *ast.StructConst) (*consta
 			fields[i] = field
 		}
 	}
+	// Packed struct constants (e.g. <{ i8 1 }>) are of packed struct type. The
+	// grammar uses the same AST node kind for packed and non-packed struct
+	// constants, which are distinguished by their first token.
+	if packed := firstToken(old) == ll.LT; packed != typ.Packed {
+		return nil, errors.Errorf("struct constant packedness mismatch of struct type %q; expected packed %v, got %v", typ, typ.Packed, packed)
+	}
+	if len(fields) != len(typ.Fields) {
+		return nil, errors.Errorf("struct constant field count mismatch of struct type %q; expected %d, got %d", typ, len(typ.Fields), len(fields))
+	}
+	for i, field := range fields {
+		if !field.Type().Equal(typ.Fields[i]) {
+			return nil, errors.Errorf("struct constant field %d type mismatch of struct type %q; expected %q, got %q", i, typ, typ.Fields[i], field.Type())
+		}
+	}
 	c := constant.NewStruct(typ, fields...)
 	return c, nil
 }
@@ -178,8 +203,14 @@ func (gen *generator) irArrayConst(t types.Type, old *ast.ArrayConst) (*constant
 		if err != nil {
 			return nil, errors.WithStack(err)
 		}
+		if !elem.Type().Equal(typ.ElemType) {
+			return nil, errors.Errorf("array constant element %d type mismatch of array type %q; expected %q, got %q", i, typ, typ.ElemType, elem.Type())
+		}
 		elems[i] = elem
 	}
+	if uint64(len(elems)) != typ.Len {
+		return nil, errors.Errorf("array constant length mismatch of array type %q; expected %d, got %d", typ, typ.Len, len(elems))
+	}
 	c := constant.NewArray(typ, elems...)
 	return c, nil
 }
@@ -214,8 +245,14 @@ func (gen *generator) irVectorConst(t types.Type, old *ast.VectorConst) (*consta
 		if err != nil {
 			return nil, errors.WithStack(err)
 		}
+		if !elem.Type().Equal(typ.ElemType) {
+			return nil, errors.Errorf("vector constant element %d type mismatch of vector type %q; expected %q, got %q", i, typ, typ.ElemType, elem.Type())
+		}
 		elems[i] = elem
 	}
+	if uint64(len(elems)) != typ.Len {
+		return nil, errors.Errorf("vector constant length mismatch of vector type %q; expected %d, got %d", typ, typ.Len, len(elems))
+	}
 	c := constant.NewVector(typ, elems...)
 	return c, nil
 }
@@ -229,7 +266,7 @@ func (gen *generator) irBlockAddressConst(t types.Type, old *ast.BlockAddressCon
 	funcName := globalIdent(old.Func())
 	v, ok := gen.new.globals[funcName]
 	if !ok {
-		return nil, errors.Errorf("unable to locate global identifier %q", funcName)
+		return nil, newPosError(old.Func(), "unable to locate global identifier %q", funcName.Ident())
 	}
 	f, ok := v.(*ir.Func)
 	if !ok {
@@ -244,7 +281,7 @@ func (gen *generator) irBlockAddressConst(t types.Type, old *ast.BlockAddressCon
 		LocalIdent: blockIdent,
 	}
 	c := constant.NewBlockAddress(f, block)
-	gen.todo = append(gen.todo, c)
+	gen.hasBlockAddrs = true
 	if typ := c.Type(); !t.Equal(typ) {
 		return nil, errors.Errorf("blockaddress constant type mismatch; expected %q, got %q", typ, t)
 	}
@@ -617,7 +617,11 @@ func (gen *generator) irShuffleVectorExpr(t types.Type, old *ast.ShuffleVectorEx
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
-	expr := constant.NewShuffleVector(x, y, mask)
+	elems, ok := constant.ShuffleMask(mask)
+	if !ok {
+		return nil, errors.Errorf("invalid shuffle mask %s; expected constant vector of i32 elements", mask)
+	}
+	expr := constant.NewShuffleVector(x, y, elems)
 	if !t.Equal(expr.Typ) {
 		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
@@ -0,0 +1,147 @@
+package asm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+func TestPrefixPrologueType(t *testing.T) {
+	m, err := ParseString("", `
+%T = type { i32, [2 x i8] }
+
+define void @f() prefix %T { i32 1, [2 x i8] c"ab" } prologue <{ i8, i16 }> <{ i8 1, i16 2 }> {
+	ret void
+}
+`)
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	f := m.Funcs[0]
+	if want, got := m.TypeDefs[0], f.Prefix.Type(); got != want {
+		t.Errorf("prefix type mismatch; expected %q, got %q", want, got)
+	}
+	want := types.NewStruct(types.I8, types.I16)
+	want.Packed = true
+	if got := f.Prologue.Type(); !got.Equal(want) {
+		t.Errorf("prologue type mismatch; expected %q, got %q", want, got)
+	}
+}
+
+func TestConstTypeMismatch(t *testing.T) {
+	golden := []string{
+		// Struct field type mismatch.
+		"%T = type { i32, i8 }\n@g = global %T { i32 1, i16 2 }",
+		// Struct field count mismatch.
+		"@g = global { i32, i8 } { i32 1 }",
+		// Struct packedness mismatch.
+		"@g = global <{ i8 }> { i8 1 }",
+		"@g = global { i8 } <{ i8 1 }>",
+		// Array element type mismatch.
+		"@g = global [2 x i32] [i32 1, i16 2]",
+		// Array length mismatch.
+		"@g = global [3 x i32] [i32 1, i32 2]",
+		// Vector element type mismatch.
+		"@g = global <2 x i32> <i32 1, i16 2>",
+		// Vector length mismatch.
+		"@g = global <2 x i32> <i32 1>",
+		// Prefix and prologue data.
+		"define void @f() prefix [2 x i32] [i32 1, i16 2] {\n\tret void\n}",
+		"define void @f() prologue <{ i8 }> { i8 1 } {\n\tret void\n}",
+	}
+	for _, g := range golden {
+		if _, err := ParseString("", g); err == nil {
+			t.Errorf("expected error for %q, got nil", g)
+		}
+	}
+}
+
+func TestBlockAddress(t *testing.T) {
+	const content = `@g = global [2 x i8*] [i8* blockaddress(@f, %foo), i8* blockaddress(@f, %bar)]
+
+define i8* @h() {
+	ret i8* blockaddress(@f, %foo)
+}
+
+define void @f() {
+	br label %bar
+
+bar:
+	br label %foo
+
+foo:
+	indirectbr i8* blockaddress(@f, %foo), [label %foo, label %bar], !foo !1
+}
+
+!named = !{!0}
+
+!0 = !{i8* blockaddress(@f, %bar)}
+!1 = !{i8* blockaddress(@f, %foo)}
+`
+	m, err := ParseString("", content)
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	f := m.Funcs[1]
+	init := m.Globals[0].Init.(*constant.Array)
+	for i, want := range []*ir.Block{f.Blocks[2], f.Blocks[1]} {
+		if got := init.Elems[i].(*constant.BlockAddress).Block; got != want {
+			t.Errorf("basic block mismatch of blockaddress %d; expected %v, got %v", i, want, got)
+		}
+	}
+	for _, addr := range []value.Value{m.Funcs[0].Blocks[0].Term.(*ir.TermRet).X, f.Blocks[2].Term.(*ir.TermIndirectBr).Addr} {
+		if got := addr.(*constant.BlockAddress).Block; got != f.Blocks[2] {
+			t.Errorf("basic block mismatch of blockaddress; expected %v, got %v", f.Blocks[2], got)
+		}
+	}
+	// Blockaddress constants of metadata.
+	for i, want := range []*ir.Block{f.Blocks[1], f.Blocks[2]} {
+		tuple := m.MetadataDefs[i].(*metadata.Tuple)
+		if got := tuple.Fields[0].(*constant.BlockAddress).Block; got != want {
+			t.Errorf("basic block mismatch of blockaddress of metadata !%d; expected %v, got %v", i, want, got)
+		}
+	}
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+
+	// Unresolved blockaddress constants are reported together.
+	const invalid = `@g = global [2 x i8*] [i8* blockaddress(@f, %foo), i8* blockaddress(@f, %2)]
+
+define void @f() {
+	ret void
+}
+`
+	_, err = ParseString("", invalid)
+	want := "unable to locate basic blocks of blockaddress constants: blockaddress(@f, %foo), blockaddress(@f, %2)"
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("error mismatch; expected %q, got %v", want, err)
+	}
+}
+
+func TestConstDepthLimit(t *testing.T) {
+	defer func(maxDepth int) { constant.MaxDepth = maxDepth }(constant.MaxDepth)
+	constant.MaxDepth = 100
+	// nested returns a global variable definition with an initializer of n
+	// nested add expressions.
+	nested := func(n int) string {
+		c := "i32 1"
+		for i := 0; i < n; i++ {
+			c = fmt.Sprintf("i32 add (%s, i32 1)", c)
+		}
+		return "@g = global " + c
+	}
+	if _, err := ParseString("", nested(99)); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	want := "nesting depth of constant exceeds limit 100"
+	if _, err := ParseString("", nested(100)); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("error mismatch; expected %q, got %v", want, err)
+	}
+}
@@ -21,6 +21,9 @@ func _() {
 	_ = x[enum.CallingConvPreserveAll-15]
 	_ = x[enum.CallingConvSwift-16]
 	_ = x[enum.CallingConvCXXFastTLS-17]
+	_ = x[enum.CallingConvTail-18]
+	_ = x[enum.CallingConvCFGuardCheck-19]
+	_ = x[enum.CallingConvSwiftTail-20]
 	_ = x[enum.CallingConvX86StdCall-64]
 	_ = x[enum.CallingConvX86FastCall-65]
 	_ = x[enum.CallingConvARM_APCS-66]
@@ -53,20 +56,24 @@ func _() {
 	_ = x[enum.CallingConvAMDGPU_LS-95]
 	_ = x[enum.CallingConvAMDGPU_ES-96]
 	_ = x[enum.CallingConvAArch64VectorCall-97]
+	_ = x[enum.CallingConvAArch64SVEVectorCall-98]
+	_ = x[enum.CallingConvWASMEmscriptenInvoke-99]
+	_ = x[enum.CallingConvAMDGPU_Gfx-100]
+	_ = x[enum.CallingConvM68kIntr-101]
 }
 
 const (
 	_CallingConv_name_0 = "noneccc"
-	_CallingConv_name_1 = "fastcccoldccghccccc 11webkit_jsccanyregccpreserve_mostccpreserve_allccswiftcccxx_fast_tlscc"
+	_CallingConv_name_1 = "fastcccoldccghccccc 11webkit_jsccanyregccpreserve_mostccpreserve_allccswiftcccxx_fast_tlscccc 18cc 19cc 20"
 	_CallingConv_name_2 = "x86_stdcallccx86_fastcallccarm_apcsccarm_aapcsccarm_aapcs_vfpccmsp430_intrccx86_thiscallccptx_kernelptx_device"
-	_CallingConv_name_3 = "spir_funcspir_kernelintel_ocl_biccx86_64_sysvccwin64ccx86_vectorcallcchhvmcchhvm_cccx86_intrccavr_intrccavr_signalcccc 86amdgpu_vsamdgpu_gsamdgpu_psamdgpu_csamdgpu_kernelx86_regcallccamdgpu_hscc 94amdgpu_lsamdgpu_esaarch64_vector_pcs"
+	_CallingConv_name_3 = "spir_funcspir_kernelintel_ocl_biccx86_64_sysvccwin64ccx86_vectorcallcchhvmcchhvm_cccx86_intrccavr_intrccavr_signalcccc 86amdgpu_vsamdgpu_gsamdgpu_psamdgpu_csamdgpu_kernelx86_regcallccamdgpu_hscc 94amdgpu_lsamdgpu_esaarch64_vector_pcscc 98cc 99cc 100cc 101"
 )
 
 var (
 	_CallingConv_index_0 = [...]uint8{0, 4, 7}
-	_CallingConv_index_1 = [...]uint8{0, 6, 12, 17, 22, 33, 41, 56, 70, 77, 91}
+	_CallingConv_index_1 = [...]uint8{0, 6, 12, 17, 22, 33, 41, 56, 70, 77, 91, 96, 101, 106}
 	_CallingConv_index_2 = [...]uint8{0, 13, 27, 37, 48, 63, 76, 90, 100, 110}
-	_CallingConv_index_3 = [...]uint8{0, 9, 20, 34, 47, 54, 70, 76, 84, 94, 104, 116, 121, 130, 139, 148, 157, 170, 183, 192, 197, 206, 215, 233}
+	_CallingConv_index_3 = [...]uint8{0, 9, 20, 34, 47, 54, 70, 76, 84, 94, 104, 116, 121, 130, 139, 148, 157, 170, 183, 192, 197, 206, 215, 233, 238, 243, 249, 255}
 )
 
 func CallingConvFromString(s string) enum.CallingConv {
@@ -47,11 +47,23 @@ func _() {
 	_ = x[enum.FuncAttrStrictFP-35]
 	_ = x[enum.FuncAttrUwtable-36]
 	_ = x[enum.FuncAttrWriteOnly-37]
+	_ = x[enum.FuncAttrHot-38]
+	_ = x[enum.FuncAttrMustProgress-39]
+	_ = x[enum.FuncAttrNoCallback-40]
+	_ = x[enum.FuncAttrNoFree-41]
+	_ = x[enum.FuncAttrNoMerge-42]
+	_ = x[enum.FuncAttrNoProfile-43]
+	_ = x[enum.FuncAttrNoSync-44]
+	_ = x[enum.FuncAttrNullPointerIsValid-45]
+	_ = x[enum.FuncAttrSanitizeMemTag-46]
+	_ = x[enum.FuncAttrUwtableAsync-47]
+	_ = x[enum.FuncAttrUwtableSync-48]
+	_ = x[enum.FuncAttrWillReturn-49]
 }
 
-const _FuncAttr_name = "alwaysinlineargmemonlybuiltincoldconvergentinaccessiblemem_or_argmemonlyinaccessiblememonlyinlinehintjumptableminsizenakednobuiltinnoduplicatenoimplicitfloatnoinlinenonlazybindnorecursenoredzonenoreturnnounwindoptnoneoptsizereadnonereadonlyreturns_twicesafestacksanitize_addresssanitize_hwaddresssanitize_memorysanitize_threadspeculatablespeculative_load_hardeningsspsspreqsspstrongstrictfpuwtablewriteonly"
+const _FuncAttr_name = "alwaysinlineargmemonlybuiltincoldconvergentinaccessiblemem_or_argmemonlyinaccessiblememonlyinlinehintjumptableminsizenakednobuiltinnoduplicatenoimplicitfloatnoinlinenonlazybindnorecursenoredzonenoreturnnounwindoptnoneoptsizereadnonereadonlyreturns_twicesafestacksanitize_addresssanitize_hwaddresssanitize_memorysanitize_threadspeculatablespeculative_load_hardeningsspsspreqsspstrongstrictfpuwtablewriteonlyhotmustprogressnocallbacknofreenomergenoprofilenosyncnull_pointer_is_validsanitize_memtaguwtable(async)uwtable(sync)willreturn"
 
-var _FuncAttr_index = [...]uint16{0, 12, 22, 29, 33, 43, 72, 91, 101, 110, 117, 122, 131, 142, 157, 165, 176, 185, 194, 202, 210, 217, 224, 232, 240, 253, 262, 278, 296, 311, 326, 338, 364, 367, 373, 382, 390, 397, 406}
+var _FuncAttr_index = [...]uint16{0, 12, 22, 29, 33, 43, 72, 91, 101, 110, 117, 122, 131, 142, 157, 165, 176, 185, 194, 202, 210, 217, 224, 232, 240, 253, 262, 278, 296, 311, 326, 338, 364, 367, 373, 382, 390, 397, 406, 409, 421, 431, 437, 444, 453, 459, 480, 495, 509, 522, 532}
 
 func FuncAttrFromString(s string) enum.FuncAttr {
 	if len(s) == 0 {
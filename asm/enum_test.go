@@ -0,0 +1,51 @@
+package asm
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestUnknownEnums(t *testing.T) {
+	// Synthetic enum values without keyword, which are represented in numeric
+	// form.
+	const (
+		callingConv  = enum.CallingConv(2000)
+		tag          = enum.DwarfTag(0x4000)
+		encoding     = enum.DwarfAttEncoding(0xF0)
+		lang         = enum.DwarfLang(0x7000)
+		emissionKind = enum.EmissionKind(100)
+		macinfo      = enum.DwarfMacinfo(200)
+	)
+	m := ir.NewModule()
+	f := m.NewFunc("f", types.Void)
+	f.CallingConv = callingConv
+	file := &metadata.DIFile{MetadataID: 0, Filename: "foo.c"}
+	basicType := &metadata.DIBasicType{MetadataID: 1, Tag: tag, Name: "t", Encoding: encoding}
+	unit := &metadata.DICompileUnit{MetadataID: 2, Distinct: true, Language: lang, File: file, EmissionKind: emissionKind}
+	macro := &metadata.DIMacro{MetadataID: 3, Type: macinfo, Name: "M"}
+	m.MetadataDefs = append(m.MetadataDefs, file, basicType, unit, macro)
+	const want = `declare cc 2000 void @f()
+
+!0 = !DIFile(filename: "foo.c", directory: "")
+!1 = !DIBasicType(tag: 16384, name: "t", encoding: 240)
+!2 = distinct !DICompileUnit(language: 28672, file: !0, emissionKind: 100)
+!3 = !DIMacro(type: 200, name: "M")
+`
+	if got := m.String(); got != want {
+		t.Fatalf("module mismatch; expected %q, got %q", want, got)
+	}
+	m2, err := ParseString("", want)
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	if got := m2.Funcs[0].CallingConv; got != callingConv {
+		t.Errorf("calling convention mismatch; expected %d, got %d", callingConv, got)
+	}
+	if got := m2.String(); got != want {
+		t.Errorf("round-trip module mismatch; expected %q, got %q", want, got)
+	}
+}
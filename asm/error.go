@@ -0,0 +1,105 @@
+package asm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/llir/ll"
+	"github.com/llir/ll/ast"
+	"github.com/pkg/errors"
+)
+
+// Error is an error of an LLVM IR assembly file, with source position.
+type Error struct {
+	// (optional) Path to the source file; or empty if not specified.
+	Path string
+	// 1-based line number of the error; or 0 if not known.
+	Line int
+	// 1-based column number of the error; or 0 if not known.
+	Col int
+	// Underlying error.
+	Err error
+}
+
+// Error returns the error message of the error, prefixed by its source
+// position.
+func (e *Error) Error() string {
+	var pos []string
+	if len(e.Path) > 0 {
+		pos = append(pos, e.Path)
+	}
+	if e.Line > 0 {
+		pos = append(pos, fmt.Sprint(e.Line), fmt.Sprint(e.Col))
+	}
+	if len(pos) == 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %v", strings.Join(pos, ":"), e.Err)
+}
+
+// newPosError returns a new error with the source position of the given AST
+// node, based on the specified format and arguments.
+func newPosError(old ast.LlvmNode, format string, args ...interface{}) *Error {
+	line, col := old.LlvmNode().LineColumn()
+	return &Error{
+		Line: line,
+		Col:  col,
+		Err:  errors.Errorf(format, args...),
+	}
+}
+
+// report reports the given semantic error encountered while translating the
+// AST node old. When collecting errors, the error is recorded and nil is
+// returned so that translation may continue. Otherwise, the error is returned
+// unmodified.
+//
+// The AST node old may be nil if not known, in which case the error is
+// recorded with the source position of its underlying cause, if any.
+func (gen *generator) report(old ast.LlvmNode, err error) error {
+	if !gen.collect {
+		return err
+	}
+	gen.errs = append(gen.errs, gen.posError(old, err))
+	return nil
+}
+
+// posError returns the given error with source position. The source position
+// of the innermost positioned error takes precedence over the source position
+// of the AST node old (which may be nil).
+func (gen *generator) posError(old ast.LlvmNode, err error) *Error {
+	e := &Error{Path: gen.path, Err: err}
+	switch cause := errors.Cause(err).(type) {
+	case *Error:
+		e.Line, e.Col, e.Err = cause.Line, cause.Col, cause.Err
+		return e
+	case ll.SyntaxError:
+		e.Line, e.Col = cause.Line, column(gen.content, cause.Offset)
+		return e
+	}
+	if old != nil {
+		e.Line, e.Col = old.LlvmNode().LineColumn()
+	}
+	return e
+}
+
+// sortErrors sorts the given errors by source position.
+func sortErrors(errs []error) {
+	less := func(i, j int) bool {
+		a, b := errs[i].(*Error), errs[j].(*Error)
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Col < b.Col
+	}
+	sort.SliceStable(errs, less)
+}
+
+// column returns the 1-based column number of the given byte offset into
+// content.
+func column(content string, offset int) int {
+	if offset > len(content) {
+		offset = len(content)
+	}
+	return offset - strings.LastIndex(content[:offset], "\n")
+}
@@ -0,0 +1,145 @@
+package asm
+
+import (
+	"testing"
+)
+
+func TestParseStringCollectErrors(t *testing.T) {
+	const content = `@x = global i32* @missing
+
+@y = global i32 42
+
+@a = alias i32, i32* @nope
+
+define i32 @f() {
+	%1 = load i32, i32* @y
+	%2 = add i32 %1, %undefined
+	ret i32 %2
+}
+
+define i32 @g() {
+	%1 = call i32 @f()
+	ret i32 %1
+}
+
+define void @h() {
+	call void @unknown()
+	ret void
+}
+`
+	m, errs := ParseStringCollectErrors("foo.ll", content)
+	if m == nil {
+		t.Fatalf("expected partial module, got nil module")
+	}
+	golden := []struct {
+		line, col int
+		want      string
+	}{
+		{line: 1, col: 18, want: `foo.ll:1:18: unable to locate global identifier "@missing"`},
+		{line: 5, col: 22, want: `foo.ll:5:22: unable to locate global identifier "@nope"`},
+		{line: 9, col: 19, want: `foo.ll:9:19: unable to locate local identifier "%undefined" of "@f"`},
+		{line: 19, col: 12, want: `foo.ll:19:12: unable to locate global identifier "@unknown"`},
+	}
+	if len(golden) != len(errs) {
+		t.Fatalf("number of errors mismatch; expected %d, got %d (%v)", len(golden), len(errs), errs)
+	}
+	for i, g := range golden {
+		e, ok := errs[i].(*Error)
+		if !ok {
+			t.Errorf("error %d type mismatch; expected *asm.Error, got %T", i, errs[i])
+			continue
+		}
+		if g.line != e.Line || g.col != e.Col {
+			t.Errorf("error %d position mismatch; expected %d:%d, got %d:%d", i, g.line, g.col, e.Line, e.Col)
+		}
+		if got := e.Error(); g.want != got {
+			t.Errorf("error %d message mismatch; expected %q, got %q", i, g.want, got)
+		}
+	}
+	// Partial module.
+	const want = `@x = external global i32*
+@y = global i32 42
+
+declare i32 @f()
+
+define i32 @g() {
+; <label>:0
+	%1 = call i32 @f()
+	ret i32 %1
+}
+
+declare void @h()
+`
+	if got := m.String(); want != got {
+		t.Errorf("partial module mismatch; expected %q, got %q", want, got)
+	}
+	// Metadata definitions which fail to translate are omitted.
+	const mdContent = `!0 = !{i32* @missing}
+!1 = !{!0, i32 1}
+!2 = !DITemplateValueParameter(name: "x", value: i32* @missing)
+`
+	m, errs = ParseStringCollectErrors("md.ll", mdContent)
+	if m == nil {
+		t.Fatalf("expected partial module, got nil module")
+	}
+	if len(errs) != 2 {
+		t.Errorf("number of errors mismatch; expected 2, got %d (%v)", len(errs), errs)
+	}
+	const wantMD = "!1 = !{!0, i32 1}\n"
+	if got := m.String(); wantMD != got {
+		t.Errorf("partial module mismatch; expected %q, got %q", wantMD, got)
+	}
+	// Syntax errors are not recoverable.
+	m, errs = ParseStringCollectErrors("bar.ll", "define void @f() {\n\tret void\n\n define\n")
+	if m != nil {
+		t.Errorf("expected nil module on syntax error, got %v", m)
+	}
+	const wantSyntax = "bar.ll:4:2: syntax error at line 4"
+	if len(errs) != 1 || errs[0].Error() != wantSyntax {
+		t.Errorf("syntax error mismatch; expected [%q], got %q", wantSyntax, errs)
+	}
+	// No errors.
+	if _, errs := ParseStringCollectErrors("", "@y = global i32 42\n"); len(errs) != 0 {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+}
+
+func TestParseStringInvalidToken(t *testing.T) {
+	// Function attributes of recent LLVM releases, as output by clang -O2, are
+	// not yet supported by the grammar and must not be skipped silently. Memory
+	// attributes are supported, but only in function attribute lists.
+	golden := []struct {
+		content string
+		want    string
+	}{
+		{
+			content: "define i32 @f(i32 %x) #0 {\n\tret i32 %x\n}\n\nattributes #0 = { mustprogress nofree norecurse nosync nounwind willreturn memory(none) uwtable(sync) }\n",
+			want:    `foo.ll:5:19: invalid token "mustprogress"`,
+		},
+		{
+			content: "declare void @g() nounwind uwtable(async)\n",
+			want:    `foo.ll:1:36: invalid token "async"`,
+		},
+		{
+			content: "declare void @h() memory(argmem: read) nosync\n",
+			want:    `foo.ll:1:40: invalid token "nosync"`,
+		},
+		{
+			content: "declare void @h() memory(argmem: none, read)\n",
+			want:    `foo.ll:1:19: default memory access kind must be specified first`,
+		},
+		{
+			content: "@x = global i32 0 memory(none)\n",
+			want:    `foo.ll:1:19: memory attribute "memory(none)" not part of function attribute list`,
+		},
+	}
+	for _, g := range golden {
+		if _, err := ParseString("foo.ll", g.content); err == nil {
+			t.Errorf("expected error for %q, got nil", g.content)
+		}
+		_, errs := ParseStringCollectErrors("foo.ll", g.content)
+		if len(errs) != 1 || errs[0].Error() != g.want {
+			t.Errorf("invalid token error mismatch; expected [%q], got %q", g.want, errs)
+		}
+	}
+}
@@ -89,6 +89,8 @@ func Example() {
 	//             Metadata:        nil,
 	//             Parent:          &ir.Module{(CYCLIC REFERENCE)},
 	//             mu:              sync.Mutex{},
+	//             dirty:           false,
+	//             assigned:        false,
 	//         },
 	//         &ir.Func{
 	//             GlobalIdent: ir.GlobalIdent{GlobalName:"rand", GlobalID:0},
@@ -113,6 +115,7 @@ func Example() {
 	//                             Ordering:   0x0,
 	//                             Align:      0x0,
 	//                             Metadata:   nil,
+	//                             InstParent: ir.InstParent{},
 	//                         },
 	//                         &ir.InstMul{
 	//                             LocalIdent: ir.LocalIdent{LocalName:"", LocalID:2},
@@ -127,6 +130,7 @@ func Example() {
 	//                             Typ:           &types.IntType{TypeName:"", BitSize:0x20},
 	//                             OverflowFlags: nil,
 	//                             Metadata:      nil,
+	//                             InstParent:    ir.InstParent{},
 	//                         },
 	//                         &ir.InstAdd{
 	//                             LocalIdent: ir.LocalIdent{LocalName:"", LocalID:3},
@@ -141,16 +145,18 @@ func Example() {
 	//                             Typ:           &types.IntType{TypeName:"", BitSize:0x20},
 	//                             OverflowFlags: nil,
 	//                             Metadata:      nil,
+	//                             InstParent:    ir.InstParent{},
 	//                         },
 	//                         &ir.InstStore{
-	//                             Src:       &ir.InstAdd{(CYCLIC REFERENCE)},
-	//                             Dst:       &ir.Global{(CYCLIC REFERENCE)},
-	//                             Atomic:    false,
-	//                             Volatile:  false,
-	//                             SyncScope: "",
-	//                             Ordering:  0x0,
-	//                             Align:     0x0,
-	//                             Metadata:  nil,
+	//                             Src:        &ir.InstAdd{(CYCLIC REFERENCE)},
+	//                             Dst:        &ir.Global{(CYCLIC REFERENCE)},
+	//                             Atomic:     false,
+	//                             Volatile:   false,
+	//                             SyncScope:  "",
+	//                             Ordering:   0x0,
+	//                             Align:      0x0,
+	//                             Metadata:   nil,
+	//                             InstParent: ir.InstParent{},
 	//                         },
 	//                         &ir.InstCall{
 	//                             LocalIdent: ir.LocalIdent{LocalName:"", LocalID:4},
@@ -159,6 +165,7 @@ func Example() {
 	//                                 &ir.InstAdd{(CYCLIC REFERENCE)},
 	//                             },
 	//                             Typ:            &types.IntType{TypeName:"", BitSize:0x20},
+	//                             FuncType:       (*types.FuncType)(nil),
 	//                             Tail:           0x0,
 	//                             FastMathFlags:  nil,
 	//                             CallingConv:    0x0,
@@ -167,6 +174,7 @@ func Example() {
 	//                             FuncAttrs:      nil,
 	//                             OperandBundles: nil,
 	//                             Metadata:       nil,
+	//                             InstParent:     ir.InstParent{},
 	//                         },
 	//                     },
 	//                     Term: &ir.TermRet{
@@ -177,6 +185,7 @@ func Example() {
 	//                                 &ir.InstAdd{(CYCLIC REFERENCE)},
 	//                             },
 	//                             Typ:            &types.IntType{TypeName:"", BitSize:0x20},
+	//                             FuncType:       (*types.FuncType)(nil),
 	//                             Tail:           0x0,
 	//                             FastMathFlags:  nil,
 	//                             CallingConv:    0x0,
@@ -185,8 +194,10 @@ func Example() {
 	//                             FuncAttrs:      nil,
 	//                             OperandBundles: nil,
 	//                             Metadata:       nil,
+	//                             InstParent:     ir.InstParent{},
 	//                         },
-	//                         Metadata: nil,
+	//                         Metadata:   nil,
+	//                         InstParent: ir.InstParent{},
 	//                     },
 	//                     Parent: &ir.Func{(CYCLIC REFERENCE)},
 	//                 },
@@ -214,6 +225,8 @@ func Example() {
 	//             Metadata:        nil,
 	//             Parent:          &ir.Module{(CYCLIC REFERENCE)},
 	//             mu:              sync.Mutex{},
+	//             dirty:           false,
+	//             assigned:        true,
 	//         },
 	//     },
 	//     SourceFilename:    "",
@@ -227,7 +240,10 @@ func Example() {
 	//     NamedMetadataDefs: {
 	//     },
 	//     MetadataDefs:    nil,
+	//     MetadataKinds:   nil,
 	//     UseListOrders:   nil,
 	//     UseListOrderBBs: nil,
+	//     symbols:         ir.symbolIndex{},
+	//     mdIndex:         ir.metadataIndex{},
 	// }
 }
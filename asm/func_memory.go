@@ -0,0 +1,154 @@
+package asm
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/llir/ll/ast"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/pkg/errors"
+)
+
+// memoryAttr is a memory attribute of an LLVM IR assembly file; e.g.
+// `memory(read, argmem: readwrite)`.
+//
+// The memory attribute is not supported by the grammar of github.com/llir/ll.
+// Memory attributes are thus extracted from the source before parsing, and
+// replaced by whitespace to retain the source position of other tokens. The
+// function attribute list of a memory attribute is located by the token
+// preceding it.
+type memoryAttr struct {
+	// 1-based line and column number of the memory attribute.
+	line, col int
+	// Byte offset of the memory attribute in the source file.
+	offset int
+	// End offset of the token preceding the memory attribute.
+	prevEnd int
+	// Memory attribute.
+	attr ir.Memory
+	// Memory attribute translated as part of a function attribute list.
+	used bool
+}
+
+// parseMemoryAttr parses the memory attribute at the start of s, returning the
+// memory attribute and its length in bytes.
+//
+// Examples:
+//
+//    memory(none)
+//    memory(read, argmem: readwrite)
+func parseMemoryAttr(s string) (ir.Memory, int, error) {
+	// 'memory' '(' MemoryEffects ')'
+	const keyword = "memory"
+	rest := strings.TrimLeft(s[len(keyword):], " \t\r\n")
+	if !strings.HasPrefix(rest, "(") {
+		return ir.Memory{}, 0, errors.Errorf("expected '(' after %q", keyword)
+	}
+	end := strings.IndexByte(rest, ')')
+	if end == -1 {
+		return ir.Memory{}, 0, errors.Errorf("expected ')' in %q attribute", keyword)
+	}
+	n := len(s) - len(rest) + end + 1
+	mem := ir.NewMemory(enum.ModRefNone)
+	seenLoc := false
+	for _, effect := range strings.Split(rest[1:end], ",") {
+		loc, kind := "", strings.TrimSpace(effect)
+		if pos := strings.IndexByte(kind, ':'); pos != -1 {
+			loc, kind = strings.TrimSpace(kind[:pos]), strings.TrimSpace(kind[pos+1:])
+		}
+		modRef, ok := modRefFromString(kind)
+		if !ok {
+			return ir.Memory{}, 0, errors.Errorf("invalid memory access kind %q; expected none, read, write or readwrite", kind)
+		}
+		switch loc {
+		case "":
+			if seenLoc {
+				return ir.Memory{}, 0, errors.New("default memory access kind must be specified first")
+			}
+			mem = ir.NewMemory(modRef)
+		case "argmem":
+			mem.ArgMem = modRef
+		case "inaccessiblemem":
+			mem.InaccessibleMem = modRef
+		default:
+			return ir.Memory{}, 0, errors.Errorf("invalid memory location %q; expected argmem or inaccessiblemem", loc)
+		}
+		if len(loc) > 0 {
+			seenLoc = true
+		}
+	}
+	return mem, n, nil
+}
+
+// irFuncAttrs translates the AST function attributes of the given AST function
+// header, attribute group definition, call instruction or invoke terminator into
+// equivalent IR function attributes. Memory attributes of the function attribute
+// list are included in order of occurrence.
+func (gen *generator) irFuncAttrs(old ast.LlvmNode, oldFuncAttrs []ast.FuncAttribute) []ir.FuncAttribute {
+	// Locate memory attributes of which the preceding token is part of old.
+	start, end := old.LlvmNode().Offset(), old.LlvmNode().Endoffset()
+	i := sort.Search(len(gen.memoryAttrs), func(i int) bool {
+		return gen.memoryAttrs[i].prevEnd > start
+	})
+	j := i
+	for j < len(gen.memoryAttrs) && gen.memoryAttrs[j].prevEnd <= end {
+		j++
+	}
+	mems := gen.memoryAttrs[i:j]
+	if len(oldFuncAttrs) == 0 && len(mems) == 0 {
+		return nil
+	}
+	funcAttrs := make([]ir.FuncAttribute, 0, len(oldFuncAttrs)+len(mems))
+	for _, oldFuncAttr := range oldFuncAttrs {
+		for len(mems) > 0 && mems[0].offset < oldFuncAttr.LlvmNode().Offset() {
+			funcAttrs = append(funcAttrs, mems[0].attr)
+			mems[0].used = true
+			mems = mems[1:]
+		}
+		funcAttr := gen.irFuncAttribute(oldFuncAttr)
+		funcAttrs = append(funcAttrs, funcAttr)
+	}
+	for _, mem := range mems {
+		funcAttrs = append(funcAttrs, mem.attr)
+		mem.used = true
+	}
+	return funcAttrs
+}
+
+// checkMemoryAttrs reports an error for memory attributes which are not part of
+// a function attribute list.
+func (gen *generator) checkMemoryAttrs() error {
+	if gen.collect && len(gen.errs) > 0 {
+		// Memory attributes of top-level entities which failed to translate are
+		// not translated.
+		return nil
+	}
+	for _, mem := range gen.memoryAttrs {
+		if mem.used {
+			continue
+		}
+		err := &Error{
+			Line: mem.line,
+			Col:  mem.col,
+			Err:  errors.Errorf("memory attribute %q not part of function attribute list", mem.attr),
+		}
+		if err := gen.report(nil, err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ### [ Helper functions ] ####################################################
+
+// modRefFromString returns the memory access kind corresponding to the given
+// string, and a boolean indicating success.
+func modRefFromString(s string) (enum.ModRef, bool) {
+	for kind := enum.ModRefNone; kind <= enum.ModRefReadWrite; kind++ {
+		if s == kind.String() {
+			return kind, true
+		}
+	}
+	return 0, false
+}
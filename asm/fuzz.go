@@ -0,0 +1,31 @@
+package asm
+
+import "fmt"
+
+// FuzzRoundTrip is a fuzzing entry point (in the style of go-fuzz) which parses
+// the given data as LLVM IR assembly, re-emits the parsed module and parses the
+// emitted LLVM IR assembly again. The module produced by the second parse must
+// be structurally equivalent to the first one; i.e. the re-emitted LLVM IR
+// assembly of both modules must be identical.
+//
+// FuzzRoundTrip returns 1 if data was successfully parsed and round-tripped,
+// and 0 if data is not valid LLVM IR assembly. It panics if the round-trip
+// fails, as this indicates a bug in either the parser or the serializer.
+//
+// A seed corpus of small valid modules is located in testdata/fuzz/corpus.
+func FuzzRoundTrip(data []byte) int {
+	m1, err := ParseBytes("<fuzz>", data)
+	if err != nil {
+		return 0
+	}
+	out1 := m1.String()
+	m2, err := ParseString("<fuzz round-trip>", out1)
+	if err != nil {
+		panic(fmt.Errorf("unable to parse re-emitted module; %v\n\ninput:\n%s\n\nre-emitted:\n%s", err, data, out1))
+	}
+	out2 := m2.String()
+	if out1 != out2 {
+		panic(fmt.Errorf("module mismatch after round-trip\n\ninput:\n%s\n\nfirst emit:\n%s\n\nsecond emit:\n%s", data, out1, out2))
+	}
+	return 1
+}
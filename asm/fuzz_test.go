@@ -0,0 +1,28 @@
+package asm
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestFuzzRoundTrip(t *testing.T) {
+	paths, err := filepath.Glob("testdata/fuzz/corpus/*.ll")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Errorf("unable to read %q; %v", path, err)
+			continue
+		}
+		if got := FuzzRoundTrip(data); got != 1 {
+			t.Errorf("round-trip mismatch of %q; expected 1, got %d", path, got)
+		}
+	}
+	// Invalid LLVM IR assembly.
+	if got := FuzzRoundTrip([]byte("define")); got != 0 {
+		t.Errorf("round-trip mismatch of invalid input; expected 0, got %d", got)
+	}
+}
@@ -13,16 +13,33 @@ import (
 type generator struct {
 	// LLVM IR module being generated.
 	m *ir.Module
+	// (optional) Existing LLVM IR module to which the generated module is to be
+	// appended; or nil if not present.
+	base *ir.Module
 	// index of AST top-level entities.
 	old oldIndex
 	// index of IR top-level entities.
 	new newIndex
 
-	// TODO: add rw mutex to gen.todo for access to blockaddress constant.
+	// Blockaddress constants with dummy basic blocks present; resolved after
+	// translation of function bodies and assignment of local IDs.
+	hasBlockAddrs bool
+	// Nesting depth of the constant currently being translated.
+	constDepth int
+	// Memory attributes extracted from the source before parsing, in order of
+	// occurrence.
+	memoryAttrs []*memoryAttr
+	// Offsets of poison constants, which are parsed as undef constants.
+	poisons map[int]bool
 
-	// Fix dummy basic blocks after translation of function bodies and assignment
-	// of local IDs.
-	todo []*constant.BlockAddress
+	// Collect recoverable semantic errors instead of stopping at the first.
+	collect bool
+	// Collected errors; each of type *Error.
+	errs []error
+	// (optional) Path to the source file; used for error reporting.
+	path string
+	// Source file contents; used for error reporting.
+	content string
 }
 
 // newGenerator returns a new generator for translating an LLVM IR module from
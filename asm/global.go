@@ -8,6 +8,7 @@ import (
 	"github.com/llir/llvm/internal/enc"
 	"github.com/llir/llvm/ir"
 	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
 	"github.com/llir/llvm/ir/types"
 	"github.com/pkg/errors"
 )
@@ -148,7 +149,11 @@ func (gen *generator) translateGlobalEntities() error {
 
 	// 4b1. Translate AST global declarations and definitions, indirect symbol
 	//      definitions, and function declarations and definitions to IR.
-	for ident, old := range gen.old.globals {
+	//
+	// Note, global entities are translated in order of occurrence, so that
+	// custom metadata kinds are registered in order of first use.
+	for _, ident := range gen.old.globalOrder {
+		old := gen.old.globals[ident]
 		v, ok := gen.new.globals[ident]
 		if !ok {
 			panic(fmt.Errorf("unable to locate global identifier %q", ident.Ident()))
@@ -160,7 +165,14 @@ func (gen *generator) translateGlobalEntities() error {
 				panic(fmt.Errorf("invalid global declaration type; expected *ir.Global, got %T", v))
 			}
 			if err := gen.irGlobal(new, old); err != nil {
-				return errors.WithStack(err)
+				// Translate as global variable declaration on error.
+				new.Init = nil
+				if new.Linkage != enum.LinkageExternWeak {
+					new.Linkage = enum.LinkageExternal
+				}
+				if err := gen.report(old, err); err != nil {
+					return errors.WithStack(err)
+				}
 			}
 		case *ast.IndirectSymbolDef:
 			kind := old.IndirectSymbolKind().Text()
@@ -171,7 +183,11 @@ func (gen *generator) translateGlobalEntities() error {
 					panic(fmt.Errorf("invalid alias definition type; expected *ir.Alias, got %T", v))
 				}
 				if err := gen.irAlias(new, old); err != nil {
-					return errors.WithStack(err)
+					// Omitted from module on error.
+					new.Aliasee = nil
+					if err := gen.report(old, err); err != nil {
+						return errors.WithStack(err)
+					}
 				}
 			case "ifunc":
 				new, ok := v.(*ir.IFunc)
@@ -179,7 +195,11 @@ func (gen *generator) translateGlobalEntities() error {
 					panic(fmt.Errorf("invalid IFunc definition type; expected *ir.IFunc, got %T", v))
 				}
 				if err := gen.irIFunc(new, old); err != nil {
-					return errors.WithStack(err)
+					// Omitted from module on error.
+					new.Resolver = nil
+					if err := gen.report(old, err); err != nil {
+						return errors.WithStack(err)
+					}
 				}
 			default:
 				panic(fmt.Errorf("support for indirect symbol kind %q not yet implemented", kind))
@@ -190,7 +210,9 @@ func (gen *generator) translateGlobalEntities() error {
 				panic(fmt.Errorf("invalid function declaration type; expected *ir.Func, got %T", v))
 			}
 			if err := gen.irFuncDecl(new, old); err != nil {
-				return errors.WithStack(err)
+				if err := gen.report(old, err); err != nil {
+					return errors.WithStack(err)
+				}
 			}
 		case *ast.FuncDef:
 			new, ok := v.(*ir.Func)
@@ -198,7 +220,14 @@ func (gen *generator) translateGlobalEntities() error {
 				panic(fmt.Errorf("invalid function definition type; expected *ir.Func, got %T", v))
 			}
 			if err := gen.irFuncDef(new, old); err != nil {
-				return errors.WithStack(err)
+				// Translate as function declaration on error.
+				new.Blocks = nil
+				if new.Linkage != enum.LinkageExternWeak {
+					new.Linkage = enum.LinkageNone
+				}
+				if err := gen.report(old, err); err != nil {
+					return errors.WithStack(err)
+				}
 			}
 		default:
 			panic(fmt.Errorf("support for global variable, indirect symbol or function %T not yet implemented", old))
@@ -493,13 +522,7 @@ func (gen *generator) irFuncHeader(new *ir.Func, old ast.FuncHeader) error {
 	}
 	// (optional) Address space: handled in newGlobalEntity.
 	// (optional) Function attributes.
-	if oldFuncAttrs := old.FuncAttrs(); len(oldFuncAttrs) > 0 {
-		new.FuncAttrs = make([]ir.FuncAttribute, len(oldFuncAttrs))
-		for i, oldFuncAttr := range oldFuncAttrs {
-			funcAttr := gen.irFuncAttribute(oldFuncAttr)
-			new.FuncAttrs[i] = funcAttr
-		}
-	}
+	new.FuncAttrs = gen.irFuncAttrs(old, old.FuncAttrs())
 
 	// (optional) Section name.
 	if n, ok := old.Section(); ok {
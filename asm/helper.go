@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/llir/ll"
 	"github.com/llir/ll/ast"
 	asmenum "github.com/llir/llvm/asm/enum"
 	"github.com/llir/llvm/internal/enc"
@@ -644,6 +645,14 @@ func text(n ast.LlvmNode) string {
 	return ""
 }
 
+// firstToken returns the first token of the given AST node; or ll.EOI if the
+// node is empty.
+func firstToken(n ast.LlvmNode) ll.Token {
+	var l ll.Lexer
+	l.Init(text(n))
+	return l.Next()
+}
+
 // findBlock returns the basic block with the given local identifier in the
 // function.
 func findBlock(f *ir.Func, blockIdent ir.LocalIdent) (*ir.Block, error) {
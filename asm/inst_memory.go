@@ -65,12 +65,25 @@ func (fgen *funcGen) newAtomicRMWInst(ident ir.LocalIdent, old *ast.AtomicRMWIns
 // newGetElementPtrInst returns a new IR getelementptr instruction (without body
 // but with type) based on the given AST getelementptr instruction.
 func (fgen *funcGen) newGetElementPtrInst(ident ir.LocalIdent, old *ast.GetElementPtrInst) (*ir.InstGetElementPtr, error) {
-	// TODO: handle address space of Src?
 	elemType, err := fgen.gen.irType(old.ElemType())
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
-	typ, err := fgen.gen.gepType(elemType, old.Indices())
+	// The address space of the result is that of the source address.
+	srcType, err := fgen.gen.irType(old.Src().Typ())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var addrSpace types.AddrSpace
+	switch srcType := srcType.(type) {
+	case *types.PointerType:
+		addrSpace = srcType.AddrSpace
+	case *types.VectorType:
+		if elem, ok := srcType.ElemType.(*types.PointerType); ok {
+			addrSpace = elem.AddrSpace
+		}
+	}
+	typ, err := fgen.gen.gepType(elemType, addrSpace, old.Indices())
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
@@ -363,8 +376,9 @@ func (fgen *funcGen) irGetElementPtrInst(new ir.Instruction, old *ast.GetElement
 
 // gepType returns the pointer type or vector of pointers type to the element at
 // the position in the type specified by the given indices, as calculated by the
-// getelementptr instruction.
-func (gen *generator) gepType(elemType types.Type, indices []ast.TypeValue) (types.Type, error) {
+// getelementptr instruction. The resulting pointer type is in the given address
+// space of the source address.
+func (gen *generator) gepType(elemType types.Type, addrSpace types.AddrSpace, indices []ast.TypeValue) (types.Type, error) {
 	e := elemType
 	for i, index := range indices {
 		if i == 0 {
@@ -433,14 +447,16 @@ func (gen *generator) gepType(elemType types.Type, indices []ast.TypeValue) (typ
 	//    %113 = getelementptr inbounds %struct.fileinfo, %struct.fileinfo* %96, <2 x i64> %110, !dbg !4736
 	//    %116 = bitcast i8** %115 to <2 x %struct.fileinfo*>*, !dbg !4738
 	//    store <2 x %struct.fileinfo*> %113, <2 x %struct.fileinfo*>* %116, align 8, !dbg !4738, !tbaa !1793
+	ptr := types.NewPointer(e)
+	ptr.AddrSpace = addrSpace
 	if len(indices) > 0 {
 		t, err := gen.irType(indices[0].Typ())
 		if err != nil {
 			return nil, errors.WithStack(err)
 		}
 		if t, ok := t.(*types.VectorType); ok {
-			return types.NewVector(t.Len, types.NewPointer(e)), nil
+			return types.NewVector(t.Len, ptr), nil
 		}
 	}
-	return types.NewPointer(e), nil
+	return ptr, nil
 }
@@ -80,7 +80,12 @@ func (fgen *funcGen) newCallInst(ident ir.LocalIdent, old *ast.CallInst) (*ir.In
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
-	return &ir.InstCall{LocalIdent: ident, Typ: typ}, nil
+	inst := &ir.InstCall{LocalIdent: ident, Typ: typ}
+	// Record explicit function type of callee.
+	if sig, ok := typ.(*types.FuncType); ok {
+		inst.FuncType = sig
+	}
+	return inst, nil
 }
 
 // newVAArgInst returns a new IR vaarg instruction (without body but with type)
@@ -272,7 +277,7 @@ func (fgen *funcGen) irCallInst(new ir.Instruction, old *ast.CallInst) error {
 		// assembly callees and constrant expressions.
 		var paramTypes []types.Type
 		if len(inst.Args) > 0 {
-			paramTypes := make([]types.Type, len(inst.Args))
+			paramTypes = make([]types.Type, len(inst.Args))
 			for i, arg := range inst.Args {
 				paramTypes[i] = arg.Type()
 			}
@@ -307,13 +312,7 @@ func (fgen *funcGen) irCallInst(new ir.Instruction, old *ast.CallInst) error {
 		inst.AddrSpace = irAddrSpace(n)
 	}
 	// (optional) Function attributes.
-	if oldFuncAttrs := old.FuncAttrs(); len(oldFuncAttrs) > 0 {
-		inst.FuncAttrs = make([]ir.FuncAttribute, len(oldFuncAttrs))
-		for i, oldFuncAttr := range oldFuncAttrs {
-			funcAttr := fgen.gen.irFuncAttribute(oldFuncAttr)
-			inst.FuncAttrs[i] = funcAttr
-		}
-	}
+	inst.FuncAttrs = fgen.gen.irFuncAttrs(old, old.FuncAttrs())
 	// (optional) Operand bundles.
 	if oldOperandBundles := old.OperandBundles(); len(oldOperandBundles) > 0 {
 		inst.OperandBundles = make([]*ir.OperandBundle, len(oldOperandBundles))
@@ -5,6 +5,7 @@ import (
 
 	"github.com/llir/ll/ast"
 	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
 	"github.com/llir/llvm/ir/types"
 	"github.com/pkg/errors"
 )
@@ -156,7 +157,15 @@ func (fgen *funcGen) irShuffleVectorInst(new ir.Instruction, old *ast.ShuffleVec
 	if err != nil {
 		return errors.WithStack(err)
 	}
-	inst.Mask = mask
+	c, ok := mask.(constant.Constant)
+	if !ok {
+		return errors.Errorf("invalid shuffle mask %s; expected constant", mask.Ident())
+	}
+	elems, ok := constant.ShuffleMask(c)
+	if !ok {
+		return errors.Errorf("invalid shuffle mask %s; expected constant vector of i32 elements", mask)
+	}
+	inst.Mask = elems
 	// (optional) Metadata.
 	md, err := fgen.gen.irMetadataAttachments(old.Metadata())
 	if err != nil {
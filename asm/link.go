@@ -0,0 +1,42 @@
+package asm
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/llir/llvm/ir"
+	"github.com/pkg/errors"
+)
+
+// ParseAndLink parses the given LLVM IR assembly files into LLVM IR modules,
+// reading from readers, and links them into a single LLVM IR module, with
+// semantics similar to those of llvm-link (see ir.LinkModules).
+//
+// Readers with a Name method (e.g. *os.File) are identified by name in error
+// messages, and other readers by their index.
+func ParseAndLink(readers ...io.Reader) (*ir.Module, error) {
+	var m *ir.Module
+	for i, r := range readers {
+		path := ""
+		file := fmt.Sprintf("input %d", i)
+		if n, ok := r.(interface{ Name() string }); ok {
+			path = n.Name()
+			file = fmt.Sprintf("%q", path)
+		}
+		src, err := Parse(path, r)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to parse %s", file)
+		}
+		if m == nil {
+			m = src
+			continue
+		}
+		if err := ir.LinkModules(m, src); err != nil {
+			return nil, errors.Wrapf(err, "unable to link %s", file)
+		}
+	}
+	if m == nil {
+		return ir.NewModule(), nil
+	}
+	return m, nil
+}
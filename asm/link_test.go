@@ -0,0 +1,122 @@
+package asm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseAndLink(t *testing.T) {
+	const a = `%T = type { i32, i8* }
+%O = type opaque
+
+@x = global i32 42
+@cnt = internal global i32 0
+@llvm.used = appending global [1 x i8*] [i8* bitcast (i32* @x to i8*)]
+
+declare i32 @g(i32)
+declare void @h(%O*)
+
+define i32 @f() {
+	%1 = load i32, i32* @x
+	%2 = call i32 @g(i32 %1)
+	ret i32 %2
+}
+
+attributes #0 = { nounwind }
+!llvm.ident = !{!0}
+!0 = !{!"a"}
+`
+	const b = `%T = type { i32, i8* }
+%O = type { i64 }
+
+@x = external global i32
+@cnt = internal global i32 1
+@y = global i32* @x
+@llvm.used = appending global [1 x i8*] [i8* bitcast (i32 (i32)* @g to i8*)]
+
+declare i32 @f()
+define weak void @h(%O* %o) {
+	ret void
+}
+
+define i32 @g(i32 %a) #0 {
+	%1 = call i32 @f()
+	%2 = load i32, i32* @cnt
+	%3 = add i32 %1, %2
+	ret i32 %3
+}
+
+attributes #0 = { noinline }
+!llvm.ident = !{!0}
+!0 = !{!"b"}
+`
+	m, err := ParseAndLink(strings.NewReader(a), strings.NewReader(b))
+	if err != nil {
+		t.Fatalf("unable to link modules; %+v", err)
+	}
+	want := `%O = type { i64 }
+%T = type { i32, i8* }
+
+@x = global i32 42
+@cnt = internal global i32 0
+@llvm.used = appending global [2 x i8*] [i8* bitcast (i32* @x to i8*), i8* bitcast (i32 (i32)* @g to i8*)]
+@cnt.1 = internal global i32 1
+@y = global i32* @x
+
+define i32 @f() {
+; <label>:0
+	%1 = load i32, i32* @x
+	%2 = call i32 @g(i32 %1)
+	ret i32 %2
+}
+
+define weak void @h(%O* %o) {
+; <label>:0
+	ret void
+}
+
+define i32 @g(i32 %a) #1 {
+; <label>:0
+	%1 = call i32 @f()
+	%2 = load i32, i32* @cnt.1
+	%3 = add i32 %1, %2
+	ret i32 %3
+}
+
+attributes #0 = { nounwind }
+attributes #1 = { noinline }
+
+!llvm.ident = !{!0, !1}
+
+!0 = !{!"a"}
+!1 = !{!"b"}
+`
+	if got := m.String(); got != want {
+		t.Errorf("module mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+	// Conflicts between modules.
+	golden := []struct {
+		src  string
+		want string
+	}{
+		{src: "@x = global i32 0", want: "duplicate definition of global identifier @x"},
+		{src: "%T = type { i64 }", want: "type definition mismatch of %T; expected { i32 }, got { i64 }"},
+		{src: `target triple = "x86_64-pc-linux-gnu"`, want: `target triple mismatch; expected "x86_64-unknown-linux-gnu", got "x86_64-pc-linux-gnu"`},
+		{src: "$c = comdat largest", want: "comdat selection kind mismatch of $c; expected any, got largest"},
+	}
+	const base = `target triple = "x86_64-unknown-linux-gnu"
+$c = comdat any
+%T = type { i32 }
+@x = global i32 42, comdat($c)
+`
+	for _, g := range golden {
+		_, err := ParseAndLink(strings.NewReader(base), strings.NewReader(g.src))
+		if err == nil {
+			t.Errorf("expected error for module %q, got nil", g.src)
+			continue
+		}
+		if want := "unable to link input 1: " + g.want; err.Error() != want {
+			t.Errorf("error mismatch for module %q; expected %q, got %q", g.src, want, err.Error())
+		}
+	}
+}
@@ -31,6 +31,9 @@ func (gen *generator) irMetadataAttachments(olds []ast.MetadataAttachment) ([]*m
 func (gen *generator) irMetadataAttachment(old ast.MetadataAttachment) (*metadata.Attachment, error) {
 	// Name.
 	name := metadataName(old.Name())
+	// Register metadata kind of attachment in the metadata kind table of the
+	// module.
+	gen.m.MetadataKindID(name)
 	// Node.
 	node, err := gen.irMDNode(old.MDNode())
 	if err != nil {
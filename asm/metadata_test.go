@@ -0,0 +1,33 @@
+package asm
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/metadata"
+)
+
+func TestMetadataString(t *testing.T) {
+	const src = `!named = !{!0}
+
+!0 = !{!"foo\00bar", !"\22h\C3\A9llo\22", !"\\"}
+`
+	m, err := ParseString("", src)
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	tuple := m.MetadataDefs[0].(*metadata.Tuple)
+	want := []string{"foo\x00bar", `"héllo"`, `\`}
+	for i, field := range tuple.Fields {
+		got := field.(*metadata.String).Value
+		if want[i] != got {
+			t.Errorf("metadata string mismatch of field %d; expected %q, got %q", i, want[i], got)
+		}
+	}
+	const wantModule = `!named = !{!0}
+
+!0 = !{!"foo\00bar", !"\22h\C3\A9llo\22", !"\5C"}
+`
+	if got := m.String(); got != wantModule {
+		t.Errorf("module mismatch; expected:\n%s\ngot:\n%s", wantModule, got)
+	}
+}
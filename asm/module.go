@@ -316,13 +316,7 @@ func (gen *generator) translateAttrGroupDefs() {
 // irAttrGroupDef translates the AST attribute group definition to an equivalent
 // IR attribute group definition.
 func (gen *generator) irAttrGroupDef(new *ir.AttrGroupDef, old *ast.AttrGroupDef) {
-	if oldFuncAttrs := old.FuncAttrs(); len(oldFuncAttrs) > 0 {
-		new.FuncAttrs = make([]ir.FuncAttribute, len(oldFuncAttrs))
-		for i, oldFuncAttr := range oldFuncAttrs {
-			funcAttr := gen.irFuncAttribute(oldFuncAttr)
-			new.FuncAttrs[i] = funcAttr
-		}
-	}
+	new.FuncAttrs = gen.irFuncAttrs(old, old.FuncAttrs())
 }
 
 // --- [ Named metadata definitions ] ------------------------------------------
@@ -338,7 +332,12 @@ func (gen *generator) translateNamedMetadataDefs() error {
 		}
 		for _, oldDef := range old {
 			if err := gen.irNamedMetadataDef(new, oldDef); err != nil {
-				return errors.WithStack(err)
+				if err := gen.report(oldDef, err); err != nil {
+					return errors.WithStack(err)
+				}
+				// Omit partially translated named metadata definition.
+				delete(gen.new.namedMetadataDefs, name)
+				break
 			}
 		}
 	}
@@ -365,15 +364,24 @@ func (gen *generator) irNamedMetadataDef(new *metadata.NamedDef, old *ast.NamedM
 // module to IR.
 func (gen *generator) translateMetadataDefs() error {
 	// 4b4. Translate AST metadata definitions to IR.
+	var failed []int64
 	for id, old := range gen.old.metadataDefs {
 		new, ok := gen.new.metadataDefs[id]
 		if !ok {
 			panic(fmt.Errorf("unable to locate metadata ID %q", enc.MetadataID(id)))
 		}
 		if err := gen.irMetadataDef(new, old); err != nil {
-			return errors.WithStack(err)
+			if err := gen.report(old, err); err != nil {
+				return errors.WithStack(err)
+			}
+			failed = append(failed, id)
 		}
 	}
+	// Omit partially translated metadata definitions.
+	for _, id := range failed {
+		delete(gen.old.metadataDefs, id)
+		delete(gen.new.metadataDefs, id)
+	}
 	return nil
 }
 
@@ -408,13 +416,16 @@ func (gen *generator) irMetadataDef(new metadata.Definition, old *ast.MetadataDe
 func (gen *generator) translateUseListOrders() error {
 	// 5. Translate use-list orders.
 	if len(gen.old.useListOrders) > 0 {
-		gen.m.UseListOrders = make([]*ir.UseListOrder, len(gen.old.useListOrders))
-		for i, oldUseListOrder := range gen.old.useListOrders {
+		gen.m.UseListOrders = make([]*ir.UseListOrder, 0, len(gen.old.useListOrders))
+		for _, oldUseListOrder := range gen.old.useListOrders {
 			useListOrder, err := gen.irUseListOrder(oldUseListOrder)
 			if err != nil {
-				return errors.WithStack(err)
+				if err := gen.report(oldUseListOrder, err); err != nil {
+					return errors.WithStack(err)
+				}
+				continue
 			}
-			gen.m.UseListOrders[i] = useListOrder
+			gen.m.UseListOrders = append(gen.m.UseListOrders, useListOrder)
 		}
 	}
 	return nil
@@ -453,13 +464,16 @@ func (gen *generator) irUseListOrder(old *ast.UseListOrder) (*ir.UseListOrder, e
 func (gen *generator) translateUseListOrderBBs() error {
 	// 6. Translate basic block specific use-list orders.
 	if len(gen.old.useListOrderBBs) > 0 {
-		gen.m.UseListOrderBBs = make([]*ir.UseListOrderBB, len(gen.old.useListOrderBBs))
-		for i, oldUseListOrderBB := range gen.old.useListOrderBBs {
+		gen.m.UseListOrderBBs = make([]*ir.UseListOrderBB, 0, len(gen.old.useListOrderBBs))
+		for _, oldUseListOrderBB := range gen.old.useListOrderBBs {
 			useListOrderBB, err := gen.irUseListOrderBB(oldUseListOrderBB)
 			if err != nil {
-				return errors.WithStack(err)
+				if err := gen.report(oldUseListOrderBB, err); err != nil {
+					return errors.WithStack(err)
+				}
+				continue
 			}
-			gen.m.UseListOrderBBs[i] = useListOrderBB
+			gen.m.UseListOrderBBs = append(gen.m.UseListOrderBBs, useListOrderBB)
 		}
 	}
 	return nil
@@ -472,7 +486,7 @@ func (gen *generator) irUseListOrderBB(old *ast.UseListOrderBB) (*ir.UseListOrde
 	funcIdent := globalIdent(old.Func())
 	v, ok := gen.new.globals[funcIdent]
 	if !ok {
-		return nil, errors.Errorf("unable to locate global identifier %q", funcIdent.Ident())
+		return nil, newPosError(old.Func(), "unable to locate global identifier %q", funcIdent.Ident())
 	}
 	f, ok := v.(*ir.Func)
 	if !ok {
@@ -0,0 +1,82 @@
+package asm
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir"
+)
+
+func TestTargetDirectives(t *testing.T) {
+	golden := []struct {
+		in         string
+		want       string
+		dataLayout string
+		triple     string
+	}{
+		// Neither data layout nor target triple.
+		{
+			in:   "@x = global i32 42\n",
+			want: "@x = global i32 42\n",
+		},
+		// Data layout only.
+		{
+			in:         "target datalayout = \"e-m:e-i64:64-n32:64\"\n\n@x = global i32 42\n",
+			want:       "target datalayout = \"e-m:e-i64:64-n32:64\"\n\n@x = global i32 42\n",
+			dataLayout: "e-m:e-i64:64-n32:64",
+		},
+		// Target triple only.
+		{
+			in:     "target triple = \"x86_64-unknown-linux-gnu\"\n\n@x = global i32 42\n",
+			want:   "target triple = \"x86_64-unknown-linux-gnu\"\n\n@x = global i32 42\n",
+			triple: "x86_64-unknown-linux-gnu",
+		},
+		// Both data layout and target triple, emitted with data layout first.
+		{
+			in:         "target triple = \"x86_64-unknown-linux-gnu\"\ntarget datalayout = \"e-m:e-i64:64-n32:64\"\n\n@x = global i32 42\n",
+			want:       "target datalayout = \"e-m:e-i64:64-n32:64\"\ntarget triple = \"x86_64-unknown-linux-gnu\"\n\n@x = global i32 42\n",
+			dataLayout: "e-m:e-i64:64-n32:64",
+			triple:     "x86_64-unknown-linux-gnu",
+		},
+		// Empty data layout and target triple are omitted.
+		{
+			in:   "target datalayout = \"\"\ntarget triple = \"\"\n\n@x = global i32 42\n",
+			want: "@x = global i32 42\n",
+		},
+		// Module with only data layout and target triple.
+		{
+			in:         "target datalayout = \"e\"\ntarget triple = \"wasm32\"\n",
+			want:       "target datalayout = \"e\"\ntarget triple = \"wasm32\"\n",
+			dataLayout: "e",
+			triple:     "wasm32",
+		},
+	}
+	canonical := &ir.Printer{CanonicalMode: true}
+	for _, g := range golden {
+		m, err := ParseString("", g.in)
+		if err != nil {
+			t.Errorf("unable to parse module %q; %+v", g.in, err)
+			continue
+		}
+		if m.DataLayout != g.dataLayout {
+			t.Errorf("data layout mismatch of %q; expected %q, got %q", g.in, g.dataLayout, m.DataLayout)
+		}
+		if m.TargetTriple != g.triple {
+			t.Errorf("target triple mismatch of %q; expected %q, got %q", g.in, g.triple, m.TargetTriple)
+		}
+		if got := m.String(); got != g.want {
+			t.Errorf("module mismatch of %q; expected %q, got %q", g.in, g.want, got)
+		}
+		if got := canonical.Sprint(m); got != g.want {
+			t.Errorf("canonical module mismatch of %q; expected %q, got %q", g.in, g.want, got)
+		}
+		// Round-trip.
+		m2, err := ParseString("", g.want)
+		if err != nil {
+			t.Errorf("unable to parse module %q; %+v", g.want, err)
+			continue
+		}
+		if got := m2.String(); got != g.want {
+			t.Errorf("round-trip module mismatch of %q; expected %q, got %q", g.want, g.want, got)
+		}
+	}
+}
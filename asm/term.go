@@ -373,13 +373,7 @@ func (fgen *funcGen) irInvokeTerm(new ir.Terminator, old *ast.InvokeTerm) error
 		term.AddrSpace = irAddrSpace(n)
 	}
 	// (optional) Function attributes.
-	if oldFuncAttrs := old.FuncAttrs(); len(oldFuncAttrs) > 0 {
-		term.FuncAttrs = make([]ir.FuncAttribute, len(oldFuncAttrs))
-		for i, oldFuncAttr := range oldFuncAttrs {
-			funcAttr := fgen.gen.irFuncAttribute(oldFuncAttr)
-			term.FuncAttrs[i] = funcAttr
-		}
-	}
+	term.FuncAttrs = fgen.gen.irFuncAttrs(old, old.FuncAttrs())
 	// (optional) Operand bundles.
 	if oldOperandBundles := old.OperandBundles(); len(oldOperandBundles) > 0 {
 		term.OperandBundles = make([]*ir.OperandBundle, len(oldOperandBundles))
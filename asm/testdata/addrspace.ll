@@ -0,0 +1,15 @@
+@g = addrspace(1) global [4 x i32] zeroinitializer, align 4
+@p = global i32 addrspace(1)* getelementptr ([4 x i32], [4 x i32] addrspace(1)* @g, i64 0, i64 2)
+
+define void @f(i32 %v, i64 %i) {
+; <label>:0
+	%x = alloca i32, align 4, addrspace(5)
+	store i32 %v, i32 addrspace(5)* %x, align 4
+	%1 = getelementptr inbounds [4 x i32], [4 x i32] addrspace(1)* @g, i64 0, i64 %i
+	%2 = load i32, i32 addrspace(1)* %1, align 4
+	%3 = load i32, i32 addrspace(5)* %x, align 4
+	%4 = add i32 %2, %3
+	store i32 %4, i32 addrspace(1)* %1, align 4
+	%5 = addrspacecast i32 addrspace(5)* %x to i32*
+	ret void
+}
@@ -0,0 +1,15 @@
+@g = global i32 0, align 8
+
+define void @f(i32 %v, i64 %n) {
+; <label>:0
+	%p = alloca i32, align 16
+	store i32 %v, i32* %p, align 4
+	%1 = load i32, i32* %p, align 4
+	%2 = load volatile i32, i32* @g, align 8
+	%3 = alloca i8, i64 %n, align 1
+	%4 = alloca inalloca i32, i32 4, align 4
+	%5 = alloca i32, align 4, addrspace(5)
+	store atomic i32 %1, i32* %p release, align 4
+	%6 = load atomic i32, i32* %p acquire, align 4
+	ret void
+}
@@ -0,0 +1,37 @@
+define swiftcc void @f() {
+entry:
+	ret void
+}
+
+define cc 1023 void @g() {
+entry:
+	ret void
+}
+
+define x86_intrcc void @h(i8* byval %p) {
+entry:
+	ret void
+}
+
+define cc 18 void @tail() {
+entry:
+	ret void
+}
+
+define cc 19 void @cfguard_check() {
+entry:
+	ret void
+}
+
+define cc 20 void @swift_tail() {
+entry:
+	ret void
+}
+
+define cc 100 void @amdgpu_gfx() {
+entry:
+	%0 = call cc 100 i32 @amdgpu_gfx_callee()
+	ret void
+}
+
+declare cc 100 i32 @amdgpu_gfx_callee()
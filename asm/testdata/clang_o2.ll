@@ -0,0 +1,51 @@
+source_filename = "sum.c"
+target datalayout = "e-m:e-i64:64-f80:128-n8:16:32:64-S128"
+target triple = "x86_64-pc-linux-gnu"
+
+@.str = private unnamed_addr constant [4 x i8] c"%d\0A\00", align 1
+
+define dso_local i32 @sum(i32* nocapture readonly %a, i32 %n) local_unnamed_addr #0 {
+entry:
+	%cmp6 = icmp sgt i32 %n, 0
+	br i1 %cmp6, label %for.body.preheader, label %for.cond.cleanup
+
+for.body.preheader:
+	%wide.trip.count = zext i32 %n to i64
+	br label %for.body
+
+for.cond.cleanup:
+	%s.0.lcssa = phi i32 [ 0, %entry ], [ %add, %for.body ]
+	ret i32 %s.0.lcssa
+
+for.body:
+	%indvars.iv = phi i64 [ 0, %for.body.preheader ], [ %indvars.iv.next, %for.body ]
+	%s.07 = phi i32 [ 0, %for.body.preheader ], [ %add, %for.body ]
+	%arrayidx = getelementptr inbounds i32, i32* %a, i64 %indvars.iv
+	%0 = load i32, i32* %arrayidx, align 4, !tbaa !2
+	%add = add nsw i32 %0, %s.07
+	%indvars.iv.next = add nuw nsw i64 %indvars.iv, 1
+	%exitcond = icmp eq i64 %indvars.iv.next, %wide.trip.count
+	br i1 %exitcond, label %for.cond.cleanup, label %for.body
+}
+
+define dso_local i32 @main() local_unnamed_addr #1 {
+entry:
+	%call = tail call i32 (i8*, ...) @printf(i8* getelementptr inbounds ([4 x i8], [4 x i8]* @.str, i64 0, i64 0), i32 42)
+	ret i32 0
+}
+
+declare dso_local i32 @printf(i8* nocapture readonly, ...) local_unnamed_addr #2
+
+attributes #0 = { norecurse nounwind readonly uwtable "correctly-rounded-divide-sqrt-fp-math"="false" "disable-tail-calls"="false" "less-precise-fpmad"="false" "min-legal-vector-width"="0" "no-frame-pointer-elim"="false" "no-infs-fp-math"="false" "no-jump-tables"="false" "no-nans-fp-math"="false" "no-signed-zeros-fp-math"="false" "no-trapping-math"="false" "stack-protector-buffer-size"="8" "target-cpu"="x86-64" "target-features"="+fxsr,+mmx,+sse,+sse2,+x87" "unsafe-fp-math"="false" "use-soft-float"="false" }
+attributes #1 = { nounwind uwtable "correctly-rounded-divide-sqrt-fp-math"="false" "disable-tail-calls"="false" "less-precise-fpmad"="false" "min-legal-vector-width"="0" "no-frame-pointer-elim"="false" "no-infs-fp-math"="false" "no-jump-tables"="false" "no-nans-fp-math"="false" "no-signed-zeros-fp-math"="false" "no-trapping-math"="false" "stack-protector-buffer-size"="8" "target-cpu"="x86-64" "target-features"="+fxsr,+mmx,+sse,+sse2,+x87" "unsafe-fp-math"="false" "use-soft-float"="false" }
+attributes #2 = { nounwind "correctly-rounded-divide-sqrt-fp-math"="false" "disable-tail-calls"="false" "less-precise-fpmad"="false" "no-frame-pointer-elim"="false" "no-infs-fp-math"="false" "no-nans-fp-math"="false" "no-signed-zeros-fp-math"="false" "no-trapping-math"="false" "stack-protector-buffer-size"="8" "target-cpu"="x86-64" "target-features"="+fxsr,+mmx,+sse,+sse2,+x87" "unsafe-fp-math"="false" "use-soft-float"="false" }
+
+!llvm.ident = !{!1}
+!llvm.module.flags = !{!0}
+
+!0 = !{i32 1, !"wchar_size", i32 4}
+!1 = !{!"clang version 8.0.0 (tags/RELEASE_800/final)"}
+!2 = !{!3, !3, i64 0}
+!3 = !{!"int", !4, i64 0}
+!4 = !{!"omnipotent char", !5, i64 0}
+!5 = !{!"Simple C/C++ TBAA"}
@@ -0,0 +1,43 @@
+$fh = comdat any
+$k = comdat any
+
+@a = dso_local global i32 0, align 4
+@b = internal global i32 0, align 4
+@c = hidden global i32 0, align 4
+@d = dso_local hidden global i32 0
+@e = external dso_local global i32
+@f = weak dso_preemptable global i32 0
+@g = private unnamed_addr constant [3 x i8] c"hi\00", align 1
+@h = dso_local thread_local global i32 0
+@i = external dllimport global i32
+@j = dso_local dllexport global i32 0
+@k = linkonce_odr dso_local hidden unnamed_addr global i32 0, comdat
+
+@al = dso_local alias i32, i32* @a
+@al2 = hidden alias i32, i32* @a
+
+@if = dso_local ifunc void (), void ()* ()* @resolver
+
+define internal void @ff() {
+; <label>:0
+	ret void
+}
+
+define dso_local hidden void @fg() {
+; <label>:0
+	ret void
+}
+
+define linkonce_odr dso_local hidden i32 @fh() comdat {
+; <label>:0
+	ret i32 0
+}
+
+declare dso_local void @fi()
+
+declare extern_weak dso_preemptable void @fj()
+
+define dso_local void ()* @resolver() {
+; <label>:0
+	ret void ()* @ff
+}
@@ -0,0 +1,8 @@
+@x = global i32 0
+@y = global i32 0
+@a = global i1 icmp eq (i32* @x, i32* @y)
+@b = global i1 icmp ult (i32 ptrtoint (i32* @x to i32), i32 ptrtoint (i32* @y to i32))
+@c = global i1 fcmp olt (double 1.0, double 2.0)
+@d = global <2 x i1> fcmp uno (<2 x float> <float 1.0, float 2.0>, <2 x float> <float 3.0, float 4.0>)
+@e = global i32* select (i1 icmp eq (i32* @x, i32* @y), i32* @x, i32* @y)
+@f = global <2 x i32> select (<2 x i1> <i1 true, i1 false>, <2 x i32> <i32 1, i32 2>, <2 x i32> <i32 3, i32 4>)
@@ -0,0 +1,20 @@
+@a = global double 1.0
+@b = global double 1.000000e+00
+@c = global double 0x3FF0000000000000
+@d = global double -0.0
+@e = global double 3.14
+@f = global double 1.5e-10
+@g = global float 0x3FB99999A0000000
+@h = global float 2.500000e-01
+@i = global half 0xH4400
+@j = global x86_fp80 0xK3FFF8000000000000000
+@k = global fp128 0xL00000000000000003FFF000000000000
+@l = global ppc_fp128 0xM3FF00000000000000000000000000000
+
+define double @fn(double %x) {
+; <label>:0
+	%1 = fadd double %x, 1.000000e+00
+	%2 = fmul double %1, 0x4000000000000000
+	%3 = fcmp olt double %2, 5.0e+00
+	ret double %2
+}
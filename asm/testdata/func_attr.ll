@@ -0,0 +1,14 @@
+define void @f() #0 {
+; <label>:0
+	ret void
+}
+
+define void @g() convergent norecurse nounwind readnone speculatable uwtable {
+; <label>:0
+	ret void
+}
+
+declare void @h() #1
+
+attributes #0 = { convergent noinline norecurse nounwind optnone sanitize_address sanitize_hwaddress sanitize_memory sanitize_thread speculative_load_hardening sspstrong uwtable "correctly-rounded-divide-sqrt-fp-math"="false" "frame-pointer"="all" "no-trapping-math"="true" "stack-protector-buffer-size"="8" "target-cpu"="x86-64" }
+attributes #1 = { inaccessiblemem_or_argmemonly nounwind strictfp }
@@ -0,0 +1,16 @@
+define void @f() memory(none) {
+; <label>:0
+	call void @g() memory(argmem: write, inaccessiblemem: read)
+	call void @g() #0
+	call void @g() nounwind memory(read), !foo !0
+	ret void
+}
+
+declare void @g() nounwind memory(read, argmem: readwrite) readnone
+
+declare void @h() memory(readwrite)
+
+attributes #0 = { memory(write) nounwind }
+attributes #1 = { noinline memory(argmem: read) "foo"="bar" }
+
+!0 = !{}
@@ -0,0 +1,7 @@
+declare i32 @printf(i8*, ...)
+
+define i32 @add(i32 %a, i32 %b) {
+entry:
+	%sum = add nsw i32 %a, %b
+	ret i32 %sum
+}
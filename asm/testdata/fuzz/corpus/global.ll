@@ -0,0 +1,3 @@
+@x = global i32 42
+@s = constant [6 x i8] c"hello\00"
+@p = global i32* @x
@@ -0,0 +1,15 @@
+define i32 @sum(i32 %n) {
+entry:
+	br label %loop
+
+loop:
+	%i = phi i32 [ 0, %entry ], [ %i.next, %loop ]
+	%acc = phi i32 [ 0, %entry ], [ %acc.next, %loop ]
+	%acc.next = add i32 %acc, %i
+	%i.next = add i32 %i, 1
+	%done = icmp sge i32 %i.next, %n
+	br i1 %done, label %exit, label %loop
+
+exit:
+	ret i32 %acc.next
+}
@@ -0,0 +1,3 @@
+!llvm.ident = !{!0}
+
+!0 = !{!"clang version 8.0.0"}
@@ -0,0 +1,8 @@
+%pair = type { i32, double }
+%list = type { i32, %list* }
+
+define double @second(%pair* %p) {
+	%1 = getelementptr %pair, %pair* %p, i32 0, i32 1
+	%2 = load double, double* %1
+	ret double %2
+}
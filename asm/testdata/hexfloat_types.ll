@@ -0,0 +1,7 @@
+@a = global half 0xH3C01
+@b = global x86_fp80 0xK4000C90FDAA22168C235
+@c = global x86_fp80 0xK00000000000000000001
+@d = global fp128 0xL0000000000000001C000921FB54442D1
+@e = global fp128 0xL00000000000000007FFF000000000000
+@f = global ppc_fp128 0xM400921FB54442D183CA1A62633145C07
+@g = global ppc_fp128 0xMBFF0000000000000BC90000000000000
@@ -0,0 +1,12 @@
+define i32 @f(i32 %x) {
+; <label>:0
+	ret i32 %x
+}
+
+define i32 @g(i32 (i32)* %fp, i32 %x) {
+; <label>:0
+	%1 = call i32 (i32) %fp(i32 %x)
+	%2 = call i32 (i32) @f(i32 %1)
+	%3 = call i32 @f(i32 %2)
+	ret i32 %3
+}
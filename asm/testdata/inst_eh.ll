@@ -0,0 +1,25 @@
+declare void @g()
+
+declare i32 @__CxxFrameHandler3(...)
+
+define void @f() personality i32 (...)* @__CxxFrameHandler3 {
+entry:
+	invoke void @g()
+		to label %exit unwind label %dispatch
+
+dispatch:
+	%cs = catchswitch within none [label %handler] unwind label %cleanup
+
+handler:
+	%cp = catchpad within %cs [i8* null, i32 64, i8* null]
+	call void @g() [ "funclet"(token %cp) ]
+	catchret from %cp to label %exit
+
+cleanup:
+	%cl = cleanuppad within none []
+	call void @g() [ "funclet"(token %cl) ]
+	cleanupret from %cl unwind to caller
+
+exit:
+	ret void
+}
@@ -5,3 +5,10 @@ define void @f() {
 	%3 = shufflevector <2 x i32> <i32 7, i32 8>, <2 x i32> <i32 9, i32 10>, <4 x i32> <i32 3, i32 2, i32 1, i32 0>
 	ret void
 }
+
+define <4 x i32> @g(<4 x i32> %a, <4 x i32> %b) {
+entry:
+	%c = shufflevector <4 x i32> %a, <4 x i32> %b, <4 x i32> <i32 0, i32 undef, i32 2, i32 poison>
+	%d = shufflevector <4 x i32> %c, <4 x i32> poison, <4 x i32> <i32 poison, i32 poison, i32 poison, i32 poison>
+	ret <4 x i32> shufflevector (<4 x i32> <i32 1, i32 2, i32 3, i32 4>, <4 x i32> poison, <4 x i32> <i32 poison, i32 3, i32 undef, i32 0>)
+}
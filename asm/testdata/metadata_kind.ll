@@ -0,0 +1,8 @@
+define void @f() {
+; <label>:0
+	%1 = add i32 1, 2, !my.custom !0, !annotation !1
+	ret void, !my.custom !0
+}
+
+!0 = !{!"foo"}
+!1 = !{!"bar", i32 42}
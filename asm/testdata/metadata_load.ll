@@ -0,0 +1,10 @@
+define i8* @f(i8** %p) {
+; <label>:0
+	%v = load i8*, i8** %p, !nonnull !0
+	%w = load i8*, i8** %p, !dereferenceable !1
+	%x = load i8*, i8** %p, !nonnull !0, !dereferenceable !1
+	ret i8* %x
+}
+
+!0 = !{}
+!1 = !{i64 8}
@@ -0,0 +1,7 @@
+define i8 @f(i8* %p) {
+; <label>:0
+	%v = load i8, i8* %p, !range !0
+	ret i8 %v
+}
+
+!0 = !{i8 0, i8 2}
@@ -0,0 +1,6 @@
+!named = !{!0, !1, !2, !3}
+
+!0 = !{!"foo\00bar"}
+!1 = !{!"a\22b\22"}
+!2 = !{!"h\C3\A9llo w\C3\B6rld\0A"}
+!3 = !{!"trailing backslash\5C"}
@@ -0,0 +1,33 @@
+%T = type { i32, [2 x i8], i8* }
+
+@g = global i32 0
+
+define void @f() prefix %T { i32 1, [2 x i8] c"ab", i8* bitcast (i32* @g to i8*) } prologue { i8, i8 } { i8 -21, i8 14 } {
+; <label>:0
+	ret void
+}
+
+define void @h() prefix <{ i32, i64 }> <{ i32 1, i64 ptrtoint (i32* @g to i64) }> prologue [2 x i32] [i32 1, i32 2] {
+; <label>:0
+	ret void
+}
+
+define void @k() prefix i32 add (i32 ptrtoint (i32* @g to i32), i32 1) prologue { i32, i8* } zeroinitializer {
+; <label>:0
+	ret void
+}
+
+define void @u() prefix [4 x i8] undef prologue <2 x i32> <i32 1, i32 2> {
+; <label>:0
+	ret void
+}
+
+define void @s() prefix i8* bitcast (void ()* @s to i8*) prologue { i8*, i64 } { i8* blockaddress(@s, %bb), i64 0 } {
+entry:
+	br label %bb
+
+bb:
+	ret void
+}
+
+declare void @d() prefix i32 1 prologue i8 2
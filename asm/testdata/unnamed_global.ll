@@ -0,0 +1,18 @@
+@0 = global i32 5
+@g = global i32* @0
+@1 = private unnamed_addr constant [3 x i8] c"hi\00"
+
+@2 = alias i32, i32* @0
+
+define i32 @3() {
+entry:
+	%x = load i32, i32* @2
+	ret i32 %x
+}
+
+define i32 @f() {
+entry:
+	%x = call i32 @3()
+	%y = getelementptr [3 x i8], [3 x i8]* @1, i64 0, i64 0
+	ret i32 %x
+}
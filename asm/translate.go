@@ -3,8 +3,8 @@
 // Note: step 3 and the substeps of 4a can be done concurrently.
 // Note: the substeps of 4a can be done concurrently.
 // Note: the substeps of 4b can be done concurrently.
-// Note: steps 5-7 can be done concurrently.
-// Note: the substeps of 8 can be done concurrently.
+// Note: steps 5-6 can be done concurrently.
+// Note: the substeps of 7 can be done concurrently.
 //
 // 1. Index AST top-level entities.
 //
@@ -53,18 +53,16 @@
 //
 //       4. Translate AST metadata definitions to IR.
 //
-// Note: steps 5-7 can be done concurrenty.
+// Note: steps 5-6 can be done concurrenty.
 //
 // 5. Translate use-list orders.
 //
 // 6. Translate basic block specific use-list orders.
 //
-// 7. Fix basic block references in blockaddress constants.
-//
-// 8. Add IR top-level declarations and definitions to the IR module in order of
+// 7. Add IR top-level declarations and definitions to the IR module in order of
 //    occurrence in the input.
 //
-//    Note: the substeps of 8 can be done concurrently.
+//    Note: the substeps of 7 can be done concurrently.
 //
 //    a) Add IR type definitions to the IR module in natural sorting order.
 //
@@ -79,6 +77,8 @@
 //    e) Add IR named metadata definitions to the IR module.
 //
 //    f) Add IR metadata definitions to the IR module in numeric order.
+//
+// 8. Fix basic block references in blockaddress constants.
 
 package asm
 
@@ -90,7 +90,6 @@ import (
 	"github.com/llir/ll/ast"
 	"github.com/llir/llvm/internal/enc"
 	"github.com/llir/llvm/ir"
-	"github.com/llir/llvm/ir/constant"
 	"github.com/llir/llvm/ir/metadata"
 	"github.com/llir/llvm/ir/types"
 	"github.com/pkg/errors"
@@ -98,14 +97,20 @@ import (
 )
 
 // translate translates the given AST module into an equivalent IR module.
-func translate(old *ast.Module) (*ir.Module, error) {
-	gen := newGenerator()
+func (gen *generator) translate(old *ast.Module) (*ir.Module, error) {
 	// 1. Index AST top-level entities.
 	indexStart := time.Now()
 	if err := gen.indexTopLevelEntities(old); err != nil {
 		return nil, errors.WithStack(err)
 	}
 	dbg.Println("index AST top-level entities took:", time.Since(indexStart))
+	// Check for conflicts with the top-level entities of the base module, when
+	// appending to an existing IR module.
+	if gen.base != nil {
+		if err := gen.checkBaseConflicts(); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
 	// 2. Resolve IR type definitions.
 	typeStart := time.Now()
 	if err := gen.resolveTypeDefs(); err != nil {
@@ -133,7 +138,7 @@ func translate(old *ast.Module) (*ir.Module, error) {
 		return nil, errors.WithStack(err)
 	}
 	dbg.Println("translate AST to IR took:", time.Since(translateStart))
-	// Note: step 5-7 can be done concurrenty.
+	// Note: step 5-6 can be done concurrenty.
 	//
 	// 5. Translate use-list orders.
 	if err := gen.translateUseListOrders(); err != nil {
@@ -143,50 +148,56 @@ func translate(old *ast.Module) (*ir.Module, error) {
 	if err := gen.translateUseListOrderBBs(); err != nil {
 		return nil, errors.WithStack(err)
 	}
-	// 7. Fix basic block references in blockaddress constants.
-	for _, c := range gen.todo {
-		if err := fixBlockAddressConst(c); err != nil {
-			return nil, errors.WithStack(err)
-		}
-	}
-	// 8. Add IR top-level declarations and definitions to the IR module in order
+	// 7. Add IR top-level declarations and definitions to the IR module in order
 	//    of occurrence in the input.
 	//
-	// Note: the substeps of 8 can be done concurrently.
+	// Note: the substeps of 7 can be done concurrently.
 	addStart := time.Now()
 	gen.addDefsToModule()
 	dbg.Println("add IR definitions to IR module took:", time.Since(addStart))
+	// 8. Fix basic block references in blockaddress constants.
+	if gen.hasBlockAddrs {
+		if err := gen.m.ResolveBlockAddresses(); err != nil {
+			if err := gen.report(nil, err); err != nil {
+				return nil, errors.WithStack(err)
+			}
+		}
+	}
+	// 9. Check that memory attributes are part of function attribute lists.
+	if err := gen.checkMemoryAttrs(); err != nil {
+		return nil, errors.WithStack(err)
+	}
 	return gen.m, nil
 }
 
 // addDefsToModule adds IR top-level declarations and definitions to the IR
 // module in order of occurrence in the input.
 func (gen *generator) addDefsToModule() {
-	// 8. Add IR top-level declarations and definitions to the IR module in order
+	// 7. Add IR top-level declarations and definitions to the IR module in order
 	//    of occurrence in the input.
 	//
-	// Note: the substeps of 8 can be done concurrently.
+	// Note: the substeps of 7 can be done concurrently.
 	//
-	// 8a. Add IR type definitions to the IR module in natural sorting order.
+	// 7a. Add IR type definitions to the IR module in natural sorting order.
 	gen.addTypeDefsToModule()
-	// 8b. Add IR comdat definitions to the IR module in natural sorting order.
+	// 7b. Add IR comdat definitions to the IR module in natural sorting order.
 	gen.addComdatDefsToModule()
-	// 8c. Add IR global variable declarations and definitions, indirect symbol
+	// 7c. Add IR global variable declarations and definitions, indirect symbol
 	//     definitions, and function declarations and definitions to the IR
 	//     module in order of occurrence in the input.
 	gen.addGlobalEntitiesToModule()
-	// 8d. Add IR attribute group definitions to the IR module in numeric order.
+	// 7d. Add IR attribute group definitions to the IR module in numeric order.
 	gen.addAttrGroupDefsToModule()
-	// 8e. Add IR named metadata definitions to the IR module.
+	// 7e. Add IR named metadata definitions to the IR module.
 	gen.addNamedMetadataDefsToModule()
-	// 8f. Add IR metadata definitions to the IR module in numeric order.
+	// 7f. Add IR metadata definitions to the IR module in numeric order.
 	gen.addMetadataDefsToModule()
 }
 
 // addTypeDefsToModule adds IR type definitions to the IR module in natural
 // sorting order.
 func (gen *generator) addTypeDefsToModule() {
-	// 8a. Add IR type definitions to the IR module in natural sorting order.
+	// 7a. Add IR type definitions to the IR module in natural sorting order.
 	typeNames := make([]string, 0, len(gen.old.typeDefs))
 	for name := range gen.old.typeDefs {
 		typeNames = append(typeNames, name)
@@ -207,7 +218,7 @@ func (gen *generator) addTypeDefsToModule() {
 // addComdatDefsToModule adds IR comdat definitions to the IR module in natural
 // sorting order.
 func (gen *generator) addComdatDefsToModule() {
-	// 8b. Add IR comdat definitions to the IR module in natural sorting order.
+	// 7b. Add IR comdat definitions to the IR module in natural sorting order.
 	comdatNames := make([]string, 0, len(gen.old.comdatDefs))
 	for name := range gen.old.comdatDefs {
 		comdatNames = append(comdatNames, name)
@@ -229,7 +240,7 @@ func (gen *generator) addComdatDefsToModule() {
 // definitions, indirect symbol definitions, and function declarations and
 // definitions to the IR module in order of occurrence in the input.
 func (gen *generator) addGlobalEntitiesToModule() {
-	// 8c. Add IR global variable declarations and definitions, indirect symbol
+	// 7c. Add IR global variable declarations and definitions, indirect symbol
 	//     definitions, and function declarations and definitions to the IR
 	//     module in order of occurrence in the input.
 	for _, ident := range gen.old.globalOrder {
@@ -241,8 +252,18 @@ func (gen *generator) addGlobalEntitiesToModule() {
 		case *ir.Global:
 			gen.m.Globals = append(gen.m.Globals, def)
 		case *ir.Alias:
+			// Omit alias definitions which failed to translate when collecting
+			// errors.
+			if def.Aliasee == nil {
+				continue
+			}
 			gen.m.Aliases = append(gen.m.Aliases, def)
 		case *ir.IFunc:
+			// Omit IFunc definitions which failed to translate when collecting
+			// errors.
+			if def.Resolver == nil {
+				continue
+			}
 			gen.m.IFuncs = append(gen.m.IFuncs, def)
 		case *ir.Func:
 			gen.m.Funcs = append(gen.m.Funcs, def)
@@ -255,7 +276,7 @@ func (gen *generator) addGlobalEntitiesToModule() {
 // addAttrGroupDefsToModule adds IR attribute group definitions to the IR module
 // in numeric order.
 func (gen *generator) addAttrGroupDefsToModule() {
-	// 8d. Add IR attribute group definitions to the IR module in numeric order.
+	// 7d. Add IR attribute group definitions to the IR module in numeric order.
 	attrGroupIDs := make([]int64, 0, len(gen.old.attrGroupDefs))
 	for id := range gen.old.attrGroupDefs {
 		attrGroupIDs = append(attrGroupIDs, id)
@@ -279,7 +300,7 @@ func (gen *generator) addAttrGroupDefsToModule() {
 // addNamedMetadataDefsToModule adds IR named metadata definitions to the IR
 // module.
 func (gen *generator) addNamedMetadataDefsToModule() {
-	// 8e. Add IR named metadata definitions to the IR module.
+	// 7e. Add IR named metadata definitions to the IR module.
 	for name, def := range gen.new.namedMetadataDefs {
 		gen.m.NamedMetadataDefs[name] = def
 	}
@@ -309,25 +330,3 @@ func (gen *generator) addMetadataDefsToModule() {
 }
 
 // ### [ Helper functions ] ####################################################
-
-// fixBlockAddressConst fixes the basic block of the given blockaddress
-// constant. During translation of constants, blockaddress constants are
-// assigned dummy basic blocks since function bodies have yet to be translated.
-//
-// pre-condition: translated function body and assigned local IDs of c.Func.
-func fixBlockAddressConst(c *constant.BlockAddress) error {
-	f, ok := c.Func.(*ir.Func)
-	if !ok {
-		panic(fmt.Errorf("invalid function type in blockaddress constant; expected *ir.Func, got %T", c.Func))
-	}
-	bb, ok := c.Block.(*ir.Block)
-	if !ok {
-		panic(fmt.Errorf("invalid basic block type in blockaddress constant; expected *ir.Block, got %T", c.Block))
-	}
-	block, err := findBlock(f, bb.LocalIdent)
-	if err != nil {
-		return errors.WithStack(err)
-	}
-	c.Block = block
-	return nil
-}
@@ -37,7 +37,6 @@ func (gen *generator) resolveTypeDefs() error {
 func (gen *generator) createTypeDefs() error {
 	// 2a. Index type identifiers and create scaffolding IR type definitions
 	//     (without bodies).
-	gen.new.typeDefs = make(map[string]types.Type)
 	for typeName, old := range gen.old.typeDefs {
 		// track is used to identify self-referential named types.
 		track := make(map[string]bool)
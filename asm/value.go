@@ -16,14 +16,14 @@ func (fgen *funcGen) irValue(typ types.Type, old ast.Value) (value.Value, error)
 		ident := globalIdent(*old)
 		v, ok := fgen.gen.new.globals[ident]
 		if !ok {
-			return nil, errors.Errorf("unable to locate global identifier %q", ident.Ident())
+			return nil, newPosError(old, "unable to locate global identifier %q", ident.Ident())
 		}
 		return v, nil
 	case *ast.LocalIdent:
 		ident := localIdent(*old)
 		v, ok := fgen.locals[ident]
 		if !ok {
-			return nil, errors.Errorf("unable to locate local identifier %q of %q", ident.Ident(), fgen.f.Ident())
+			return nil, newPosError(old, "unable to locate local identifier %q of %q", ident.Ident(), fgen.f.Ident())
 		}
 		return v, nil
 	case *ast.InlineAsm:
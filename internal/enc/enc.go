@@ -229,18 +229,17 @@ func Unescape(s string) []byte {
 	buf := []byte(s)
 	for i := 0; i < len(s); i++ {
 		b := s[i]
-		if b == '\\' && i+2 < len(s) {
-			if s[i+1] == '\\' {
-				b = '\\'
-				i++
-			} else {
-				x1, ok := unhex(s[i+1])
+		if b == '\\' && i+1 < len(s) && s[i+1] == '\\' {
+			// Note, the escaped backslash may be the last character of s.
+			b = '\\'
+			i++
+		} else if b == '\\' && i+2 < len(s) {
+			x1, ok := unhex(s[i+1])
+			if ok {
+				x2, ok := unhex(s[i+2])
 				if ok {
-					x2, ok := unhex(s[i+2])
-					if ok {
-						b = x1<<4 | x2
-						i += 2
-					}
+					b = x1<<4 | x2
+					i += 2
 				}
 			}
 		}
@@ -330,6 +330,12 @@ func TestUnescape(t *testing.T) {
 		{s: `foo \\ bar`, want: []byte(`foo \ bar`)},
 		// i=13 (arbitrary data, invalid UTF-8)
 		{s: `foo\81\82bar`, want: []byte{'f', 'o', 'o', 0x81, 0x82, 'b', 'a', 'r'}},
+		// i=14
+		{s: `foo\\`, want: []byte(`foo\`)},
+		// i=15
+		{s: `\\`, want: []byte(`\`)},
+		// i=16
+		{s: `foo\00bar\00`, want: []byte("foo\x00bar\x00")},
 	}
 	for i, g := range golden {
 		got := Unescape(g.s)
@@ -25,12 +25,16 @@ type Block struct {
 
 	// extra.
 
-	// Parent function; field set by ir.Func.NewBlock.
+	// Parent function; field set by ir.Func.NewBlock and
+	// ir.Module.ResolveParents.
 	Parent *Func
 }
 
 // NewBlock returns a new basic block based on the given label name. An empty
 // label name indicates an unnamed basic block.
+//
+// The basic block is not attached to any function; use ir.Func.NewBlock to
+// create a basic block which is appended to a function.
 func NewBlock(name string) *Block {
 	block := &Block{}
 	block.SetName(name)
@@ -51,19 +55,39 @@ func (block *Block) Type() types.Type {
 // LLString returns the LLVM syntax representation of the basic block
 // definition.
 func (block *Block) LLString() string {
+	return block.llString(nil)
+}
+
+// llString returns the LLVM syntax representation of the basic block
+// definition, with the given comments printed before the basic block and its
+// instructions.
+func (block *Block) llString(comments Comments) string {
 	// Name=LabelIdentopt Insts=Instruction* Term=Terminator
 	buf := &strings.Builder{}
+	writeComments(buf, comments, block, "")
 	if block.IsUnnamed() {
 		fmt.Fprintf(buf, "; <label>:%d\n", block.LocalID)
 	} else {
 		fmt.Fprintf(buf, "%s\n", enc.Label(block.LocalName))
 	}
 	for _, inst := range block.Insts {
+		writeComments(buf, comments, inst, "\t")
 		fmt.Fprintf(buf, "\t%s\n", inst.LLString())
 	}
 	if block.Term == nil {
 		panic(fmt.Sprintf("missing terminator in basic block %q.\ncurrent instructions:\n%s", block.Name(), buf.String()))
 	}
+	writeComments(buf, comments, block.Term, "\t")
 	fmt.Fprintf(buf, "\t%s", block.Term.LLString())
 	return buf.String()
 }
+
+// ### [ Helper functions ] ####################################################
+
+// markDirty marks the parent function of the basic block as modified, if
+// attached to a function.
+func (block *Block) markDirty() {
+	if block.Parent != nil {
+		block.Parent.MarkDirty()
+	}
+}
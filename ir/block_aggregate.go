@@ -12,7 +12,9 @@ import (
 // based on the given aggregate value and indicies.
 func (block *Block) NewExtractValue(x value.Value, indices ...uint64) *InstExtractValue {
 	inst := NewExtractValue(x, indices...)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -22,6 +24,8 @@ func (block *Block) NewExtractValue(x value.Value, indices ...uint64) *InstExtra
 // on the given aggregate value, element and indicies.
 func (block *Block) NewInsertValue(x, elem value.Value, indices ...uint64) *InstInsertValue {
 	inst := NewInsertValue(x, elem, indices...)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
@@ -12,7 +12,9 @@ import (
 // operands.
 func (block *Block) NewShl(x, y value.Value) *InstShl {
 	inst := NewShl(x, y)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -22,7 +24,9 @@ func (block *Block) NewShl(x, y value.Value) *InstShl {
 // operands.
 func (block *Block) NewLShr(x, y value.Value) *InstLShr {
 	inst := NewLShr(x, y)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -32,7 +36,9 @@ func (block *Block) NewLShr(x, y value.Value) *InstLShr {
 // operands.
 func (block *Block) NewAShr(x, y value.Value) *InstAShr {
 	inst := NewAShr(x, y)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -42,7 +48,9 @@ func (block *Block) NewAShr(x, y value.Value) *InstAShr {
 // operands.
 func (block *Block) NewAnd(x, y value.Value) *InstAnd {
 	inst := NewAnd(x, y)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -52,7 +60,9 @@ func (block *Block) NewAnd(x, y value.Value) *InstAnd {
 // operands.
 func (block *Block) NewOr(x, y value.Value) *InstOr {
 	inst := NewOr(x, y)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -62,6 +72,8 @@ func (block *Block) NewOr(x, y value.Value) *InstOr {
 // operands.
 func (block *Block) NewXor(x, y value.Value) *InstXor {
 	inst := NewXor(x, y)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
@@ -13,7 +13,9 @@ import (
 // given source value and target type.
 func (block *Block) NewTrunc(from value.Value, to types.Type) *InstTrunc {
 	inst := NewTrunc(from, to)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -23,7 +25,9 @@ func (block *Block) NewTrunc(from value.Value, to types.Type) *InstTrunc {
 // source value and target type.
 func (block *Block) NewZExt(from value.Value, to types.Type) *InstZExt {
 	inst := NewZExt(from, to)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -33,7 +37,9 @@ func (block *Block) NewZExt(from value.Value, to types.Type) *InstZExt {
 // source value and target type.
 func (block *Block) NewSExt(from value.Value, to types.Type) *InstSExt {
 	inst := NewSExt(from, to)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -43,7 +49,9 @@ func (block *Block) NewSExt(from value.Value, to types.Type) *InstSExt {
 // given source value and target type.
 func (block *Block) NewFPTrunc(from value.Value, to types.Type) *InstFPTrunc {
 	inst := NewFPTrunc(from, to)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -53,7 +61,9 @@ func (block *Block) NewFPTrunc(from value.Value, to types.Type) *InstFPTrunc {
 // given source value and target type.
 func (block *Block) NewFPExt(from value.Value, to types.Type) *InstFPExt {
 	inst := NewFPExt(from, to)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -63,7 +73,9 @@ func (block *Block) NewFPExt(from value.Value, to types.Type) *InstFPExt {
 // given source value and target type.
 func (block *Block) NewFPToUI(from value.Value, to types.Type) *InstFPToUI {
 	inst := NewFPToUI(from, to)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -73,7 +85,9 @@ func (block *Block) NewFPToUI(from value.Value, to types.Type) *InstFPToUI {
 // given source value and target type.
 func (block *Block) NewFPToSI(from value.Value, to types.Type) *InstFPToSI {
 	inst := NewFPToSI(from, to)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -83,7 +97,9 @@ func (block *Block) NewFPToSI(from value.Value, to types.Type) *InstFPToSI {
 // given source value and target type.
 func (block *Block) NewUIToFP(from value.Value, to types.Type) *InstUIToFP {
 	inst := NewUIToFP(from, to)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -93,7 +109,9 @@ func (block *Block) NewUIToFP(from value.Value, to types.Type) *InstUIToFP {
 // given source value and target type.
 func (block *Block) NewSIToFP(from value.Value, to types.Type) *InstSIToFP {
 	inst := NewSIToFP(from, to)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -103,7 +121,9 @@ func (block *Block) NewSIToFP(from value.Value, to types.Type) *InstSIToFP {
 // the given source value and target type.
 func (block *Block) NewPtrToInt(from value.Value, to types.Type) *InstPtrToInt {
 	inst := NewPtrToInt(from, to)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -113,7 +133,9 @@ func (block *Block) NewPtrToInt(from value.Value, to types.Type) *InstPtrToInt {
 // the given source value and target type.
 func (block *Block) NewIntToPtr(from value.Value, to types.Type) *InstIntToPtr {
 	inst := NewIntToPtr(from, to)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -123,7 +145,9 @@ func (block *Block) NewIntToPtr(from value.Value, to types.Type) *InstIntToPtr {
 // given source value and target type.
 func (block *Block) NewBitCast(from value.Value, to types.Type) *InstBitCast {
 	inst := NewBitCast(from, to)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -133,6 +157,8 @@ func (block *Block) NewBitCast(from value.Value, to types.Type) *InstBitCast {
 // based on the given source value and target type.
 func (block *Block) NewAddrSpaceCast(from value.Value, to types.Type) *InstAddrSpaceCast {
 	inst := NewAddrSpaceCast(from, to)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
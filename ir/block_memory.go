@@ -14,7 +14,9 @@ import (
 // given element type.
 func (block *Block) NewAlloca(elemType types.Type) *InstAlloca {
 	inst := NewAlloca(elemType)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -24,7 +26,9 @@ func (block *Block) NewAlloca(elemType types.Type) *InstAlloca {
 // source address.
 func (block *Block) NewLoad(src value.Value) *InstLoad {
 	inst := NewLoad(src)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -34,7 +38,9 @@ func (block *Block) NewLoad(src value.Value) *InstLoad {
 // given source value and destination address.
 func (block *Block) NewStore(src, dst value.Value) *InstStore {
 	inst := NewStore(src, dst)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -44,7 +50,9 @@ func (block *Block) NewStore(src, dst value.Value) *InstStore {
 // given atomic ordering.
 func (block *Block) NewFence(ordering enum.AtomicOrdering) *InstFence {
 	inst := NewFence(ordering)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -55,7 +63,9 @@ func (block *Block) NewFence(ordering enum.AtomicOrdering) *InstFence {
 // orderings for success and failure.
 func (block *Block) NewCmpXchg(ptr, cmp, new value.Value, successOrdering, failureOrdering enum.AtomicOrdering) *InstCmpXchg {
 	inst := NewCmpXchg(ptr, cmp, new, successOrdering, failureOrdering)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -65,7 +75,9 @@ func (block *Block) NewCmpXchg(ptr, cmp, new value.Value, successOrdering, failu
 // the given atomic operation, destination address, operand and atomic ordering.
 func (block *Block) NewAtomicRMW(op enum.AtomicOp, dst, x value.Value, ordering enum.AtomicOrdering) *InstAtomicRMW {
 	inst := NewAtomicRMW(op, dst, x, ordering)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -75,6 +87,8 @@ func (block *Block) NewAtomicRMW(op enum.AtomicOp, dst, x value.Value, ordering
 // based on the given source address and element indices.
 func (block *Block) NewGetElementPtr(src value.Value, indices ...value.Value) *InstGetElementPtr {
 	inst := NewGetElementPtr(src, indices...)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
@@ -14,7 +14,9 @@ import (
 // integer comparison predicate and integer scalar or vector operands.
 func (block *Block) NewICmp(pred enum.IPred, x, y value.Value) *InstICmp {
 	inst := NewICmp(pred, x, y)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -25,7 +27,9 @@ func (block *Block) NewICmp(pred enum.IPred, x, y value.Value) *InstICmp {
 // operands.
 func (block *Block) NewFCmp(pred enum.FPred, x, y value.Value) *InstFCmp {
 	inst := NewFCmp(pred, x, y)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -35,7 +39,9 @@ func (block *Block) NewFCmp(pred enum.FPred, x, y value.Value) *InstFCmp {
 // incoming values.
 func (block *Block) NewPhi(incs ...*Incoming) *InstPhi {
 	inst := NewPhi(incs...)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -45,7 +51,9 @@ func (block *Block) NewPhi(incs ...*Incoming) *InstPhi {
 // given selection condition and operands.
 func (block *Block) NewSelect(cond, x, y value.Value) *InstSelect {
 	inst := NewSelect(cond, x, y)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -57,7 +65,9 @@ func (block *Block) NewSelect(cond, x, y value.Value) *InstSelect {
 // TODO: specify the set of underlying types of callee.
 func (block *Block) NewCall(callee value.Value, args ...value.Value) *InstCall {
 	inst := NewCall(callee, args...)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -67,7 +77,9 @@ func (block *Block) NewCall(callee value.Value, args ...value.Value) *InstCall {
 // given variable argument list and argument type.
 func (block *Block) NewVAArg(vaList value.Value, argType types.Type) *InstVAArg {
 	inst := NewVAArg(vaList, argType)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -77,7 +89,9 @@ func (block *Block) NewVAArg(vaList value.Value, argType types.Type) *InstVAArg
 // on the given result type and filter/catch clauses.
 func (block *Block) NewLandingPad(resultType types.Type, clauses ...*Clause) *InstLandingPad {
 	inst := NewLandingPad(resultType, clauses...)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -87,7 +101,9 @@ func (block *Block) NewLandingPad(resultType types.Type, clauses ...*Clause) *In
 // the given exception scope and exception arguments.
 func (block *Block) NewCatchPad(scope *TermCatchSwitch, args ...value.Value) *InstCatchPad {
 	inst := NewCatchPad(scope, args...)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -97,6 +113,8 @@ func (block *Block) NewCatchPad(scope *TermCatchSwitch, args ...value.Value) *In
 // on the given exception scope and exception arguments.
 func (block *Block) NewCleanupPad(scope ExceptionScope, args ...value.Value) *InstCleanupPad {
 	inst := NewCleanupPad(scope, args...)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
@@ -13,7 +13,9 @@ import (
 // on the given return value. A nil return value indicates a void return.
 func (block *Block) NewRet(x value.Value) *TermRet {
 	term := NewRet(x)
+	term.Parent = block
 	block.Term = term
+	block.markDirty()
 	return term
 }
 
@@ -23,7 +25,9 @@ func (block *Block) NewRet(x value.Value) *TermRet {
 // terminator based on the given target basic block.
 func (block *Block) NewBr(target *Block) *TermBr {
 	term := NewBr(target)
+	term.Parent = block
 	block.Term = term
+	block.markDirty()
 	return term
 }
 
@@ -34,7 +38,9 @@ func (block *Block) NewBr(target *Block) *TermBr {
 // basic blocks.
 func (block *Block) NewCondBr(cond value.Value, targetTrue, targetFalse *Block) *TermCondBr {
 	term := NewCondBr(cond, targetTrue, targetFalse)
+	term.Parent = block
 	block.Term = term
+	block.markDirty()
 	return term
 }
 
@@ -45,7 +51,9 @@ func (block *Block) NewCondBr(cond value.Value, targetTrue, targetFalse *Block)
 // cases.
 func (block *Block) NewSwitch(x value.Value, targetDefault *Block, cases ...*Case) *TermSwitch {
 	term := NewSwitch(x, targetDefault, cases...)
+	term.Parent = block
 	block.Term = term
+	block.markDirty()
 	return term
 }
 
@@ -56,7 +64,9 @@ func (block *Block) NewSwitch(x value.Value, targetDefault *Block, cases ...*Cas
 // constant) and set of valid target basic blocks.
 func (block *Block) NewIndirectBr(addr constant.Constant, validTargets ...*Block) *TermIndirectBr {
 	term := NewIndirectBr(addr, validTargets...)
+	term.Parent = block
 	block.Term = term
+	block.markDirty()
 	return term
 }
 
@@ -69,7 +79,9 @@ func (block *Block) NewIndirectBr(addr constant.Constant, validTargets ...*Block
 // TODO: specify the set of underlying types of invokee.
 func (block *Block) NewInvoke(invokee value.Value, args []value.Value, normal, exception *Block) *TermInvoke {
 	term := NewInvoke(invokee, args, normal, exception)
+	term.Parent = block
 	block.Term = term
+	block.markDirty()
 	return term
 }
 
@@ -79,7 +91,9 @@ func (block *Block) NewInvoke(invokee value.Value, args []value.Value, normal, e
 // based on the given exception argument to propagate.
 func (block *Block) NewResume(x value.Value) *TermResume {
 	term := NewResume(x)
+	term.Parent = block
 	block.Term = term
+	block.markDirty()
 	return term
 }
 
@@ -90,7 +104,9 @@ func (block *Block) NewResume(x value.Value) *TermResume {
 // target.
 func (block *Block) NewCatchSwitch(scope ExceptionScope, handlers []*Block, unwindTarget UnwindTarget) *TermCatchSwitch {
 	term := NewCatchSwitch(scope, handlers, unwindTarget)
+	term.Parent = block
 	block.Term = term
+	block.markDirty()
 	return term
 }
 
@@ -100,7 +116,9 @@ func (block *Block) NewCatchSwitch(scope ExceptionScope, handlers []*Block, unwi
 // terminator based on the given exit catchpad and target basic block.
 func (block *Block) NewCatchRet(from *InstCatchPad, to *Block) *TermCatchRet {
 	term := NewCatchRet(from, to)
+	term.Parent = block
 	block.Term = term
+	block.markDirty()
 	return term
 }
 
@@ -110,7 +128,9 @@ func (block *Block) NewCatchRet(from *InstCatchPad, to *Block) *TermCatchRet {
 // terminator based on the given exit cleanuppad and unwind target.
 func (block *Block) NewCleanupRet(from *InstCleanupPad, to UnwindTarget) *TermCleanupRet {
 	term := NewCleanupRet(from, to)
+	term.Parent = block
 	block.Term = term
+	block.markDirty()
 	return term
 }
 
@@ -120,6 +140,8 @@ func (block *Block) NewCleanupRet(from *InstCleanupPad, to UnwindTarget) *TermCl
 // terminator.
 func (block *Block) NewUnreachable() *TermUnreachable {
 	term := NewUnreachable()
+	term.Parent = block
 	block.Term = term
+	block.markDirty()
 	return term
 }
@@ -12,6 +12,8 @@ import (
 // operand.
 func (block *Block) NewFNeg(x value.Value) *InstFNeg {
 	inst := NewFNeg(x)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
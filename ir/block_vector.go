@@ -12,7 +12,9 @@ import (
 // based on the given vector and element index.
 func (block *Block) NewExtractElement(x, index value.Value) *InstExtractElement {
 	inst := NewExtractElement(x, index)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -22,7 +24,9 @@ func (block *Block) NewExtractElement(x, index value.Value) *InstExtractElement
 // based on the given vector, element and element index.
 func (block *Block) NewInsertElement(x, elem, index value.Value) *InstInsertElement {
 	inst := NewInsertElement(x, elem, index)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
 
@@ -30,8 +34,10 @@ func (block *Block) NewInsertElement(x, elem, index value.Value) *InstInsertElem
 
 // NewShuffleVector appends a new shufflevector instruction to the basic block
 // based on the given vectors and shuffle mask.
-func (block *Block) NewShuffleVector(x, y, mask value.Value) *InstShuffleVector {
+func (block *Block) NewShuffleVector(x, y value.Value, mask []int) *InstShuffleVector {
 	inst := NewShuffleVector(x, y, mask)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
+	block.markDirty()
 	return inst
 }
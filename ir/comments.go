@@ -0,0 +1,37 @@
+package ir
+
+import (
+	"fmt"
+	"io"
+)
+
+// --- [ Comments ] ------------------------------------------------------------
+
+// Comments is a side table of line comments associated with entities of an
+// LLVM IR module; as captured by asm.ParseStringComments and printed by
+// Printer.
+//
+// Comments may be associated with global variables (*Global), aliases
+// (*Alias), IFuncs (*IFunc), functions (*Func), basic blocks (*Block) and
+// instructions (Instruction and Terminator), and are printed on lines of their
+// own directly before the associated entity.
+//
+// Each comment is stored without the leading ';' and surrounding whitespace.
+type Comments map[interface{}][]string
+
+// Add appends the given comments to the comments associated with entity.
+func (c Comments) Add(entity interface{}, comments ...string) {
+	c[entity] = append(c[entity], comments...)
+}
+
+// writeComments writes the comments associated with entity to w, each on a line
+// of its own preceded by indent.
+func writeComments(w io.Writer, comments Comments, entity interface{}, indent string) {
+	for _, comment := range comments[entity] {
+		if len(comment) == 0 {
+			fmt.Fprintf(w, "%s;\n", indent)
+			continue
+		}
+		fmt.Fprintf(w, "%s; %s\n", indent, comment)
+	}
+}
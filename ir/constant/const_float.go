@@ -2,7 +2,6 @@ package constant
 
 import (
 	"fmt"
-	"log"
 	"math"
 	"math/big"
 	"strconv"
@@ -25,10 +24,29 @@ type Float struct {
 	X *big.Float
 	// NaN specifies whether the floating-point constant is Not-a-Number.
 	NaN bool
+	// Signaling specifies whether the Not-a-Number floating-point constant is a
+	// signaling NaN, rather than a quiet NaN; only valid if NaN is set.
+	Signaling bool
+	// (optional) Payload of the Not-a-Number floating-point constant; the bits
+	// of the significand below the quiet bit, in the binary format of the
+	// floating-point type. Only the high-order 64 bits of the significand of
+	// fp128 and ppc_fp128 constants are represented. A signaling NaN without
+	// payload has the bit below the quiet bit set, as used by LLVM; only valid
+	// if NaN is set.
+	Payload uint64
+
+	// extra.
+
+	// (optional) Original textual representation of the floating-point
+	// literal; or nil if not parsed from a string.
+	lit *floatLit
 }
 
 // NewFloat returns a new floating-point constant based on the given
 // floating-point type and double precision floating-point value.
+//
+// Programmatically constructed floating-point constants are printed in
+// canonical form.
 func NewFloat(typ *types.FloatType, x float64) *Float {
 	if math.IsNaN(x) {
 		f := &Float{Typ: typ, X: &big.Float{}, NaN: true}
@@ -41,6 +59,25 @@ func NewFloat(typ *types.FloatType, x float64) *Float {
 	return &Float{Typ: typ, X: big.NewFloat(x)}
 }
 
+// NaN returns a new quiet Not-a-Number floating-point constant of the given
+// floating-point type.
+func NaN(typ *types.FloatType) *Float {
+	return &Float{Typ: typ, X: &big.Float{}, NaN: true}
+}
+
+// SNaN returns a new signaling Not-a-Number floating-point constant of the
+// given floating-point type.
+func SNaN(typ *types.FloatType) *Float {
+	return &Float{Typ: typ, X: &big.Float{}, NaN: true, Signaling: true}
+}
+
+// Inf returns a new infinity floating-point constant of the given
+// floating-point type; positive infinity if sign >= 0, negative infinity if
+// sign < 0.
+func Inf(typ *types.FloatType, sign int) *Float {
+	return &Float{Typ: typ, X: new(big.Float).SetInf(sign < 0)}
+}
+
 // NewFloatFromString returns a new floating-point constant based on the given
 // floating-point type and floating-point string.
 //
@@ -56,7 +93,28 @@ func NewFloat(typ *types.FloatType, x float64) *Float {
 //         0xL[0-9A-Fa-f]{32} // HexFP128
 //         0xM[0-9A-Fa-f]{32} // HexPPC128
 //         0xH[0-9A-Fa-f]{4}  // HexHalf
+//
+// The original textual representation of the floating-point literal is
+// retained, and printed verbatim unless the value of the constant is changed.
 func NewFloatFromString(typ *types.FloatType, s string) (*Float, error) {
+	c, err := parseFloat(typ, s)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	c.lit = &floatLit{
+		text:      s,
+		typ:       c.Typ,
+		x:         new(big.Float).Copy(c.X),
+		nan:       c.NaN,
+		signaling: c.Signaling,
+		payload:   c.Payload,
+	}
+	return c, nil
+}
+
+// parseFloat returns a new floating-point constant based on the given
+// floating-point type and floating-point string.
+func parseFloat(typ *types.FloatType, s string) (*Float, error) {
 	// TODO: implement NewFloatFromString. return 0 for now.
 	if strings.HasPrefix(s, "0x") {
 		switch {
@@ -74,11 +132,23 @@ func NewFloatFromString(typ *types.FloatType, s string) (*Float, error) {
 			}
 			f := float80x86.NewFromBits(uint16(se), m)
 			x, nan := f.Big()
-			return &Float{Typ: typ, X: x, NaN: nan}, nil
+			return &Float{Typ: typ, X: x, NaN: nan, Signaling: nan && m&(1<<62) == 0, Payload: nanPayload(nan, m, 62)}, nil
 		case strings.HasPrefix(s, "0xL"):
-			//s = s[len("0xL"):]
+			// The low 64 bits precede the high 64 bits.
+			lo, hi, err := parseHexPair(s[len("0xL"):])
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			x, nan := fp128Big(hi, lo)
+			return &Float{Typ: typ, X: x, NaN: nan, Signaling: nan && hi&(1<<47) == 0, Payload: nanPayload(nan, hi, 47)}, nil
 		case strings.HasPrefix(s, "0xM"):
-			//s = s[len("0xM"):]
+			// The high-order double precedes the low-order double.
+			hi, lo, err := parseHexPair(s[len("0xM"):])
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			x, nan := ppcFP128Big(hi, lo)
+			return &Float{Typ: typ, X: x, NaN: nan, Signaling: nan && hi&(1<<51) == 0, Payload: nanPayload(nan, hi, 51)}, nil
 		case strings.HasPrefix(s, "0xH"):
 			hex := s[len("0xH"):]
 			bits, err := strconv.ParseUint(hex, 16, 16)
@@ -87,7 +157,7 @@ func NewFloatFromString(typ *types.FloatType, s string) (*Float, error) {
 			}
 			f := binary16.NewFromBits(uint16(bits))
 			x, nan := f.Big()
-			return &Float{Typ: typ, X: x, NaN: nan}, nil
+			return &Float{Typ: typ, X: x, NaN: nan, Signaling: nan && bits&(1<<9) == 0, Payload: nanPayload(nan, bits, 9)}, nil
 		default:
 			hex := s[len("0x"):]
 			bits, err := strconv.ParseUint(hex, 16, 64)
@@ -100,7 +170,9 @@ func NewFloatFromString(typ *types.FloatType, s string) (*Float, error) {
 				// probably be using binary16.NewFromBits.
 				f16 := math.Float64frombits(bits)
 				if math.IsNaN(f16) {
-					f := &Float{Typ: typ, X: &big.Float{}, NaN: true}
+					// The payload is truncated to the 10 bits of significand of
+					// half.
+					f := &Float{Typ: typ, X: &big.Float{}, NaN: true, Signaling: bits&(1<<51) == 0, Payload: nanPayload(true, bits>>42, 9)}
 					// Store sign of NaN.
 					if math.Signbit(f16) {
 						f.X.SetFloat64(-1)
@@ -126,7 +198,7 @@ func NewFloatFromString(typ *types.FloatType, s string) (*Float, error) {
 				// error-detection measure, the IR parser requires them to be zero.
 				f32 := math.Float64frombits(bits)
 				if math.IsNaN(f32) {
-					f := &Float{Typ: typ, X: &big.Float{}, NaN: true}
+					f := &Float{Typ: typ, X: &big.Float{}, NaN: true, Signaling: bits&(1<<51) == 0, Payload: nanPayload(true, bits>>29, 22)}
 					// Store sign of NaN.
 					if math.Signbit(f32) {
 						f.X.SetFloat64(-1)
@@ -140,7 +212,7 @@ func NewFloatFromString(typ *types.FloatType, s string) (*Float, error) {
 			case types.FloatKindDouble:
 				f32 := math.Float64frombits(bits)
 				if math.IsNaN(f32) {
-					f := &Float{Typ: typ, X: &big.Float{}, NaN: true}
+					f := &Float{Typ: typ, X: &big.Float{}, NaN: true, Signaling: bits&(1<<51) == 0, Payload: nanPayload(true, bits, 51)}
 					// Store sign of NaN.
 					if math.Signbit(f32) {
 						f.X.SetFloat64(-1)
@@ -153,9 +225,6 @@ func NewFloatFromString(typ *types.FloatType, s string) (*Float, error) {
 				panic(fmt.Errorf("support for hexadecimal floating-point literal %q of kind %v not yet implemented", s, typ.Kind))
 			}
 		}
-		log.Printf("constant.NewFloatFromString(%q): not yet implemented", s)
-		return NewFloat(typ, 0), nil
-		//panic(fmt.Errorf("support for hexadecimal floating-point constant %q not yet implemented", s))
 	}
 	switch typ.Kind {
 	case types.FloatKindHalf:
@@ -191,6 +260,39 @@ func NewFloatFromString(typ *types.FloatType, s string) (*Float, error) {
 			X:   x,
 		}
 		return c, nil
+	case types.FloatKindX86_FP80:
+		const precision = 64
+		x, _, err := big.ParseFloat(s, 10, precision, big.ToNearestEven)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		c := &Float{
+			Typ: typ,
+			X:   x,
+		}
+		return c, nil
+	case types.FloatKindFP128:
+		const precision = 113
+		x, _, err := big.ParseFloat(s, 10, precision, big.ToNearestEven)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		c := &Float{
+			Typ: typ,
+			X:   x,
+		}
+		return c, nil
+	case types.FloatKindPPC_FP128:
+		const precision = 106
+		x, _, err := big.ParseFloat(s, 10, precision, big.ToNearestEven)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		c := &Float{
+			Typ: typ,
+			X:   x,
+		}
+		return c, nil
 	default:
 		panic(fmt.Errorf("support for floating-point kind %v not yet implemented", typ.Kind))
 	}
@@ -211,17 +313,19 @@ func (c *Float) Type() types.Type {
 func (c *Float) Ident() string {
 	// FloatLit
 	// TODO: add support for hexadecimal format.
-	// TODO: add support for NaN, +-Inf.
 
+	// Original textual representation.
+	if c.lit != nil && c.lit.matches(c) {
+		return c.lit.text
+	}
 	switch c.Typ.Kind {
 	case types.FloatKindHalf:
 		if c.NaN || c.X.IsInf() || !float.IsExact16(c.X) {
 			var bits uint16
 			if c.NaN {
+				bits = uint16(c.NaNBits(uint64(binary16.NaN.Bits()), 9))
 				if c.X.Signbit() {
-					bits = binary16.NegNaN.Bits()
-				} else {
-					bits = binary16.NaN.Bits()
+					bits |= 0x8000
 				}
 			} else {
 				f, acc := binary16.NewFromBig(c.X)
@@ -251,8 +355,10 @@ func (c *Float) Ident() string {
 			//    bias: 127
 			var bits32 uint32
 			if c.NaN {
-				// TODO: handle sign bit.
-				bits32 = 0xFFFFFFFF
+				bits32 = uint32(c.NaNBits(0x7FC00000, 22))
+				if c.X.Signbit() {
+					bits32 |= 0x80000000
+				}
 			} else {
 				f, _ := c.X.Float32()
 				bits32 = math.Float32bits(f)
@@ -289,11 +395,10 @@ func (c *Float) Ident() string {
 		}
 	case types.FloatKindDouble:
 		if c.NaN {
-			f := math.NaN()
+			bits := c.NaNBits(0x7FF8000000000000, 51)
 			if c.X != nil && c.X.Signbit() {
-				f = math.Copysign(f, -1)
+				bits |= 1 << 63
 			}
-			bits := math.Float64bits(f)
 			return fmt.Sprintf("0x%X", bits)
 		}
 		if c.X.IsInf() || !float.IsExact64(c.X) {
@@ -305,14 +410,28 @@ func (c *Float) Ident() string {
 			//return fmt.Sprintf("0x%016X", bits)
 		}
 	case types.FloatKindX86_FP80:
-		// TODO: handle NaN.
-		f, acc := float80x86.NewFromBig(c.X)
-		// TODO: check acc.
-		_ = acc
-		se, m := f.Bits()
+		var se uint16
+		var m uint64
+		if c.NaN {
+			se, m = 0x7FFF, c.NaNBits(0xC000000000000000, 62)
+			if c.X.Signbit() {
+				se |= 0x8000
+			}
+		} else {
+			f, acc := float80x86.NewFromBig(c.X)
+			// TODO: check acc.
+			_ = acc
+			se, m = f.Bits()
+		}
 		return fmt.Sprintf("0xK%04X%016X", se, m)
-		//case types.FloatKindFP128:
-		//case types.FloatKindPPCFP128:
+	case types.FloatKindFP128:
+		// The low 64 bits precede the high 64 bits.
+		hi, lo := c.Bits128()
+		return fmt.Sprintf("0xL%016X%016X", lo, hi)
+	case types.FloatKindPPC_FP128:
+		// The high-order double precedes the low-order double.
+		hi, lo := c.Bits128()
+		return fmt.Sprintf("0xM%016X%016X", hi, lo)
 	}
 
 	// Insert decimal point if not present.
@@ -328,3 +447,262 @@ func (c *Float) Ident() string {
 	}
 	return s
 }
+
+// IsNaN reports whether the floating-point constant is Not-a-Number.
+func (c *Float) IsNaN() bool {
+	return c.NaN
+}
+
+// IsInf reports whether the floating-point constant is positive or negative
+// infinity.
+func (c *Float) IsInf() bool {
+	return !c.NaN && c.X != nil && c.X.IsInf()
+}
+
+// NaNBits returns the NaN bit pattern of the floating-point constant, based on
+// the given quiet NaN bit pattern with quiet bit q. The quiet bit is cleared if
+// the floating-point constant is a signaling NaN, and the bits below the quiet
+// bit are set to the payload of the constant; or to the next lower bit for
+// signaling NaNs without payload, as used by LLVM.
+func (c *Float) NaNBits(quiet uint64, q uint) uint64 {
+	mask := uint64(1)<<q - 1
+	bits := quiet&^(1<<q|mask) | c.Payload&mask
+	if !c.Signaling {
+		return bits | 1<<q
+	}
+	if c.Payload&mask == 0 {
+		bits |= 1 << (q - 1)
+	}
+	return bits
+}
+
+// Bits128 returns the bit representation of the fp128 or ppc_fp128
+// floating-point constant. For fp128 constants, hi and lo are the high-order and
+// low-order 64 bits; for ppc_fp128 constants, hi and lo are the bits of the
+// high-order and low-order double.
+func (c *Float) Bits128() (hi, lo uint64) {
+	switch c.Typ.Kind {
+	case types.FloatKindFP128:
+		hi, lo = fp128Bits(c.X, c.NaN)
+		if c.NaN {
+			hi = hi&^0xFFFFFFFFFFFF | c.NaNBits(1<<47, 47)
+		}
+	case types.FloatKindPPC_FP128:
+		hi, lo = ppcFP128Bits(c.X, c.NaN)
+		if c.NaN {
+			hi = hi&^0xFFFFFFFFFFFFF | c.NaNBits(1<<51, 51)
+		}
+	default:
+		panic(fmt.Errorf("invalid type of 128-bit floating-point constant; expected fp128 or ppc_fp128, got %v", c.Typ))
+	}
+	return hi, lo
+}
+
+// floatLit is the original textual representation of a floating-point literal,
+// and the value it was parsed into.
+type floatLit struct {
+	// Floating-point literal.
+	text string
+	// Floating-point type at time of parsing.
+	typ *types.FloatType
+	// Copy of floating-point constant at time of parsing.
+	x *big.Float
+	// NaN at time of parsing.
+	nan bool
+	// Signaling NaN at time of parsing.
+	signaling bool
+	// NaN payload at time of parsing.
+	payload uint64
+}
+
+// matches reports whether the value of the given floating-point constant is
+// unchanged since parsing the floating-point literal.
+func (lit *floatLit) matches(c *Float) bool {
+	if c.Typ != lit.typ || c.NaN != lit.nan || c.X == nil {
+		return false
+	}
+	if c.NaN && (c.Signaling != lit.signaling || c.Payload != lit.payload) {
+		return false
+	}
+	if c.X.Signbit() != lit.x.Signbit() {
+		return false
+	}
+	return c.NaN || c.X.Cmp(lit.x) == 0
+}
+
+// ### [ Helper functions ] ####################################################
+
+// nanPayload returns the payload of the given NaN bit pattern with quiet bit q;
+// the bits below the quiet bit. The payload is zero if nan is not set.
+func nanPayload(nan bool, bits uint64, q uint) uint64 {
+	if !nan {
+		return 0
+	}
+	return bits & (1<<q - 1)
+}
+
+// parseHexPair parses the given string of 32 hexadecimal digits into a pair of
+// 64-bit integers, the first of which is represented by the first 16 digits.
+func parseHexPair(s string) (a, b uint64, err error) {
+	if len(s) != 32 {
+		return 0, 0, errors.Errorf("invalid length of hexadecimal floating-point literal %q; expected 32 hexadecimal digits, got %d", s, len(s))
+	}
+	a, err = strconv.ParseUint(s[:16], 16, 64)
+	if err != nil {
+		return 0, 0, errors.WithStack(err)
+	}
+	b, err = strconv.ParseUint(s[16:], 16, 64)
+	if err != nil {
+		return 0, 0, errors.WithStack(err)
+	}
+	return a, b, nil
+}
+
+// Quadruple precision.
+//
+//      1 bit:  sign
+//     15 bits: exponent
+//    112 bits: mantissa
+//
+//    bias: 16383
+const (
+	fp128Precision = 113
+	fp128Bias      = 16383
+)
+
+// fp128Big returns the multi-precision floating-point number representation of
+// the given quadruple precision bit pattern (split into the high and low 64
+// bits), and a boolean indicating whether it is Not-a-Number.
+func fp128Big(hi, lo uint64) (x *big.Float, nan bool) {
+	signbit := hi>>63 == 1
+	exp := int(hi >> 48 & 0x7FFF)
+	mant := new(big.Int).SetUint64(hi & 0xFFFFFFFFFFFF)
+	mant.Lsh(mant, 64)
+	mant.Or(mant, new(big.Int).SetUint64(lo))
+	x = new(big.Float).SetPrec(fp128Precision)
+	switch exp {
+	case 0x7FFF:
+		if mant.Sign() == 0 {
+			// +-Inf
+			x.SetInf(signbit)
+			return x, false
+		}
+		// +-NaN
+		if signbit {
+			x.Neg(x)
+		}
+		return x, true
+	case 0:
+		// Zero or denormalized number.
+		//
+		//    (-1)^signbit * 2^(-16382) * 0.mant_2
+		exp = 1
+	default:
+		// Normalized number.
+		//
+		//    (-1)^signbit * 2^(exp-16383) * 1.mant_2
+		mant.SetBit(mant, 112, 1)
+	}
+	x.SetInt(mant)
+	x.SetMantExp(x, exp-fp128Bias-112)
+	if signbit {
+		x.Neg(x)
+	}
+	return x, false
+}
+
+// fp128Bits returns the quadruple precision bit pattern (split into the high
+// and low 64 bits) of the given multi-precision floating-point number, or of
+// NaN if nan is set.
+func fp128Bits(x *big.Float, nan bool) (hi, lo uint64) {
+	if x.Signbit() {
+		hi |= 1 << 63
+	}
+	switch {
+	case nan:
+		// Quiet NaN.
+		hi |= 0x7FFF800000000000
+		return hi, 0
+	case x.IsInf():
+		hi |= 0x7FFF000000000000
+		return hi, 0
+	case x.Sign() == 0:
+		return hi, 0
+	}
+	// Round to quadruple precision.
+	y := new(big.Float).SetPrec(fp128Precision).SetMode(big.ToNearestEven)
+	y.Abs(x)
+	// y = mant * 2^exp; 0.5 <= mant < 1
+	exp := y.MantExp(nil) - 1 + fp128Bias
+	if exp >= 0x7FFF {
+		// Overflow to +-Inf.
+		hi |= 0x7FFF000000000000
+		return hi, 0
+	}
+	if exp <= 0 {
+		// Denormalized number.
+		y.SetMantExp(y, fp128Bias-1+112)
+		exp = 0
+	} else {
+		y.SetMantExp(y, -(exp - fp128Bias - 112))
+	}
+	mant, _ := y.Int(nil)
+	mant.SetBit(mant, 112, 0)
+	hi |= uint64(exp) << 48
+	hi |= new(big.Int).Rsh(mant, 64).Uint64()
+	lo = new(big.Int).And(mant, new(big.Int).SetUint64(math.MaxUint64)).Uint64()
+	return hi, lo
+}
+
+// ppcFP128Big returns the multi-precision floating-point number representation
+// of the given double-double bit pattern (split into the high-order and
+// low-order doubles), and a boolean indicating whether it is Not-a-Number.
+func ppcFP128Big(hi, lo uint64) (x *big.Float, nan bool) {
+	h := math.Float64frombits(hi)
+	l := math.Float64frombits(lo)
+	if math.IsNaN(h) {
+		x = &big.Float{}
+		// Store sign of NaN.
+		if math.Signbit(h) {
+			x.SetFloat64(-1)
+		}
+		return x, true
+	}
+	x = big.NewFloat(h)
+	if x.IsInf() || math.IsNaN(l) || math.IsInf(l, 0) {
+		return x, false
+	}
+	// The sum of the high-order and low-order doubles is exact given enough
+	// precision.
+	const maxPrecision = 2 * 1100
+	x.SetPrec(maxPrecision)
+	x.Add(x, big.NewFloat(l))
+	prec := x.MinPrec()
+	if prec < 106 {
+		prec = 106
+	}
+	x.SetPrec(prec)
+	return x, false
+}
+
+// ppcFP128Bits returns the double-double bit pattern (split into the high-order
+// and low-order doubles) of the given multi-precision floating-point number, or
+// of NaN if nan is set.
+func ppcFP128Bits(x *big.Float, nan bool) (hi, lo uint64) {
+	if nan {
+		// Quiet NaN.
+		hi = 0x7FF8000000000000
+		if x.Signbit() {
+			hi |= 1 << 63
+		}
+		return hi, 0
+	}
+	h, _ := x.Float64()
+	if math.IsInf(h, 0) {
+		return math.Float64bits(h), 0
+	}
+	rem := new(big.Float).SetPrec(x.Prec() + 53)
+	rem.Sub(x, big.NewFloat(h))
+	l, _ := rem.Float64()
+	return math.Float64bits(h), math.Float64bits(l)
+}
@@ -0,0 +1,165 @@
+package constant
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestNewFloatFromStringHex(t *testing.T) {
+	golden := []struct {
+		typ *types.FloatType
+		s   string
+	}{
+		// half
+		{typ: types.Half, s: "0xH3C01"},
+		{typ: types.Half, s: "0xH7C00"},
+		{typ: types.Half, s: "0xH7E00"},
+		{typ: types.Half, s: "0xH7D00"},
+		{typ: types.Half, s: "0xH7C01"},
+		{typ: types.Half, s: "0xHFE05"},
+		// double
+		{typ: types.Double, s: "0x7FF8000000000000"},
+		{typ: types.Double, s: "0x7FF4000000000000"},
+		{typ: types.Double, s: "0xFFF8000000000000"},
+		{typ: types.Double, s: "0x7FF0000000000000"},
+		{typ: types.Double, s: "0x7FF0000000000001"},
+		{typ: types.Double, s: "0x7FF8000000000005"},
+		// float
+		{typ: types.Float, s: "0x7FF8000000000000"},
+		{typ: types.Float, s: "0x7FF4000000000000"},
+		{typ: types.Float, s: "0xFFF0000000000000"},
+		{typ: types.Float, s: "0x7FF0000020000000"},
+		{typ: types.Float, s: "0xFFF80000A0000000"},
+		// x86_fp80
+		{typ: types.X86_FP80, s: "0xK3FFF8000000000000000"},
+		{typ: types.X86_FP80, s: "0xK4000C90FDAA22168C235"},
+		{typ: types.X86_FP80, s: "0xKBFFE8000000000000001"},
+		{typ: types.X86_FP80, s: "0xK00000000000000000001"},
+		{typ: types.X86_FP80, s: "0xK7FFF8000000000000000"},
+		{typ: types.X86_FP80, s: "0xK7FFFC000000000000000"},
+		{typ: types.X86_FP80, s: "0xK7FFFA000000000000000"},
+		{typ: types.X86_FP80, s: "0xK7FFF8000000000000001"},
+		{typ: types.X86_FP80, s: "0xKFFFFC000000000000005"},
+		// fp128
+		{typ: types.FP128, s: "0xL00000000000000003FFF000000000000"},
+		{typ: types.FP128, s: "0xL0000000000000001C000921FB54442D1"},
+		{typ: types.FP128, s: "0xL00000000000000018000000000000000"},
+		{typ: types.FP128, s: "0xL00000000000000007FFF000000000000"},
+		{typ: types.FP128, s: "0xL00000000000000007FFF800000000000"},
+		{typ: types.FP128, s: "0xL00000000000000007FFF400000000000"},
+		{typ: types.FP128, s: "0xL00000000000000008000000000000000"},
+		// ppc_fp128
+		{typ: types.PPC_FP128, s: "0xM3FF00000000000000000000000000000"},
+		{typ: types.PPC_FP128, s: "0xM400921FB54442D183CA1A62633145C07"},
+		{typ: types.PPC_FP128, s: "0xMBFF0000000000000BC90000000000000"},
+		{typ: types.PPC_FP128, s: "0xM7FF00000000000000000000000000000"},
+		{typ: types.PPC_FP128, s: "0xM7FF80000000000000000000000000000"},
+		{typ: types.PPC_FP128, s: "0xM7FF40000000000000000000000000000"},
+	}
+	for _, g := range golden {
+		c, err := NewFloatFromString(g.typ, g.s)
+		if err != nil {
+			t.Errorf("unable to parse %q; %v", g.s, err)
+			continue
+		}
+		// Print the parsed value in canonical form, rather than the original
+		// textual representation of the floating-point literal.
+		canon := &Float{Typ: c.Typ, X: c.X, NaN: c.NaN, Signaling: c.Signaling, Payload: c.Payload}
+		if got := canon.Ident(); g.s != got {
+			t.Errorf("%v floating-point literal mismatch; expected %q, got %q", g.typ, g.s, got)
+		}
+	}
+}
+
+func TestFloatLit(t *testing.T) {
+	c, err := NewFloatFromString(types.Double, "1.000000e+00")
+	if err != nil {
+		t.Fatalf("unable to parse floating-point literal; %v", err)
+	}
+	if want, got := "1.000000e+00", c.Ident(); want != got {
+		t.Errorf("floating-point literal mismatch; expected %q, got %q", want, got)
+	}
+	// Print canonical form after change of value.
+	c.X.SetFloat64(2)
+	if want, got := "2.0", c.Ident(); want != got {
+		t.Errorf("floating-point literal mismatch; expected %q, got %q", want, got)
+	}
+	// Print canonical form of programmatically constructed floating-point
+	// constants.
+	if want, got := "1.0", NewFloat(types.Double, 1).Ident(); want != got {
+		t.Errorf("floating-point literal mismatch; expected %q, got %q", want, got)
+	}
+}
+
+func TestFloatSpecial(t *testing.T) {
+	golden := []struct {
+		c     *Float
+		want  string
+		nan   bool
+		isInf bool
+	}{
+		// half
+		{c: NaN(types.Half), want: "0xH7E00", nan: true},
+		{c: SNaN(types.Half), want: "0xH7D00", nan: true},
+		{c: Inf(types.Half, 1), want: "0xH7C00", isInf: true},
+		{c: Inf(types.Half, -1), want: "0xHFC00", isInf: true},
+		// float
+		{c: NaN(types.Float), want: "0x7FF8000000000000", nan: true},
+		{c: SNaN(types.Float), want: "0x7FF4000000000000", nan: true},
+		{c: Inf(types.Float, 1), want: "0x7FF0000000000000", isInf: true},
+		{c: Inf(types.Float, -1), want: "0xFFF0000000000000", isInf: true},
+		// double
+		{c: NaN(types.Double), want: "0x7FF8000000000000", nan: true},
+		{c: SNaN(types.Double), want: "0x7FF4000000000000", nan: true},
+		{c: Inf(types.Double, 1), want: "0x7FF0000000000000", isInf: true},
+		{c: Inf(types.Double, -1), want: "0xFFF0000000000000", isInf: true},
+		// x86_fp80
+		{c: NaN(types.X86_FP80), want: "0xK7FFFC000000000000000", nan: true},
+		{c: SNaN(types.X86_FP80), want: "0xK7FFFA000000000000000", nan: true},
+		{c: Inf(types.X86_FP80, 1), want: "0xK7FFF8000000000000000", isInf: true},
+		{c: Inf(types.X86_FP80, -1), want: "0xKFFFF8000000000000000", isInf: true},
+		// fp128
+		{c: NaN(types.FP128), want: "0xL00000000000000007FFF800000000000", nan: true},
+		{c: SNaN(types.FP128), want: "0xL00000000000000007FFF400000000000", nan: true},
+		{c: Inf(types.FP128, 1), want: "0xL00000000000000007FFF000000000000", isInf: true},
+		{c: Inf(types.FP128, -1), want: "0xL0000000000000000FFFF000000000000", isInf: true},
+		// ppc_fp128
+		{c: NaN(types.PPC_FP128), want: "0xM7FF80000000000000000000000000000", nan: true},
+		{c: SNaN(types.PPC_FP128), want: "0xM7FF40000000000000000000000000000", nan: true},
+		{c: Inf(types.PPC_FP128, 1), want: "0xM7FF00000000000000000000000000000", isInf: true},
+		{c: Inf(types.PPC_FP128, -1), want: "0xMFFF00000000000000000000000000000", isInf: true},
+		// finite
+		{c: NewFloat(types.Double, 1), want: "1.0"},
+	}
+	for _, g := range golden {
+		if got := g.c.Ident(); g.want != got {
+			t.Errorf("%v floating-point literal mismatch; expected %q, got %q", g.c.Typ, g.want, got)
+		}
+		if got := g.c.IsNaN(); g.nan != got {
+			t.Errorf("%v NaN mismatch of %q; expected %v, got %v", g.c.Typ, g.want, g.nan, got)
+		}
+		if got := g.c.IsInf(); g.isInf != got {
+			t.Errorf("%v Inf mismatch of %q; expected %v, got %v", g.c.Typ, g.want, g.isInf, got)
+		}
+		// Round-trip.
+		c, err := NewFloatFromString(g.c.Typ, g.want)
+		if err != nil {
+			t.Errorf("unable to parse %q; %v", g.want, err)
+			continue
+		}
+		if c.IsNaN() != g.nan || c.IsInf() != g.isInf || c.Signaling != g.c.Signaling {
+			t.Errorf("%v round-trip mismatch of %q; expected NaN=%v, Inf=%v, signaling=%v, got NaN=%v, Inf=%v, signaling=%v", g.c.Typ, g.want, g.nan, g.isInf, g.c.Signaling, c.IsNaN(), c.IsInf(), c.Signaling)
+		}
+	}
+	// The original textual representation is not retained after changing the
+	// kind of NaN.
+	c, err := NewFloatFromString(types.Double, "0x7FF8000000000000")
+	if err != nil {
+		t.Fatalf("unable to parse floating-point literal; %v", err)
+	}
+	c.Signaling = true
+	if want, got := "0x7FF4000000000000", c.Ident(); want != got {
+		t.Errorf("floating-point literal mismatch; expected %q, got %q", want, got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/llir/llvm/ir/types"
+	"github.com/pkg/errors"
 )
 
 // --- [ Struct constants ] ----------------------------------------------------
@@ -80,3 +81,32 @@ func (c *Struct) Ident() string {
 	}
 	return buf.String()
 }
+
+// NumFields returns the number of fields of the struct constant.
+func (c *Struct) NumFields() int {
+	return len(c.Fields)
+}
+
+// Field returns the field at index i of the struct constant. An error is
+// reported if the index is out of bounds.
+func (c *Struct) Field(i int) (Constant, error) {
+	if i < 0 || i >= len(c.Fields) {
+		return nil, errors.Errorf("field index %d out of bounds [0, %d) of struct constant %s", i, len(c.Fields), c.Type())
+	}
+	return c.Fields[i], nil
+}
+
+// SetField sets the field at index i of the struct constant to v. An error is
+// reported if the index is out of bounds, or if the type of v does not match
+// the field type of the struct type.
+func (c *Struct) SetField(i int, v Constant) error {
+	if i < 0 || i >= len(c.Fields) {
+		return errors.Errorf("field index %d out of bounds [0, %d) of struct constant %s", i, len(c.Fields), c.Type())
+	}
+	t := c.Type().(*types.StructType)
+	if i < len(t.Fields) && !v.Type().Equal(t.Fields[i]) {
+		return errors.Errorf("field %d type mismatch of struct constant %s; expected %s, got %s", i, t, t.Fields[i], v.Type())
+	}
+	c.Fields[i] = v
+	return nil
+}
@@ -0,0 +1,44 @@
+package constant
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestStructField(t *testing.T) {
+	c := NewStruct(nil, NewInt(types.I32, 1), NewNull(types.I8Ptr))
+	if got := c.NumFields(); got != 2 {
+		t.Errorf("number of fields mismatch; expected 2, got %d", got)
+	}
+	field, err := c.Field(1)
+	if err != nil {
+		t.Fatalf("unexpected error; %v", err)
+	}
+	if want, got := "i8* null", field.String(); want != got {
+		t.Errorf("field mismatch; expected %q, got %q", want, got)
+	}
+	for _, i := range []int{-1, 2} {
+		if _, err := c.Field(i); err == nil {
+			t.Errorf("expected error for field index %d", i)
+		}
+	}
+	if err := c.SetField(0, NewInt(types.I32, 2)); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	if want, got := "{ i32, i8* } { i32 2, i8* null }", c.String(); want != got {
+		t.Errorf("struct constant mismatch; expected %q, got %q", want, got)
+	}
+	// Field type mismatch.
+	want := "field 0 type mismatch of struct constant { i32, i8* }; expected i32, got i64"
+	if err := c.SetField(0, NewInt(types.I64, 3)); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("error mismatch; expected %q, got %v", want, err)
+	}
+	if err := c.SetField(2, NewInt(types.I32, 3)); err == nil {
+		t.Errorf("expected error for field index 2")
+	}
+	if want, got := "{ i32, i8* } { i32 2, i8* null }", c.String(); want != got {
+		t.Errorf("struct constant mismatch; expected %q, got %q", want, got)
+	}
+}
@@ -30,6 +30,44 @@ func NewVector(t *types.VectorType, elems ...Constant) *Vector {
 	return c
 }
 
+// NewSplat returns a new vector constant of the given vector type, with every
+// element set to elem.
+//
+// Example:
+//
+//    constant.NewSplat(types.NewVector(4, types.I32), constant.NewInt(types.I32, 7))
+//
+// is emitted as
+//
+//    <4 x i32> <i32 7, i32 7, i32 7, i32 7>
+func NewSplat(t *types.VectorType, elem Constant) *Vector {
+	if !elem.Type().Equal(t.ElemType) {
+		panic(fmt.Errorf("splat element type mismatch; expected %q, got %q", t.ElemType, elem.Type()))
+	}
+	elems := make([]Constant, t.Len)
+	for i := range elems {
+		elems[i] = elem
+	}
+	return NewVector(t, elems...)
+}
+
+// SplatValue returns the element of the vector constant if all elements are
+// identical (i.e. have the same LLVM syntax representation). The boolean return
+// value indicates success.
+func (c *Vector) SplatValue() (Constant, bool) {
+	if len(c.Elems) == 0 {
+		return nil, false
+	}
+	first := c.Elems[0]
+	key := first.String()
+	for _, elem := range c.Elems[1:] {
+		if elem != first && elem.String() != key {
+			return nil, false
+		}
+	}
+	return first, true
+}
+
 // String returns the LLVM syntax representation of the constant as a type-value
 // pair.
 func (c *Vector) String() string {
@@ -0,0 +1,44 @@
+package constant
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestVectorSplat(t *testing.T) {
+	vecType := types.NewVector(4, types.I32)
+	seven := NewInt(types.I32, 7)
+	splat := NewSplat(vecType, seven)
+	if want, got := "<4 x i32> <i32 7, i32 7, i32 7, i32 7>", splat.String(); want != got {
+		t.Errorf("splat mismatch; expected %q, got %q", want, got)
+	}
+	golden := []struct {
+		in *Vector
+		// Expected splat value; or nil if not a splat.
+		want Constant
+	}{
+		{in: splat, want: seven},
+		// Identical elements need not share the same in-memory representation.
+		{in: NewVector(vecType, NewInt(types.I32, 7), NewInt(types.I32, 7), NewInt(types.I32, 7), NewInt(types.I32, 7)), want: seven},
+		{in: NewVector(vecType, seven, seven, seven, NewInt(types.I32, 8))},
+		{in: NewSplat(types.NewVector(2, types.Float), NewFloat(types.Float, 1)), want: NewFloat(types.Float, 1)},
+		{in: NewVector(types.NewVector(2, types.I8Ptr), NewNull(types.I8Ptr), NewUndef(types.I8Ptr))},
+	}
+	for _, g := range golden {
+		got, ok := g.in.SplatValue()
+		if g.want == nil {
+			if ok {
+				t.Errorf("expected %q to not be a splat, got splat value %q", g.in, got)
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("expected %q to be a splat", g.in)
+			continue
+		}
+		if g.want.String() != got.String() {
+			t.Errorf("splat value mismatch of %q; expected %q, got %q", g.in, g.want, got)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package constant
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// --- [ Nesting depth ] -------------------------------------------------------
+
+// MaxDepth is the maximum nesting depth of constant expressions and aggregate
+// constants, as checked by CheckDepth and enforced by the asm package when
+// translating constants of LLVM IR assembly.
+//
+// Printing, folding and walking of constants recurse on operands, and may thus
+// overflow the stack for pathologically nested constants. The limit is also
+// enforced when printing modules (ir.Module.WriteTo), when walking constants
+// of modules (e.g. ir.Module.Verify) and when folding constants in the irutil
+// package. The String methods of individual constants do not check the limit.
+var MaxDepth = 10000
+
+// CheckDepth reports an error if the nesting depth of the given constant
+// exceeds MaxDepth. A constant without operands has nesting depth 1.
+//
+// The nesting depth is computed without recursion, and may thus be used on
+// constants of any depth.
+func CheckDepth(c Constant) error {
+	// Constants reachable by a path of the current length from c.
+	level := []Constant{c}
+	for depth := 1; len(level) > 0; depth++ {
+		if depth > MaxDepth {
+			return errors.Errorf("nesting depth of constant exceeds limit %d", MaxDepth)
+		}
+		var next []Constant
+		seen := make(map[Constant]bool)
+		for _, c := range level {
+			for _, operand := range operands(c) {
+				if !seen[operand] {
+					seen[operand] = true
+					next = append(next, operand)
+				}
+			}
+		}
+		level = next
+	}
+	return nil
+}
+
+// pkgPath is the import path of the constant package.
+var pkgPath = reflect.TypeOf(Int{}).PkgPath()
+
+// operands returns the constant operands of the given constant expression or
+// aggregate constant. Global values are treated as constants without operands.
+func operands(c Constant) []Constant {
+	v := reflect.ValueOf(c)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct || v.Elem().Type().PkgPath() != pkgPath {
+		return nil
+	}
+	var ops []Constant
+	add := func(field reflect.Value) {
+		if field.Kind() != reflect.Interface || field.IsNil() {
+			return
+		}
+		if op, ok := field.Interface().(Constant); ok {
+			ops = append(ops, op)
+		}
+	}
+	s := v.Elem()
+	for i := 0; i < s.NumField(); i++ {
+		switch field := s.Field(i); field.Kind() {
+		case reflect.Interface:
+			add(field)
+		case reflect.Slice:
+			for j := 0; j < field.Len(); j++ {
+				add(field.Index(j))
+			}
+		}
+	}
+	return ops
+}
@@ -0,0 +1,36 @@
+package constant
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestCheckDepth(t *testing.T) {
+	// Nested add expressions sharing operands; the nesting depth of add n is
+	// n+1.
+	nested := func(n int) Constant {
+		var c Constant = NewInt(types.I32, 1)
+		for i := 0; i < n; i++ {
+			c = NewAdd(c, c)
+		}
+		return c
+	}
+	golden := []struct {
+		in   Constant
+		want bool
+	}{
+		{in: NewInt(types.I32, 1), want: true},
+		{in: nested(99), want: true},
+		{in: nested(100), want: false},
+		{in: NewArray(types.NewArray(1, types.I32), nested(99)), want: false},
+		{in: NewGetElementPtr(NewNull(types.NewPointer(types.I32)), &Index{Constant: nested(98)}), want: false},
+	}
+	defer func(maxDepth int) { MaxDepth = maxDepth }(MaxDepth)
+	MaxDepth = 100
+	for _, g := range golden {
+		if got := CheckDepth(g.in) == nil; got != g.want {
+			t.Errorf("nesting depth validity mismatch; expected %v, got %v", g.want, got)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package constant
 
 import (
 	"fmt"
+	"math/big"
 	"strings"
 
 	"github.com/llir/llvm/ir/enum"
@@ -62,8 +63,15 @@ func (e *ExprAdd) Ident() string {
 
 // Simplify returns an equivalent (and potentially simplified) constant to the
 // constant expression.
+//
+// The add expression is evaluated if both operands are integer constants or
+// vectors thereof, and the result is not poison.
 func (e *ExprAdd) Simplify() Constant {
-	panic("not yet implemented")
+	return foldIntBinary(e, e.X, e.Y, true, func(x, y *Int) (*big.Int, bool) {
+		s := new(big.Int).Add(signedValue(x), signedValue(y))
+		u := new(big.Int).Add(unsignedValue(x), unsignedValue(y))
+		return s, !overflows(x.Typ, e.OverflowFlags, s, u)
+	})
 }
 
 // ~~~ [ fadd ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
@@ -166,8 +174,15 @@ func (e *ExprSub) Ident() string {
 
 // Simplify returns an equivalent (and potentially simplified) constant to the
 // constant expression.
+//
+// The sub expression is evaluated if both operands are integer constants or
+// vectors thereof, and the result is not poison.
 func (e *ExprSub) Simplify() Constant {
-	panic("not yet implemented")
+	return foldIntBinary(e, e.X, e.Y, true, func(x, y *Int) (*big.Int, bool) {
+		s := new(big.Int).Sub(signedValue(x), signedValue(y))
+		u := new(big.Int).Sub(unsignedValue(x), unsignedValue(y))
+		return s, !overflows(x.Typ, e.OverflowFlags, s, u)
+	})
 }
 
 // ~~~ [ fsub ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
@@ -270,8 +285,15 @@ func (e *ExprMul) Ident() string {
 
 // Simplify returns an equivalent (and potentially simplified) constant to the
 // constant expression.
+//
+// The mul expression is evaluated if both operands are integer constants or
+// vectors thereof, and the result is not poison.
 func (e *ExprMul) Simplify() Constant {
-	panic("not yet implemented")
+	return foldIntBinary(e, e.X, e.Y, false, func(x, y *Int) (*big.Int, bool) {
+		s := new(big.Int).Mul(signedValue(x), signedValue(y))
+		u := new(big.Int).Mul(unsignedValue(x), unsignedValue(y))
+		return s, !overflows(x.Typ, e.OverflowFlags, s, u)
+	})
 }
 
 // ~~~ [ fmul ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
@@ -374,8 +396,18 @@ func (e *ExprUDiv) Ident() string {
 
 // Simplify returns an equivalent (and potentially simplified) constant to the
 // constant expression.
+//
+// The udiv expression is evaluated if both operands are integer constants or
+// vectors thereof, the divisor is non-zero, and the result is not poison.
 func (e *ExprUDiv) Simplify() Constant {
-	panic("not yet implemented")
+	return foldIntBinary(e, e.X, e.Y, false, func(x, y *Int) (*big.Int, bool) {
+		a, b := unsignedValue(x), unsignedValue(y)
+		if b.Sign() == 0 {
+			return nil, false
+		}
+		q, r := new(big.Int).QuoRem(a, b, new(big.Int))
+		return q, !e.Exact || r.Sign() == 0
+	})
 }
 
 // ~~~ [ sdiv ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
@@ -430,8 +462,19 @@ func (e *ExprSDiv) Ident() string {
 
 // Simplify returns an equivalent (and potentially simplified) constant to the
 // constant expression.
+//
+// The sdiv expression is evaluated if both operands are integer constants or
+// vectors thereof, the divisor is non-zero, the division does not overflow,
+// and the result is not poison.
 func (e *ExprSDiv) Simplify() Constant {
-	panic("not yet implemented")
+	return foldIntBinary(e, e.X, e.Y, false, func(x, y *Int) (*big.Int, bool) {
+		a, b := signedValue(x), signedValue(y)
+		if b.Sign() == 0 || signedOverflow(x.Typ, a, b) {
+			return nil, false
+		}
+		q, r := new(big.Int).QuoRem(a, b, new(big.Int))
+		return q, !e.Exact || r.Sign() == 0
+	})
 }
 
 // ~~~ [ fdiv ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
@@ -526,8 +569,17 @@ func (e *ExprURem) Ident() string {
 
 // Simplify returns an equivalent (and potentially simplified) constant to the
 // constant expression.
+//
+// The urem expression is evaluated if both operands are integer constants or
+// vectors thereof, and the divisor is non-zero.
 func (e *ExprURem) Simplify() Constant {
-	panic("not yet implemented")
+	return foldIntBinary(e, e.X, e.Y, false, func(x, y *Int) (*big.Int, bool) {
+		a, b := unsignedValue(x), unsignedValue(y)
+		if b.Sign() == 0 {
+			return nil, false
+		}
+		return new(big.Int).Rem(a, b), true
+	})
 }
 
 // ~~~ [ srem ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
@@ -574,8 +626,19 @@ func (e *ExprSRem) Ident() string {
 
 // Simplify returns an equivalent (and potentially simplified) constant to the
 // constant expression.
+//
+// The srem expression is evaluated if both operands are integer constants or
+// vectors thereof, the divisor is non-zero, and the division does not
+// overflow.
 func (e *ExprSRem) Simplify() Constant {
-	panic("not yet implemented")
+	return foldIntBinary(e, e.X, e.Y, false, func(x, y *Int) (*big.Int, bool) {
+		a, b := signedValue(x), signedValue(y)
+		if b.Sign() == 0 || signedOverflow(x.Typ, a, b) {
+			return nil, false
+		}
+		// The sign of the remainder matches the sign of the dividend.
+		return new(big.Int).Rem(a, b), true
+	})
 }
 
 // ~~~ [ frem ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
@@ -625,3 +688,105 @@ func (e *ExprFRem) Ident() string {
 func (e *ExprFRem) Simplify() Constant {
 	panic("not yet implemented")
 }
+
+// ### [ Helper functions ] ####################################################
+
+// foldIntBinary returns the constant produced by evaluating the given integer
+// binary operation on the operands x and y of the constant expression e, which
+// are integer constants or vectors thereof; or e if the operation could not be
+// evaluated. The operation returns the result before truncation to the bit size
+// of the integer type, and reports whether the result is defined (i.e. not
+// poison, and not undefined behaviour).
+//
+// Lanes of vector operands with a poison operand element fold to poison. Lanes
+// with an undef operand element fold to undef if undef is true (i.e. if any
+// result of the operation may be produced by some value of the undef operand),
+// and otherwise prevent the evaluation of the operation.
+func foldIntBinary(e Constant, x, y Constant, undef bool, op func(x, y *Int) (*big.Int, bool)) Constant {
+	fold := func(x, y Constant) (Constant, bool) {
+		a, ok := x.(*Int)
+		if !ok {
+			return nil, false
+		}
+		b, ok := y.(*Int)
+		if !ok {
+			return nil, false
+		}
+		z, ok := op(a, b)
+		if !ok {
+			return nil, false
+		}
+		return newInt(a.Typ, z), true
+	}
+	if c, ok := fold(x, y); ok {
+		return c
+	}
+	if !undef && (hasUndefElem(x) || hasUndefElem(y)) {
+		return e
+	}
+	if c, ok := foldVector(e.Type(), x, y, fold); ok {
+		return c
+	}
+	return e
+}
+
+// hasUndefElem reports whether the given constant is a vector constant with an
+// undef element.
+func hasUndefElem(c Constant) bool {
+	if v, ok := c.(*Vector); ok {
+		for _, elem := range v.Elems {
+			if _, ok := elem.(*Undef); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// overflows reports whether the signed result s or the unsigned result u of an
+// integer operation, before truncation to the bit size of the given integer
+// type, violates the given overflow flags.
+func overflows(typ *types.IntType, flags []enum.OverflowFlag, s, u *big.Int) bool {
+	for _, flag := range flags {
+		switch flag {
+		case enum.OverflowFlagNSW:
+			if signedValue(&Int{Typ: typ, X: s}).Cmp(s) != 0 {
+				return true
+			}
+		case enum.OverflowFlagNUW:
+			if unsignedValue(&Int{Typ: typ, X: u}).Cmp(u) != 0 {
+				return true
+			}
+		default:
+			panic(fmt.Errorf("support for overflow flag %v not yet implemented", flag))
+		}
+	}
+	return false
+}
+
+// signedOverflow reports whether the signed division of x by y overflows the
+// given integer type; i.e. whether x is the minimum signed value and y is -1.
+func signedOverflow(typ *types.IntType, x, y *big.Int) bool {
+	if y.Cmp(big.NewInt(-1)) != 0 {
+		return false
+	}
+	min := new(big.Int).Lsh(big.NewInt(1), uint(typ.BitSize-1))
+	return x.Cmp(min.Neg(min)) == 0
+}
+
+// shiftAmount returns the shift amount of the given integer constant. The
+// boolean return value indicates success, and is false if the shift amount is
+// not less than the bit size of the given integer type (which produces poison).
+func shiftAmount(typ *types.IntType, y *Int) (uint, bool) {
+	n := unsignedValue(y)
+	if n.Cmp(big.NewInt(int64(typ.BitSize))) >= 0 {
+		return 0, false
+	}
+	return uint(n.Uint64()), true
+}
+
+// shiftsOutZeros reports whether the n least significant bits of the unsigned
+// value x are zero; i.e. whether a right shift of x by n is exact.
+func shiftsOutZeros(x *big.Int, n uint) bool {
+	return x.TrailingZeroBits() >= n || x.Sign() == 0
+}
@@ -0,0 +1,51 @@
+package constant
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestIntBinarySimplify(t *testing.T) {
+	one := NewInt(types.I32, 1)
+	two := NewInt(types.I32, 2)
+	undef := NewUndef(types.I32)
+	poison := NewPoison(types.I32)
+	golden := []struct {
+		in   Expression
+		want string
+	}{
+		{in: NewAdd(one, two), want: "i32 3"},
+		{in: NewAdd(NewVector(nil, one, two), NewVector(nil, two, two)), want: "<2 x i32> <i32 3, i32 4>"},
+		// Undef and poison lanes propagate.
+		{
+			in:   NewAdd(NewVector(nil, one, undef, poison), NewVector(nil, two, two, undef)),
+			want: "<3 x i32> <i32 3, i32 undef, i32 poison>",
+		},
+		{
+			in:   NewShl(NewVector(nil, one, poison), NewVector(nil, two, two)),
+			want: "<2 x i32> <i32 4, i32 poison>",
+		},
+		// Undef lanes are not folded by operations which may not produce every
+		// value (e.g. and undef, 0 is 0).
+		{
+			in:   NewAnd(NewVector(nil, one, undef), NewVector(nil, one, NewInt(types.I32, 0))),
+			want: "<2 x i32> and (<2 x i32> <i32 1, i32 undef>, <2 x i32> <i32 1, i32 0>)",
+		},
+		// Lanes which cannot be evaluated prevent folding of the vector.
+		{
+			in:   NewUDiv(NewVector(nil, one, two), NewVector(nil, one, NewInt(types.I32, 0))),
+			want: "<2 x i32> udiv (<2 x i32> <i32 1, i32 2>, <2 x i32> <i32 1, i32 0>)",
+		},
+		// Non-constant operands are not folded.
+		{
+			in:   NewSub(NewPtrToInt(NewNull(types.I8Ptr), types.I32), one),
+			want: "i32 sub (i32 ptrtoint (i8* null to i32), i32 1)",
+		},
+	}
+	for _, g := range golden {
+		if got := g.in.Simplify().String(); g.want != got {
+			t.Errorf("%v: simplified constant mismatch; expected %q, got %q", g.in, g.want, got)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package constant
 
 import (
 	"fmt"
+	"math/big"
 	"strings"
 
 	"github.com/llir/llvm/ir/enum"
@@ -62,8 +63,19 @@ func (e *ExprShl) Ident() string {
 
 // Simplify returns an equivalent (and potentially simplified) constant to the
 // constant expression.
+//
+// The shl expression is evaluated if both operands are integer constants or
+// vectors thereof, and the result is not poison.
 func (e *ExprShl) Simplify() Constant {
-	panic("not yet implemented")
+	return foldIntBinary(e, e.X, e.Y, false, func(x, y *Int) (*big.Int, bool) {
+		n, ok := shiftAmount(x.Typ, y)
+		if !ok {
+			return nil, false
+		}
+		s := new(big.Int).Lsh(signedValue(x), n)
+		u := new(big.Int).Lsh(unsignedValue(x), n)
+		return s, !overflows(x.Typ, e.OverflowFlags, s, u)
+	})
 }
 
 // ~~~ [ lshr ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
@@ -119,8 +131,18 @@ func (e *ExprLShr) Ident() string {
 
 // Simplify returns an equivalent (and potentially simplified) constant to the
 // constant expression.
+//
+// The lshr expression is evaluated if both operands are integer constants or
+// vectors thereof, and the result is not poison.
 func (e *ExprLShr) Simplify() Constant {
-	panic("not yet implemented")
+	return foldIntBinary(e, e.X, e.Y, false, func(x, y *Int) (*big.Int, bool) {
+		n, ok := shiftAmount(x.Typ, y)
+		if !ok {
+			return nil, false
+		}
+		a := unsignedValue(x)
+		return new(big.Int).Rsh(a, n), !e.Exact || shiftsOutZeros(a, n)
+	})
 }
 
 // ~~~ [ ashr ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
@@ -176,8 +198,20 @@ func (e *ExprAShr) Ident() string {
 
 // Simplify returns an equivalent (and potentially simplified) constant to the
 // constant expression.
+//
+// The ashr expression is evaluated if both operands are integer constants or
+// vectors thereof, and the result is not poison.
 func (e *ExprAShr) Simplify() Constant {
-	panic("not yet implemented")
+	return foldIntBinary(e, e.X, e.Y, false, func(x, y *Int) (*big.Int, bool) {
+		n, ok := shiftAmount(x.Typ, y)
+		if !ok {
+			return nil, false
+		}
+		// Right shift of negative values rounds towards negative infinity, and
+		// is thus an arithmetic shift.
+		z := new(big.Int).Rsh(signedValue(x), n)
+		return z, !e.Exact || shiftsOutZeros(unsignedValue(x), n)
+	})
 }
 
 // ~~~ [ and ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
@@ -224,8 +258,13 @@ func (e *ExprAnd) Ident() string {
 
 // Simplify returns an equivalent (and potentially simplified) constant to the
 // constant expression.
+//
+// The and expression is evaluated if both operands are integer constants or
+// vectors thereof.
 func (e *ExprAnd) Simplify() Constant {
-	panic("not yet implemented")
+	return foldIntBinary(e, e.X, e.Y, false, func(x, y *Int) (*big.Int, bool) {
+		return new(big.Int).And(unsignedValue(x), unsignedValue(y)), true
+	})
 }
 
 // ~~~ [ or ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
@@ -272,8 +311,13 @@ func (e *ExprOr) Ident() string {
 
 // Simplify returns an equivalent (and potentially simplified) constant to the
 // constant expression.
+//
+// The or expression is evaluated if both operands are integer constants or
+// vectors thereof.
 func (e *ExprOr) Simplify() Constant {
-	panic("not yet implemented")
+	return foldIntBinary(e, e.X, e.Y, false, func(x, y *Int) (*big.Int, bool) {
+		return new(big.Int).Or(unsignedValue(x), unsignedValue(y)), true
+	})
 }
 
 // ~~~ [ xor ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
@@ -320,6 +364,11 @@ func (e *ExprXor) Ident() string {
 
 // Simplify returns an equivalent (and potentially simplified) constant to the
 // constant expression.
+//
+// The xor expression is evaluated if both operands are integer constants or
+// vectors thereof.
 func (e *ExprXor) Simplify() Constant {
-	panic("not yet implemented")
+	return foldIntBinary(e, e.X, e.Y, true, func(x, y *Int) (*big.Int, bool) {
+		return new(big.Int).Xor(unsignedValue(x), unsignedValue(y)), true
+	})
 }
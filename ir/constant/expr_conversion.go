@@ -2,6 +2,7 @@ package constant
 
 import (
 	"fmt"
+	"math/big"
 
 	"github.com/llir/llvm/ir/types"
 )
@@ -46,8 +47,15 @@ func (e *ExprTrunc) Ident() string {
 
 // Simplify returns an equivalent (and potentially simplified) constant to the
 // constant expression.
+//
+// The trunc expression is evaluated if the source value is an integer
+// constant or a vector of integer constants, by masking the value to the width
+// of the target type.
 func (e *ExprTrunc) Simplify() Constant {
-	panic("not yet implemented")
+	if c, ok := foldIntCast(e.From, e.To, unsignedValue); ok {
+		return c
+	}
+	return e
 }
 
 // ~~~ [ zext ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
@@ -88,8 +96,15 @@ func (e *ExprZExt) Ident() string {
 
 // Simplify returns an equivalent (and potentially simplified) constant to the
 // constant expression.
+//
+// The zext expression is evaluated if the source value is an integer
+// constant or a vector of integer constants, by zero-extending the value to the
+// width of the target type.
 func (e *ExprZExt) Simplify() Constant {
-	panic("not yet implemented")
+	if c, ok := foldIntCast(e.From, e.To, unsignedValue); ok {
+		return c
+	}
+	return e
 }
 
 // ~~~ [ sext ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
@@ -130,8 +145,15 @@ func (e *ExprSExt) Ident() string {
 
 // Simplify returns an equivalent (and potentially simplified) constant to the
 // constant expression.
+//
+// The sext expression is evaluated if the source value is an integer
+// constant or a vector of integer constants, by sign-extending the value to the
+// width of the target type.
 func (e *ExprSExt) Simplify() Constant {
-	panic("not yet implemented")
+	if c, ok := foldIntCast(e.From, e.To, signedValue); ok {
+		return c
+	}
+	return e
 }
 
 // ~~~ [ fptrunc ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
@@ -424,8 +446,47 @@ func (e *ExprPtrToInt) Ident() string {
 
 // Simplify returns an equivalent (and potentially simplified) constant to the
 // constant expression.
+//
+// A ptrtoint of the null pointer is folded to zero. Pointers with symbolic
+// addresses (e.g. global variables) are kept unfolded.
 func (e *ExprPtrToInt) Simplify() Constant {
-	panic("not yet implemented")
+	return e.SimplifyDataLayout(nil)
+}
+
+// SimplifyDataLayout returns an equivalent (and potentially simplified)
+// constant to the constant expression, based on the given data layout. A nil
+// data layout restricts simplification to layout-independent folding.
+//
+// With a data layout, ptrtoint of inttoptr is folded by truncating or
+// zero-extending the integer to the pointer size.
+func (e *ExprPtrToInt) SimplifyDataLayout(dl *types.DataLayout) Constant {
+	toType, ok := e.To.(*types.IntType)
+	if !ok {
+		return e
+	}
+	switch from := e.From.(type) {
+	case *Null:
+		return NewInt(toType, 0)
+	case *ExprIntToPtr:
+		if dl == nil {
+			return e
+		}
+		fromType, ok := from.To.(*types.PointerType)
+		if !ok {
+			return e
+		}
+		x, ok := from.From.(*Int)
+		if !ok {
+			return e
+		}
+		// The pointer holds the integer value truncated or zero-extended to
+		// the pointer size, which is then truncated or zero-extended to the
+		// target type.
+		ptrType := types.NewInt(dl.PointerSize(fromType.AddrSpace))
+		addr := unsignedValue(newInt(ptrType, unsignedValue(x)))
+		return newInt(toType, addr)
+	}
+	return e
 }
 
 // ~~~ [ inttoptr ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
@@ -466,8 +527,51 @@ func (e *ExprIntToPtr) Ident() string {
 
 // Simplify returns an equivalent (and potentially simplified) constant to the
 // constant expression.
+//
+// An inttoptr of the integer zero is folded to the null pointer. Other integer
+// values are kept unfolded.
 func (e *ExprIntToPtr) Simplify() Constant {
-	panic("not yet implemented")
+	return e.SimplifyDataLayout(nil)
+}
+
+// SimplifyDataLayout returns an equivalent (and potentially simplified)
+// constant to the constant expression, based on the given data layout. A nil
+// data layout restricts simplification to layout-independent folding.
+//
+// With a data layout, the integer is truncated or zero-extended to the pointer
+// size before being compared against zero, and inttoptr of ptrtoint is folded
+// to the original pointer if the integer type is large enough to hold the
+// pointer.
+func (e *ExprIntToPtr) SimplifyDataLayout(dl *types.DataLayout) Constant {
+	toType, ok := e.To.(*types.PointerType)
+	if !ok {
+		return e
+	}
+	switch from := e.From.(type) {
+	case *Int:
+		x := unsignedValue(from)
+		if dl != nil {
+			ptrType := types.NewInt(dl.PointerSize(toType.AddrSpace))
+			x = unsignedValue(newInt(ptrType, x))
+		}
+		if x.Sign() == 0 {
+			return NewNull(toType)
+		}
+	case *ExprPtrToInt:
+		if dl == nil {
+			return e
+		}
+		fromType, ok := from.From.Type().(*types.PointerType)
+		if !ok || !fromType.Equal(toType) {
+			return e
+		}
+		intType, ok := from.To.(*types.IntType)
+		if !ok || intType.BitSize < dl.PointerSize(toType.AddrSpace) {
+			return e
+		}
+		return from.From
+	}
+	return e
 }
 
 // ~~~ [ bitcast ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
@@ -508,8 +612,28 @@ func (e *ExprBitCast) Ident() string {
 
 // Simplify returns an equivalent (and potentially simplified) constant to the
 // constant expression.
+//
+// Chains of bitcast expressions are collapsed into a single bitcast expression,
+// and bitcast expressions to the type of the source value are removed. Pointer
+// casts across address spaces are never folded, as they require addrspacecast.
 func (e *ExprBitCast) Simplify() Constant {
-	panic("not yet implemented")
+	from := e.From
+	// Collapse chains of bitcast expressions.
+	for {
+		inner, ok := from.(*ExprBitCast)
+		if !ok || !sameAddrSpace(inner.From.Type(), e.To) {
+			break
+		}
+		from = inner.From
+	}
+	// Remove no-op bitcast expression.
+	if from.Type().Equal(e.To) {
+		return from
+	}
+	if from == e.From {
+		return e
+	}
+	return NewBitCast(from, e.To)
 }
 
 // ~~~ [ addrspacecast ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
@@ -553,3 +677,50 @@ func (e *ExprAddrSpaceCast) Ident() string {
 func (e *ExprAddrSpaceCast) Simplify() Constant {
 	panic("not yet implemented")
 }
+
+// ### [ Helper functions ] ####################################################
+
+// sameAddrSpace reports whether the given types are either both non-pointer
+// types or both pointer types in the same address space.
+func sameAddrSpace(t, u types.Type) bool {
+	tp, tok := t.(*types.PointerType)
+	up, uok := u.(*types.PointerType)
+	if tok && uok {
+		return tp.AddrSpace == up.AddrSpace
+	}
+	return tok == uok
+}
+
+// foldIntCast returns the integer constant (or vector of integer constants)
+// produced by converting the given integer constant (or vector of integer
+// constants) to the target type, based on the value of each integer constant as
+// returned by value. The boolean return value indicates success.
+func foldIntCast(from Constant, to types.Type, value func(c *Int) *big.Int) (Constant, bool) {
+	switch from := from.(type) {
+	case *Int:
+		toType, ok := to.(*types.IntType)
+		if !ok {
+			return nil, false
+		}
+		return newInt(toType, value(from)), true
+	case *Vector:
+		toType, ok := to.(*types.VectorType)
+		if !ok {
+			return nil, false
+		}
+		elemType, ok := toType.ElemType.(*types.IntType)
+		if !ok {
+			return nil, false
+		}
+		elems := make([]Constant, len(from.Elems))
+		for i, elem := range from.Elems {
+			c, ok := elem.(*Int)
+			if !ok {
+				return nil, false
+			}
+			elems[i] = newInt(elemType, value(c))
+		}
+		return NewVector(toType, elems...), true
+	}
+	return nil, false
+}
@@ -0,0 +1,196 @@
+package constant
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+func TestBitCastSimplify(t *testing.T) {
+	i32Ptr := types.NewPointer(types.I32)
+	i32PtrAS1 := types.NewPointer(types.I32)
+	i32PtrAS1.AddrSpace = 1
+	i8PtrAS1 := types.NewPointer(types.I8)
+	i8PtrAS1.AddrSpace = 1
+	g := NewNull(i32Ptr)
+	gAS1 := NewNull(i32PtrAS1)
+	golden := []struct {
+		in   *ExprBitCast
+		want string
+	}{
+		// Bitcast to same type.
+		{
+			in:   NewBitCast(g, i32Ptr),
+			want: "i32* null",
+		},
+		// Nested bitcasts.
+		{
+			in:   NewBitCast(NewBitCast(g, types.I8Ptr), types.I8Ptr),
+			want: "i8* bitcast (i32* null to i8*)",
+		},
+		// Nested bitcasts back to the source type.
+		{
+			in:   NewBitCast(NewBitCast(NewBitCast(g, types.I8Ptr), types.I16Ptr), i32Ptr),
+			want: "i32* null",
+		},
+		// Nested bitcasts within non-default address space.
+		{
+			in:   NewBitCast(NewBitCast(gAS1, i8PtrAS1), i8PtrAS1),
+			want: "i8 addrspace(1)* bitcast (i32 addrspace(1)* null to i8 addrspace(1)*)",
+		},
+		// Bitcasts across address spaces are not folded.
+		{
+			in:   NewBitCast(NewBitCast(gAS1, i8PtrAS1), types.I8Ptr),
+			want: "i8* bitcast (i8 addrspace(1)* bitcast (i32 addrspace(1)* null to i8 addrspace(1)*) to i8*)",
+		},
+	}
+	for _, g := range golden {
+		got := g.in.Simplify().String()
+		if g.want != got {
+			t.Errorf("bitcast simplification mismatch of `%v`; expected `%v`, got `%v`", g.in, g.want, got)
+		}
+	}
+}
+
+func TestIntCastSimplify(t *testing.T) {
+	golden := []struct {
+		in   Expression
+		want string
+	}{
+		// trunc
+		{in: NewTrunc(NewInt(types.I32, 257), types.I8), want: "i8 1"},
+		{in: NewTrunc(NewInt(types.I32, 255), types.I8), want: "i8 -1"},
+		{in: NewTrunc(NewInt(types.I32, 3), types.I1), want: "i1 true"},
+		{in: NewTrunc(NewInt(types.I32, -2), types.I1), want: "i1 false"},
+		// zext
+		{in: NewZExt(True, types.I8), want: "i8 1"},
+		{in: NewZExt(NewInt(types.I8, -1), types.I32), want: "i32 255"},
+		// sext
+		{in: NewSExt(True, types.I8), want: "i8 -1"},
+		{in: NewSExt(NewInt(types.I8, -1), types.I32), want: "i32 -1"},
+		{in: NewSExt(NewInt(types.I8, 127), types.I32), want: "i32 127"},
+		// Vectors.
+		{
+			in:   NewSExt(NewVector(nil, True, False), types.NewVector(2, types.I32)),
+			want: "<2 x i32> <i32 -1, i32 0>",
+		},
+		{
+			in:   NewTrunc(NewVector(nil, NewInt(types.I16, 256), NewInt(types.I16, 511)), types.NewVector(2, types.I8)),
+			want: "<2 x i8> <i8 0, i8 -1>",
+		},
+		// Non-constant operands are not folded.
+		{
+			in:   NewZExt(NewPtrToInt(NewNull(types.I8Ptr), types.I8), types.I32),
+			want: "i32 zext (i8 ptrtoint (i8* null to i8) to i32)",
+		},
+	}
+	for _, g := range golden {
+		if got := g.in.Simplify().String(); g.want != got {
+			t.Errorf("integer conversion simplification mismatch of `%v`; expected `%v`, got `%v`", g.in, g.want, got)
+		}
+	}
+}
+
+func TestPtrIntCastSimplify(t *testing.T) {
+	// 32-bit pointers in the default address space, 64-bit pointers in address
+	// space 1.
+	dl, err := types.NewDataLayout("e-p:32:32-p1:64:64")
+	if err != nil {
+		t.Fatalf("unable to parse data layout; %v", err)
+	}
+	i8PtrAS1 := types.NewPointer(types.I8)
+	i8PtrAS1.AddrSpace = 1
+	g := &global{name: "g", typ: types.I8Ptr}
+	golden := []struct {
+		in Expression
+		dl *types.DataLayout
+		// Expected result without data layout.
+		want string
+		// Expected result with data layout.
+		wantDL string
+	}{
+		// ptrtoint of null pointer.
+		{
+			in:     NewPtrToInt(NewNull(types.I8Ptr), types.I64),
+			want:   "i64 0",
+			wantDL: "i64 0",
+		},
+		// inttoptr of zero.
+		{
+			in:     NewIntToPtr(NewInt(types.I64, 0), types.I8Ptr),
+			want:   "i8* null",
+			wantDL: "i8* null",
+		},
+		// inttoptr of non-zero integer truncated to zero by 32-bit pointers.
+		{
+			in:     NewIntToPtr(NewInt(types.I64, 1<<32), types.I8Ptr),
+			want:   "i8* inttoptr (i64 4294967296 to i8*)",
+			wantDL: "i8* null",
+		},
+		// inttoptr of non-zero integer within 64-bit address space.
+		{
+			in:     NewIntToPtr(NewInt(types.I64, 1<<32), i8PtrAS1),
+			want:   "i8 addrspace(1)* inttoptr (i64 4294967296 to i8 addrspace(1)*)",
+			wantDL: "i8 addrspace(1)* inttoptr (i64 4294967296 to i8 addrspace(1)*)",
+		},
+		// ptrtoint of inttoptr truncated to pointer size.
+		{
+			in:     NewPtrToInt(NewIntToPtr(NewInt(types.I64, 1<<32|7), types.I8Ptr), types.I64),
+			want:   "i64 ptrtoint (i8* inttoptr (i64 4294967303 to i8*) to i64)",
+			wantDL: "i64 7",
+		},
+		// ptrtoint of inttoptr zero-extended to pointer size.
+		{
+			in:     NewPtrToInt(NewIntToPtr(NewInt(types.I8, -1), i8PtrAS1), types.I64),
+			want:   "i64 ptrtoint (i8 addrspace(1)* inttoptr (i8 -1 to i8 addrspace(1)*) to i64)",
+			wantDL: "i64 255",
+		},
+		// inttoptr of ptrtoint with integer type large enough to hold pointer.
+		{
+			in:     NewIntToPtr(NewPtrToInt(g, types.I32), types.I8Ptr),
+			want:   "i8* inttoptr (i32 ptrtoint (i8* @g to i32) to i8*)",
+			wantDL: "i8* @g",
+		},
+		// inttoptr of ptrtoint with integer type too small to hold pointer.
+		{
+			in:     NewIntToPtr(NewPtrToInt(g, types.I16), types.I8Ptr),
+			want:   "i8* inttoptr (i16 ptrtoint (i8* @g to i16) to i8*)",
+			wantDL: "i8* inttoptr (i16 ptrtoint (i8* @g to i16) to i8*)",
+		},
+		// Symbolic addresses are not folded.
+		{
+			in:     NewPtrToInt(g, types.I64),
+			want:   "i64 ptrtoint (i8* @g to i64)",
+			wantDL: "i64 ptrtoint (i8* @g to i64)",
+		},
+	}
+	for _, g := range golden {
+		if got := g.in.Simplify().String(); g.want != got {
+			t.Errorf("pointer conversion simplification mismatch of `%v`; expected `%v`, got `%v`", g.in, g.want, got)
+		}
+		var got Constant
+		switch in := g.in.(type) {
+		case *ExprPtrToInt:
+			got = in.SimplifyDataLayout(dl)
+		case *ExprIntToPtr:
+			got = in.SimplifyDataLayout(dl)
+		}
+		if g.wantDL != got.String() {
+			t.Errorf("pointer conversion simplification mismatch of `%v` with data layout; expected `%v`, got `%v`", g.in, g.wantDL, got)
+		}
+	}
+}
+
+// global is a global variable stub with a symbolic address.
+type global struct {
+	name string
+	typ  types.Type
+}
+
+func (g *global) String() string   { return fmt.Sprintf("%s %s", g.typ, g.Ident()) }
+func (g *global) Type() types.Type { return g.typ }
+func (g *global) Ident() string    { return "@" + g.name }
+func (g *global) Kind() value.Kind { return value.KindGlobal }
+func (g *global) IsConstant()      {}
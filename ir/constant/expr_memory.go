@@ -63,7 +63,7 @@ func (e *ExprGetElementPtr) Type() types.Type {
 	}
 	// Cache type if not present.
 	if e.Typ == nil {
-		e.Typ = gepType(e.ElemType, e.Indices)
+		e.Typ = gepType(e.ElemType, srcAddrSpace(e.Src.Type()), e.Indices)
 	}
 	return e.Typ
 }
@@ -87,8 +87,26 @@ func (e *ExprGetElementPtr) Ident() string {
 
 // Simplify returns an equivalent (and potentially simplified) constant to the
 // constant expression.
+//
+// A getelementptr expression with all-zero indices is folded to the source
+// address, bitcast to the result type if the types differ (e.g. a pointer to
+// the first element of an array). The address space of the source address is
+// thus preserved. Vector getelementptr expressions are only folded if the
+// source address is of the result type.
 func (e *ExprGetElementPtr) Simplify() Constant {
-	panic("not yet implemented")
+	for _, index := range e.Indices {
+		if !IsZeroIndex(index) {
+			return e
+		}
+	}
+	typ := e.Type()
+	if e.Src.Type().Equal(typ) {
+		return e.Src
+	}
+	if types.IsVector(typ) {
+		return e
+	}
+	return NewBitCast(e.Src, typ)
 }
 
 // ___ [ gep indices ] _________________________________________________________
@@ -120,12 +138,35 @@ func (index *Index) String() string {
 	return index.Constant.String()
 }
 
+// IsZeroIndex reports whether the given getelementptr index is zero; i.e. an
+// integer constant of value zero, or a zeroinitializer or vector constant of
+// zero elements (optionally wrapped in a *constant.Index).
+func IsZeroIndex(index Constant) bool {
+	switch index := index.(type) {
+	case *Index:
+		return IsZeroIndex(index.Constant)
+	case *Int:
+		return index.X.Sign() == 0
+	case *ZeroInitializer:
+		return true
+	case *Vector:
+		for _, elem := range index.Elems {
+			if !IsZeroIndex(elem) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
 // ### [ Helper functions ] ####################################################
 
 // gepType returns the pointer type or vector of pointers type to the element at
 // the position in the type specified by the given indices, as calculated by the
-// getelementptr instruction.
-func gepType(elemType types.Type, indices []Constant) types.Type {
+// getelementptr instruction. The resulting pointer type is in the given address
+// space of the source address.
+func gepType(elemType types.Type, addrSpace types.AddrSpace, indices []Constant) types.Type {
 	e := elemType
 	for i, index := range indices {
 		// unpack inrange indices.
@@ -186,6 +227,8 @@ func gepType(elemType types.Type, indices []Constant) types.Type {
 	//    %113 = getelementptr inbounds %struct.fileinfo, %struct.fileinfo* %96, <2 x i64> %110, !dbg !4736
 	//    %116 = bitcast i8** %115 to <2 x %struct.fileinfo*>*, !dbg !4738
 	//    store <2 x %struct.fileinfo*> %113, <2 x %struct.fileinfo*>* %116, align 8, !dbg !4738, !tbaa !1793
+	ptr := types.NewPointer(e)
+	ptr.AddrSpace = addrSpace
 	if len(indices) > 0 {
 		index := indices[0]
 		// unpack inrange index.
@@ -193,8 +236,22 @@ func gepType(elemType types.Type, indices []Constant) types.Type {
 			index = idx.Constant
 		}
 		if t, ok := index.Type().(*types.VectorType); ok {
-			return types.NewVector(t.Len, types.NewPointer(e))
+			return types.NewVector(t.Len, ptr)
+		}
+	}
+	return ptr
+}
+
+// srcAddrSpace returns the address space of the given source address type of a
+// getelementptr expression (pointer type or vector of pointers type).
+func srcAddrSpace(srcType types.Type) types.AddrSpace {
+	switch t := srcType.(type) {
+	case *types.PointerType:
+		return t.AddrSpace
+	case *types.VectorType:
+		if elem, ok := t.ElemType.(*types.PointerType); ok {
+			return elem.AddrSpace
 		}
 	}
-	return types.NewPointer(e)
+	return 0
 }
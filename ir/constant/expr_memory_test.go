@@ -0,0 +1,71 @@
+package constant
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestGetElementPtrSimplify(t *testing.T) {
+	arrType := types.NewArray(4, types.I32)
+	arrPtr := types.NewPointer(arrType)
+	arrPtrAS1 := types.NewPointer(arrType)
+	arrPtrAS1.AddrSpace = 1
+	structType := types.NewStruct(types.NewStruct(types.I8, types.I64), types.I32)
+	structPtr := types.NewPointer(structType)
+	p := NewNull(arrPtr)
+	pAS1 := NewNull(arrPtrAS1)
+	q := NewNull(structPtr)
+	zero := NewInt(types.I64, 0)
+	zero32 := NewInt(types.I32, 0)
+	one := NewInt(types.I64, 1)
+	inBounds := NewGetElementPtr(p, zero, zero)
+	inBounds.InBounds = true
+	nonZeroInBounds := NewGetElementPtr(p, one, zero)
+	nonZeroInBounds.InBounds = true
+	golden := []struct {
+		in   *ExprGetElementPtr
+		want string
+	}{
+		// Single zero index.
+		{
+			in:   NewGetElementPtr(p, zero),
+			want: "[4 x i32]* null",
+		},
+		// All-zero indices to first array element.
+		{
+			in:   NewGetElementPtr(p, zero, zero),
+			want: "i32* bitcast ([4 x i32]* null to i32*)",
+		},
+		// All-zero index chain through nested structs.
+		{
+			in:   NewGetElementPtr(q, zero, zero32, zero32),
+			want: "i8* bitcast ({ { i8, i64 }, i32 }* null to i8*)",
+		},
+		// Zero indices in non-default address space.
+		{
+			in:   NewGetElementPtr(pAS1, zero, NewZeroInitializer(types.I64)),
+			want: "i32 addrspace(1)* bitcast ([4 x i32] addrspace(1)* null to i32 addrspace(1)*)",
+		},
+		// Non-zero indices are not folded.
+		{
+			in:   NewGetElementPtr(p, zero, one),
+			want: "i32* getelementptr ([4 x i32], [4 x i32]* null, i64 0, i64 1)",
+		},
+		// Non-folded inbounds expressions are kept intact.
+		{
+			in:   nonZeroInBounds,
+			want: "i32* getelementptr inbounds ([4 x i32], [4 x i32]* null, i64 1, i64 0)",
+		},
+		// All-zero inbounds expressions.
+		{
+			in:   inBounds,
+			want: "i32* bitcast ([4 x i32]* null to i32*)",
+		},
+	}
+	for _, g := range golden {
+		if got := g.in.Simplify().String(); g.want != got {
+			t.Errorf("getelementptr simplification mismatch of `%v`; expected `%v`, got `%v`", g.in, g.want, got)
+		}
+	}
+}
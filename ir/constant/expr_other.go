@@ -63,8 +63,19 @@ func (e *ExprICmp) Ident() string {
 
 // Simplify returns an equivalent (and potentially simplified) constant to the
 // constant expression.
+//
+// The icmp expression is evaluated if both operands are integer constants,
+// null pointer constants or vectors thereof.
 func (e *ExprICmp) Simplify() Constant {
-	panic("not yet implemented")
+	if c, ok := foldICmp(e.Pred, e.X, e.Y); ok {
+		return c
+	}
+	if c, ok := foldVector(e.Type(), e.X, e.Y, func(x, y Constant) (Constant, bool) {
+		return foldICmp(e.Pred, x, y)
+	}); ok {
+		return c
+	}
+	return e
 }
 
 // ~~~ [ fcmp ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
@@ -121,8 +132,19 @@ func (e *ExprFCmp) Ident() string {
 
 // Simplify returns an equivalent (and potentially simplified) constant to the
 // constant expression.
+//
+// The fcmp expression is evaluated if both operands are floating-point
+// constants or vectors thereof.
 func (e *ExprFCmp) Simplify() Constant {
-	panic("not yet implemented")
+	if c, ok := foldFCmp(e.Pred, e.X, e.Y); ok {
+		return c
+	}
+	if c, ok := foldVector(e.Type(), e.X, e.Y, func(x, y Constant) (Constant, bool) {
+		return foldFCmp(e.Pred, x, y)
+	}); ok {
+		return c
+	}
+	return e
 }
 
 // ~~~ [ select ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
@@ -172,6 +194,144 @@ func (e *ExprSelect) Ident() string {
 
 // Simplify returns an equivalent (and potentially simplified) constant to the
 // constant expression.
+//
+// The select expression is evaluated if the selection condition is a boolean
+// constant, or a vector of boolean constants.
 func (e *ExprSelect) Simplify() Constant {
-	panic("not yet implemented")
+	switch cond := e.Cond.(type) {
+	case *Int:
+		if cond.X.Sign() != 0 {
+			return e.X
+		}
+		return e.Y
+	case *Vector:
+		// Element-wise selection.
+		xs, ok := e.X.(*Vector)
+		if !ok {
+			return e
+		}
+		ys, ok := e.Y.(*Vector)
+		if !ok {
+			return e
+		}
+		elems := make([]Constant, len(cond.Elems))
+		for i, elem := range cond.Elems {
+			c, ok := elem.(*Int)
+			if !ok {
+				return e
+			}
+			if c.X.Sign() != 0 {
+				elems[i] = xs.Elems[i]
+			} else {
+				elems[i] = ys.Elems[i]
+			}
+		}
+		return NewVector(xs.Typ, elems...)
+	}
+	return e
+}
+
+// ### [ Helper functions ] ####################################################
+
+// foldICmp returns the boolean constant produced by evaluating the integer
+// comparison of the given scalar operands. The boolean return value indicates
+// success.
+func foldICmp(pred enum.IPred, x, y Constant) (Constant, bool) {
+	var cmp, scmp int
+	switch x := x.(type) {
+	case *Int:
+		y, ok := y.(*Int)
+		if !ok {
+			return nil, false
+		}
+		cmp = unsignedValue(x).Cmp(unsignedValue(y))
+		scmp = signedValue(x).Cmp(signedValue(y))
+	case *Null:
+		if _, ok := y.(*Null); !ok {
+			return nil, false
+		}
+		// Null pointer constants are equal.
+	default:
+		return nil, false
+	}
+	switch pred {
+	case enum.IPredEQ:
+		return NewBool(cmp == 0), true
+	case enum.IPredNE:
+		return NewBool(cmp != 0), true
+	case enum.IPredSGE:
+		return NewBool(scmp >= 0), true
+	case enum.IPredSGT:
+		return NewBool(scmp > 0), true
+	case enum.IPredSLE:
+		return NewBool(scmp <= 0), true
+	case enum.IPredSLT:
+		return NewBool(scmp < 0), true
+	case enum.IPredUGE:
+		return NewBool(cmp >= 0), true
+	case enum.IPredUGT:
+		return NewBool(cmp > 0), true
+	case enum.IPredULE:
+		return NewBool(cmp <= 0), true
+	case enum.IPredULT:
+		return NewBool(cmp < 0), true
+	default:
+		panic(fmt.Errorf("support for integer comparison predicate %v not yet implemented", pred))
+	}
+}
+
+// foldFCmp returns the boolean constant produced by evaluating the
+// floating-point comparison of the given scalar operands. The boolean return
+// value indicates success.
+func foldFCmp(pred enum.FPred, x, y Constant) (Constant, bool) {
+	a, ok := x.(*Float)
+	if !ok {
+		return nil, false
+	}
+	b, ok := y.(*Float)
+	if !ok {
+		return nil, false
+	}
+	// Comparisons are unordered if either operand is NaN.
+	uno := a.NaN || b.NaN
+	cmp := 0
+	if !uno {
+		cmp = a.X.Cmp(b.X)
+	}
+	switch pred {
+	case enum.FPredFalse:
+		return False, true
+	case enum.FPredOEQ:
+		return NewBool(!uno && cmp == 0), true
+	case enum.FPredOGE:
+		return NewBool(!uno && cmp >= 0), true
+	case enum.FPredOGT:
+		return NewBool(!uno && cmp > 0), true
+	case enum.FPredOLE:
+		return NewBool(!uno && cmp <= 0), true
+	case enum.FPredOLT:
+		return NewBool(!uno && cmp < 0), true
+	case enum.FPredONE:
+		return NewBool(!uno && cmp != 0), true
+	case enum.FPredORD:
+		return NewBool(!uno), true
+	case enum.FPredTrue:
+		return True, true
+	case enum.FPredUEQ:
+		return NewBool(uno || cmp == 0), true
+	case enum.FPredUGE:
+		return NewBool(uno || cmp >= 0), true
+	case enum.FPredUGT:
+		return NewBool(uno || cmp > 0), true
+	case enum.FPredULE:
+		return NewBool(uno || cmp <= 0), true
+	case enum.FPredULT:
+		return NewBool(uno || cmp < 0), true
+	case enum.FPredUNE:
+		return NewBool(uno || cmp != 0), true
+	case enum.FPredUNO:
+		return NewBool(uno), true
+	default:
+		panic(fmt.Errorf("support for floating-point comparison predicate %v not yet implemented", pred))
+	}
 }
@@ -0,0 +1,101 @@
+package constant
+
+import (
+	"math"
+	"testing"
+
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestICmpSimplify(t *testing.T) {
+	minusOne := NewInt(types.I32, -1)
+	one := NewInt(types.I32, 1)
+	golden := []struct {
+		in   *ExprICmp
+		want string
+	}{
+		{in: NewICmp(enum.IPredEQ, one, one), want: "i1 true"},
+		{in: NewICmp(enum.IPredNE, one, one), want: "i1 false"},
+		{in: NewICmp(enum.IPredSLT, minusOne, one), want: "i1 true"},
+		{in: NewICmp(enum.IPredULT, minusOne, one), want: "i1 false"},
+		{in: NewICmp(enum.IPredUGT, minusOne, one), want: "i1 true"},
+		{in: NewICmp(enum.IPredEQ, NewNull(types.I8Ptr), NewNull(types.I8Ptr)), want: "i1 true"},
+		{
+			in:   NewICmp(enum.IPredSGE, NewVector(nil, minusOne, one), NewVector(nil, one, one)),
+			want: "<2 x i1> <i1 false, i1 true>",
+		},
+		// Undef and poison lanes propagate.
+		{
+			in:   NewICmp(enum.IPredEQ, NewVector(nil, one, NewUndef(types.I32), one), NewVector(nil, one, one, NewPoison(types.I32))),
+			want: "<3 x i1> <i1 true, i1 undef, i1 poison>",
+		},
+		{
+			in:   NewICmp(enum.IPredEQ, NewVector(nil, NewUndef(types.I32), NewPoison(types.I32)), NewVector(nil, NewPoison(types.I32), NewUndef(types.I32))),
+			want: "<2 x i1> <i1 poison, i1 poison>",
+		},
+		// Vectors with lanes that cannot be folded are not folded.
+		{
+			in:   NewICmp(enum.IPredEQ, NewVector(nil, NewUndef(types.I32), NewPtrToInt(NewNull(types.I8Ptr), types.I32)), NewVector(nil, one, one)),
+			want: "<2 x i1> icmp eq (<2 x i32> <i32 undef, i32 ptrtoint (i8* null to i32)>, <2 x i32> <i32 1, i32 1>)",
+		},
+		// Non-constant operands are not folded.
+		{
+			in:   NewICmp(enum.IPredEQ, NewPtrToInt(NewNull(types.I8Ptr), types.I32), one),
+			want: "i1 icmp eq (i32 ptrtoint (i8* null to i32), i32 1)",
+		},
+	}
+	for _, g := range golden {
+		if got := g.in.Simplify().String(); g.want != got {
+			t.Errorf("icmp simplification mismatch of `%v`; expected `%v`, got `%v`", g.in, g.want, got)
+		}
+	}
+}
+
+func TestFCmpSimplify(t *testing.T) {
+	one := NewFloat(types.Double, 1)
+	two := NewFloat(types.Double, 2)
+	nan := NewFloat(types.Double, math.NaN())
+	golden := []struct {
+		in   *ExprFCmp
+		want string
+	}{
+		{in: NewFCmp(enum.FPredOLT, one, two), want: "i1 true"},
+		{in: NewFCmp(enum.FPredOGE, one, two), want: "i1 false"},
+		{in: NewFCmp(enum.FPredOEQ, nan, nan), want: "i1 false"},
+		{in: NewFCmp(enum.FPredUEQ, nan, one), want: "i1 true"},
+		{in: NewFCmp(enum.FPredORD, one, two), want: "i1 true"},
+		{in: NewFCmp(enum.FPredUNO, one, nan), want: "i1 true"},
+		{
+			in:   NewFCmp(enum.FPredOLT, NewVector(nil, one, NewUndef(types.Double)), NewVector(nil, two, two)),
+			want: "<2 x i1> <i1 true, i1 undef>",
+		},
+	}
+	for _, g := range golden {
+		if got := g.in.Simplify().String(); g.want != got {
+			t.Errorf("fcmp simplification mismatch of `%v`; expected `%v`, got `%v`", g.in, g.want, got)
+		}
+	}
+}
+
+func TestSelectSimplify(t *testing.T) {
+	one := NewInt(types.I32, 1)
+	two := NewInt(types.I32, 2)
+	golden := []struct {
+		in   *ExprSelect
+		want string
+	}{
+		{in: NewSelect(True, one, two), want: "i32 1"},
+		{in: NewSelect(False, one, two), want: "i32 2"},
+		{in: NewSelect(NewICmp(enum.IPredEQ, one, two), one, two), want: "i32 select (i1 icmp eq (i32 1, i32 2), i32 1, i32 2)"},
+		{
+			in:   NewSelect(NewVector(nil, True, False), NewVector(nil, one, one), NewVector(nil, two, two)),
+			want: "<2 x i32> <i32 1, i32 2>",
+		},
+	}
+	for _, g := range golden {
+		if got := g.in.Simplify().String(); g.want != got {
+			t.Errorf("select simplification mismatch of `%v`; expected `%v`, got `%v`", g.in, g.want, got)
+		}
+	}
+}
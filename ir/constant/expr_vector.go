@@ -126,8 +126,9 @@ func (e *ExprInsertElement) Simplify() Constant {
 type ExprShuffleVector struct {
 	// Vectors.
 	X, Y Constant
-	// Shuffle mask.
-	Mask Constant
+	// Shuffle mask; UndefMaskElem for undef lanes and PoisonMaskElem for poison
+	// lanes.
+	Mask []int
 
 	// extra.
 
@@ -136,8 +137,9 @@ type ExprShuffleVector struct {
 }
 
 // NewShuffleVector returns a new shufflevector expression based on the given
-// vectors and shuffle mask.
-func NewShuffleVector(x, y, mask Constant) *ExprShuffleVector {
+// vectors and shuffle mask elements, using UndefMaskElem for undef lanes and
+// PoisonMaskElem for poison lanes.
+func NewShuffleVector(x, y Constant, mask []int) *ExprShuffleVector {
 	e := &ExprShuffleVector{X: x, Y: y, Mask: mask}
 	// Compute type.
 	e.Type()
@@ -158,11 +160,7 @@ func (e *ExprShuffleVector) Type() types.Type {
 		if !ok {
 			panic(fmt.Errorf("invalid vector type; expected *types.VectorType, got %T", e.X.Type()))
 		}
-		maskType, ok := e.Mask.Type().(*types.VectorType)
-		if !ok {
-			panic(fmt.Errorf("invalid vector type; expected *types.VectorType, got %T", e.Mask.Type()))
-		}
-		e.Typ = types.NewVector(maskType.Len, xType.ElemType)
+		e.Typ = types.NewVector(uint64(len(e.Mask)), xType.ElemType)
 	}
 	return e.Typ
 }
@@ -170,7 +168,7 @@ func (e *ExprShuffleVector) Type() types.Type {
 // Ident returns the identifier associated with the constant expression.
 func (e *ExprShuffleVector) Ident() string {
 	// 'shufflevector' '(' X=TypeConst ',' Y=TypeConst ',' Mask=TypeConst ')'
-	return fmt.Sprintf("shufflevector (%s, %s, %s)", e.X, e.Y, e.Mask)
+	return fmt.Sprintf("shufflevector (%s, %s, %s)", e.X, e.Y, NewShuffleMask(e.Mask...))
 }
 
 // Simplify returns an equivalent (and potentially simplified) constant to the
@@ -0,0 +1,96 @@
+package constant
+
+import (
+	"math/big"
+
+	"github.com/llir/llvm/ir/types"
+)
+
+// ### [ Helper functions ] ####################################################
+
+// newInt returns a new integer constant of the given integer type, based on the
+// value x truncated to the bit size of the type. The value is stored in signed
+// form, except for boolean values.
+func newInt(typ *types.IntType, x *big.Int) *Int {
+	c := &Int{Typ: typ, X: x}
+	if typ.BitSize == 1 {
+		c.X = unsignedValue(c)
+	} else {
+		c.X = signedValue(c)
+	}
+	return c
+}
+
+// unsignedValue returns the value of the given integer constant, interpreted as
+// an unsigned integer of the bit size of its type.
+func unsignedValue(c *Int) *big.Int {
+	mask := new(big.Int).Lsh(big.NewInt(1), uint(c.Typ.BitSize))
+	mask.Sub(mask, big.NewInt(1))
+	// Two's complement representation of negative values is produced by the
+	// bitwise AND with the mask.
+	return new(big.Int).And(c.X, mask)
+}
+
+// signedValue returns the value of the given integer constant, interpreted as
+// a signed integer of the bit size of its type.
+func signedValue(c *Int) *big.Int {
+	x := unsignedValue(c)
+	if c.Typ.BitSize > 0 && x.Bit(int(c.Typ.BitSize-1)) == 1 {
+		x.Sub(x, new(big.Int).Lsh(big.NewInt(1), uint(c.Typ.BitSize)))
+	}
+	return x
+}
+
+// foldVector returns a vector constant produced by applying the given folding
+// function to each pair of corresponding elements of the given vector
+// constants. The boolean return value indicates success, and is false if x or
+// y is not a vector constant, or if the folding of any element failed.
+//
+// Lanes with a poison operand element fold to poison, and lanes with an undef
+// operand element fold to undef, without invoking the folding function. Poison
+// lanes are thus only produced from poison operand elements (see Poison).
+func foldVector(typ types.Type, x, y Constant, fold func(x, y Constant) (Constant, bool)) (Constant, bool) {
+	xs, ok := x.(*Vector)
+	if !ok {
+		return nil, false
+	}
+	ys, ok := y.(*Vector)
+	if !ok || len(xs.Elems) != len(ys.Elems) {
+		return nil, false
+	}
+	t, ok := typ.(*types.VectorType)
+	if !ok {
+		return nil, false
+	}
+	elems := make([]Constant, len(xs.Elems))
+	for i := range xs.Elems {
+		if elem, ok := foldUndefLane(t.ElemType, xs.Elems[i], ys.Elems[i]); ok {
+			elems[i] = elem
+			continue
+		}
+		elem, ok := fold(xs.Elems[i], ys.Elems[i])
+		if !ok {
+			return nil, false
+		}
+		elems[i] = elem
+	}
+	return NewVector(t, elems...), true
+}
+
+// foldUndefLane returns the poison or undef value of the given element type
+// produced by a lane of a vector operation with the given operand elements.
+// Poison takes precedence over undef. The boolean return value indicates
+// whether either operand element is poison or undef.
+func foldUndefLane(elemType types.Type, x, y Constant) (Constant, bool) {
+	_, xPoison := x.(*Poison)
+	_, yPoison := y.(*Poison)
+	if xPoison || yPoison {
+		return NewPoison(elemType), true
+	}
+	_, xUndef := x.(*Undef)
+	_, yUndef := y.(*Undef)
+	if xUndef || yUndef {
+		return NewUndef(elemType), true
+	}
+	return nil, false
+}
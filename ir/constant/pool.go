@@ -0,0 +1,55 @@
+package constant
+
+import (
+	"github.com/llir/llvm/ir/types"
+)
+
+// --- [ Constant pool ] -------------------------------------------------------
+
+// Pool is a pool of interned constants, which may be used to share identical
+// constants between the elements of large aggregate constants (e.g. lookup
+// tables) to reduce memory usage.
+//
+// Constants are considered identical if they have the same LLVM syntax
+// representation as type-value pair; thus interning never changes the emitted
+// LLVM IR assembly. Interned constants are shared and must not be modified.
+type Pool struct {
+	// Interned constants, indexed by LLVM syntax representation.
+	consts map[string]Constant
+}
+
+// NewPool returns a new empty constant pool.
+func NewPool() *Pool {
+	return &Pool{consts: make(map[string]Constant)}
+}
+
+// Intern returns the constant of the pool identical to the given constant. The
+// given constant is added to the pool if no identical constant is present.
+func (pool *Pool) Intern(c Constant) Constant {
+	key := c.String()
+	if prev, ok := pool.consts[key]; ok {
+		return prev
+	}
+	pool.consts[key] = c
+	return c
+}
+
+// Len returns the number of distinct constants in the pool.
+func (pool *Pool) Len() int {
+	return len(pool.consts)
+}
+
+// NewArrayInterned returns a new array constant based on the given array type
+// and elements, where each element is interned in the given constant pool. The
+// array type is infered from the type of the elements if t is nil.
+//
+// The array constant is identical to the one returned by NewArray, except that
+// identical elements share the same in-memory representation. The given
+// elements slice is left unmodified.
+func NewArrayInterned(pool *Pool, t *types.ArrayType, elems ...Constant) *Array {
+	interned := make([]Constant, len(elems))
+	for i, elem := range elems {
+		interned[i] = pool.Intern(elem)
+	}
+	return NewArray(t, interned...)
+}
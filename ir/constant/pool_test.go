@@ -0,0 +1,73 @@
+package constant
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestNewArrayInterned(t *testing.T) {
+	pool := NewPool()
+	var elems, want []Constant
+	for i := 0; i < 10; i++ {
+		elems = append(elems, NewInt(types.I8, int64(i%3)))
+		want = append(want, NewInt(types.I8, int64(i%3)))
+	}
+	elems = append(elems, NewFloat(types.Double, 1), NewFloat(types.Double, 1))
+	want = append(want, NewFloat(types.Double, 1), NewFloat(types.Double, 1))
+	got := NewArrayInterned(pool, types.NewArray(uint64(len(elems)), types.I8), elems...)
+	if got, want := got.String(), NewArray(got.Typ, want...).String(); got != want {
+		t.Errorf("interned array mismatch; expected `%v`, got `%v`", want, got)
+	}
+	if got, want := pool.Len(), 4; got != want {
+		t.Errorf("pool size mismatch; expected %d, got %d", want, got)
+	}
+	if got.Elems[0] != got.Elems[3] {
+		t.Errorf("identical elements not shared; `%v` and `%v`", got.Elems[0], got.Elems[3])
+	}
+	if got.Elems[0] == got.Elems[1] {
+		t.Errorf("distinct elements shared; `%v` and `%v`", got.Elems[0], got.Elems[1])
+	}
+	if elems[0] == elems[3] {
+		t.Errorf("elements of caller modified; `%v` and `%v` shared", elems[0], elems[3])
+	}
+}
+
+// arrayLen is the number of elements in benchmarked array constants.
+const arrayLen = 1000000
+
+func BenchmarkNewArray(b *testing.B) {
+	benchmarkArray(b, func(elems []Constant) *Array {
+		return NewArray(nil, elems...)
+	})
+}
+
+func BenchmarkNewArrayInterned(b *testing.B) {
+	benchmarkArray(b, func(elems []Constant) *Array {
+		return NewArrayInterned(NewPool(), nil, elems...)
+	})
+}
+
+// benchmarkArray benchmarks the construction of an i8 array constant of
+// repeated values using the given array constructor, and reports the heap
+// memory retained by the array constant.
+func benchmarkArray(b *testing.B, newArray func(elems []Constant) *Array) {
+	b.ReportAllocs()
+	var retained uint64
+	for i := 0; i < b.N; i++ {
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		elems := make([]Constant, arrayLen)
+		for j := range elems {
+			elems[j] = NewInt(types.I8, int64(j%16))
+		}
+		c := newArray(elems)
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+		retained += after.HeapAlloc - before.HeapAlloc
+		runtime.KeepAlive(c)
+	}
+	b.ReportMetric(float64(retained)/float64(b.N), "retained-B/op")
+}
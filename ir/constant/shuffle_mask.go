@@ -0,0 +1,88 @@
+package constant
+
+import (
+	"github.com/llir/llvm/ir/types"
+)
+
+// --- [ Shuffle masks ] -------------------------------------------------------
+
+// Shuffle mask elements of undefined lanes.
+const (
+	// UndefMaskElem is the shuffle mask element of an undef lane.
+	UndefMaskElem = -1
+	// PoisonMaskElem is the shuffle mask element of a poison lane.
+	PoisonMaskElem = -2
+)
+
+// NewShuffleMask returns a new shuffle mask constant of type <N x i32> based on
+// the given mask elements, where N is the number of mask elements. Mask elements
+// of value UndefMaskElem and PoisonMaskElem are emitted as undef and poison
+// respectively.
+//
+// Example:
+//
+//    constant.NewShuffleMask(0, constant.UndefMaskElem, 2, constant.PoisonMaskElem)
+//
+// is emitted as
+//
+//    <4 x i32> <i32 0, i32 undef, i32 2, i32 poison>
+func NewShuffleMask(elems ...int) *Vector {
+	cs := make([]Constant, len(elems))
+	for i, elem := range elems {
+		switch elem {
+		case UndefMaskElem:
+			cs[i] = NewUndef(types.I32)
+		case PoisonMaskElem:
+			cs[i] = NewPoison(types.I32)
+		default:
+			cs[i] = NewInt(types.I32, int64(elem))
+		}
+	}
+	return NewVector(types.NewVector(uint64(len(elems)), types.I32), cs...)
+}
+
+// ShuffleMask returns the mask elements of the given shuffle mask constant,
+// using UndefMaskElem for undef lanes and PoisonMaskElem for poison lanes (see
+// ExprShuffleVector.Mask). The boolean return value indicates success, and is
+// false if mask is not a constant vector of i32 elements.
+func ShuffleMask(mask Constant) ([]int, bool) {
+	t, ok := mask.Type().(*types.VectorType)
+	if !ok || !t.ElemType.Equal(types.I32) {
+		return nil, false
+	}
+	elems := make([]int, t.Len)
+	switch mask := mask.(type) {
+	case *Vector:
+		if uint64(len(mask.Elems)) != t.Len {
+			return nil, false
+		}
+		for i, elem := range mask.Elems {
+			switch elem := elem.(type) {
+			case *Int:
+				if !elem.X.IsInt64() || elem.X.Int64() < 0 {
+					return nil, false
+				}
+				elems[i] = int(elem.X.Int64())
+			case *Undef:
+				elems[i] = UndefMaskElem
+			case *Poison:
+				elems[i] = PoisonMaskElem
+			default:
+				return nil, false
+			}
+		}
+	case *ZeroInitializer:
+		// All lanes select the first element.
+	case *Undef:
+		for i := range elems {
+			elems[i] = UndefMaskElem
+		}
+	case *Poison:
+		for i := range elems {
+			elems[i] = PoisonMaskElem
+		}
+	default:
+		return nil, false
+	}
+	return elems, true
+}
@@ -0,0 +1,44 @@
+package constant
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestShuffleMask(t *testing.T) {
+	mask := NewShuffleMask(0, UndefMaskElem, 2, PoisonMaskElem)
+	if want, got := "<4 x i32> <i32 0, i32 undef, i32 2, i32 poison>", mask.String(); want != got {
+		t.Errorf("shuffle mask mismatch; expected %q, got %q", want, got)
+	}
+	maskType := types.NewVector(2, types.I32)
+	golden := []struct {
+		in Constant
+		// Expected mask elements; or nil if not a valid shuffle mask.
+		want []int
+	}{
+		{in: mask, want: []int{0, UndefMaskElem, 2, PoisonMaskElem}},
+		{in: NewVector(maskType, NewPoison(types.I32), NewInt(types.I32, 1)), want: []int{PoisonMaskElem, 1}},
+		{in: NewZeroInitializer(maskType), want: []int{0, 0}},
+		{in: NewUndef(maskType), want: []int{UndefMaskElem, UndefMaskElem}},
+		{in: NewPoison(maskType), want: []int{PoisonMaskElem, PoisonMaskElem}},
+		// Negative mask elements are invalid; undefined lanes must be undef or
+		// poison.
+		{in: NewVector(maskType, NewInt(types.I32, -1), NewInt(types.I32, 1))},
+		// Mask elements must be of type i32.
+		{in: NewVector(types.NewVector(2, types.I64), NewInt(types.I64, 0), NewInt(types.I64, 1))},
+	}
+	for _, g := range golden {
+		got, ok := ShuffleMask(g.in)
+		if g.want == nil {
+			if ok {
+				t.Errorf("%v: expected invalid shuffle mask, got %v", g.in, got)
+			}
+			continue
+		}
+		if !ok || !reflect.DeepEqual(g.want, got) {
+			t.Errorf("%v: mask elements mismatch; expected %v, got %v", g.in, g.want, got)
+		}
+	}
+}
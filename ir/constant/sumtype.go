@@ -1,5 +1,7 @@
 package constant
 
+import "github.com/llir/llvm/ir/value"
+
 // === [ constant.Constant ] ===================================================
 
 // IsConstant ensures that only constants can be assigned to the
@@ -42,6 +44,10 @@ func (*ZeroInitializer) IsConstant() {}
 // constant.Constant interface.
 func (*Undef) IsConstant() {}
 
+// IsConstant ensures that only constants can be assigned to the
+// constant.Constant interface.
+func (*Poison) IsConstant() {}
+
 // IsConstant ensures that only constants can be assigned to the
 // constant.Constant interface.
 func (*BlockAddress) IsConstant() {}
@@ -225,3 +231,286 @@ func (*ExprFCmp) IsConstant() {}
 // IsConstant ensures that only constants can be assigned to the
 // constant.Constant interface.
 func (*ExprSelect) IsConstant() {}
+
+// === [ value.Value ] =========================================================
+
+// Kind returns the kind of the value.
+func (*Int) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*Float) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*Null) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*NoneToken) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*Struct) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*Array) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*CharArray) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*Vector) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ZeroInitializer) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*Undef) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*Poison) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*BlockAddress) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// --- [ Unary expressions ] ---------------------------------------------------
+
+// Kind returns the kind of the value.
+func (*ExprFNeg) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// --- [ Binary expressions ] --------------------------------------------------
+
+// Kind returns the kind of the value.
+func (*ExprAdd) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprFAdd) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprSub) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprFSub) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprMul) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprFMul) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprUDiv) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprSDiv) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprFDiv) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprURem) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprSRem) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprFRem) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// --- [ Bitwise expressions ] -------------------------------------------------
+
+// Kind returns the kind of the value.
+func (*ExprShl) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprLShr) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprAShr) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprAnd) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprOr) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprXor) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// --- [ Vector expressions ] --------------------------------------------------
+
+// Kind returns the kind of the value.
+func (*ExprExtractElement) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprInsertElement) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprShuffleVector) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// --- [ Aggregate expressions ] -----------------------------------------------
+
+// Kind returns the kind of the value.
+func (*ExprExtractValue) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprInsertValue) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// --- [ Memory expressions ] --------------------------------------------------
+
+// Kind returns the kind of the value.
+func (*ExprGetElementPtr) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// --- [ Conversion expressions ] ----------------------------------------------
+
+// Kind returns the kind of the value.
+func (*ExprTrunc) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprZExt) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprSExt) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprFPTrunc) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprFPExt) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprFPToUI) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprFPToSI) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprUIToFP) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprSIToFP) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprPtrToInt) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprIntToPtr) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprBitCast) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprAddrSpaceCast) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// --- [ Other expressions ] ---------------------------------------------------
+
+// Kind returns the kind of the value.
+func (*ExprICmp) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprFCmp) Kind() value.Kind {
+	return value.KindConstant
+}
+
+// Kind returns the kind of the value.
+func (*ExprSelect) Kind() value.Kind {
+	return value.KindConstant
+}
@@ -61,6 +61,12 @@ const (
 	CallingConvPreserveAll  CallingConv = 15 // preserve_allcc
 	CallingConvSwift        CallingConv = 16 // swiftcc
 	CallingConvCXXFastTLS   CallingConv = 17 // cxx_fast_tlscc
+	// Note, the tailcc, cfguard_checkcc and swifttailcc keywords are not
+	// supported by the LLVM IR grammar of the asm package; use the equivalent
+	// numeric form.
+	CallingConvTail         CallingConv = 18 // cc 18
+	CallingConvCFGuardCheck CallingConv = 19 // cc 19
+	CallingConvSwiftTail    CallingConv = 20 // cc 20
 
 	// Start of target-specific calling conventions.
 	CallingConvFirstTarget = CallingConvX86StdCall
@@ -98,6 +104,12 @@ const (
 	CallingConvAMDGPU_LS         CallingConv = 95 // amdgpu_ls
 	CallingConvAMDGPU_ES         CallingConv = 96 // amdgpu_es
 	CallingConvAArch64VectorCall CallingConv = 97 // aarch64_vector_pcs
+	// Note, the aarch64_sve_vector_pcs and amdgpu_gfx keywords are not supported
+	// by the LLVM IR grammar of the asm package; use the equivalent numeric form.
+	CallingConvAArch64SVEVectorCall CallingConv = 98  // cc 98
+	CallingConvWASMEmscriptenInvoke CallingConv = 99  // cc 99
+	CallingConvAMDGPU_Gfx           CallingConv = 100 // cc 100
+	CallingConvM68kIntr             CallingConv = 101 // cc 101
 )
 
 //go:generate stringer -linecomment -type ChecksumKind
@@ -738,6 +750,21 @@ const (
 	FuncAttrStrictFP                                    // strictfp
 	FuncAttrUwtable                                     // uwtable
 	FuncAttrWriteOnly                                   // writeonly
+	// Function attributes of recent LLVM releases. Note, these are not yet
+	// supported by the grammar of the asm package; they may be constructed and
+	// printed, but not parsed.
+	FuncAttrHot                // hot
+	FuncAttrMustProgress       // mustprogress
+	FuncAttrNoCallback         // nocallback
+	FuncAttrNoFree             // nofree
+	FuncAttrNoMerge            // nomerge
+	FuncAttrNoProfile          // noprofile
+	FuncAttrNoSync             // nosync
+	FuncAttrNullPointerIsValid // null_pointer_is_valid
+	FuncAttrSanitizeMemTag     // sanitize_memtag
+	FuncAttrUwtableAsync       // uwtable(async)
+	FuncAttrUwtableSync        // uwtable(sync)
+	FuncAttrWillReturn         // willreturn
 )
 
 //go:generate stringer -linecomment -type IPred
@@ -781,6 +808,20 @@ const (
 	LinkageExternWeak // extern_weak
 )
 
+//go:generate stringer -linecomment -type ModRef
+
+// ModRef specifies the kind of memory access of a memory location (used in
+// memory attributes).
+type ModRef uint8
+
+// Memory access kinds.
+const (
+	ModRefNone      ModRef = iota // none
+	ModRefRead                    // read
+	ModRefWrite                   // write
+	ModRefReadWrite               // readwrite
+)
+
 //go:generate stringer -linecomment -type NameTableKind
 
 // NameTableKind is a name table specifier.
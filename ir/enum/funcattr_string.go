@@ -46,11 +46,23 @@ func _() {
 	_ = x[FuncAttrStrictFP-35]
 	_ = x[FuncAttrUwtable-36]
 	_ = x[FuncAttrWriteOnly-37]
+	_ = x[FuncAttrHot-38]
+	_ = x[FuncAttrMustProgress-39]
+	_ = x[FuncAttrNoCallback-40]
+	_ = x[FuncAttrNoFree-41]
+	_ = x[FuncAttrNoMerge-42]
+	_ = x[FuncAttrNoProfile-43]
+	_ = x[FuncAttrNoSync-44]
+	_ = x[FuncAttrNullPointerIsValid-45]
+	_ = x[FuncAttrSanitizeMemTag-46]
+	_ = x[FuncAttrUwtableAsync-47]
+	_ = x[FuncAttrUwtableSync-48]
+	_ = x[FuncAttrWillReturn-49]
 }
 
-const _FuncAttr_name = "alwaysinlineargmemonlybuiltincoldconvergentinaccessiblemem_or_argmemonlyinaccessiblememonlyinlinehintjumptableminsizenakednobuiltinnoduplicatenoimplicitfloatnoinlinenonlazybindnorecursenoredzonenoreturnnounwindoptnoneoptsizereadnonereadonlyreturns_twicesafestacksanitize_addresssanitize_hwaddresssanitize_memorysanitize_threadspeculatablespeculative_load_hardeningsspsspreqsspstrongstrictfpuwtablewriteonly"
+const _FuncAttr_name = "alwaysinlineargmemonlybuiltincoldconvergentinaccessiblemem_or_argmemonlyinaccessiblememonlyinlinehintjumptableminsizenakednobuiltinnoduplicatenoimplicitfloatnoinlinenonlazybindnorecursenoredzonenoreturnnounwindoptnoneoptsizereadnonereadonlyreturns_twicesafestacksanitize_addresssanitize_hwaddresssanitize_memorysanitize_threadspeculatablespeculative_load_hardeningsspsspreqsspstrongstrictfpuwtablewriteonlyhotmustprogressnocallbacknofreenomergenoprofilenosyncnull_pointer_is_validsanitize_memtaguwtable(async)uwtable(sync)willreturn"
 
-var _FuncAttr_index = [...]uint16{0, 12, 22, 29, 33, 43, 72, 91, 101, 110, 117, 122, 131, 142, 157, 165, 176, 185, 194, 202, 210, 217, 224, 232, 240, 253, 262, 278, 296, 311, 326, 338, 364, 367, 373, 382, 390, 397, 406}
+var _FuncAttr_index = [...]uint16{0, 12, 22, 29, 33, 43, 72, 91, 101, 110, 117, 122, 131, 142, 157, 165, 176, 185, 194, 202, 210, 217, 224, 232, 240, 253, 262, 278, 296, 311, 326, 338, 364, 367, 373, 382, 390, 397, 406, 409, 421, 431, 437, 444, 453, 459, 480, 495, 509, 522, 532}
 
 func (i FuncAttr) String() string {
 	if i >= FuncAttr(len(_FuncAttr_index)-1) {
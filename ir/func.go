@@ -67,11 +67,19 @@ type Func struct {
 	// (optional) Metadata.
 	Metadata
 
-	// Parent module; field set by ir.Module.NewFunc.
+	// Parent module; field set by ir.Module.NewFunc and
+	// ir.Module.ResolveParents.
 	Parent *Module
 
 	// mu prevents races on AssignIDs.
 	mu sync.Mutex
+	// dirty specifies whether the function has been modified since local IDs
+	// were last assigned; set by MarkDirty and cleared by AssignIDs.
+	dirty bool
+	// assigned specifies whether local IDs have been assigned by AssignIDs, in
+	// which case they are reassigned from scratch as local variables may since
+	// have been renamed.
+	assigned bool
 }
 
 // NewFunc returns a new function based on the given function name, return type
@@ -104,9 +112,25 @@ func (f *Func) Type() types.Type {
 	return f.Typ
 }
 
+// Entry returns the entry basic block of the function, which is always the
+// first basic block of f.Blocks; or nil if the function is a declaration.
+func (f *Func) Entry() *Block {
+	if len(f.Blocks) == 0 {
+		return nil
+	}
+	return f.Blocks[0]
+}
+
 // LLString returns the LLVM syntax representation of the function definition or
 // declaration.
 func (f *Func) LLString() string {
+	return f.llString(nil)
+}
+
+// llString returns the LLVM syntax representation of the function definition or
+// declaration, with the given comments printed before the basic blocks and
+// instructions of the function.
+func (f *Func) llString(comments Comments) string {
 	// Function declaration.
 	//
 	//    'declare' Metadata=MetadataAttachment* Header=FuncHeader
@@ -128,7 +152,7 @@ func (f *Func) LLString() string {
 		return buf.String()
 	}
 	// Function definition.
-	if err := f.AssignIDs(); err != nil {
+	if err := f.assignIDsIfDirty(); err != nil {
 		panic(fmt.Errorf("unable to assign IDs of function %q; %v", f.Ident(), err))
 	}
 	buf.WriteString("define")
@@ -139,17 +163,87 @@ func (f *Func) LLString() string {
 	for _, md := range f.Metadata {
 		fmt.Fprintf(buf, " %s", md)
 	}
-	fmt.Fprintf(buf, " %s", bodyString(f))
+	fmt.Fprintf(buf, " %s", bodyString(f, comments))
 	return buf.String()
 }
 
-// AssignIDs assigns IDs to unnamed local variables.
+// MarkDirty marks the function as modified, so that local IDs are reassigned
+// the next time the function is printed.
+//
+// Basic blocks and instructions added through the ir.Func and ir.Block builder
+// methods (e.g. ir.Func.NewBlock and ir.Block.NewAdd) mark the function as
+// modified automatically. MarkDirty must be called after direct modification of
+// the basic blocks or instructions of a function (e.g. by appending to
+// block.Insts) or after renaming local variables (using SetName).
+func (f *Func) MarkDirty() {
+	f.mu.Lock()
+	f.dirty = true
+	f.mu.Unlock()
+}
+
+// ResetIDs resets the IDs of unnamed local variables of the function and marks
+// the function as modified, so that IDs are reassigned the next time the
+// function is printed. ResetIDs should be called by transformations which
+// insert unnamed basic blocks or instructions before existing ones, or remove
+// unnamed basic blocks or instructions.
+func (f *Func) ResetIDs() {
+	reset := func(v interface{}) {
+		if n, ok := v.(local); ok && n.IsUnnamed() {
+			n.SetID(0)
+		}
+	}
+	for _, param := range f.Params {
+		reset(param)
+	}
+	for _, block := range f.Blocks {
+		reset(block)
+		for _, inst := range block.Insts {
+			reset(inst)
+		}
+		reset(block.Term)
+	}
+	f.MarkDirty()
+}
+
+// AssignIDs assigns IDs to unnamed local variables. An error is reported if
+// the names of named local variables or basic blocks are not unique.
 func (f *Func) AssignIDs() error {
 	if len(f.Blocks) == 0 {
 		return nil
 	}
+	if err := f.verifyLocalNames(); err != nil {
+		return errors.WithStack(err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.assignIDs()
+}
+
+// assignIDsIfDirty assigns IDs to unnamed local variables if the function has
+// been modified since IDs were last assigned.
+func (f *Func) assignIDsIfDirty() error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
+	if f.assigned && !f.dirty {
+		return nil
+	}
+	return f.assignIDs()
+}
+
+// assignIDs assigns IDs to unnamed local variables.
+//
+// pre-condition: f.mu is locked.
+func (f *Func) assignIDs() error {
+	if f.assigned {
+		// Reset previously assigned IDs, as local variables may have been renamed
+		// since.
+		f.walkLocals(func(n local) error {
+			if n.IsUnnamed() {
+				n.SetID(0)
+			}
+			return nil
+		})
+	}
 	id := int64(0)
 	setName := func(n local) error {
 		if n.IsUnnamed() {
@@ -163,16 +257,51 @@ func (f *Func) AssignIDs() error {
 		}
 		return nil
 	}
+	if err := f.walkLocals(setName); err != nil {
+		return errors.WithStack(err)
+	}
+	f.dirty = false
+	f.assigned = true
+	return nil
+}
+
+// verifyLocalNames reports an error if the names of named local variables
+// (i.e. parameters, basic blocks and non-void instructions and terminators) of
+// the function are not unique. Basic block labels share the namespace of local
+// variables.
+func (f *Func) verifyLocalNames() error {
+	names := make(map[string]bool)
+	return f.walkLocals(func(n local) error {
+		if n.IsUnnamed() {
+			return nil
+		}
+		name := n.Name()
+		if names[name] {
+			if _, ok := n.(*Block); ok {
+				return errors.Errorf("duplicate basic block label %s in function %s", n.Ident(), f.Ident())
+			}
+			return errors.Errorf("duplicate local variable name %s in function %s", n.Ident(), f.Ident())
+		}
+		names[name] = true
+		return nil
+	})
+}
+
+// walkLocals invokes visit for each local variable of the function which may be
+// assigned a local ID (i.e. parameters, basic blocks and non-void instructions
+// and terminators), in order of occurrence. Walking stops at the first error
+// returned by visit.
+func (f *Func) walkLocals(visit func(n local) error) error {
 	for _, param := range f.Params {
 		// Assign local IDs to unnamed parameters of function definitions.
-		if err := setName(param); err != nil {
-			return errors.WithStack(err)
+		if err := visit(param); err != nil {
+			return err
 		}
 	}
 	for _, block := range f.Blocks {
 		// Assign local IDs to unnamed basic blocks.
-		if err := setName(block); err != nil {
-			return errors.WithStack(err)
+		if err := visit(block); err != nil {
+			return err
 		}
 		for _, inst := range block.Insts {
 			n, ok := inst.(local)
@@ -186,8 +315,8 @@ func (f *Func) AssignIDs() error {
 				continue
 			}
 			// Assign local IDs to unnamed local variables.
-			if err := setName(n); err != nil {
-				return errors.WithStack(err)
+			if err := visit(n); err != nil {
+				return err
 			}
 		}
 		n, ok := block.Term.(local)
@@ -197,8 +326,8 @@ func (f *Func) AssignIDs() error {
 		if isVoidValue(n) {
 			continue
 		}
-		if err := setName(n); err != nil {
-			return errors.WithStack(err)
+		if err := visit(n); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -208,6 +337,14 @@ func (f *Func) AssignIDs() error {
 
 // headerString returns the string representation of the function header.
 func headerString(f *Func) string {
+	return funcHeaderString(f, false)
+}
+
+// funcHeaderString returns the string representation of the function header.
+// If canonical is set, parameters are printed as by LLVM; i.e. unnamed
+// parameters of function definitions with their local ID, and parameters of
+// function declarations without name.
+func funcHeaderString(f *Func, canonical bool) string {
 	// (Linkage | ExternLinkage)? Preemptionopt Visibilityopt DLLStorageClassopt
 	// CallingConvopt ReturnAttrs=ReturnAttribute* RetType=Type Name=GlobalIdent
 	// '(' Params ')' UnnamedAddropt AddrSpaceopt FuncAttrs=FuncAttribute*
@@ -234,7 +371,17 @@ func headerString(f *Func) string {
 		if i != 0 {
 			buf.WriteString(", ")
 		}
-		buf.WriteString(param.LLString())
+		switch {
+		case canonical && len(f.Blocks) == 0:
+			buf.WriteString(param.Typ.String())
+			for _, attr := range param.Attrs {
+				fmt.Fprintf(buf, " %s", attr)
+			}
+		case canonical && param.IsUnnamed():
+			fmt.Fprintf(buf, "%s %s", param.LLString(), param.Ident())
+		default:
+			buf.WriteString(param.LLString())
+		}
 	}
 	if f.Sig.Variadic {
 		if len(f.Params) > 0 {
@@ -274,8 +421,9 @@ func headerString(f *Func) string {
 	return buf.String()
 }
 
-// bodyString returns the string representation of the function body.
-func bodyString(body *Func) string {
+// bodyString returns the string representation of the function body, with the
+// given comments printed before the basic blocks and instructions.
+func bodyString(body *Func, comments Comments) string {
 	// '{' Blocks=Block+ UseListOrders=UseListOrder* '}'
 	buf := &strings.Builder{}
 	buf.WriteString("{\n")
@@ -283,7 +431,7 @@ func bodyString(body *Func) string {
 		if i != 0 {
 			buf.WriteString("\n")
 		}
-		fmt.Fprintf(buf, "%s\n", block.LLString())
+		fmt.Fprintf(buf, "%s\n", block.llString(comments))
 	}
 	if len(body.UseListOrders) > 0 {
 		buf.WriteString("\n")
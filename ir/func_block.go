@@ -1,5 +1,7 @@
 package ir
 
+import "fmt"
+
 // NewBlock appends a new basic block to the function based on the given label
 // name. An empty label name indicates an unnamed basic block.
 //
@@ -8,5 +10,34 @@ func (f *Func) NewBlock(name string) *Block {
 	block := NewBlock(name)
 	block.Parent = f
 	f.Blocks = append(f.Blocks, block)
+	f.MarkDirty()
 	return block
 }
+
+// AddBlocks appends the given basic blocks to the function, and marks the
+// function as modified once for the entire batch.
+//
+// The Parent field of each block is set to f. AddBlocks panics if a block
+// occurs more than once among the given blocks.
+func (f *Func) AddBlocks(blocks ...*Block) {
+	if len(blocks) == 0 {
+		return
+	}
+	seen := make(map[*Block]bool, len(blocks))
+	for _, block := range blocks {
+		if seen[block] {
+			panic(fmt.Errorf("duplicate basic block %s added to function %s", block.Ident(), f.Ident()))
+		}
+		seen[block] = true
+	}
+	if n := len(f.Blocks) + len(blocks); n > cap(f.Blocks) {
+		bs := make([]*Block, len(f.Blocks), n)
+		copy(bs, f.Blocks)
+		f.Blocks = bs
+	}
+	for _, block := range blocks {
+		block.Parent = f
+		f.Blocks = append(f.Blocks, block)
+	}
+	f.MarkDirty()
+}
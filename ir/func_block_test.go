@@ -0,0 +1,59 @@
+package ir_test
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestAddBlocks(t *testing.T) {
+	m := ir.NewModule()
+	f := m.NewFunc("f", types.Void)
+	entry := f.NewBlock("entry")
+	var blocks []*ir.Block
+	for i := 0; i < 3; i++ {
+		block := ir.NewBlock("")
+		block.NewRet(nil)
+		blocks = append(blocks, block)
+	}
+	entry.NewBr(blocks[0])
+	// Print function to assign IDs before adding blocks.
+	_ = f.LLString()
+	f.AddBlocks(blocks...)
+	if len(f.Blocks) != 4 {
+		t.Fatalf("number of basic blocks mismatch; expected 4, got %d", len(f.Blocks))
+	}
+	for i, block := range f.Blocks[1:] {
+		if block != blocks[i] {
+			t.Errorf("basic block %d mismatch; expected %p, got %p", i+1, blocks[i], block)
+		}
+		if block.Parent != f {
+			t.Errorf("parent mismatch of basic block %d; expected %v, got %v", i+1, f, block.Parent)
+		}
+	}
+	want := `define void @f() {
+entry:
+	br label %0
+
+; <label>:0
+	ret void
+
+; <label>:1
+	ret void
+
+; <label>:2
+	ret void
+}`
+	if got := f.LLString(); got != want {
+		t.Errorf("function mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+	// Duplicate basic blocks.
+	defer func() {
+		if e := recover(); e == nil {
+			t.Errorf("expected panic for duplicate basic blocks")
+		}
+	}()
+	block := ir.NewBlock("dup")
+	f.AddBlocks(block, block)
+}
@@ -0,0 +1,132 @@
+package ir
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+
+	"github.com/llir/llvm/ir/enum"
+)
+
+// ContentHash returns a SHA-256 hash of the contents of the function; i.e. its
+// signature, linkage and other header properties, function attributes
+// (including the contents of referenced attribute groups), and the
+// instructions and terminators of its basic blocks, with operands and
+// constants.
+//
+// The hash is independent of the names of local variables (parameters, basic
+// blocks and instructions), which are hashed by canonical numbering. It is
+// also independent of the order of basic blocks other than the entry block;
+// basic blocks are hashed in depth-first order of the control flow graph,
+// followed by unreachable basic blocks in order of occurrence. References to
+// global values, named types and metadata are hashed by identifier.
+//
+// The hash is stable for a given version of this package, and is intended as a
+// cache key (e.g. of compiled machine code). It is not guaranteed to be stable
+// across versions, as it is derived from the LLVM IR assembly representation
+// of the function, which may change; caches should be versioned accordingly.
+//
+// ContentHash temporarily renumbers the local variables of the function, and
+// must thus not be called concurrently with other uses of the function.
+func (f *Func) ContentHash() [32]byte {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	h := sha256.New()
+	if len(f.Blocks) == 0 {
+		io.WriteString(h, "declare")
+	} else {
+		io.WriteString(h, "define")
+	}
+	if f.Linkage != enum.LinkageNone {
+		fmt.Fprintf(h, " %s", f.Linkage)
+	}
+	blocks := canonicalBlocks(f)
+	// Renumber local variables in canonical order.
+	var locals []*LocalIdent
+	for _, param := range f.Params {
+		locals = append(locals, &param.LocalIdent)
+	}
+	for _, block := range blocks {
+		locals = append(locals, &block.LocalIdent)
+		for _, inst := range block.Insts {
+			if n, ok := inst.(localIdenter); ok && !isVoidValue(n) {
+				locals = append(locals, n.localIdent())
+			}
+		}
+		if n, ok := block.Term.(localIdenter); ok && !isVoidValue(n) {
+			locals = append(locals, n.localIdent())
+		}
+	}
+	saved := make([]LocalIdent, len(locals))
+	for i, ident := range locals {
+		saved[i] = *ident
+		ident.LocalName = ""
+		ident.LocalID = int64(i)
+	}
+	defer func() {
+		for i, ident := range locals {
+			*ident = saved[i]
+		}
+	}()
+	io.WriteString(h, headerString(f))
+	for _, attr := range f.FuncAttrs {
+		if def, ok := attr.(*AttrGroupDef); ok {
+			fmt.Fprintf(h, "\n%s", def.LLString())
+		}
+	}
+	for _, block := range blocks {
+		fmt.Fprintf(h, "\n%s", block.LLString())
+	}
+	for _, u := range f.UseListOrders {
+		fmt.Fprintf(h, "\n%s", u)
+	}
+	var sum [32]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+// ### [ Helper functions ] ####################################################
+
+// localIdenter is a local variable with an embedded local identifier.
+type localIdenter interface {
+	local
+	// localIdent returns the local identifier of the local variable.
+	localIdent() *LocalIdent
+}
+
+// localIdent returns the local identifier of the local variable.
+func (i *LocalIdent) localIdent() *LocalIdent {
+	return i
+}
+
+// canonicalBlocks returns the basic blocks of the function in canonical order;
+// i.e. in depth-first order of the control flow graph starting at the entry
+// basic block, followed by unreachable basic blocks in order of occurrence.
+func canonicalBlocks(f *Func) []*Block {
+	if len(f.Blocks) == 0 {
+		return nil
+	}
+	var blocks []*Block
+	visited := make(map[*Block]bool)
+	var visit func(block *Block)
+	visit = func(block *Block) {
+		if visited[block] {
+			return
+		}
+		visited[block] = true
+		blocks = append(blocks, block)
+		if block.Term == nil {
+			return
+		}
+		for _, succ := range block.Term.Succs() {
+			visit(succ)
+		}
+	}
+	visit(f.Blocks[0])
+	for _, block := range f.Blocks {
+		if !visited[block] {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
@@ -0,0 +1,57 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestFuncContentHash(t *testing.T) {
+	// newFunc returns a function computing max(x, c), with the given local
+	// names and with the basic blocks of the branches in the given order.
+	newFunc := func(names []string, swap bool, c int64) *Func {
+		x := NewParam(names[0], types.I32)
+		f := NewFunc("max", types.I32, x)
+		entry := f.NewBlock(names[1])
+		then := NewBlock(names[2])
+		els := NewBlock(names[3])
+		cond := entry.NewICmp(enum.IPredSGT, x, constant.NewInt(types.I32, c))
+		cond.SetName(names[4])
+		entry.NewCondBr(cond, then, els)
+		then.NewRet(x)
+		els.NewRet(constant.NewInt(types.I32, c))
+		if swap {
+			f.Blocks = append(f.Blocks, els, then)
+		} else {
+			f.Blocks = append(f.Blocks, then, els)
+		}
+		return f
+	}
+	f := newFunc([]string{"", "", "", "", ""}, false, 42)
+	before := f.LLString()
+	h := f.ContentHash()
+	if after := f.LLString(); before != after {
+		t.Errorf("function modified by ContentHash; expected %q, got %q", before, after)
+	}
+	// Same contents.
+	for _, g := range []*Func{
+		newFunc([]string{"x", "entry", "then", "else", "cond"}, false, 42),
+		newFunc([]string{"a", "", "b", "", "c"}, true, 42),
+	} {
+		if got := g.ContentHash(); h != got {
+			t.Errorf("content hash mismatch of functions with equal contents;\n%v\n%v", f.LLString(), g.LLString())
+		}
+	}
+	// Different contents.
+	g := newFunc([]string{"", "", "", "", ""}, false, 43)
+	if got := g.ContentHash(); h == got {
+		t.Errorf("content hash collision of functions with different contents;\n%v\n%v", f.LLString(), g.LLString())
+	}
+	g = newFunc([]string{"", "", "", "", ""}, false, 42)
+	g.FuncAttrs = append(g.FuncAttrs, enum.FuncAttrNoUnwind)
+	if got := g.ContentHash(); h == got {
+		t.Errorf("content hash collision of functions with different function attributes")
+	}
+}
@@ -0,0 +1,201 @@
+package ir
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/llir/llvm/ir/enum"
+)
+
+// Memory is a memory effects attribute, specifying the kind of memory access of
+// a function per memory location.
+//
+// The memory attribute supersedes the legacy readnone, readonly, writeonly,
+// argmemonly, inaccessiblememonly and inaccessiblemem_or_argmemonly function
+// attributes.
+//
+// Examples:
+//
+//    memory(none)
+//    memory(read, argmem: readwrite)
+//    memory(argmem: write, inaccessiblemem: read)
+type Memory struct {
+	// Memory access kind of memory locations not otherwise specified.
+	Default enum.ModRef
+	// Memory access kind of memory pointed to by pointer arguments.
+	ArgMem enum.ModRef
+	// Memory access kind of memory not accessible by the current module.
+	InaccessibleMem enum.ModRef
+}
+
+// NewMemory returns a new memory attribute with the given memory access kind
+// for all memory locations.
+func NewMemory(kind enum.ModRef) Memory {
+	return Memory{Default: kind, ArgMem: kind, InaccessibleMem: kind}
+}
+
+// String returns the string representation of the memory attribute.
+func (m Memory) String() string {
+	// 'memory' '(' MemoryEffects ')'
+	if m.ArgMem == m.Default && m.InaccessibleMem == m.Default {
+		return fmt.Sprintf("memory(%s)", m.Default)
+	}
+	var effects []string
+	if m.Default != enum.ModRefNone {
+		effects = append(effects, m.Default.String())
+	}
+	if m.ArgMem != m.Default {
+		effects = append(effects, fmt.Sprintf("argmem: %s", m.ArgMem))
+	}
+	if m.InaccessibleMem != m.Default {
+		effects = append(effects, fmt.Sprintf("inaccessiblemem: %s", m.InaccessibleMem))
+	}
+	return fmt.Sprintf("memory(%s)", strings.Join(effects, ", "))
+}
+
+// ModRef returns the union of memory access kinds of all memory locations.
+func (m Memory) ModRef() enum.ModRef {
+	return m.Default | m.ArgMem | m.InaccessibleMem
+}
+
+// DoesNotAccessMemory reports whether memory is not accessed at all.
+func (m Memory) DoesNotAccessMemory() bool {
+	return m.ModRef() == enum.ModRefNone
+}
+
+// OnlyReadsMemory reports whether memory is at most read.
+func (m Memory) OnlyReadsMemory() bool {
+	return m.ModRef()&enum.ModRefWrite == 0
+}
+
+// OnlyWritesMemory reports whether memory is at most written.
+func (m Memory) OnlyWritesMemory() bool {
+	return m.ModRef()&enum.ModRefRead == 0
+}
+
+// OnlyAccessesArgMemory reports whether only memory pointed to by pointer
+// arguments is accessed, if any.
+func (m Memory) OnlyAccessesArgMemory() bool {
+	return m.Default == enum.ModRefNone && m.InaccessibleMem == enum.ModRefNone
+}
+
+// OnlyAccessesInaccessibleMemory reports whether only memory not accessible by
+// the current module is accessed, if any.
+func (m Memory) OnlyAccessesInaccessibleMemory() bool {
+	return m.Default == enum.ModRefNone && m.ArgMem == enum.ModRefNone
+}
+
+// MemoryEffects returns the memory effects of the given function attributes,
+// as specified by either a memory attribute or by the legacy memory attributes
+// (e.g. readonly and argmemonly). Attributes of referenced attribute groups are
+// taken into account. Memory effects default to readwrite for all memory
+// locations if not specified.
+func MemoryEffects(attrs []FuncAttribute) Memory {
+	// Legacy memory access kind.
+	kind := enum.ModRefReadWrite
+	// Legacy memory locations.
+	argMem, inaccessibleMem, otherMem := true, true, true
+	var (
+		mem    Memory
+		hasMem bool
+	)
+	var visit func(attrs []FuncAttribute)
+	visit = func(attrs []FuncAttribute) {
+		for _, attr := range attrs {
+			switch attr := attr.(type) {
+			case Memory:
+				mem, hasMem = attr, true
+			case *AttrGroupDef:
+				visit(attr.FuncAttrs)
+			case enum.FuncAttr:
+				switch attr {
+				case enum.FuncAttrReadNone:
+					kind = enum.ModRefNone
+				case enum.FuncAttrReadOnly:
+					kind &= enum.ModRefRead
+				case enum.FuncAttrWriteOnly:
+					kind &= enum.ModRefWrite
+				case enum.FuncAttrArgMemOnly:
+					inaccessibleMem, otherMem = false, false
+				case enum.FuncAttrInaccessibleMemOnly:
+					argMem, otherMem = false, false
+				case enum.FuncAttrInaccessibleMemOrArgMemOnly:
+					otherMem = false
+				}
+			}
+		}
+	}
+	visit(attrs)
+	if hasMem {
+		return mem
+	}
+	mem = NewMemory(kind)
+	if !argMem {
+		mem.ArgMem = enum.ModRefNone
+	}
+	if !inaccessibleMem {
+		mem.InaccessibleMem = enum.ModRefNone
+	}
+	if !otherMem {
+		mem.Default = enum.ModRefNone
+	}
+	return mem
+}
+
+// NormalizeMemoryAttrs returns the given function attributes with the legacy
+// memory attributes (e.g. readonly and argmemonly) replaced by an equivalent
+// memory attribute. Attributes of referenced attribute groups are left
+// unmodified. If neither legacy memory attributes nor a memory attribute are
+// present, the function attributes are returned unmodified.
+func NormalizeMemoryAttrs(attrs []FuncAttribute) []FuncAttribute {
+	var (
+		// Attributes other than memory attributes.
+		normalized []FuncAttribute
+		// Memory attributes, excluding attribute groups.
+		memAttrs []FuncAttribute
+		// Position of the first memory attribute.
+		pos int
+	)
+	for _, attr := range attrs {
+		if !isMemoryAttr(attr) {
+			normalized = append(normalized, attr)
+			continue
+		}
+		if len(memAttrs) == 0 {
+			pos = len(normalized)
+		}
+		memAttrs = append(memAttrs, attr)
+	}
+	if len(memAttrs) == 0 {
+		return attrs
+	}
+	if len(memAttrs) == 1 {
+		if _, ok := memAttrs[0].(Memory); ok {
+			// Already normalized.
+			return attrs
+		}
+	}
+	normalized = append(normalized, nil)
+	copy(normalized[pos+1:], normalized[pos:])
+	normalized[pos] = MemoryEffects(memAttrs)
+	return normalized
+}
+
+// MemoryEffects returns the memory effects of the function, as specified by
+// its function attributes.
+func (f *Func) MemoryEffects() Memory {
+	return MemoryEffects(f.FuncAttrs)
+}
+
+// ### [ Helper functions ] ####################################################
+
+// isMemoryAttr reports whether the given function attribute is a memory
+// attribute or a legacy memory attribute.
+func isMemoryAttr(attr FuncAttribute) bool {
+	switch attr {
+	case enum.FuncAttrReadNone, enum.FuncAttrReadOnly, enum.FuncAttrWriteOnly, enum.FuncAttrArgMemOnly, enum.FuncAttrInaccessibleMemOnly, enum.FuncAttrInaccessibleMemOrArgMemOnly:
+		return true
+	}
+	_, ok := attr.(Memory)
+	return ok
+}
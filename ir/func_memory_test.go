@@ -0,0 +1,85 @@
+package ir
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestMemoryString(t *testing.T) {
+	golden := []struct {
+		in   Memory
+		want string
+	}{
+		{in: NewMemory(enum.ModRefNone), want: "memory(none)"},
+		{in: NewMemory(enum.ModRefReadWrite), want: "memory(readwrite)"},
+		{in: Memory{Default: enum.ModRefRead, ArgMem: enum.ModRefReadWrite, InaccessibleMem: enum.ModRefRead}, want: "memory(read, argmem: readwrite)"},
+		{in: Memory{ArgMem: enum.ModRefWrite, InaccessibleMem: enum.ModRefRead}, want: "memory(argmem: write, inaccessiblemem: read)"},
+		{in: Memory{Default: enum.ModRefRead, InaccessibleMem: enum.ModRefRead}, want: "memory(read, argmem: none)"},
+	}
+	for _, g := range golden {
+		if got := g.in.String(); g.want != got {
+			t.Errorf("memory attribute mismatch; expected %q, got %q", g.want, got)
+		}
+	}
+	m := NewModule()
+	f := m.NewFunc("f", types.Void)
+	f.FuncAttrs = append(f.FuncAttrs, Memory{Default: enum.ModRefRead, ArgMem: enum.ModRefReadWrite, InaccessibleMem: enum.ModRefRead}, enum.FuncAttrNoUnwind)
+	want := "declare void @f() memory(read, argmem: readwrite) nounwind"
+	if got := f.LLString(); want != got {
+		t.Errorf("function declaration mismatch; expected %q, got %q", want, got)
+	}
+}
+
+func TestMemoryEffects(t *testing.T) {
+	group := &AttrGroupDef{ID: 0, FuncAttrs: []FuncAttribute{enum.FuncAttrArgMemOnly}}
+	golden := []struct {
+		in   []FuncAttribute
+		want Memory
+	}{
+		{in: nil, want: NewMemory(enum.ModRefReadWrite)},
+		{in: []FuncAttribute{enum.FuncAttrNoUnwind}, want: NewMemory(enum.ModRefReadWrite)},
+		{in: []FuncAttribute{enum.FuncAttrReadNone}, want: NewMemory(enum.ModRefNone)},
+		{in: []FuncAttribute{enum.FuncAttrReadOnly}, want: NewMemory(enum.ModRefRead)},
+		{in: []FuncAttribute{enum.FuncAttrWriteOnly}, want: NewMemory(enum.ModRefWrite)},
+		{in: []FuncAttribute{enum.FuncAttrReadOnly, enum.FuncAttrWriteOnly}, want: NewMemory(enum.ModRefNone)},
+		{in: []FuncAttribute{enum.FuncAttrArgMemOnly, enum.FuncAttrReadOnly}, want: Memory{ArgMem: enum.ModRefRead}},
+		{in: []FuncAttribute{enum.FuncAttrInaccessibleMemOnly}, want: Memory{InaccessibleMem: enum.ModRefReadWrite}},
+		{in: []FuncAttribute{enum.FuncAttrInaccessibleMemOrArgMemOnly, enum.FuncAttrWriteOnly}, want: Memory{ArgMem: enum.ModRefWrite, InaccessibleMem: enum.ModRefWrite}},
+		{in: []FuncAttribute{enum.FuncAttrReadOnly, group}, want: Memory{ArgMem: enum.ModRefRead}},
+		{in: []FuncAttribute{NewMemory(enum.ModRefWrite)}, want: NewMemory(enum.ModRefWrite)},
+	}
+	for _, g := range golden {
+		if got := MemoryEffects(g.in); g.want != got {
+			t.Errorf("memory effects mismatch of %v; expected %v, got %v", g.in, g.want, got)
+		}
+	}
+	mem := Memory{ArgMem: enum.ModRefRead}
+	if mem.DoesNotAccessMemory() || !mem.OnlyReadsMemory() || mem.OnlyWritesMemory() || !mem.OnlyAccessesArgMemory() || mem.OnlyAccessesInaccessibleMemory() {
+		t.Errorf("memory effects queries mismatch of %v", mem)
+	}
+	if !NewMemory(enum.ModRefNone).DoesNotAccessMemory() {
+		t.Errorf("expected memory(none) to not access memory")
+	}
+}
+
+func TestNormalizeMemoryAttrs(t *testing.T) {
+	group := &AttrGroupDef{ID: 0, FuncAttrs: []FuncAttribute{enum.FuncAttrReadOnly}}
+	golden := []struct {
+		in   []FuncAttribute
+		want string
+	}{
+		{in: []FuncAttribute{enum.FuncAttrNoUnwind}, want: "[nounwind]"},
+		{in: []FuncAttribute{enum.FuncAttrNoUnwind, enum.FuncAttrReadOnly, enum.FuncAttrArgMemOnly, enum.FuncAttrWillReturn}, want: "[nounwind memory(argmem: read) willreturn]"},
+		{in: []FuncAttribute{enum.FuncAttrReadNone, enum.FuncAttrNoUnwind}, want: "[memory(none) nounwind]"},
+		{in: []FuncAttribute{group, enum.FuncAttrWriteOnly}, want: "[#0 memory(write)]"},
+		{in: []FuncAttribute{NewMemory(enum.ModRefRead)}, want: "[memory(read)]"},
+	}
+	for _, g := range golden {
+		if got := fmt.Sprint(NormalizeMemoryAttrs(g.in)); g.want != got {
+			t.Errorf("normalized attributes mismatch of %v; expected %q, got %q", g.in, g.want, got)
+		}
+	}
+}
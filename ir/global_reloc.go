@@ -0,0 +1,31 @@
+package ir
+
+import (
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/value"
+)
+
+// --- [ Initializer relocations ] ---------------------------------------------
+
+// InitializerRelocations returns the global values (global variables,
+// functions, aliases and IFuncs) referenced by the initializer of the global
+// variable; i.e. the addresses which require relocation when emitting the
+// initializer to an object file. References are located through constant
+// expressions (e.g. getelementptr and bitcast) and aggregate constants (e.g.
+// structs, arrays and vectors). The function of a blockaddress constant is
+// included among the references.
+//
+// Each global value is included once, in order of first occurrence in the
+// initializer. A nil slice is returned for global variable declarations.
+func (g *Global) InitializerRelocations() []value.Value {
+	var refs []value.Value
+	visit := func(c constant.Constant) {
+		switch c := c.(type) {
+		case *Global, *Func, *Alias, *IFunc:
+			refs = append(refs, c)
+		}
+	}
+	r := &constReplacer{visited: make(map[constant.Constant]bool), visit: visit}
+	r.replaceConst(&g.Init)
+	return refs
+}
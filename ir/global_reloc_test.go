@@ -0,0 +1,63 @@
+package ir_test
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/asm"
+)
+
+func TestInitializerRelocations(t *testing.T) {
+	const content = `@q = global i32 0
+@p = global i32* @q
+@arr = global [4 x i32] zeroinitializer
+@s = global { i8*, [3 x i32*] } { i8* bitcast (void ()* @f to i8*), [3 x i32*] [i32* getelementptr ([4 x i32], [4 x i32]* @arr, i64 0, i64 1), i32* @q, i32* null] }
+@b = global i8* blockaddress(@f, %entry)
+@n = global i32 42
+@e = external global i32*
+
+define void @f() {
+entry:
+	ret void
+}
+`
+	m, err := asm.ParseString("", content)
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	// Round-trip of global variables referencing other global values.
+	if got := m.String(); got != content {
+		t.Errorf("module mismatch; expected:\n%s\ngot:\n%s", content, got)
+	}
+	golden := []struct {
+		name string
+		want []string
+	}{
+		{name: "q"},
+		{name: "p", want: []string{"@q"}},
+		{name: "arr"},
+		{name: "s", want: []string{"@f", "@arr", "@q"}},
+		{name: "b", want: []string{"@f"}},
+		{name: "n"},
+		{name: "e"},
+	}
+	for _, g := range golden {
+		global, ok := m.Global(g.name)
+		if !ok {
+			t.Fatalf("unable to locate global variable %q", g.name)
+		}
+		var got []string
+		for _, ref := range global.InitializerRelocations() {
+			got = append(got, ref.Ident())
+		}
+		if len(got) != len(g.want) {
+			t.Errorf("relocations mismatch of %q; expected %v, got %v", global.Ident(), g.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != g.want[i] {
+				t.Errorf("relocations mismatch of %q; expected %v, got %v", global.Ident(), g.want, got)
+				break
+			}
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/llir/llvm/ir/metadata"
 	"github.com/llir/llvm/ir/types"
 	"github.com/llir/llvm/ir/value"
+	"github.com/pkg/errors"
 )
 
 // Align is a memory alignment attribute.
@@ -133,6 +134,7 @@ type ExceptionScope interface {
 //    ir.Align
 //    ir.AlignStack
 //    ir.AllocSize
+//    ir.Memory
 //    enum.FuncAttr
 type FuncAttribute interface {
 	fmt.Stringer
@@ -153,12 +155,8 @@ func (i GlobalIdent) Ident() string {
 		id := strconv.FormatInt(i.GlobalID, 10)
 		return enc.Global(id)
 	}
-	name := i.GlobalName
-	if x, err := strconv.ParseInt(name, 10, 64); err == nil {
-		// Print GlobalName with quotes if it is a number; e.g. @"42".
-		return fmt.Sprintf(`@"%d"`, x)
-	}
-	return enc.Global(name)
+	// Print GlobalName with quotes if it is a number; e.g. @"42".
+	return "@" + QuoteIdent(i.GlobalName)
 }
 
 // Name returns the name of the global identifier.
@@ -197,6 +195,28 @@ func (i GlobalIdent) IsUnnamed() bool {
 	return len(i.GlobalName) == 0
 }
 
+// InstParent tracks the parent basic block of an instruction or terminator.
+//
+// The parent basic block is only reliable for instructions and terminators
+// created through the builder methods of ir.Block (e.g. ir.Block.NewAdd), which
+// append the instruction to the basic block and set its parent, or after
+// back-filling parent pointers using ir.Module.ResolveParents.
+type InstParent struct {
+	// Parent basic block; or nil if not set.
+	Parent *Block
+}
+
+// GetParent returns the parent basic block of the instruction or terminator; or
+// nil if not set.
+func (p InstParent) GetParent() *Block {
+	return p.Parent
+}
+
+// setParent sets the parent basic block of the instruction or terminator.
+func (p *InstParent) setParent(parent *Block) {
+	p.Parent = parent
+}
+
 // LocalIdent is a local identifier.
 type LocalIdent struct {
 	LocalName string
@@ -209,12 +229,8 @@ func (i LocalIdent) Ident() string {
 		id := strconv.FormatInt(i.LocalID, 10)
 		return enc.Local(id)
 	}
-	name := i.LocalName
-	if x, err := strconv.ParseInt(name, 10, 64); err == nil {
-		// Print LocalName with quotes if it is a number; e.g. %"42".
-		return fmt.Sprintf(`%%"%d"`, x)
-	}
-	return enc.Local(name)
+	// Print LocalName with quotes if it is a number; e.g. %"42".
+	return "%" + QuoteIdent(i.LocalName)
 }
 
 // Name returns the name of the local identifier.
@@ -233,6 +249,11 @@ func (i LocalIdent) Name() string {
 }
 
 // SetName sets the name of the local identifier.
+//
+// A non-empty name marks the local identifier as named, so that it is not
+// assigned a local ID. An empty name marks the local identifier as unnamed. The
+// parent function should be marked as modified (using ir.Func.MarkDirty) so that
+// local IDs are reassigned the next time it is printed.
 func (i *LocalIdent) SetName(name string) {
 	i.LocalName = name
 	i.LocalID = 0
@@ -261,6 +282,30 @@ func (mds Metadata) MDAttachments() []*metadata.Attachment {
 	return mds
 }
 
+// attachment returns the metadata node of the metadata attachment with the
+// given name. The boolean return value indicates success.
+func (mds Metadata) attachment(name string) (metadata.MDNode, bool) {
+	for _, md := range mds {
+		if md.Name == name {
+			return md.Node, true
+		}
+	}
+	return nil, false
+}
+
+// setAttachment sets the metadata node of the metadata attachment with the
+// given name, replacing any existing metadata attachment of the same name.
+func (mds *Metadata) setAttachment(name string, node metadata.MDNode) {
+	attachment := &metadata.Attachment{Name: name, Node: node}
+	for i, md := range *mds {
+		if md.Name == name {
+			(*mds)[i] = attachment
+			return
+		}
+	}
+	*mds = append(*mds, attachment)
+}
+
 // OperandBundle is an operand bundle.
 type OperandBundle struct {
 	Tag    string
@@ -355,12 +400,44 @@ type Param struct {
 	Attrs []ParamAttribute
 }
 
-// NewParam returns a new function parameter based on the given name and type.
-func NewParam(name string, typ types.Type) *Param {
-	return &Param{
+// NewParam returns a new function parameter based on the given name, type and
+// optional parameter attributes.
+//
+// NewParam panics if a parameter attribute is not legal for the parameter type
+// (e.g. signext on a pointer parameter); use NewParamErr to handle such errors.
+func NewParam(name string, typ types.Type, attrs ...ParamAttribute) *Param {
+	p, err := NewParamErr(name, typ, attrs...)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// NewParamErr returns a new function parameter based on the given name, type
+// and optional parameter attributes. An error is returned if a parameter
+// attribute is not legal for the parameter type.
+func NewParamErr(name string, typ types.Type, attrs ...ParamAttribute) (*Param, error) {
+	p := &Param{
 		LocalIdent: LocalIdent{LocalName: name},
 		Typ:        typ,
 	}
+	if err := p.AddAttr(attrs...); err != nil {
+		return nil, errors.Wrap(err, "unable to create function parameter")
+	}
+	return p, nil
+}
+
+// AddAttr adds the given parameter attributes to the function parameter. An
+// error is returned if a parameter attribute is not legal for the parameter
+// type, in which case no attributes are added.
+func (p *Param) AddAttr(attrs ...ParamAttribute) error {
+	for _, attr := range attrs {
+		if err := verifyParamAttr(p.Typ, attr); err != nil {
+			return errors.Wrapf(err, "invalid parameter attribute of %s", p.Ident())
+		}
+	}
+	p.Attrs = append(p.Attrs, attrs...)
+	return nil
 }
 
 // String returns the LLVM syntax representation of the function parameter as a
@@ -419,12 +496,16 @@ func unquote(s string) string {
 // callingConvString returns the string representation of the given calling
 // convention.
 func callingConvString(callingConv enum.CallingConv) string {
-	s := callingConv.String()
-	cc := uint(callingConv)
-	if unknown := fmt.Sprintf("CallingConv(%d)", cc); s == unknown {
-		return fmt.Sprintf("cc %d", cc)
+	if !isKnownCallingConv(callingConv) {
+		return fmt.Sprintf("cc %d", uint(callingConv))
 	}
-	return s
+	return callingConv.String()
+}
+
+// isKnownCallingConv reports whether the given calling convention is a known
+// calling convention of the enum package.
+func isKnownCallingConv(callingConv enum.CallingConv) bool {
+	return callingConv.String() != fmt.Sprintf("CallingConv(%d)", uint(callingConv))
 }
 
 // tlsModelString returns the string representation of the given thread local
@@ -27,6 +27,9 @@ type InstExtractValue struct {
 	Typ types.Type
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewExtractValue returns a new extractvalue instruction based on the given
@@ -88,6 +91,9 @@ type InstInsertValue struct {
 	Typ types.Type
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewInsertValue returns a new insertvalue instruction based on the given
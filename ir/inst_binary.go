@@ -28,6 +28,9 @@ type InstAdd struct {
 	OverflowFlags []enum.OverflowFlag
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewAdd returns a new add instruction based on the given operands.
@@ -87,6 +90,9 @@ type InstFAdd struct {
 	FastMathFlags []enum.FastMathFlag
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewFAdd returns a new fadd instruction based on the given operands.
@@ -146,6 +152,9 @@ type InstSub struct {
 	OverflowFlags []enum.OverflowFlag
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewSub returns a new sub instruction based on the given operands.
@@ -205,6 +214,9 @@ type InstFSub struct {
 	FastMathFlags []enum.FastMathFlag
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewFSub returns a new fsub instruction based on the given operands.
@@ -264,6 +276,9 @@ type InstMul struct {
 	OverflowFlags []enum.OverflowFlag
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewMul returns a new mul instruction based on the given operands.
@@ -323,6 +338,9 @@ type InstFMul struct {
 	FastMathFlags []enum.FastMathFlag
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewFMul returns a new fmul instruction based on the given operands.
@@ -382,6 +400,9 @@ type InstUDiv struct {
 	Exact bool
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewUDiv returns a new udiv instruction based on the given operands.
@@ -441,6 +462,9 @@ type InstSDiv struct {
 	Exact bool
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewSDiv returns a new sdiv instruction based on the given operands.
@@ -500,6 +524,9 @@ type InstFDiv struct {
 	FastMathFlags []enum.FastMathFlag
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewFDiv returns a new fdiv instruction based on the given operands.
@@ -557,6 +584,9 @@ type InstURem struct {
 	Typ types.Type
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewURem returns a new urem instruction based on the given operands.
@@ -609,6 +639,9 @@ type InstSRem struct {
 	Typ types.Type
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewSRem returns a new srem instruction based on the given operands.
@@ -663,6 +696,9 @@ type InstFRem struct {
 	FastMathFlags []enum.FastMathFlag
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewFRem returns a new frem instruction based on the given operands.
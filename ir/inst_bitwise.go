@@ -28,6 +28,9 @@ type InstShl struct {
 	OverflowFlags []enum.OverflowFlag
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewShl returns a new shl instruction based on the given operands.
@@ -87,6 +90,9 @@ type InstLShr struct {
 	Exact bool
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewLShr returns a new lshr instruction based on the given operands.
@@ -146,6 +152,9 @@ type InstAShr struct {
 	Exact bool
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewAShr returns a new ashr instruction based on the given operands.
@@ -203,6 +212,9 @@ type InstAnd struct {
 	Typ types.Type
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewAnd returns a new and instruction based on the given operands.
@@ -255,6 +267,9 @@ type InstOr struct {
 	Typ types.Type
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewOr returns a new or instruction based on the given operands.
@@ -307,6 +322,9 @@ type InstXor struct {
 	Typ types.Type
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewXor returns a new xor instruction based on the given operands.
@@ -0,0 +1,72 @@
+package ir
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/value"
+)
+
+// === [ Cloning ] =============================================================
+
+// CloneInst returns a copy of the given instruction or terminator, with the
+// same operands, flags (e.g. nsw, exact, inbounds and fast-math flags),
+// attributes and metadata attachments. The clone is unnamed and has no parent
+// basic block.
+//
+// Slices of the instruction (e.g. call arguments, phi incoming values, switch
+// cases and metadata attachments) are copied, so that operands of the clone
+// may be replaced without affecting the original instruction.
+func CloneInst(inst Instruction) Instruction {
+	v := reflect.ValueOf(inst)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		panic(fmt.Errorf("support for instruction %T not yet implemented", inst))
+	}
+	c := reflect.New(v.Elem().Type())
+	c.Elem().Set(v.Elem())
+	copySlices(c.Elem())
+	clone := c.Interface().(Instruction)
+	if n, ok := clone.(value.Named); ok {
+		n.SetName("")
+	}
+	setParent(clone, nil)
+	return clone
+}
+
+// ### [ Helper functions ] ####################################################
+
+// clonedElemTypes specifies the element types of slice fields which are copied
+// by value when cloning instructions, as their fields may be replaced in place
+// (e.g. by assigning through the operand pointers returned by Operands).
+var clonedElemTypes = map[reflect.Type]bool{
+	reflect.TypeOf(Incoming{}):            true,
+	reflect.TypeOf(Case{}):                true,
+	reflect.TypeOf(Clause{}):              true,
+	reflect.TypeOf(OperandBundle{}):       true,
+	reflect.TypeOf(metadata.Attachment{}): true,
+}
+
+// copySlices replaces the exported slice fields of the given struct value by
+// copies, recursively copying elements pointing to values of clonedElemTypes.
+func copySlices(s reflect.Value) {
+	for i := 0; i < s.NumField(); i++ {
+		field := s.Field(i)
+		if field.Kind() != reflect.Slice || field.IsNil() || !field.CanSet() {
+			continue
+		}
+		elems := reflect.MakeSlice(field.Type(), field.Len(), field.Len())
+		reflect.Copy(elems, field)
+		for j := 0; j < elems.Len(); j++ {
+			elem := elems.Index(j)
+			if elem.Kind() != reflect.Ptr || elem.IsNil() || !clonedElemTypes[elem.Type().Elem()] {
+				continue
+			}
+			p := reflect.New(elem.Type().Elem())
+			p.Elem().Set(elem.Elem())
+			copySlices(p.Elem())
+			elem.Set(p)
+		}
+		field.Set(elems)
+	}
+}
@@ -0,0 +1,58 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestCloneInst(t *testing.T) {
+	x := NewParam("x", types.Float)
+	y := NewParam("y", types.Float)
+	f := NewFunc("f", types.Float, x, y)
+	entry := f.NewBlock("entry")
+	exit := f.NewBlock("exit")
+	dbg := &metadata.Attachment{Name: "dbg", Node: &metadata.Tuple{MetadataID: -1}}
+	mul := entry.NewFMul(x, y)
+	mul.SetName("m")
+	mul.FastMathFlags = []enum.FastMathFlag{enum.FastMathFlagFast}
+	mul.Metadata = append(mul.Metadata, dbg)
+	add := entry.NewAdd(constant.NewInt(types.I32, 1), constant.NewInt(types.I32, 2))
+	add.OverflowFlags = []enum.OverflowFlag{enum.OverflowFlagNUW, enum.OverflowFlagNSW}
+	entry.NewBr(exit)
+	phi := exit.NewPhi(NewIncoming(mul, entry))
+	exit.NewRet(phi)
+
+	// Flags and metadata attachments are preserved.
+	c := CloneInst(mul).(*InstFMul)
+	if want, got := "%0 = fmul fast float %x, %y, !dbg !{}", c.LLString(); want != got {
+		t.Errorf("clone mismatch of fmul; expected %q, got %q", want, got)
+	}
+	if c.Parent != nil {
+		t.Errorf("expected nil parent of clone, got %v", c.Parent.Ident())
+	}
+	if want, got := "%0 = add nuw nsw i32 1, 2", CloneInst(add).LLString(); want != got {
+		t.Errorf("clone mismatch of add; expected %q, got %q", want, got)
+	}
+	// Flags, metadata and operands of the clone are independent of the
+	// original.
+	c.FastMathFlags[0] = enum.FastMathFlagNNaN
+	c.Metadata[0].Name = "foo"
+	*Operands(c)[0] = y
+	if want, got := "%m = fmul fast float %x, %y, !dbg !{}", mul.LLString(); want != got {
+		t.Errorf("original fmul modified by clone; expected %q, got %q", want, got)
+	}
+	cp := CloneInst(phi).(*InstPhi)
+	*Operands(cp)[0] = x
+	if phi.Incs[0].X != mul {
+		t.Errorf("original phi modified by clone; expected incoming value %v, got %v", mul.Ident(), phi.Incs[0].X.Ident())
+	}
+	// Terminators are cloned with their successors.
+	br := CloneInst(entry.Term.(Instruction)).(*TermBr)
+	if br.Target != exit {
+		t.Errorf("clone mismatch of br; expected target %v, got %v", exit.Ident(), br.Target.Ident())
+	}
+}
@@ -25,6 +25,9 @@ type InstTrunc struct {
 
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewTrunc returns a new trunc instruction based on the given source value and
@@ -98,6 +101,9 @@ type InstZExt struct {
 
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewZExt returns a new zext instruction based on the given source value and
@@ -144,6 +150,9 @@ type InstSExt struct {
 
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewSExt returns a new sext instruction based on the given source value and
@@ -190,6 +199,9 @@ type InstFPTrunc struct {
 
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewFPTrunc returns a new fptrunc instruction based on the given source value
@@ -236,6 +248,9 @@ type InstFPExt struct {
 
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewFPExt returns a new fpext instruction based on the given source value and
@@ -282,6 +297,9 @@ type InstFPToUI struct {
 
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewFPToUI returns a new fptoui instruction based on the given source value
@@ -328,6 +346,9 @@ type InstFPToSI struct {
 
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewFPToSI returns a new fptosi instruction based on the given source value
@@ -374,6 +395,9 @@ type InstUIToFP struct {
 
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewUIToFP returns a new uitofp instruction based on the given source value
@@ -420,6 +444,9 @@ type InstSIToFP struct {
 
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewSIToFP returns a new sitofp instruction based on the given source value
@@ -466,6 +493,9 @@ type InstPtrToInt struct {
 
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewPtrToInt returns a new ptrtoint instruction based on the given source
@@ -512,6 +542,9 @@ type InstIntToPtr struct {
 
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewIntToPtr returns a new inttoptr instruction based on the given source
@@ -558,6 +591,9 @@ type InstBitCast struct {
 
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewBitCast returns a new bitcast instruction based on the given source value
@@ -604,6 +640,9 @@ type InstAddrSpaceCast struct {
 
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewAddrSpaceCast returns a new addrspacecast instruction based on the given
@@ -36,6 +36,9 @@ type InstAlloca struct {
 	Align Align
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewAlloca returns a new alloca instruction based on the given element type.
@@ -115,6 +118,9 @@ type InstLoad struct {
 	Align Align
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewLoad returns a new load instruction based on the given source address.
@@ -204,6 +210,9 @@ type InstStore struct {
 	Align Align
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewStore returns a new store instruction based on the given source value and
@@ -269,6 +278,9 @@ type InstFence struct {
 	SyncScope string
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewFence returns a new fence instruction based on the given atomic ordering.
@@ -322,6 +334,9 @@ type InstCmpXchg struct {
 	SyncScope string
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewCmpXchg returns a new cmpxchg instruction based on the given address,
@@ -401,6 +416,9 @@ type InstAtomicRMW struct {
 	SyncScope string
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewAtomicRMW returns a new atomicrmw instruction based on the given atomic
@@ -473,6 +491,9 @@ type InstGetElementPtr struct {
 	InBounds bool
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewGetElementPtr returns a new getelementptr instruction based on the given
@@ -509,7 +530,7 @@ func (inst *InstGetElementPtr) Type() types.Type {
 	}
 	// Cache type if not present.
 	if inst.Typ == nil {
-		inst.Typ = gepType(inst.ElemType, inst.Indices)
+		inst.Typ = gepType(inst.ElemType, srcAddrSpace(inst.Src.Type()), inst.Indices)
 	}
 	return inst.Typ
 }
@@ -538,8 +559,9 @@ func (inst *InstGetElementPtr) LLString() string {
 
 // gepType returns the pointer type or vector of pointers type to the element at
 // the position in the type specified by the given indices, as calculated by the
-// getelementptr instruction.
-func gepType(elemType types.Type, indices []value.Value) types.Type {
+// getelementptr instruction. The resulting pointer type is in the given address
+// space of the source address.
+func gepType(elemType types.Type, addrSpace types.AddrSpace, indices []value.Value) types.Type {
 	e := elemType
 	for i, index := range indices {
 		if i == 0 {
@@ -596,10 +618,26 @@ func gepType(elemType types.Type, indices []value.Value) types.Type {
 	//    %113 = getelementptr inbounds %struct.fileinfo, %struct.fileinfo* %96, <2 x i64> %110, !dbg !4736
 	//    %116 = bitcast i8** %115 to <2 x %struct.fileinfo*>*, !dbg !4738
 	//    store <2 x %struct.fileinfo*> %113, <2 x %struct.fileinfo*>* %116, align 8, !dbg !4738, !tbaa !1793
+	ptr := types.NewPointer(e)
+	ptr.AddrSpace = addrSpace
 	if len(indices) > 0 {
 		if t, ok := indices[0].Type().(*types.VectorType); ok {
-			return types.NewVector(t.Len, types.NewPointer(e))
+			return types.NewVector(t.Len, ptr)
 		}
 	}
-	return types.NewPointer(e)
+	return ptr
+}
+
+// srcAddrSpace returns the address space of the given source address type of a
+// getelementptr instruction (pointer type or vector of pointers type).
+func srcAddrSpace(srcType types.Type) types.AddrSpace {
+	switch t := srcType.(type) {
+	case *types.PointerType:
+		return t.AddrSpace
+	case *types.VectorType:
+		if elem, ok := t.ElemType.(*types.PointerType); ok {
+			return elem.AddrSpace
+		}
+	}
+	return 0
 }
@@ -28,6 +28,9 @@ type InstICmp struct {
 	Typ types.Type // boolean or boolean vector
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewICmp returns a new icmp instruction based on the given integer comparison
@@ -93,6 +96,9 @@ type InstFCmp struct {
 	FastMathFlags []enum.FastMathFlag
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewFCmp returns a new fcmp instruction based on the given floating-point
@@ -158,6 +164,9 @@ type InstPhi struct {
 	Typ types.Type // type of incoming value
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewPhi returns a new phi instruction based on the given incoming values.
@@ -241,6 +250,9 @@ type InstSelect struct {
 	Typ types.Type
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewSelect returns a new select instruction based on the given selection
@@ -302,6 +314,11 @@ type InstCall struct {
 	// Type of result produced by the instruction, or function signature of the
 	// callee (as used when callee is variadic).
 	Typ types.Type
+	// (optional) Explicit function type of the callee; nil if not present. If
+	// present, the function type is used instead of the type of the callee
+	// value to determine the return type and parameter types of the call, and
+	// is always emitted (e.g. `call i32 (i32) %fp(i32 %x)`).
+	FuncType *types.FuncType
 	// (optional) Tail; zero if not present.
 	Tail enum.Tail
 	// (optional) Fast math flags.
@@ -318,11 +335,19 @@ type InstCall struct {
 	OperandBundles []*OperandBundle
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewCall returns a new call instruction based on the given callee and function
 // arguments.
 //
+// The function arguments are not checked against the function signature of the
+// call, as the explicit function type (FuncType) may be set after construction;
+// use Func.Verify or Module.Verify to report argument count and type
+// mismatches.
+//
 // TODO: specify the set of underlying types of callee.
 func NewCall(callee value.Value, args ...value.Value) *InstCall {
 	inst := &InstCall{Callee: callee, Args: args}
@@ -331,6 +356,48 @@ func NewCall(callee value.Value, args ...value.Value) *InstCall {
 	return inst
 }
 
+// WithCC sets the calling convention of the call instruction, and returns the
+// call instruction to allow chaining. The calling convention is cleared if cc
+// is enum.CallingConvNone. WithCC panics if cc is not a known calling
+// convention.
+//
+// Example:
+//
+//    block.NewCall(f, x).WithCC(enum.CallingConvFast).WithTail(enum.TailTail)
+func (inst *InstCall) WithCC(cc enum.CallingConv) *InstCall {
+	if cc != enum.CallingConvNone && !isKnownCallingConv(cc) {
+		panic(fmt.Errorf("invalid calling convention %d of call %s; unknown calling convention", uint(cc), inst.Ident()))
+	}
+	inst.CallingConv = cc
+	return inst
+}
+
+// WithReturnAttrs sets the return attributes of the call instruction, and
+// returns the call instruction to allow chaining. The return attributes are
+// cleared if no attributes are given. WithReturnAttrs panics if a return
+// attribute is nil.
+func (inst *InstCall) WithReturnAttrs(attrs ...ReturnAttribute) *InstCall {
+	for _, attr := range attrs {
+		if attr == nil {
+			panic(fmt.Errorf("invalid return attribute of call %s; nil return attribute", inst.Ident()))
+		}
+	}
+	inst.ReturnAttrs = attrs
+	return inst
+}
+
+// WithTail sets the tail call attribute (tail, musttail or notail) of the call
+// instruction, and returns the call instruction to allow chaining. The tail
+// call attribute is cleared if t is enum.TailNone. WithTail panics if t is not
+// a known tail call attribute.
+func (inst *InstCall) WithTail(t enum.Tail) *InstCall {
+	if t > enum.TailTail {
+		panic(fmt.Errorf("invalid tail call attribute %d of call %s; unknown tail call attribute", uint(t), inst.Ident()))
+	}
+	inst.Tail = t
+	return inst
+}
+
 // String returns the LLVM syntax representation of the instruction as a
 // type-value pair.
 func (inst *InstCall) String() string {
@@ -341,14 +408,7 @@ func (inst *InstCall) String() string {
 func (inst *InstCall) Type() types.Type {
 	// Cache type if not present.
 	if inst.Typ == nil {
-		t, ok := inst.Callee.Type().(*types.PointerType)
-		if !ok {
-			panic(fmt.Errorf("invalid callee type; expected *types.PointerType, got %T", inst.Callee.Type()))
-		}
-		sig, ok := t.ElemType.(*types.FuncType)
-		if !ok {
-			panic(fmt.Errorf("invalid callee type; expected *types.FuncType, got %T", t.ElemType))
-		}
+		sig := inst.Sig()
 		if sig.Variadic {
 			inst.Typ = sig
 		} else {
@@ -361,6 +421,28 @@ func (inst *InstCall) Type() types.Type {
 	return inst.Typ
 }
 
+// Sig returns the function signature of the call; i.e. the explicit function
+// type of the callee if present, and the function type pointed to by the type
+// of the callee value otherwise.
+func (inst *InstCall) Sig() *types.FuncType {
+	if inst.FuncType != nil {
+		return inst.FuncType
+	}
+	if sig, ok := inst.Callee.Type().(*types.FuncType); ok {
+		// Inline assembler expression of function type.
+		return sig
+	}
+	t, ok := inst.Callee.Type().(*types.PointerType)
+	if !ok {
+		panic(fmt.Errorf("invalid callee type; expected *types.PointerType, got %T", inst.Callee.Type()))
+	}
+	sig, ok := t.ElemType.(*types.FuncType)
+	if !ok {
+		panic(fmt.Errorf("invalid callee type; expected *types.FuncType, got %T", t.ElemType))
+	}
+	return sig
+}
+
 // LLString returns the LLVM syntax representation of the instruction.
 func (inst *InstCall) LLString() string {
 	// Tailopt 'call' FastMathFlags=FastMathFlag* CallingConvopt
@@ -384,13 +466,17 @@ func (inst *InstCall) LLString() string {
 	for _, attr := range inst.ReturnAttrs {
 		fmt.Fprintf(buf, " %s", attr)
 	}
-	// Use function signature instead of return type for variadic functions.
+	// Use function signature instead of return type for variadic functions and
+	// calls with explicit function type.
 	typ := inst.Type()
 	if t, ok := inst.Typ.(*types.FuncType); ok {
 		if t.Variadic {
 			typ = t
 		}
 	}
+	if inst.FuncType != nil {
+		typ = inst.FuncType
+	}
 	fmt.Fprintf(buf, " %s %s(", typ, inst.Callee.Ident())
 	for i, arg := range inst.Args {
 		if i != 0 {
@@ -433,6 +519,9 @@ type InstVAArg struct {
 
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewVAArg returns a new va_arg instruction based on the given variable
@@ -483,6 +572,9 @@ type InstLandingPad struct {
 
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewLandingPad returns a new landingpad instruction based on the given result
@@ -561,6 +653,9 @@ type InstCatchPad struct {
 
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewCatchPad returns a new catchpad instruction based on the given exception
@@ -619,6 +714,9 @@ type InstCleanupPad struct {
 
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewCleanupPad returns a new cleanuppad instruction based on the given
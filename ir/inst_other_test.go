@@ -0,0 +1,49 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestCallSetters(t *testing.T) {
+	m := NewModule()
+	g := m.NewFunc("g", types.I8Ptr, NewParam("x", types.I32))
+	f := m.NewFunc("f", types.I8Ptr, NewParam("x", types.I32))
+	entry := f.NewBlock("entry")
+	call := entry.NewCall(g, f.Params[0]).WithCC(enum.CallingConvFast).WithReturnAttrs(enum.ReturnAttrNoAlias, enum.ReturnAttrNonNull).WithTail(enum.TailTail)
+	call.SetName("p")
+	entry.NewRet(call)
+	if want, got := "%p = tail call fastcc noalias nonnull i8* @g(i32 %x)", call.LLString(); want != got {
+		t.Errorf("call mismatch; expected %q, got %q", want, got)
+	}
+	// Change and clear attributes.
+	call.WithTail(enum.TailNoTail).WithReturnAttrs().WithCC(enum.CallingConvSwiftTail)
+	if want, got := "%p = notail call cc 20 i8* @g(i32 %x)", call.LLString(); want != got {
+		t.Errorf("call mismatch; expected %q, got %q", want, got)
+	}
+	call.WithTail(enum.TailNone).WithCC(enum.CallingConvNone)
+	if want, got := "%p = call i8* @g(i32 %x)", call.LLString(); want != got {
+		t.Errorf("call mismatch; expected %q, got %q", want, got)
+	}
+	// Invalid calling conventions, return attributes and tail call attributes.
+	golden := []func(){
+		func() { call.WithCC(enum.CallingConv(2000)) },
+		func() { call.WithReturnAttrs(nil) },
+		func() { call.WithTail(enum.Tail(42)) },
+	}
+	for i, g := range golden {
+		func() {
+			defer func() {
+				if e := recover(); e == nil {
+					t.Errorf("expected panic for invalid setter %d", i)
+				}
+			}()
+			g()
+		}()
+	}
+	if want, got := "%p = call i8* @g(i32 %x)", call.LLString(); want != got {
+		t.Errorf("call mismatch; expected %q, got %q", want, got)
+	}
+}
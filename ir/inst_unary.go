@@ -28,6 +28,9 @@ type InstFNeg struct {
 	FastMathFlags []enum.FastMathFlag
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewFNeg returns a new fneg instruction based on the given operand.
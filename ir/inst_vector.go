@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/llir/llvm/ir/constant"
 	"github.com/llir/llvm/ir/types"
 	"github.com/llir/llvm/ir/value"
 )
@@ -27,6 +28,9 @@ type InstExtractElement struct {
 	Typ types.Type
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewExtractElement returns a new extractelement instruction based on the given
@@ -89,6 +93,9 @@ type InstInsertElement struct {
 	Typ *types.VectorType
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewInsertElement returns a new insertelement instruction based on the given
@@ -140,8 +147,9 @@ type InstShuffleVector struct {
 	LocalIdent
 	// Vectors.
 	X, Y value.Value
-	// Shuffle mask.
-	Mask value.Value
+	// Shuffle mask; constant.UndefMaskElem for undef lanes and
+	// constant.PoisonMaskElem for poison lanes.
+	Mask []int
 
 	// extra.
 
@@ -149,11 +157,15 @@ type InstShuffleVector struct {
 	Typ *types.VectorType
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewShuffleVector returns a new shufflevector instruction based on the given
-// vectors and shuffle mask.
-func NewShuffleVector(x, y, mask value.Value) *InstShuffleVector {
+// vectors and shuffle mask elements, using constant.UndefMaskElem for undef
+// lanes and constant.PoisonMaskElem for poison lanes.
+func NewShuffleVector(x, y value.Value, mask []int) *InstShuffleVector {
 	inst := &InstShuffleVector{X: x, Y: y, Mask: mask}
 	// Compute type.
 	inst.Type()
@@ -174,11 +186,7 @@ func (inst *InstShuffleVector) Type() types.Type {
 		if !ok {
 			panic(fmt.Errorf("invalid vector type; expected *types.VectorType, got %T", inst.X.Type()))
 		}
-		maskType, ok := inst.Mask.Type().(*types.VectorType)
-		if !ok {
-			panic(fmt.Errorf("invalid vector type; expected *types.VectorType, got %T", inst.Mask.Type()))
-		}
-		inst.Typ = types.NewVector(maskType.Len, xType.ElemType)
+		inst.Typ = types.NewVector(uint64(len(inst.Mask)), xType.ElemType)
 	}
 	return inst.Typ
 }
@@ -189,7 +197,7 @@ func (inst *InstShuffleVector) LLString() string {
 	// Metadata=(',' MetadataAttachment)+?
 	buf := &strings.Builder{}
 	fmt.Fprintf(buf, "%s = ", inst.Ident())
-	fmt.Fprintf(buf, "shufflevector %s, %s, %s", inst.X, inst.Y, inst.Mask)
+	fmt.Fprintf(buf, "shufflevector %s, %s, %s", inst.X, inst.Y, constant.NewShuffleMask(inst.Mask...))
 	for _, md := range inst.Metadata {
 		fmt.Fprintf(buf, ", %s", md)
 	}
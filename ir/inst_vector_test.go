@@ -0,0 +1,73 @@
+package ir_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/asm"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestShuffleVectorUndefMask(t *testing.T) {
+	const src = `define <4 x i32> @f(<4 x i32> %a, <4 x i32> %b) {
+entry:
+	%c = shufflevector <4 x i32> %a, <4 x i32> %b, <4 x i32> <i32 0, i32 undef, i32 2, i32 poison>
+	ret <4 x i32> %c
+}
+`
+	m, err := asm.ParseString("", src)
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	if got := m.String(); got != src {
+		t.Errorf("module mismatch; expected:\n%s\ngot:\n%s", src, got)
+	}
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	inst := m.Funcs[0].Blocks[0].Insts[0].(*ir.InstShuffleVector)
+	want := []int{0, constant.UndefMaskElem, 2, constant.PoisonMaskElem}
+	if !reflect.DeepEqual(want, inst.Mask) {
+		t.Errorf("mask elements mismatch; expected %v, got %v", want, inst.Mask)
+	}
+
+	// Undef and poison lanes of shuffle mask constants are kept distinct.
+	mask := constant.NewVector(nil, constant.NewInt(types.I32, 0), constant.NewUndef(types.I32), constant.NewInt(types.I32, 2), constant.NewPoison(types.I32))
+	elems, ok := constant.ShuffleMask(mask)
+	if !ok || !reflect.DeepEqual(want, elems) {
+		t.Errorf("mask elements mismatch; expected %v, got %v", want, elems)
+	}
+	c := ir.NewShuffleVector(m.Funcs[0].Params[0], m.Funcs[0].Params[1], elems)
+	c.SetName("d")
+	wantInst := "%d = shufflevector <4 x i32> %a, <4 x i32> %b, <4 x i32> <i32 0, i32 undef, i32 2, i32 poison>"
+	if got := c.LLString(); got != wantInst {
+		t.Errorf("instruction mismatch; expected %q, got %q", wantInst, got)
+	}
+
+	// Lanes selecting elements of %b are in range; lanes past %b are not.
+	inst.Mask = []int{7, constant.UndefMaskElem, 4, constant.PoisonMaskElem}
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	inst.Mask = []int{8, constant.UndefMaskElem, 4, 0}
+	wantErr := "invalid shufflevector %c: shuffle mask element 8 at lane 0 out of range [0, 8)"
+	if err := m.Verify(); err == nil || !strings.Contains(err.Error(), wantErr) {
+		t.Errorf("error mismatch; expected %q, got %v", wantErr, err)
+	}
+}
+
+func TestShuffleVectorInvalidMask(t *testing.T) {
+	const src = `define <2 x i32> @f(<2 x i32> %a, <2 x i32> %b) {
+entry:
+	%c = shufflevector <2 x i32> %a, <2 x i32> %b, <2 x i32> %a
+	ret <2 x i32> %c
+}
+`
+	const wantErr = "invalid shuffle mask %a; expected constant"
+	if _, err := asm.ParseString("", src); err == nil || !strings.Contains(err.Error(), wantErr) {
+		t.Errorf("error mismatch; expected %q, got %v", wantErr, err)
+	}
+}
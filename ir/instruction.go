@@ -100,9 +100,105 @@ package ir
 //    *ir.InstLandingPad   // https://godoc.org/github.com/llir/llvm/ir#InstLandingPad
 //    *ir.InstCatchPad     // https://godoc.org/github.com/llir/llvm/ir#InstCatchPad
 //    *ir.InstCleanupPad   // https://godoc.org/github.com/llir/llvm/ir#InstCleanupPad
+//
+// Terminators also implement the Instruction interface, as yielded by
+// ir.Block.All, but are stored separately in the Term field of basic blocks.
 type Instruction interface {
 	LLStringer
+	// GetParent returns the parent basic block of the instruction; or nil if not
+	// set.
+	GetParent() *Block
 	// isInstruction ensures that only instructions can be assigned to the
 	// instruction.Instruction interface.
 	isInstruction()
 }
+
+// ResetType resets the cached type of the given instruction, if derived from
+// its operands, to be recomputed on next use.
+//
+// Instructions compute their type from their operands on first use, and cache
+// it for subsequent uses (e.g. when printing). The cached type remains valid
+// when operands are replaced by values of the same type (e.g. through
+// irutil.ReplaceAllUsesWith). ResetType must be invoked after modifying the
+// operands of an instruction in a way which changes its type.
+//
+// The types of conversion instructions, alloca instructions and terminators are
+// explicit and thus not reset. Similarly, the element type of getelementptr
+// instructions is explicit and not reset.
+func ResetType(inst Instruction) {
+	switch inst := inst.(type) {
+	// Unary instructions.
+	case *InstFNeg:
+		inst.Typ = nil
+	// Binary instructions.
+	case *InstAdd:
+		inst.Typ = nil
+	case *InstFAdd:
+		inst.Typ = nil
+	case *InstSub:
+		inst.Typ = nil
+	case *InstFSub:
+		inst.Typ = nil
+	case *InstMul:
+		inst.Typ = nil
+	case *InstFMul:
+		inst.Typ = nil
+	case *InstUDiv:
+		inst.Typ = nil
+	case *InstSDiv:
+		inst.Typ = nil
+	case *InstFDiv:
+		inst.Typ = nil
+	case *InstURem:
+		inst.Typ = nil
+	case *InstSRem:
+		inst.Typ = nil
+	case *InstFRem:
+		inst.Typ = nil
+	// Bitwise instructions.
+	case *InstShl:
+		inst.Typ = nil
+	case *InstLShr:
+		inst.Typ = nil
+	case *InstAShr:
+		inst.Typ = nil
+	case *InstAnd:
+		inst.Typ = nil
+	case *InstOr:
+		inst.Typ = nil
+	case *InstXor:
+		inst.Typ = nil
+	// Vector instructions.
+	case *InstExtractElement:
+		inst.Typ = nil
+	case *InstInsertElement:
+		inst.Typ = nil
+	case *InstShuffleVector:
+		inst.Typ = nil
+	// Aggregate instructions.
+	case *InstExtractValue:
+		inst.Typ = nil
+	case *InstInsertValue:
+		inst.Typ = nil
+	// Memory instructions.
+	case *InstLoad:
+		inst.Typ = nil
+	case *InstCmpXchg:
+		inst.Typ = nil
+	case *InstAtomicRMW:
+		inst.Typ = nil
+	case *InstGetElementPtr:
+		inst.Typ = nil
+	// Other instructions.
+	case *InstICmp:
+		inst.Typ = nil
+	case *InstFCmp:
+		inst.Typ = nil
+	case *InstPhi:
+		inst.Typ = nil
+	case *InstSelect:
+		inst.Typ = nil
+	case *InstCall:
+		inst.Typ = nil
+	}
+}
@@ -1,10 +1,12 @@
 package ir
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
 	"github.com/llir/llvm/ir/metadata"
 	"github.com/llir/llvm/ir/types"
 	"github.com/llir/llvm/ir/value"
@@ -30,6 +32,21 @@ func TestModuleString(t *testing.T) {
 			},
 			want: "%foo = type { i32 }",
 		},
+		// Data layout only.
+		{
+			in:   &Module{DataLayout: "e-m:e-i64:64-n32:64"},
+			want: `target datalayout = "e-m:e-i64:64-n32:64"`,
+		},
+		// Target triple only.
+		{
+			in:   &Module{TargetTriple: "x86_64-unknown-linux-gnu"},
+			want: `target triple = "x86_64-unknown-linux-gnu"`,
+		},
+		// Both data layout and target triple, emitted with data layout first.
+		{
+			in:   &Module{TargetTriple: "x86_64-unknown-linux-gnu", DataLayout: "e-m:e-i64:64-n32:64"},
+			want: "target datalayout = \"e-m:e-i64:64-n32:64\"\ntarget triple = \"x86_64-unknown-linux-gnu\"",
+		},
 	}
 	for _, g := range golden {
 		got := strings.TrimSpace(g.in.String())
@@ -39,6 +56,95 @@ func TestModuleString(t *testing.T) {
 	}
 }
 
+func TestModuleAssignGlobalIDs(t *testing.T) {
+	m := NewModule()
+	x := m.NewGlobalDef("", constant.NewInt(types.I32, 5))
+	y := m.NewGlobalDef("", constant.NewInt(types.I32, 6))
+	m.NewGlobalDef("g", x)
+	f := m.NewFunc("", types.I32)
+	entry := f.NewBlock("entry")
+	v := entry.NewLoad(y)
+	v.SetName("v")
+	entry.NewRet(v)
+	want := `@0 = global i32 5
+@1 = global i32 6
+@g = global i32* @0
+
+define i32 @2() {
+entry:
+	%v = load i32, i32* @1
+	ret i32 %v
+}
+`
+	if got := m.String(); want != got {
+		t.Errorf("module mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+	// IDs are reassigned on removal of unnamed global values.
+	m.Globals = m.Globals[1:]
+	m.Globals[1].Init = constant.NewUndef(m.Globals[1].ContentType)
+	want = `@0 = global i32 6
+@g = global i32* undef
+
+define i32 @1() {
+entry:
+	%v = load i32, i32* @0
+	ret i32 %v
+}
+`
+	if got := m.String(); want != got {
+		t.Errorf("module mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestModuleWriteTo(t *testing.T) {
+	m := NewModule()
+	m.SourceFilename = "foo.c"
+	m.NewGlobalDef("x", constant.NewInt(types.I32, 42))
+	for i := 0; i < 3; i++ {
+		f := m.NewFunc(fmt.Sprintf("f%d", i), types.I32, NewParam("a", types.I32))
+		entry := f.NewBlock("entry")
+		entry.NewRet(entry.NewAdd(f.Params[0], constant.NewInt(types.I32, int64(i))))
+	}
+	m.NamedMetadataDefs["foo"] = &metadata.NamedDef{Name: "foo"}
+	want := m.String()
+	w := &writeCounter{}
+	n, err := m.WriteTo(w)
+	if err != nil {
+		t.Fatalf("unable to write module; %v", err)
+	}
+	if got := w.buf.String(); got != want {
+		t.Errorf("module mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+	if n != int64(len(want)) {
+		t.Errorf("number of bytes written mismatch; expected %d, got %d", len(want), n)
+	}
+	// Output is flushed after each function.
+	if w.writes < len(m.Funcs) {
+		t.Errorf("number of writes mismatch; expected at least %d, got %d", len(m.Funcs), w.writes)
+	}
+	// Write errors are reported.
+	w = &writeCounter{limit: 10}
+	if _, err := m.WriteTo(w); err == nil {
+		t.Errorf("expected write error, got nil")
+	}
+}
+
+// writeCounter is an io.Writer which records the number of calls to Write, and
+// fails once more than limit bytes have been written (if limit is non-zero).
+type writeCounter struct {
+	buf    strings.Builder
+	writes int
+	limit  int
+}
+
+func (w *writeCounter) Write(p []byte) (int, error) {
+	w.writes++
+	if w.limit != 0 && w.buf.Len()+len(p) > w.limit {
+		return 0, fmt.Errorf("write limit %d exceeded", w.limit)
+	}
+	return w.buf.Write(p)
+}
+
 // Assert that each constant implements the constant.Constant interface.
 var (
 	// Constants.
@@ -146,10 +252,14 @@ var (
 	// Other values.
 	_ value.Named = (*Global)(nil)
 	_ value.Named = (*Func)(nil)
+	_ value.Named = (*Alias)(nil)
+	_ value.Named = (*IFunc)(nil)
 	_ value.Named = (*Param)(nil)
 	_ value.Named = (*Block)(nil)
 
 	// Instructions.
+	// Unary instructions.
+	_ value.Named = (*InstFNeg)(nil)
 	// Binary instructions.
 	_ value.Named = (*InstAdd)(nil)
 	_ value.Named = (*InstFAdd)(nil)
@@ -212,3 +322,622 @@ var (
 	_ value.Named = (*TermInvoke)(nil)
 	_ value.Named = (*TermCatchSwitch)(nil) // token result used by catchpad
 )
+
+func TestMetadataKindID(t *testing.T) {
+	m := NewModule()
+	golden := []struct {
+		name string
+		want int64
+	}{
+		{name: "dbg", want: 0},
+		{name: "range", want: 4},
+		{name: "callback", want: 26},
+		{name: "noundef", want: 29},
+		{name: "annotation", want: 30},
+		{name: "my.custom", want: 31},
+		{name: "my.other", want: 32},
+		{name: "my.custom", want: 31},
+	}
+	for _, g := range golden {
+		if got := m.MetadataKindID(g.name); g.want != got {
+			t.Errorf("metadata kind ID mismatch of %q; expected %d, got %d", g.name, g.want, got)
+		}
+	}
+}
+
+func TestModuleMetadataID(t *testing.T) {
+	m := NewModule()
+	inline := &metadata.Tuple{MetadataID: -1, Fields: []metadata.Field{&metadata.String{Value: "inline"}}}
+	a := &metadata.Tuple{MetadataID: -1, Fields: []metadata.Field{&metadata.String{Value: "a"}}}
+	b := &metadata.Tuple{MetadataID: 0, Fields: []metadata.Field{&metadata.String{Value: "b"}}}
+	c := &metadata.Tuple{MetadataID: -1, Fields: []metadata.Field{inline}}
+	m.MetadataDefs = append(m.MetadataDefs, a, b, c)
+	golden := []struct {
+		node metadata.Node
+		id   int64
+		ok   bool
+	}{
+		{node: a, id: 1, ok: true},
+		{node: b, id: 0, ok: true},
+		{node: c, id: 2, ok: true},
+		{node: inline, id: -1, ok: false},
+	}
+	for _, g := range golden {
+		id, ok := m.MetadataID(g.node)
+		if id != g.id || ok != g.ok {
+			t.Errorf("metadata ID mismatch of %q; expected %d (%v), got %d (%v)", g.node.Ident(), g.id, g.ok, id, ok)
+		}
+		if !g.ok {
+			continue
+		}
+		if node, ok := m.MetadataByID(g.id); !ok || node != g.node {
+			t.Errorf("metadata node mismatch of ID %d; expected %v, got %v", g.id, g.node, node)
+		}
+		// IDs match the module output.
+		want := fmt.Sprintf("!%d = %s", g.id, g.node.(metadata.Definition).LLString())
+		if s := m.String(); !strings.Contains(s, want) {
+			t.Errorf("missing %q in module:\n%s", want, s)
+		}
+	}
+	if _, ok := m.MetadataByID(3); ok {
+		t.Errorf("unexpected metadata node of ID 3")
+	}
+	// Metadata definitions appended, replaced or renumbered since indexing are
+	// located.
+	d := &metadata.Tuple{MetadataID: -1}
+	m.MetadataDefs = append(m.MetadataDefs, d)
+	if node, ok := m.MetadataByID(3); !ok || node != d {
+		t.Errorf("metadata node mismatch of ID 3; expected %v, got %v", d, node)
+	}
+	e := &metadata.Tuple{MetadataID: 2}
+	m.MetadataDefs[2] = e
+	if id, ok := m.MetadataID(e); !ok || id != 2 {
+		t.Errorf("metadata ID mismatch; expected 2, got %d (%v)", id, ok)
+	}
+	if node, ok := m.MetadataByID(2); !ok || node != e {
+		t.Errorf("metadata node mismatch of ID 2; expected %v, got %v", e, node)
+	}
+	if _, ok := m.MetadataID(c); ok {
+		t.Errorf("unexpected metadata ID of replaced metadata node")
+	}
+	a.SetID(4)
+	if id, ok := m.MetadataID(a); !ok || id != 4 {
+		t.Errorf("metadata ID mismatch; expected 4, got %d (%v)", id, ok)
+	}
+	if node, ok := m.MetadataByID(4); !ok || node != a {
+		t.Errorf("metadata node mismatch of ID 4; expected %v, got %v", a, node)
+	}
+	if _, ok := m.MetadataByID(1); ok {
+		t.Errorf("unexpected metadata node of ID 1")
+	}
+}
+
+func TestFuncNewBlock(t *testing.T) {
+	m := NewModule()
+	f := m.NewFunc("f", types.Void)
+	entry := f.NewBlock("entry")
+	exit := f.NewBlock("")
+	if len(f.Blocks) != 2 || f.Blocks[0] != entry || f.Blocks[1] != exit {
+		t.Fatalf("basic blocks not appended to function in order; got %v", f.Blocks)
+	}
+	for _, block := range f.Blocks {
+		if block.Parent != f {
+			t.Errorf("parent function mismatch of basic block %v; expected %v, got %v", block.Ident(), f.Ident(), block.Parent)
+		}
+	}
+	if block := NewBlock("detached"); block.Parent != nil {
+		t.Errorf("expected detached basic block without parent function, got %v", block.Parent.Ident())
+	}
+}
+
+func TestInstParent(t *testing.T) {
+	m := NewModule()
+	f := m.NewFunc("f", types.I32)
+	entry := f.NewBlock("entry")
+	add := entry.NewAdd(constant.NewInt(types.I32, 1), constant.NewInt(types.I32, 2))
+	ret := entry.NewRet(add)
+	for _, inst := range entry.Insts {
+		if got := inst.GetParent(); got != entry {
+			t.Errorf("parent basic block mismatch of instruction %v; expected %v, got %v", inst.LLString(), entry.Ident(), got)
+		}
+	}
+	if got := ret.GetParent(); got != entry {
+		t.Errorf("parent basic block mismatch of terminator %v; expected %v, got %v", ret.LLString(), entry.Ident(), got)
+	}
+	if term, ok := entry.Term.(Instruction); !ok || term.GetParent() != entry {
+		t.Errorf("expected terminator %v to implement Instruction with parent basic block %v", ret.LLString(), entry.Ident())
+	}
+	if got := NewAdd(add, add).GetParent(); got != nil {
+		t.Errorf("expected detached instruction without parent basic block, got %v", got.Ident())
+	}
+}
+
+func TestModuleResolveParents(t *testing.T) {
+	// Manually construct module without builder methods.
+	block := NewBlock("entry")
+	add := NewAdd(constant.NewInt(types.I32, 1), constant.NewInt(types.I32, 2))
+	ret := NewRet(add)
+	block.Insts = append(block.Insts, add)
+	block.Term = ret
+	f := NewFunc("f", types.I32)
+	f.Blocks = append(f.Blocks, block)
+	m := &Module{Funcs: []*Func{f}}
+	m.ResolveParents()
+	if f.Parent != m {
+		t.Errorf("parent module mismatch of function %v", f.Ident())
+	}
+	if block.Parent != f {
+		t.Errorf("parent function mismatch of basic block %v; expected %v, got %v", block.Ident(), f.Ident(), block.Parent)
+	}
+	if add.Parent != block {
+		t.Errorf("parent basic block mismatch of instruction %v; expected %v, got %v", add.LLString(), block.Ident(), add.Parent)
+	}
+	if ret.Parent != block {
+		t.Errorf("parent basic block mismatch of terminator %v; expected %v, got %v", ret.LLString(), block.Ident(), ret.Parent)
+	}
+}
+
+func TestFuncMarkDirty(t *testing.T) {
+	m := NewModule()
+	f := m.NewFunc("f", types.I32)
+	entry := f.NewBlock("")
+	add := entry.NewAdd(constant.NewInt(types.I32, 1), constant.NewInt(types.I32, 2))
+	entry.NewRet(add)
+	_ = m.String()
+	if got, want := add.Ident(), "%1"; got != want {
+		t.Errorf("local ID mismatch of instruction; expected %v, got %v", want, got)
+	}
+	// Direct modification requires an explicit MarkDirty.
+	mul := NewMul(add, add)
+	entry.Insts = append(entry.Insts, mul)
+	_ = m.String()
+	if got, want := mul.ID(), int64(0); got != want {
+		t.Errorf("local ID mismatch of instruction before MarkDirty; expected %v, got %v", want, got)
+	}
+	f.MarkDirty()
+	_ = m.String()
+	if got, want := mul.Ident(), "%2"; got != want {
+		t.Errorf("local ID mismatch of instruction after MarkDirty; expected %v, got %v", want, got)
+	}
+	// Builder methods mark the function as dirty.
+	sub := entry.NewSub(add, add)
+	_ = m.String()
+	if got, want := sub.Ident(), "%3"; got != want {
+		t.Errorf("local ID mismatch of instruction appended by builder; expected %v, got %v", want, got)
+	}
+}
+
+func TestFuncSetName(t *testing.T) {
+	m := NewModule()
+	x := NewParam("", types.I32)
+	f := m.NewFunc("f", types.I32, x)
+	entry := f.NewBlock("")
+	add := entry.NewAdd(x, x)
+	mul := entry.NewMul(add, add)
+	entry.NewRet(mul)
+	_ = m.String()
+	if got, want := mul.Ident(), "%3"; got != want {
+		t.Errorf("local ID mismatch of instruction; expected %v, got %v", want, got)
+	}
+	// Naming a value renumbers subsequent unnamed values.
+	add.SetName("sum")
+	entry.SetName("entry")
+	f.MarkDirty()
+	_ = m.String()
+	if got, want := add.Ident(), "%sum"; got != want {
+		t.Errorf("local name mismatch of instruction; expected %v, got %v", want, got)
+	}
+	if got, want := mul.Ident(), "%1"; got != want {
+		t.Errorf("local ID mismatch of instruction after renaming; expected %v, got %v", want, got)
+	}
+	// Unnaming a value renumbers subsequent unnamed values.
+	add.SetName("")
+	f.MarkDirty()
+	want := "define i32 @f(i32) {\nentry:\n\t%1 = add i32 %0, %0\n\t%2 = mul i32 %1, %1\n\tret i32 %2\n}"
+	if got := f.LLString(); got != want {
+		t.Errorf("function mismatch after unnaming; expected %q, got %q", want, got)
+	}
+}
+
+func BenchmarkModuleString(b *testing.B) {
+	m := NewModule()
+	for i := 0; i < 10; i++ {
+		f := m.NewFunc(fmt.Sprintf("f%d", i), types.I32, NewParam("", types.I32))
+		entry := f.NewBlock("")
+		var x value.Value = f.Params[0]
+		for j := 0; j < 100; j++ {
+			x = entry.NewAdd(x, constant.NewInt(types.I32, int64(j)))
+		}
+		entry.NewRet(x)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = m.String()
+	}
+}
+
+func BenchmarkModuleStringDirty(b *testing.B) {
+	m := NewModule()
+	for i := 0; i < 10; i++ {
+		f := m.NewFunc(fmt.Sprintf("f%d", i), types.I32, NewParam("", types.I32))
+		entry := f.NewBlock("")
+		var x value.Value = f.Params[0]
+		for j := 0; j < 100; j++ {
+			x = entry.NewAdd(x, constant.NewInt(types.I32, int64(j)))
+		}
+		entry.NewRet(x)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Force reassignment of local IDs, as done before each print prior to
+		// tracking modifications.
+		for _, f := range m.Funcs {
+			f.MarkDirty()
+		}
+		_ = m.String()
+	}
+}
+
+// newLargeFunc returns a new module with a large function of getelementptr,
+// bitcast and phi instructions, for use in benchmarks.
+func newLargeFunc() (*Module, *Func) {
+	m := NewModule()
+	elemType := types.NewStruct(types.I32, types.NewArray(8, types.I64))
+	p := NewParam("", types.NewPointer(elemType))
+	f := m.NewFunc("f", types.I64, p)
+	entry := f.NewBlock("")
+	zero := constant.NewInt(types.I32, 0)
+	prev := entry
+	var x value.Value = constant.NewInt(types.I64, 0)
+	for i := 0; i < 1000; i++ {
+		block := f.NewBlock("")
+		prev.NewBr(block)
+		phi := block.NewPhi(NewIncoming(x, prev))
+		gep := block.NewGetElementPtr(p, zero, constant.NewInt(types.I32, 1), constant.NewInt(types.I32, int64(i%8)))
+		ptr := block.NewBitCast(gep, types.NewPointer(types.I64))
+		load := block.NewLoad(ptr)
+		x = block.NewAdd(phi, load)
+		prev = block
+	}
+	prev.NewRet(x)
+	return m, f
+}
+
+// BenchmarkFuncString measures printing of a large function, with types of
+// instructions cached after first use.
+func BenchmarkFuncString(b *testing.B) {
+	_, f := newLargeFunc()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = f.LLString()
+	}
+}
+
+// BenchmarkFuncStringResetTypes measures printing of a large function, with
+// types of instructions recomputed on each print (i.e. without caching).
+func BenchmarkFuncStringResetTypes(b *testing.B) {
+	_, f := newLargeFunc()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, block := range f.Blocks {
+			for _, inst := range block.Insts {
+				ResetType(inst)
+			}
+		}
+		_ = f.LLString()
+	}
+}
+
+func TestResetType(t *testing.T) {
+	_, f := newLargeFunc()
+	block := f.Blocks[1]
+	phi := block.Insts[0].(*InstPhi)
+	add := block.Insts[4].(*InstAdd)
+	if got, want := add.Type(), types.I64; !got.Equal(want) {
+		t.Errorf("type mismatch; expected %v, got %v", want, got)
+	}
+	// Replace operands by values of another type; the cached types remain until
+	// reset.
+	phi.Incs[0].X = constant.NewInt(types.I32, 0)
+	add.X, add.Y = phi, constant.NewInt(types.I32, 1)
+	if got, want := add.Type(), types.I64; !got.Equal(want) {
+		t.Errorf("cached type mismatch; expected %v, got %v", want, got)
+	}
+	ResetType(phi)
+	ResetType(add)
+	if got, want := add.Type(), types.I32; !got.Equal(want) {
+		t.Errorf("type mismatch after reset; expected %v, got %v", want, got)
+	}
+	if got, want := phi.Type(), types.I32; !got.Equal(want) {
+		t.Errorf("type mismatch after reset; expected %v, got %v", want, got)
+	}
+}
+
+func TestModuleNewOpaqueTypeDef(t *testing.T) {
+	m := NewModule()
+	node := m.NewOpaqueTypeDef("Node")
+	if got, want := m.String(), "%Node = type opaque\n"; got != want {
+		t.Errorf("module mismatch of opaque type definition; expected %q, got %q", want, got)
+	}
+	nodePtr := types.NewPointer(node)
+	node.SetBody(types.I32, nodePtr)
+	if got, want := m.String(), "%Node = type { i32, %Node* }\n"; got != want {
+		t.Errorf("module mismatch of recursive type definition; expected %q, got %q", want, got)
+	}
+	// Self-referential types must not cause infinite recursion.
+	if !node.Equal(nodePtr.ElemType) {
+		t.Errorf("expected recursive type %v to be equal to itself", node)
+	}
+	if !nodePtr.Equal(node.Fields[1]) {
+		t.Errorf("expected pointer type %v to be equal to field type %v", nodePtr, node.Fields[1])
+	}
+}
+
+func TestModuleSortedTypeDefs(t *testing.T) {
+	m := NewModule()
+	// Type definitions in reverse dependency order.
+	outer := m.NewOpaqueTypeDef("outer")
+	middle := m.NewOpaqueTypeDef("middle")
+	node := m.NewOpaqueTypeDef("node")
+	inner := m.NewOpaqueTypeDef("inner")
+	arr := m.NewTypeDef("arr", types.NewArray(2, middle))
+	outer.SetBody(arr, types.NewPointer(outer))
+	middle.SetBody(types.NewStruct(inner, types.I8))
+	node.SetBody(types.I32, types.NewPointer(node), types.NewPointer(outer))
+	inner.SetBody(types.I64)
+	var got []string
+	for _, t := range m.SortedTypeDefs() {
+		got = append(got, t.Name())
+	}
+	want := []string{"inner", "middle", "outer", "node"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("sorted type definitions mismatch; expected %v, got %v", want, got)
+	}
+}
+
+func TestModuleRenameGlobal(t *testing.T) {
+	m := NewModule()
+	comdat := &ComdatDef{Name: "foo", Kind: enum.SelectionKindAny}
+	m.ComdatDefs = append(m.ComdatDefs, comdat)
+	foo := m.NewFunc("foo", types.I32)
+	foo.Comdat = comdat
+	foo.NewBlock("").NewRet(constant.NewInt(types.I32, 42))
+	m.NewGlobalDef("fp", foo)
+	m.NewGlobalDef("baz", constant.NewInt(types.I32, 0))
+	main := m.NewFunc("main", types.I32)
+	main.NewBlock("").NewRet(main.Blocks[0].NewCall(foo))
+	if err := m.RenameGlobal("foo", "baz"); err == nil {
+		t.Errorf("expected error for name collision, got nil")
+	}
+	if err := m.RenameGlobal("qux", "quux"); err == nil {
+		t.Errorf("expected error for missing global, got nil")
+	}
+	if err := m.RenameGlobal("foo", "bar"); err != nil {
+		t.Fatalf("unable to rename global; %v", err)
+	}
+	got := m.String()
+	if strings.Contains(got, "@foo") || strings.Contains(got, "$foo") {
+		t.Errorf("expected no references to renamed global @foo, got:\n%s", got)
+	}
+	for _, want := range []string{"$bar = comdat any", "@fp = global i32 ()* @bar", "define i32 @bar() comdat {", "call i32 @bar()"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in module, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestPreemptionOrder(t *testing.T) {
+	m := NewModule()
+	g := m.NewGlobalDef("g", constant.NewInt(types.I32, 0))
+	g.Linkage = enum.LinkageWeak
+	g.Preemption = enum.PreemptionDSOLocal
+	g.Visibility = enum.VisibilityHidden
+	g.UnnamedAddr = enum.UnnamedAddrUnnamedAddr
+	e := m.NewGlobal("e", types.I32)
+	e.Linkage = enum.LinkageExternal
+	e.Preemption = enum.PreemptionDSOPreemptable
+	e.DLLStorageClass = enum.DLLStorageClassDLLImport
+	f := m.NewFunc("f", types.Void)
+	f.Linkage = enum.LinkageLinkOnceODR
+	f.Preemption = enum.PreemptionDSOLocal
+	f.Visibility = enum.VisibilityProtected
+	f.NewBlock("").NewRet(nil)
+	d := m.NewFunc("d", types.Void)
+	d.Linkage = enum.LinkageExternWeak
+	d.Preemption = enum.PreemptionDSOLocal
+	a := m.NewAlias("a", g)
+	a.Linkage = enum.LinkagePrivate
+	a.Preemption = enum.PreemptionDSOLocal
+	golden := []struct {
+		in   interface{ LLString() string }
+		want string
+	}{
+		{in: g, want: "@g = weak dso_local hidden unnamed_addr global i32 0"},
+		{in: e, want: "@e = external dso_preemptable dllimport global i32"},
+		{in: f, want: "define linkonce_odr dso_local protected void @f() {\n; <label>:0\n\tret void\n}"},
+		{in: d, want: "declare extern_weak dso_local void @d()"},
+		{in: a, want: "@a = private dso_local alias i32, i32* @g"},
+	}
+	for _, g := range golden {
+		if got := g.in.LLString(); g.want != got {
+			t.Errorf("preemption order mismatch; expected %q, got %q", g.want, got)
+		}
+	}
+}
+
+func TestFuncAttrs(t *testing.T) {
+	m := NewModule()
+	f := m.NewFunc("f", types.Void)
+	f.FuncAttrs = []FuncAttribute{
+		enum.FuncAttrMustProgress,
+		enum.FuncAttrNoFree,
+		enum.FuncAttrNoRecurse,
+		enum.FuncAttrNoSync,
+		enum.FuncAttrNoUnwind,
+		enum.FuncAttrWillReturn,
+		enum.FuncAttrUwtableSync,
+	}
+	g := m.NewFunc("g", types.Void)
+	g.FuncAttrs = []FuncAttribute{
+		enum.FuncAttrConvergent,
+		enum.FuncAttrHot,
+		enum.FuncAttrNoCallback,
+		enum.FuncAttrNoMerge,
+		enum.FuncAttrNoProfile,
+		enum.FuncAttrNullPointerIsValid,
+		enum.FuncAttrSanitizeMemTag,
+		enum.FuncAttrUwtableAsync,
+	}
+	golden := []struct {
+		in   *Func
+		want string
+	}{
+		{in: f, want: "declare void @f() mustprogress nofree norecurse nosync nounwind willreturn uwtable(sync)"},
+		{in: g, want: "declare void @g() convergent hot nocallback nomerge noprofile null_pointer_is_valid sanitize_memtag uwtable(async)"},
+	}
+	for _, g := range golden {
+		if got := g.in.LLString(); g.want != got {
+			t.Errorf("function attributes mismatch; expected %q, got %q", g.want, got)
+		}
+	}
+}
+
+func TestOperands(t *testing.T) {
+	f := NewFunc("f", types.I32, NewParam("x", types.I32), NewParam("y", types.I32))
+	entry := f.NewBlock("entry")
+	x, y := f.Params[0], f.Params[1]
+	add := entry.NewAdd(x, y)
+	add.SetName("a")
+	call := entry.NewCall(f, add, x)
+	call.SetName("b")
+	ret := entry.NewRet(call)
+	golden := []struct {
+		inst Instruction
+		want []value.Value
+	}{
+		{inst: add, want: []value.Value{x, y}},
+		{inst: call, want: []value.Value{f, add, x}},
+		{inst: ret, want: []value.Value{call}},
+	}
+	for _, g := range golden {
+		ops := Operands(g.inst)
+		if len(ops) != len(g.want) {
+			t.Errorf("number of operands mismatch of %q; expected %d, got %d", g.inst.LLString(), len(g.want), len(ops))
+			continue
+		}
+		for i, op := range ops {
+			if *op != g.want[i] {
+				t.Errorf("operand %d mismatch of %q; expected %v, got %v", i, g.inst.LLString(), g.want[i].Ident(), (*op).Ident())
+			}
+		}
+	}
+	// Replace operand in place.
+	*Operands(add)[1] = x
+	if got, want := add.LLString(), "%a = add i32 %x, %x"; got != want {
+		t.Errorf("instruction mismatch after replacing operand; expected %q, got %q", want, got)
+	}
+}
+
+func TestValueKind(t *testing.T) {
+	m := NewModule()
+	g := m.NewGlobal("g", types.I32)
+	f := m.NewFunc("f", types.Void, NewParam("x", types.I32))
+	alias := m.NewAlias("a", g)
+	entry := f.NewBlock("entry")
+	add := entry.NewAdd(f.Params[0], constant.NewInt(types.I32, 1))
+	invoke := entry.NewInvoke(f, []value.Value{add}, entry, entry)
+	golden := []struct {
+		v    value.Value
+		want value.Kind
+	}{
+		{v: add, want: value.KindInstruction},
+		{v: invoke, want: value.KindInstruction},
+		{v: constant.NewInt(types.I32, 1), want: value.KindConstant},
+		{v: constant.NewPtrToInt(g, types.I64), want: value.KindConstant},
+		{v: g, want: value.KindGlobal},
+		{v: alias, want: value.KindGlobal},
+		{v: f, want: value.KindFunc},
+		{v: f.Params[0], want: value.KindParam},
+		{v: entry, want: value.KindBasicBlock},
+		{v: NewInlineAsm(types.NewPointer(types.NewFunc(types.Void)), "nop", ""), want: value.KindInlineAsm},
+		{v: &metadata.Value{Value: &metadata.String{Value: "foo"}}, want: value.KindMetadata},
+	}
+	for _, g := range golden {
+		if got := g.v.Kind(); got != g.want {
+			t.Errorf("value kind mismatch of %q; expected %v, got %v", g.v.Ident(), g.want, got)
+		}
+	}
+}
+
+// operandCounts records the number of operands of each kind.
+type operandCounts struct {
+	insts, consts, globals, params, others int
+}
+
+// BenchmarkOperandKind measures classification of the operands of a large
+// function by value kind.
+func BenchmarkOperandKind(b *testing.B) {
+	operands := largeFuncOperands()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var c operandCounts
+		for _, v := range operands {
+			switch v.Kind() {
+			case value.KindInstruction:
+				c.insts++
+			case value.KindConstant:
+				c.consts++
+			case value.KindGlobal, value.KindFunc:
+				c.globals++
+			case value.KindParam:
+				c.params++
+			default:
+				c.others++
+			}
+		}
+	}
+}
+
+// BenchmarkOperandTypeSwitch measures classification of the operands of a
+// large function by type switch, for comparison with BenchmarkOperandKind.
+func BenchmarkOperandTypeSwitch(b *testing.B) {
+	operands := largeFuncOperands()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var c operandCounts
+		for _, v := range operands {
+			switch v.(type) {
+			case Instruction:
+				c.insts++
+			case *Global, *Alias, *IFunc, *Func:
+				c.globals++
+			case constant.Constant:
+				c.consts++
+			case *Param:
+				c.params++
+			default:
+				c.others++
+			}
+		}
+	}
+}
+
+// largeFuncOperands returns the operands of the instructions and terminators of
+// a large function.
+func largeFuncOperands() []value.Value {
+	_, f := newLargeFunc()
+	var operands []value.Value
+	for _, block := range f.Blocks {
+		for _, inst := range block.Insts {
+			for _, operand := range Operands(inst) {
+				operands = append(operands, *operand)
+			}
+		}
+		if term, ok := block.Term.(Instruction); ok {
+			for _, operand := range Operands(term) {
+				operands = append(operands, *operand)
+			}
+		}
+	}
+	return operands
+}
@@ -0,0 +1,34 @@
+package ir
+
+// --- [ Iteration ] -----------------------------------------------------------
+
+// yieldInsts calls yield for each instruction of the function in order, across
+// all basic blocks, excluding terminators. It reports whether iteration was
+// completed without yield returning false.
+func (f *Func) yieldInsts(yield func(inst Instruction) bool) bool {
+	for _, block := range f.Blocks {
+		for _, inst := range block.Insts {
+			if !yield(inst) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// yieldAll calls yield for each instruction of the basic block in order,
+// followed by the terminator (if present). It reports whether iteration was
+// completed without yield returning false.
+func (block *Block) yieldAll(yield func(inst Instruction) bool) bool {
+	for _, inst := range block.Insts {
+		if !yield(inst) {
+			return false
+		}
+	}
+	if block.Term != nil {
+		if term, ok := block.Term.(Instruction); ok {
+			return yield(term)
+		}
+	}
+	return true
+}
@@ -0,0 +1,26 @@
+package ir
+
+import "iter"
+
+// Instructions returns an iterator over the instructions of the function in
+// order, across all basic blocks. Terminators are not included; use
+// ir.Block.All to iterate over the terminators of basic blocks.
+//
+// Example:
+//
+//    for inst := range f.Instructions() {
+//       fmt.Println(inst.LLString())
+//    }
+func (f *Func) Instructions() iter.Seq[Instruction] {
+	return func(yield func(inst Instruction) bool) {
+		f.yieldInsts(yield)
+	}
+}
+
+// All returns an iterator over the instructions of the basic block in order,
+// followed by the terminator.
+func (block *Block) All() iter.Seq[Instruction] {
+	return func(yield func(inst Instruction) bool) {
+		block.yieldAll(yield)
+	}
+}
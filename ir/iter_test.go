@@ -0,0 +1,63 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestFuncInstructions(t *testing.T) {
+	m := NewModule()
+	f := m.NewFunc("f", types.I32)
+	entry := f.NewBlock("entry")
+	exit := f.NewBlock("exit")
+	add := entry.NewAdd(constant.NewInt(types.I32, 1), constant.NewInt(types.I32, 2))
+	br := entry.NewBr(exit)
+	mul := exit.NewMul(add, add)
+	sub := exit.NewSub(mul, add)
+	ret := exit.NewRet(sub)
+	var got []Instruction
+	for inst := range f.Instructions() {
+		got = append(got, inst)
+	}
+	want := []Instruction{add, mul, sub}
+	if !equalInsts(got, want) {
+		t.Errorf("function instructions mismatch; expected %v, got %v", want, got)
+	}
+	got = nil
+	for _, block := range f.Blocks {
+		for inst := range block.All() {
+			got = append(got, inst)
+		}
+	}
+	want = []Instruction{add, br, mul, sub, ret}
+	if !equalInsts(got, want) {
+		t.Errorf("basic block instructions mismatch; expected %v, got %v", want, got)
+	}
+	// Early termination.
+	got = nil
+	for inst := range f.Instructions() {
+		got = append(got, inst)
+		if inst == mul {
+			break
+		}
+	}
+	want = []Instruction{add, mul}
+	if !equalInsts(got, want) {
+		t.Errorf("function instructions mismatch on break; expected %v, got %v", want, got)
+	}
+}
+
+// equalInsts reports whether the given instruction slices are identical.
+func equalInsts(a, b []Instruction) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
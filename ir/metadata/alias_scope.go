@@ -0,0 +1,39 @@
+package metadata
+
+// --- [ Alias scope metadata ] ------------------------------------------------
+
+// NewAliasScopeDomain returns a new alias scope domain node with the given
+// name. The name is used as the unique identifier of the domain; domains of the
+// same name are considered identical.
+//
+// Example:
+//
+//    !{!"domain"}
+func NewAliasScopeDomain(name string) *Tuple {
+	return &Tuple{MetadataID: -1, Fields: []Field{&String{Value: name}}}
+}
+
+// NewAliasScope returns a new alias scope node with the given name within the
+// given alias scope domain. The name is used as the unique identifier of the
+// scope; scopes of the same name and domain are considered identical.
+//
+// Example:
+//
+//    !{!"scope", !0}
+func NewAliasScope(name string, domain *Tuple) *Tuple {
+	return &Tuple{MetadataID: -1, Fields: []Field{&String{Value: name}, domain}}
+}
+
+// NewAliasScopeList returns a new list of alias scopes, for use with
+// !alias.scope and !noalias metadata attachments.
+//
+// Example:
+//
+//    !{!1, !2}
+func NewAliasScopeList(scopes ...*Tuple) *Tuple {
+	md := &Tuple{MetadataID: -1}
+	for _, scope := range scopes {
+		md.Fields = append(md.Fields, scope)
+	}
+	return md
+}
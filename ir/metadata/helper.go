@@ -1,6 +1,8 @@
 package metadata
 
 import (
+	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/llir/llvm/internal/enc"
@@ -40,14 +42,14 @@ func dispFlagsString(flags enum.DISPFlag) string {
 	return strings.Join(ss, " | ")
 }
 
-// TODO: fix string representation for all enums which are defined in the
-// grammar as `FooEnum | FooInt`, in the same way as dwarfTagString.
-
-// dwarfTagString returns the string representation of the given Dwarf tag.
-func dwarfTagString(tag enum.DwarfTag) string {
-	s := tag.String()
-	if strings.HasPrefix(s, "DwarfTag(") && strings.HasSuffix(s, ")") {
-		return s[len("DwarfTag(") : len(s)-len(")")]
+// enumString returns the string representation of the given enum, which is
+// defined in the grammar as `FooEnum | FooInt` (e.g. DwarfTag). Enum values
+// without keyword are represented in integer form; e.g. 100 for DwarfTag(100).
+func enumString(v fmt.Stringer) string {
+	s := v.String()
+	prefix := reflect.TypeOf(v).Name() + "("
+	if strings.HasPrefix(s, prefix) && strings.HasSuffix(s, ")") {
+		return s[len(prefix) : len(s)-len(")")]
 	}
 	return s
 }
@@ -0,0 +1,22 @@
+package metadata
+
+import (
+	"github.com/llir/llvm/ir/constant"
+)
+
+// --- [ Range metadata ] ------------------------------------------------------
+
+// NewRange returns a new metadata tuple for use with !range metadata
+// attachments, based on the given value ranges. Each pair specifies the lower
+// bound (inclusive) and upper bound (exclusive) of a range of possible values.
+//
+// Example:
+//
+//    !{i8 0, i8 2}
+func NewRange(pairs ...[2]*constant.Int) *Tuple {
+	md := &Tuple{MetadataID: -1}
+	for _, pair := range pairs {
+		md.Fields = append(md.Fields, pair[0], pair[1])
+	}
+	return md
+}
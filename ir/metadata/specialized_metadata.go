@@ -52,7 +52,7 @@ func (md *DIBasicType) LLString() string {
 	}
 	var fields []string
 	if md.Tag != 0 {
-		field := fmt.Sprintf("tag: %s", dwarfTagString(md.Tag))
+		field := fmt.Sprintf("tag: %s", enumString(md.Tag))
 		fields = append(fields, field)
 	}
 	if len(md.Name) > 0 {
@@ -68,7 +68,7 @@ func (md *DIBasicType) LLString() string {
 		fields = append(fields, field)
 	}
 	if md.Encoding != 0 {
-		field := fmt.Sprintf("encoding: %s", md.Encoding)
+		field := fmt.Sprintf("encoding: %s", enumString(md.Encoding))
 		fields = append(fields, field)
 	}
 	if md.Flags != 0 {
@@ -139,7 +139,7 @@ func (md *DICompileUnit) LLString() string {
 		buf.WriteString("distinct ")
 	}
 	var fields []string
-	field := fmt.Sprintf("language: %s", md.Language)
+	field := fmt.Sprintf("language: %s", enumString(md.Language))
 	fields = append(fields, field)
 	field = fmt.Sprintf("file: %s", md.File)
 	fields = append(fields, field)
@@ -164,7 +164,7 @@ func (md *DICompileUnit) LLString() string {
 		fields = append(fields, field)
 	}
 	if md.EmissionKind != 0 {
-		field = fmt.Sprintf("emissionKind: %s", md.EmissionKind)
+		field = fmt.Sprintf("emissionKind: %s", enumString(md.EmissionKind))
 		fields = append(fields, field)
 	}
 	if md.Enums != nil {
@@ -200,7 +200,7 @@ func (md *DICompileUnit) LLString() string {
 		fields = append(fields, field)
 	}
 	if md.NameTableKind != 0 {
-		field = fmt.Sprintf("nameTableKind: %s", md.NameTableKind)
+		field = fmt.Sprintf("nameTableKind: %s", enumString(md.NameTableKind))
 		fields = append(fields, field)
 	}
 	if md.DebugBaseAddress {
@@ -269,7 +269,7 @@ func (md *DICompositeType) LLString() string {
 		buf.WriteString("distinct ")
 	}
 	var fields []string
-	field := fmt.Sprintf("tag: %s", dwarfTagString(md.Tag))
+	field := fmt.Sprintf("tag: %s", enumString(md.Tag))
 	fields = append(fields, field)
 	if len(md.Name) > 0 {
 		field := fmt.Sprintf("name: %s", quote(md.Name))
@@ -389,7 +389,7 @@ func (md *DIDerivedType) LLString() string {
 		buf.WriteString("distinct ")
 	}
 	var fields []string
-	field := fmt.Sprintf("tag: %s", dwarfTagString(md.Tag))
+	field := fmt.Sprintf("tag: %s", enumString(md.Tag))
 	fields = append(fields, field)
 	if len(md.Name) > 0 {
 		field := fmt.Sprintf("name: %s", quote(md.Name))
@@ -822,7 +822,7 @@ func (md *DIImportedEntity) LLString() string {
 		buf.WriteString("distinct ")
 	}
 	var fields []string
-	field := fmt.Sprintf("tag: %s", dwarfTagString(md.Tag))
+	field := fmt.Sprintf("tag: %s", enumString(md.Tag))
 	fields = append(fields, field)
 	field = fmt.Sprintf("scope: %s", md.Scope)
 	fields = append(fields, field)
@@ -1225,7 +1225,7 @@ func (md *DIMacro) LLString() string {
 		buf.WriteString("distinct ")
 	}
 	var fields []string
-	field := fmt.Sprintf("type: %s", md.Type)
+	field := fmt.Sprintf("type: %s", enumString(md.Type))
 	fields = append(fields, field)
 	if md.Line != 0 {
 		field := fmt.Sprintf("line: %d", md.Line)
@@ -1288,7 +1288,7 @@ func (md *DIMacroFile) LLString() string {
 	}
 	var fields []string
 	if md.Type != 0 {
-		field := fmt.Sprintf("type: %s", md.Type)
+		field := fmt.Sprintf("type: %s", enumString(md.Type))
 		fields = append(fields, field)
 	}
 	if md.Line != 0 {
@@ -1620,7 +1620,7 @@ func (md *DISubprogram) LLString() string {
 		fields = append(fields, field)
 	}
 	if md.Virtuality != 0 {
-		field := fmt.Sprintf("virtuality: %s", md.Virtuality)
+		field := fmt.Sprintf("virtuality: %s", enumString(md.Virtuality))
 		fields = append(fields, field)
 	}
 	if md.VirtualIndex != 0 {
@@ -1771,7 +1771,7 @@ func (md *DISubroutineType) LLString() string {
 		fields = append(fields, field)
 	}
 	if md.CC != 0 {
-		field := fmt.Sprintf("cc: %s", md.CC)
+		field := fmt.Sprintf("cc: %s", enumString(md.CC))
 		fields = append(fields, field)
 	}
 	field := fmt.Sprintf("types: %s", md.Types)
@@ -1882,7 +1882,7 @@ func (md *DITemplateValueParameter) LLString() string {
 	}
 	var fields []string
 	if md.Tag != 0 {
-		field := fmt.Sprintf("tag: %s", dwarfTagString(md.Tag))
+		field := fmt.Sprintf("tag: %s", enumString(md.Tag))
 		fields = append(fields, field)
 	}
 	if len(md.Name) > 0 {
@@ -1944,7 +1944,7 @@ func (md *GenericDINode) LLString() string {
 		buf.WriteString("distinct ")
 	}
 	var fields []string
-	field := fmt.Sprintf("tag: %s", dwarfTagString(md.Tag))
+	field := fmt.Sprintf("tag: %s", enumString(md.Tag))
 	fields = append(fields, field)
 	if len(md.Header) > 0 {
 		field := fmt.Sprintf("header: %s", quote(md.Header))
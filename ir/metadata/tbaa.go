@@ -0,0 +1,74 @@
+package metadata
+
+import (
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+// --- [ TBAA metadata ] -------------------------------------------------------
+
+// TBAAField is a field of a TBAA struct type node.
+type TBAAField struct {
+	// Type node of the field.
+	Type *Tuple
+	// Offset in bytes of the field within the struct type.
+	Offset uint64
+}
+
+// NewTBAARoot returns a new TBAA root node with the given name, for use as the
+// ancestor of TBAA scalar type nodes. Type nodes with different roots are
+// assumed to alias.
+//
+// Example:
+//
+//    !{!"Simple C/C++ TBAA"}
+func NewTBAARoot(name string) *Tuple {
+	return &Tuple{MetadataID: -1, Fields: []Field{&String{Value: name}}}
+}
+
+// NewTBAAScalarType returns a new TBAA scalar type node with the given name and
+// parent type node (e.g. the TBAA root node, or the type node of char).
+//
+// Example:
+//
+//    !{!"int", !1, i64 0}
+func NewTBAAScalarType(name string, parent *Tuple) *Tuple {
+	return &Tuple{MetadataID: -1, Fields: []Field{&String{Value: name}, parent, newTBAAInt(0)}}
+}
+
+// NewTBAAStructType returns a new TBAA struct type node with the given name and
+// fields, in order of increasing offset.
+//
+// Example:
+//
+//    !{!"S", !2, i64 0, !3, i64 4}
+func NewTBAAStructType(name string, fields ...TBAAField) *Tuple {
+	md := &Tuple{MetadataID: -1, Fields: []Field{&String{Value: name}}}
+	for _, field := range fields {
+		md.Fields = append(md.Fields, field.Type, newTBAAInt(field.Offset))
+	}
+	return md
+}
+
+// NewTBAAAccessTag returns a new TBAA access tag for use with !tbaa metadata
+// attachments, based on the given base type node, access type node and offset
+// in bytes of the access within the base type. If immutable is set, the
+// accessed memory is marked as constant.
+//
+// Example:
+//
+//    !{!4, !2, i64 0}
+func NewTBAAAccessTag(base, access *Tuple, offset uint64, immutable bool) *Tuple {
+	md := &Tuple{MetadataID: -1, Fields: []Field{base, access, newTBAAInt(offset)}}
+	if immutable {
+		md.Fields = append(md.Fields, newTBAAInt(1))
+	}
+	return md
+}
+
+// newTBAAInt returns a new i64 integer constant for use in TBAA nodes.
+func newTBAAInt(x uint64) *constant.Int {
+	c := constant.NewInt(types.I64, 0)
+	c.X.SetUint64(x)
+	return c
+}
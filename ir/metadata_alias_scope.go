@@ -0,0 +1,199 @@
+package ir
+
+import (
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/pkg/errors"
+)
+
+// --- [ Alias scope metadata ] ------------------------------------------------
+
+// SetAliasScopes sets the !alias.scope metadata attachment of the load
+// instruction to a list of the given alias scopes (see metadata.NewAliasScope).
+// An error is returned if any alias scope is invalid.
+//
+// Example:
+//
+//    %v = load i32, i32* %p, !alias.scope !3
+func (inst *InstLoad) SetAliasScopes(scopes ...*metadata.Tuple) error {
+	return setScopeList(&inst.Metadata, "alias.scope", scopes)
+}
+
+// SetNoAlias sets the !noalias metadata attachment of the load instruction to a
+// list of the given alias scopes (see metadata.NewAliasScope). An error is
+// returned if any alias scope is invalid.
+//
+// Example:
+//
+//    %v = load i32, i32* %p, !noalias !4
+func (inst *InstLoad) SetNoAlias(scopes ...*metadata.Tuple) error {
+	return setScopeList(&inst.Metadata, "noalias", scopes)
+}
+
+// SetAliasScopes sets the !alias.scope metadata attachment of the store
+// instruction to a list of the given alias scopes (see metadata.NewAliasScope).
+// An error is returned if any alias scope is invalid.
+func (inst *InstStore) SetAliasScopes(scopes ...*metadata.Tuple) error {
+	return setScopeList(&inst.Metadata, "alias.scope", scopes)
+}
+
+// SetNoAlias sets the !noalias metadata attachment of the store instruction to
+// a list of the given alias scopes (see metadata.NewAliasScope). An error is
+// returned if any alias scope is invalid.
+func (inst *InstStore) SetNoAlias(scopes ...*metadata.Tuple) error {
+	return setScopeList(&inst.Metadata, "noalias", scopes)
+}
+
+// SetAliasScopes sets the !alias.scope metadata attachment of the call
+// instruction to a list of the given alias scopes (see metadata.NewAliasScope).
+// An error is returned if any alias scope is invalid.
+func (inst *InstCall) SetAliasScopes(scopes ...*metadata.Tuple) error {
+	return setScopeList(&inst.Metadata, "alias.scope", scopes)
+}
+
+// SetNoAlias sets the !noalias metadata attachment of the call instruction to a
+// list of the given alias scopes (see metadata.NewAliasScope). An error is
+// returned if any alias scope is invalid.
+func (inst *InstCall) SetNoAlias(scopes ...*metadata.Tuple) error {
+	return setScopeList(&inst.Metadata, "noalias", scopes)
+}
+
+// GetAliasScopes returns the alias scopes of the !alias.scope metadata
+// attachment of the given instruction. The boolean return value indicates
+// success, and is false if the instruction has no well-formed !alias.scope
+// metadata attachment.
+func GetAliasScopes(inst Instruction) ([]*metadata.Tuple, bool) {
+	return getScopeList(inst, "alias.scope")
+}
+
+// GetNoAlias returns the alias scopes of the !noalias metadata attachment of the
+// given instruction. The boolean return value indicates success, and is false
+// if the instruction has no well-formed !noalias metadata attachment.
+func GetNoAlias(inst Instruction) ([]*metadata.Tuple, bool) {
+	return getScopeList(inst, "noalias")
+}
+
+// setScopeList sets the metadata attachment of the given name (!alias.scope or
+// !noalias) of the given metadata attachments to a list of the given alias
+// scopes, replacing any existing metadata attachment of the same name.
+func setScopeList(mds *Metadata, name string, scopes []*metadata.Tuple) error {
+	if len(scopes) == 0 {
+		return errors.Errorf("missing alias scopes of !%s metadata", name)
+	}
+	for _, scope := range scopes {
+		if err := verifyAliasScope(scope); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	mds.setAttachment(name, metadata.NewAliasScopeList(scopes...))
+	return nil
+}
+
+// getScopeList returns the alias scopes of the metadata attachment of the given
+// name (!alias.scope or !noalias) of the given instruction. The boolean return
+// value indicates success.
+func getScopeList(inst Instruction, name string) ([]*metadata.Tuple, bool) {
+	md, ok := inst.(interface {
+		MDAttachments() []*metadata.Attachment
+	})
+	if !ok {
+		return nil, false
+	}
+	for _, attachment := range md.MDAttachments() {
+		if attachment.Name != name {
+			continue
+		}
+		list, ok := attachment.Node.(*metadata.Tuple)
+		if !ok {
+			return nil, false
+		}
+		scopes := make([]*metadata.Tuple, 0, len(list.Fields))
+		for _, field := range list.Fields {
+			scope, ok := field.(*metadata.Tuple)
+			if !ok {
+				return nil, false
+			}
+			scopes = append(scopes, scope)
+		}
+		return scopes, true
+	}
+	return nil, false
+}
+
+// verifyAliasScopeMetadata reports an error if the given instruction has an
+// invalid !alias.scope or !noalias metadata attachment.
+func verifyAliasScopeMetadata(inst Instruction) error {
+	md, ok := inst.(interface {
+		MDAttachments() []*metadata.Attachment
+	})
+	if !ok {
+		return nil
+	}
+	for _, attachment := range md.MDAttachments() {
+		if attachment.Name != "alias.scope" && attachment.Name != "noalias" {
+			continue
+		}
+		scopes, ok := getScopeList(inst, attachment.Name)
+		if !ok {
+			return errors.Errorf("invalid !%s metadata %s; expected list of alias scopes", attachment.Name, attachment.Node.Ident())
+		}
+		for _, scope := range scopes {
+			if err := verifyAliasScope(scope); err != nil {
+				return errors.Wrapf(err, "invalid !%s metadata %s", attachment.Name, attachment.Node.Ident())
+			}
+		}
+	}
+	return nil
+}
+
+// verifyAliasScope reports an error if the given alias scope is invalid. An
+// alias scope consists of a unique identifier (a self-reference or a metadata
+// string), its alias scope domain and an optional name. An alias scope domain
+// consists of a unique identifier and an optional name.
+//
+// ref: https://llvm.org/docs/LangRef.html#noalias-and-alias-scope-metadata
+func verifyAliasScope(scope *metadata.Tuple) error {
+	if scope == nil {
+		return errors.New("invalid nil alias scope")
+	}
+	if len(scope.Fields) < 2 || len(scope.Fields) > 3 {
+		return errors.Errorf("invalid number of fields in alias scope %s; expected 2 or 3, got %d", scope.Ident(), len(scope.Fields))
+	}
+	if !isScopeIdentifier(scope) {
+		return errors.Errorf("invalid identifier of alias scope %s; expected self-reference or metadata string, got %s", scope.Ident(), scope.Fields[0])
+	}
+	domain, ok := scope.Fields[1].(*metadata.Tuple)
+	if !ok {
+		return errors.Errorf("invalid domain of alias scope %s; expected metadata tuple, got %s", scope.Ident(), scope.Fields[1])
+	}
+	if len(domain.Fields) < 1 || len(domain.Fields) > 2 || !isScopeIdentifier(domain) || !hasScopeName(domain, 1) {
+		return errors.Errorf("invalid domain %s of alias scope %s; expected identifier and optional name", domain.Ident(), scope.Ident())
+	}
+	if !hasScopeName(scope, 2) {
+		return errors.Errorf("invalid name of alias scope %s; expected metadata string, got %s", scope.Ident(), scope.Fields[2])
+	}
+	return nil
+}
+
+// hasScopeName reports whether the optional name at the given field index of
+// the given alias scope or alias scope domain is either absent or a metadata
+// string.
+func hasScopeName(node *metadata.Tuple, index int) bool {
+	if index >= len(node.Fields) {
+		return true
+	}
+	_, ok := node.Fields[index].(*metadata.String)
+	return ok
+}
+
+// isScopeIdentifier reports whether the first field of the given alias scope or
+// alias scope domain is a valid unique identifier; i.e. a self-reference or a
+// metadata string.
+func isScopeIdentifier(node *metadata.Tuple) bool {
+	switch id := node.Fields[0].(type) {
+	case *metadata.Tuple:
+		return id == node
+	case *metadata.String:
+		return true
+	}
+	return false
+}
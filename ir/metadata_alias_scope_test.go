@@ -0,0 +1,109 @@
+package ir_test
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/asm"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestAliasScopes(t *testing.T) {
+	domain := metadata.NewAliasScopeDomain("f")
+	a := metadata.NewAliasScope("f: %a", domain)
+	b := metadata.NewAliasScope("f: %b", domain)
+
+	m := ir.NewModule()
+	pa := ir.NewParam("a", types.I32Ptr)
+	pb := ir.NewParam("b", types.I32Ptr)
+	f := m.NewFunc("f", types.Void, pa, pb)
+	entry := f.NewBlock("entry")
+	load := entry.NewLoad(pa)
+	load.SetName("v")
+	store := entry.NewStore(load, pb)
+	entry.NewRet(nil)
+	if err := load.SetAliasScopes(a); err != nil {
+		t.Fatalf("unable to set !alias.scope metadata; %v", err)
+	}
+	if err := load.SetNoAlias(b); err != nil {
+		t.Fatalf("unable to set !noalias metadata; %v", err)
+	}
+	if err := store.SetAliasScopes(b); err != nil {
+		t.Fatalf("unable to set !alias.scope metadata; %v", err)
+	}
+	if err := store.SetNoAlias(a); err != nil {
+		t.Fatalf("unable to set !noalias metadata; %v", err)
+	}
+	// Define metadata nodes.
+	for _, node := range []metadata.Definition{domain, a, b, load.Metadata[0].Node.(*metadata.Tuple), load.Metadata[1].Node.(*metadata.Tuple)} {
+		m.MetadataDefs = append(m.MetadataDefs, node)
+	}
+	if err := m.AssignMetadataIDs(); err != nil {
+		t.Fatalf("unable to assign metadata IDs; %v", err)
+	}
+	if want, got := "%v = load i32, i32* %a, !alias.scope !3, !noalias !4", load.LLString(); want != got {
+		t.Errorf("load mismatch; expected %q, got %q", want, got)
+	}
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	// Round-trip.
+	m2, err := asm.ParseString("", m.String())
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	if want, got := m.String(), m2.String(); want != got {
+		t.Errorf("module mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+	if err := m2.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	load2 := m2.Funcs[0].Blocks[0].Insts[0]
+	if scopes, ok := ir.GetAliasScopes(load2); !ok || len(scopes) != 1 || scopes[0].LLString() != a.LLString() {
+		t.Errorf("alias scopes mismatch; expected [%v], got %v", a.LLString(), scopes)
+	}
+	if scopes, ok := ir.GetNoAlias(load2); !ok || len(scopes) != 1 || scopes[0].LLString() != b.LLString() {
+		t.Errorf("noalias scopes mismatch; expected [%v], got %v", b.LLString(), scopes)
+	}
+
+	// Self-referential alias scopes and domains, as emitted by LLVM.
+	m3, err := asm.ParseString("", `
+define void @g(i32* %a) {
+	%v = load i32, i32* %a, !alias.scope !2, !noalias !3
+	ret void
+}
+
+!0 = distinct !{!0, !"g"}
+!1 = distinct !{!1, !0, !"g: %a"}
+!2 = !{!1}
+!3 = !{!4}
+!4 = distinct !{!4, !0}
+`)
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	if err := m3.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+
+	// Invalid alias scopes.
+	golden := []struct {
+		scope *metadata.Tuple
+		want  string
+	}{
+		{scope: domain, want: `invalid number of fields in alias scope !0; expected 2 or 3, got 1`},
+		{scope: metadata.NewAliasScope("s", a), want: `invalid domain !1 of alias scope !{!"s", !1}; expected identifier and optional name`},
+		{scope: &metadata.Tuple{MetadataID: -1, Fields: []metadata.Field{domain, domain}}, want: `invalid identifier of alias scope !{!0, !0}; expected self-reference or metadata string, got !0`},
+	}
+	for _, g := range golden {
+		err := load.SetAliasScopes(g.scope)
+		if err == nil {
+			t.Errorf("expected error for alias scope %v, got nil", g.scope)
+			continue
+		}
+		if g.want != err.Error() {
+			t.Errorf("error mismatch for alias scope %v; expected %q, got %q", g.scope, g.want, err.Error())
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package ir
+
+import (
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/pkg/errors"
+)
+
+// --- [ Callees metadata ] ----------------------------------------------------
+
+// SetCallees sets the !callees metadata attachment of the call instruction to
+// the given functions; i.e. the possible callees of an indirect call.
+//
+// Example:
+//
+//    call void %fp(), !callees !{void ()* @f, void ()* @g}
+//
+// ref: https://llvm.org/docs/LangRef.html#callees-metadata
+func (inst *InstCall) SetCallees(fns ...*Func) {
+	inst.Metadata.setAttachment("callees", newCallees(fns))
+}
+
+// SetCallees sets the !callees metadata attachment of the invoke terminator to
+// the given functions; i.e. the possible callees of an indirect invoke.
+func (term *TermInvoke) SetCallees(fns ...*Func) {
+	term.Metadata.setAttachment("callees", newCallees(fns))
+}
+
+// GetCallees returns the functions of the !callees metadata attachment of the
+// given instruction. The boolean return value indicates success, and is false
+// if the instruction has no well-formed !callees metadata attachment.
+func GetCallees(inst Instruction) ([]*Func, bool) {
+	md, ok := inst.(interface {
+		MDAttachments() []*metadata.Attachment
+	})
+	if !ok {
+		return nil, false
+	}
+	for _, attachment := range md.MDAttachments() {
+		if attachment.Name != "callees" {
+			continue
+		}
+		tuple, ok := attachment.Node.(*metadata.Tuple)
+		if !ok {
+			return nil, false
+		}
+		fns := make([]*Func, 0, len(tuple.Fields))
+		for _, field := range tuple.Fields {
+			f, ok := field.(*Func)
+			if !ok {
+				return nil, false
+			}
+			fns = append(fns, f)
+		}
+		return fns, true
+	}
+	return nil, false
+}
+
+// newCallees returns a new !callees metadata tuple of the given functions.
+func newCallees(fns []*Func) *metadata.Tuple {
+	tuple := &metadata.Tuple{MetadataID: -1}
+	for _, f := range fns {
+		tuple.Fields = append(tuple.Fields, f)
+	}
+	return tuple
+}
+
+// verifyCalleesMetadata reports an error if the given instruction has an
+// invalid !callees metadata attachment.
+func verifyCalleesMetadata(inst Instruction) error {
+	md, ok := inst.(interface {
+		MDAttachments() []*metadata.Attachment
+	})
+	if !ok {
+		return nil
+	}
+	for _, attachment := range md.MDAttachments() {
+		if attachment.Name != "callees" {
+			continue
+		}
+		if _, ok := GetCallees(inst); !ok {
+			return errors.Errorf("invalid !callees metadata %s; expected tuple of functions", attachment.Node.Ident())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package ir_test
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/asm"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestCallees(t *testing.T) {
+	m := ir.NewModule()
+	f := m.NewFunc("f", types.Void)
+	f.NewBlock("entry").NewRet(nil)
+	g := m.NewFunc("g", types.Void)
+	g.NewBlock("entry").NewRet(nil)
+	fp := ir.NewParam("fp", types.NewPointer(types.NewFunc(types.Void)))
+	h := m.NewFunc("h", types.Void, fp)
+	entry := h.NewBlock("entry")
+	call := entry.NewCall(fp)
+	entry.NewRet(nil)
+	call.SetCallees(f, g)
+	if want, got := "call void %fp(), !callees !{void ()* @f, void ()* @g}", call.LLString(); want != got {
+		t.Errorf("call mismatch; expected %q, got %q", want, got)
+	}
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	// Round-trip of !callees metadata definition.
+	node, _ := call.Metadata[0].Node.(*metadata.Tuple)
+	m.MetadataDefs = append(m.MetadataDefs, node)
+	if err := m.AssignMetadataIDs(); err != nil {
+		t.Fatalf("unable to assign metadata IDs; %v", err)
+	}
+	if want, got := "call void %fp(), !callees !0", call.LLString(); want != got {
+		t.Errorf("call mismatch; expected %q, got %q", want, got)
+	}
+	m2, err := asm.ParseString("", m.String())
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	if want, got := m.String(), m2.String(); want != got {
+		t.Errorf("module mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+	if err := m2.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	callees, ok := ir.GetCallees(m2.Funcs[2].Blocks[0].Insts[0])
+	if !ok {
+		t.Fatalf("missing !callees metadata of call")
+	}
+	if len(callees) != 2 || callees[0] != m2.Funcs[0] || callees[1] != m2.Funcs[1] {
+		t.Errorf("callees mismatch; expected [@f @g], got %v", callees)
+	}
+	// Invalid !callees metadata.
+	node.Fields = append(node.Fields, &metadata.String{Value: "foo"})
+	if _, ok := ir.GetCallees(call); ok {
+		t.Errorf("unexpected callees of invalid !callees metadata")
+	}
+	if err := m.Verify(); err == nil {
+		t.Errorf("expected error for invalid !callees metadata, got nil")
+	}
+}
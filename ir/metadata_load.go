@@ -0,0 +1,90 @@
+package ir
+
+import (
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+	"github.com/pkg/errors"
+)
+
+// --- [ Non-null metadata ] ---------------------------------------------------
+
+// SetNonNull sets the !nonnull metadata attachment of the load instruction,
+// which indicates that the loaded pointer value is never null. An error is
+// returned if the load instruction is not of pointer type.
+//
+// Example:
+//
+//    %v = load i8*, i8** %p, !nonnull !{}
+func (inst *InstLoad) SetNonNull() error {
+	if !types.IsPointer(inst.Type()) {
+		return errors.Errorf("invalid type of load with !nonnull metadata; expected pointer type, got %s", inst.Type())
+	}
+	inst.Metadata.setAttachment("nonnull", &metadata.Tuple{MetadataID: -1})
+	return nil
+}
+
+// IsNonNull reports whether the load instruction has a !nonnull metadata
+// attachment.
+func (inst *InstLoad) IsNonNull() bool {
+	_, ok := inst.Metadata.attachment("nonnull")
+	return ok
+}
+
+// --- [ Dereferenceable metadata ] --------------------------------------------
+
+// SetDereferenceable sets the !dereferenceable metadata attachment of the load
+// instruction, which indicates that the loaded pointer value is dereferenceable
+// for the given number of bytes. An error is returned if the load instruction
+// is not of pointer type.
+//
+// Example:
+//
+//    %v = load i8*, i8** %p, !dereferenceable !{i64 8}
+func (inst *InstLoad) SetDereferenceable(n uint64) error {
+	if !types.IsPointer(inst.Type()) {
+		return errors.Errorf("invalid type of load with !dereferenceable metadata; expected pointer type, got %s", inst.Type())
+	}
+	x := constant.NewInt(types.I64, 0)
+	x.X.SetUint64(n)
+	node := &metadata.Tuple{MetadataID: -1, Fields: []metadata.Field{x}}
+	inst.Metadata.setAttachment("dereferenceable", node)
+	return nil
+}
+
+// Dereferenceable returns the number of dereferenceable bytes specified by the
+// !dereferenceable metadata attachment of the load instruction. The boolean
+// return value indicates success, and is false if the load instruction has no
+// well-formed !dereferenceable metadata attachment.
+func (inst *InstLoad) Dereferenceable() (uint64, bool) {
+	node, ok := inst.Metadata.attachment("dereferenceable")
+	if !ok {
+		return 0, false
+	}
+	tuple, ok := node.(*metadata.Tuple)
+	if !ok || len(tuple.Fields) != 1 {
+		return 0, false
+	}
+	x, ok := tuple.Fields[0].(*constant.Int)
+	if !ok || !x.Typ.Equal(types.I64) || !x.X.IsUint64() {
+		return 0, false
+	}
+	return x.X.Uint64(), true
+}
+
+// verifyLoadMetadata reports an error if the given load instruction has an
+// invalid !nonnull or !dereferenceable metadata attachment.
+func verifyLoadMetadata(inst *InstLoad) error {
+	if inst.IsNonNull() && !types.IsPointer(inst.Type()) {
+		return errors.Errorf("invalid !nonnull metadata of load %s; expected pointer type, got %s", inst.Ident(), inst.Type())
+	}
+	if _, ok := inst.Metadata.attachment("dereferenceable"); ok {
+		if !types.IsPointer(inst.Type()) {
+			return errors.Errorf("invalid !dereferenceable metadata of load %s; expected pointer type, got %s", inst.Ident(), inst.Type())
+		}
+		if _, ok := inst.Dereferenceable(); !ok {
+			return errors.Errorf("invalid !dereferenceable metadata of load %s; expected i64 constant", inst.Ident())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package ir
+
+import (
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+	"github.com/pkg/errors"
+)
+
+// --- [ Function entry count metadata ] ---------------------------------------
+
+// SetEntryCount sets the !prof metadata attachment of the function to the given
+// function entry count; i.e. the number of times the function was invoked, as
+// recorded by profile-guided optimization.
+//
+// Example:
+//
+//    define void @f() !prof !{!"function_entry_count", i64 42} { ... }
+//
+// ref: https://llvm.org/docs/BranchWeightMetadata.html#function-entry-counts
+func (f *Func) SetEntryCount(n uint64) {
+	// Store the entry count as signed integer, as printed by LLVM.
+	x := constant.NewInt(types.I64, int64(n))
+	node := &metadata.Tuple{
+		MetadataID: -1,
+		Fields:     []metadata.Field{&metadata.String{Value: "function_entry_count"}, x},
+	}
+	f.Metadata.setAttachment("prof", node)
+}
+
+// EntryCount returns the function entry count specified by the !prof metadata
+// attachment of the function. Both real and synthetic function entry counts
+// (synthetic_function_entry_count) are recognized. The boolean return value
+// indicates success, and is false if the function has no well-formed function
+// entry count.
+func (f *Func) EntryCount() (uint64, bool) {
+	node, ok := f.Metadata.attachment("prof")
+	if !ok {
+		return 0, false
+	}
+	tuple, ok := node.(*metadata.Tuple)
+	if !ok || len(tuple.Fields) < 2 || !isEntryCountKind(tuple.Fields[0]) {
+		return 0, false
+	}
+	// Function entry counts may be followed by the GUIDs of functions imported
+	// by ThinLTO.
+	x, ok := tuple.Fields[1].(*constant.Int)
+	if !ok || !x.Typ.Equal(types.I64) {
+		return 0, false
+	}
+	switch {
+	case x.X.IsInt64():
+		return uint64(x.X.Int64()), true
+	case x.X.IsUint64():
+		return x.X.Uint64(), true
+	}
+	return 0, false
+}
+
+// isEntryCountKind reports whether the given metadata field is the kind of a
+// function entry count.
+func isEntryCountKind(field metadata.Field) bool {
+	s, ok := field.(*metadata.String)
+	return ok && (s.Value == "function_entry_count" || s.Value == "synthetic_function_entry_count")
+}
+
+// verifyEntryCountMetadata reports an error if the given function has an
+// invalid function entry count in its !prof metadata attachment.
+func verifyEntryCountMetadata(f *Func) error {
+	node, ok := f.Metadata.attachment("prof")
+	if !ok {
+		return nil
+	}
+	tuple, ok := node.(*metadata.Tuple)
+	if !ok || len(tuple.Fields) == 0 || !isEntryCountKind(tuple.Fields[0]) {
+		return nil
+	}
+	if _, ok := f.EntryCount(); !ok {
+		return errors.Errorf("invalid !prof metadata %s of function %s; expected i64 function entry count", node.Ident(), f.Ident())
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package ir_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/llir/llvm/asm"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestEntryCount(t *testing.T) {
+	m := ir.NewModule()
+	f := m.NewFunc("f", types.Void)
+	f.NewBlock("entry").NewRet(nil)
+	if _, ok := f.EntryCount(); ok {
+		t.Errorf("unexpected function entry count of function without !prof metadata")
+	}
+	f.SetEntryCount(42)
+	f.SetEntryCount(math.MaxUint64)
+	if want, got := "define void @f() !prof !{!\"function_entry_count\", i64 -1} {\nentry:\n\tret void\n}", f.LLString(); want != got {
+		t.Errorf("function mismatch; expected %q, got %q", want, got)
+	}
+	if n, ok := f.EntryCount(); !ok || n != math.MaxUint64 {
+		t.Errorf("function entry count mismatch; expected %d, got %d", uint64(math.MaxUint64), n)
+	}
+	f.SetEntryCount(1000)
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	// Round-trip of !prof metadata definition.
+	node, _ := f.Metadata[0].Node.(*metadata.Tuple)
+	m.MetadataDefs = append(m.MetadataDefs, node)
+	if err := m.AssignMetadataIDs(); err != nil {
+		t.Fatalf("unable to assign metadata IDs; %v", err)
+	}
+	const want = `define void @f() !prof !0 {
+entry:
+	ret void
+}
+
+!0 = !{!"function_entry_count", i64 1000}
+`
+	if got := m.String(); got != want {
+		t.Errorf("module mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+	m2, err := asm.ParseString("", want)
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	if got := m2.String(); got != want {
+		t.Errorf("module mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+	if err := m2.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	if n, ok := m2.Funcs[0].EntryCount(); !ok || n != 1000 {
+		t.Errorf("function entry count mismatch; expected 1000, got %d", n)
+	}
+	// Synthetic function entry counts and imported function GUIDs.
+	m3, err := asm.ParseString("", `define void @g() !prof !{!"synthetic_function_entry_count", i64 7, i64 123456} {
+	ret void
+}`)
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	if n, ok := m3.Funcs[0].EntryCount(); !ok || n != 7 {
+		t.Errorf("function entry count mismatch; expected 7, got %d", n)
+	}
+	// Invalid function entry count.
+	node.Fields[1] = &metadata.String{Value: "foo"}
+	if _, ok := f.EntryCount(); ok {
+		t.Errorf("unexpected function entry count of invalid !prof metadata")
+	}
+	if err := m.Verify(); err == nil {
+		t.Errorf("expected error for invalid !prof metadata, got nil")
+	}
+}
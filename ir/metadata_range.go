@@ -0,0 +1,159 @@
+package ir
+
+import (
+	"math/big"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+	"github.com/pkg/errors"
+)
+
+// --- [ Range metadata ] ------------------------------------------------------
+
+// SetRange sets the !range metadata attachment of the load instruction based on
+// the given value ranges. Each pair specifies the lower bound (inclusive) and
+// upper bound (exclusive) of a range of possible values of the result. An error
+// is returned if the value ranges are invalid.
+func (inst *InstLoad) SetRange(pairs ...[2]*constant.Int) error {
+	return setRange(&inst.Metadata, inst.Type(), pairs)
+}
+
+// SetRange sets the !range metadata attachment of the call instruction based on
+// the given value ranges. Each pair specifies the lower bound (inclusive) and
+// upper bound (exclusive) of a range of possible values of the result. An error
+// is returned if the value ranges are invalid.
+func (inst *InstCall) SetRange(pairs ...[2]*constant.Int) error {
+	return setRange(&inst.Metadata, inst.Type(), pairs)
+}
+
+// SetRange sets the !range metadata attachment of the invoke terminator based
+// on the given value ranges. Each pair specifies the lower bound (inclusive)
+// and upper bound (exclusive) of a range of possible values of the result. An
+// error is returned if the value ranges are invalid.
+func (term *TermInvoke) SetRange(pairs ...[2]*constant.Int) error {
+	return setRange(&term.Metadata, term.Type(), pairs)
+}
+
+// GetRange returns the value ranges of the !range metadata attachment of the
+// given instruction. The boolean return value indicates success, and is false
+// if the instruction has no well-formed !range metadata attachment.
+func GetRange(inst Instruction) ([][2]*constant.Int, bool) {
+	md, ok := inst.(interface {
+		MDAttachments() []*metadata.Attachment
+	})
+	if !ok {
+		return nil, false
+	}
+	for _, attachment := range md.MDAttachments() {
+		if attachment.Name != "range" {
+			continue
+		}
+		tuple, ok := attachment.Node.(*metadata.Tuple)
+		if !ok || len(tuple.Fields) == 0 || len(tuple.Fields)%2 != 0 {
+			return nil, false
+		}
+		var pairs [][2]*constant.Int
+		for i := 0; i < len(tuple.Fields); i += 2 {
+			lo, ok := tuple.Fields[i].(*constant.Int)
+			if !ok {
+				return nil, false
+			}
+			hi, ok := tuple.Fields[i+1].(*constant.Int)
+			if !ok {
+				return nil, false
+			}
+			pairs = append(pairs, [2]*constant.Int{lo, hi})
+		}
+		return pairs, true
+	}
+	return nil, false
+}
+
+// setRange sets the !range metadata attachment of the given metadata
+// attachments, replacing any existing !range metadata attachment.
+func setRange(mds *Metadata, typ types.Type, pairs [][2]*constant.Int) error {
+	if err := verifyRange(typ, pairs); err != nil {
+		return errors.WithStack(err)
+	}
+	mds.setAttachment("range", metadata.NewRange(pairs...))
+	return nil
+}
+
+// verifyRangeMetadata reports an error if the given instruction has an invalid
+// !range metadata attachment.
+func verifyRangeMetadata(inst Instruction) error {
+	md, ok := inst.(interface {
+		MDAttachments() []*metadata.Attachment
+	})
+	if !ok {
+		return nil
+	}
+	for _, attachment := range md.MDAttachments() {
+		if attachment.Name != "range" {
+			continue
+		}
+		pairs, ok := GetRange(inst)
+		if !ok {
+			return errors.Errorf("invalid !range metadata %s; expected pairs of integer constants", attachment.Node.Ident())
+		}
+		if err := verifyRange(inst.(value.Value).Type(), pairs); err != nil {
+			return errors.Wrapf(err, "invalid !range metadata of %s", inst.(value.Value).Ident())
+		}
+	}
+	return nil
+}
+
+// verifyRange reports an error if the given value ranges are not valid !range
+// metadata for a value of the given type. Value ranges must be of the integer
+// type of the value, non-empty, sorted by lower bound, and must neither overlap
+// nor be contiguous. Only the last range may wrap around.
+//
+// ref: https://llvm.org/docs/LangRef.html#range-metadata
+func verifyRange(typ types.Type, pairs [][2]*constant.Int) error {
+	if !types.IsInt(typ) {
+		return errors.Errorf("invalid type of value with !range metadata; expected integer type, got %s", typ)
+	}
+	if len(pairs) == 0 {
+		return errors.New("missing value ranges of !range metadata")
+	}
+	var prevHi *big.Int
+	for i, pair := range pairs {
+		for _, bound := range pair {
+			if !bound.Type().Equal(typ) {
+				return errors.Errorf("invalid type of range %d bound %s; expected %s", i, bound, typ)
+			}
+		}
+		lo, hi := signedInt(pair[0]), signedInt(pair[1])
+		if lo.Cmp(hi) == 0 {
+			return errors.Errorf("invalid empty or full range %d [%s, %s)", i, lo, hi)
+		}
+		if prevHi != nil && prevHi.Cmp(lo) >= 0 {
+			return errors.Errorf("range %d [%s, %s) overlaps, is contiguous with or precedes the previous range", i, lo, hi)
+		}
+		if lo.Cmp(hi) > 0 {
+			// Wrapped range.
+			if i != len(pairs)-1 {
+				return errors.Errorf("invalid wrapped range %d [%s, %s); only the last range may wrap", i, lo, hi)
+			}
+			if first := signedInt(pairs[0][0]); i != 0 && hi.Cmp(first) >= 0 {
+				return errors.Errorf("wrapped range %d [%s, %s) overlaps or is contiguous with the first range", i, lo, hi)
+			}
+		}
+		prevHi = hi
+	}
+	return nil
+}
+
+// signedInt returns the value of the given integer constant, interpreted as a
+// signed integer of the bit size of its type.
+func signedInt(c *constant.Int) *big.Int {
+	bits := c.Typ.BitSize
+	mask := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+	x := new(big.Int).Mod(c.X, mask)
+	if bits > 0 && x.Bit(int(bits-1)) == 1 {
+		x.Sub(x, mask)
+	}
+	return x
+}
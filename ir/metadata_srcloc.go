@@ -0,0 +1,67 @@
+package ir
+
+import (
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+	"github.com/pkg/errors"
+)
+
+// --- [ Source location metadata ] --------------------------------------------
+
+// SetSrcLoc sets the !srcloc metadata attachment of the call instruction to the
+// given source location cookies; i.e. opaque values used by the front-end to
+// map diagnostics of inline assembly back to the source code. An error is
+// returned if the callee of the call instruction is not an inline assembler
+// expression.
+//
+// Example:
+//
+//    call void asm sideeffect "nop", ""(), !srcloc !{i64 42}
+func (inst *InstCall) SetSrcLoc(cookies ...int64) error {
+	if _, ok := inst.Callee.(*InlineAsm); !ok {
+		return errors.Errorf("invalid callee of call with !srcloc metadata; expected inline assembler expression, got %s", inst.Callee.Ident())
+	}
+	node := &metadata.Tuple{MetadataID: -1}
+	for _, cookie := range cookies {
+		node.Fields = append(node.Fields, constant.NewInt(types.I64, cookie))
+	}
+	inst.Metadata.setAttachment("srcloc", node)
+	return nil
+}
+
+// SrcLoc returns the source location cookies of the !srcloc metadata attachment
+// of the call instruction. The boolean return value indicates success, and is
+// false if the call instruction has no well-formed !srcloc metadata attachment.
+func (inst *InstCall) SrcLoc() ([]int64, bool) {
+	node, ok := inst.Metadata.attachment("srcloc")
+	if !ok {
+		return nil, false
+	}
+	tuple, ok := node.(*metadata.Tuple)
+	if !ok {
+		return nil, false
+	}
+	cookies := make([]int64, 0, len(tuple.Fields))
+	for _, field := range tuple.Fields {
+		x, ok := field.(*constant.Int)
+		if !ok || !x.X.IsInt64() {
+			return nil, false
+		}
+		cookies = append(cookies, x.X.Int64())
+	}
+	return cookies, true
+}
+
+// verifySrcLocMetadata reports an error if the given call instruction has an
+// invalid !srcloc metadata attachment.
+func verifySrcLocMetadata(inst *InstCall) error {
+	node, ok := inst.Metadata.attachment("srcloc")
+	if !ok {
+		return nil
+	}
+	if _, ok := inst.SrcLoc(); !ok {
+		return errors.Errorf("invalid !srcloc metadata %s; expected tuple of integer constants", node.Ident())
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package ir_test
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/asm"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestSrcLoc(t *testing.T) {
+	m := ir.NewModule()
+	f := m.NewFunc("f", types.Void)
+	entry := f.NewBlock("entry")
+	inlineAsm := ir.NewInlineAsm(types.NewFunc(types.Void), "nop", "")
+	inlineAsm.SideEffect = true
+	call := entry.NewCall(inlineAsm)
+	g := m.NewFunc("g", types.Void)
+	call2 := entry.NewCall(g)
+	entry.NewRet(nil)
+	if err := call.SetSrcLoc(12, 34); err != nil {
+		t.Fatalf("unable to set !srcloc metadata; %v", err)
+	}
+	if err := call2.SetSrcLoc(56); err == nil {
+		t.Errorf("expected error for !srcloc metadata of call to non-inline assembly, got nil")
+	}
+	if want, got := `call void asm sideeffect "nop", ""(), !srcloc !{i64 12, i64 34}`, call.LLString(); want != got {
+		t.Errorf("call mismatch; expected %q, got %q", want, got)
+	}
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	// Round-trip of !srcloc metadata definition.
+	node, _ := call.Metadata[0].Node.(*metadata.Tuple)
+	m.MetadataDefs = append(m.MetadataDefs, node)
+	if err := m.AssignMetadataIDs(); err != nil {
+		t.Fatalf("unable to assign metadata IDs; %v", err)
+	}
+	if want, got := `call void asm sideeffect "nop", ""(), !srcloc !0`, call.LLString(); want != got {
+		t.Errorf("call mismatch; expected %q, got %q", want, got)
+	}
+	m2, err := asm.ParseString("", m.String())
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	if want, got := m.String(), m2.String(); want != got {
+		t.Errorf("module mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+	if err := m2.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	call3, ok := m2.Funcs[0].Blocks[0].Insts[0].(*ir.InstCall)
+	if !ok {
+		t.Fatalf("invalid instruction; expected *ir.InstCall, got %T", m2.Funcs[0].Blocks[0].Insts[0])
+	}
+	cookies, ok := call3.SrcLoc()
+	if !ok {
+		t.Fatalf("missing !srcloc metadata of call")
+	}
+	if len(cookies) != 2 || cookies[0] != 12 || cookies[1] != 34 {
+		t.Errorf("source location cookies mismatch; expected [12 34], got %v", cookies)
+	}
+	// Invalid !srcloc metadata.
+	node.Fields = append(node.Fields, &metadata.String{Value: "foo"})
+	if _, ok := call.SrcLoc(); ok {
+		t.Errorf("unexpected source location cookies of invalid !srcloc metadata")
+	}
+	if err := m.Verify(); err == nil {
+		t.Errorf("expected error for invalid !srcloc metadata, got nil")
+	}
+}
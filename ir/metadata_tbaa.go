@@ -0,0 +1,218 @@
+package ir
+
+import (
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+	"github.com/pkg/errors"
+)
+
+// --- [ TBAA metadata ] -------------------------------------------------------
+
+// SetTBAA sets the !tbaa metadata attachment of the load instruction to the
+// given TBAA access tag (see metadata.NewTBAAAccessTag). An error is returned
+// if the access tag is invalid.
+//
+// Example:
+//
+//    %v = load i32, i32* %p, !tbaa !3
+func (inst *InstLoad) SetTBAA(tag *metadata.Tuple) error {
+	return setTBAA(&inst.Metadata, tag)
+}
+
+// SetTBAA sets the !tbaa metadata attachment of the store instruction to the
+// given TBAA access tag (see metadata.NewTBAAAccessTag). An error is returned
+// if the access tag is invalid.
+func (inst *InstStore) SetTBAA(tag *metadata.Tuple) error {
+	return setTBAA(&inst.Metadata, tag)
+}
+
+// SetTBAA sets the !tbaa metadata attachment of the call instruction (e.g. a
+// call to llvm.memcpy) to the given TBAA access tag (see
+// metadata.NewTBAAAccessTag). An error is returned if the access tag is
+// invalid.
+func (inst *InstCall) SetTBAA(tag *metadata.Tuple) error {
+	return setTBAA(&inst.Metadata, tag)
+}
+
+// GetTBAA returns the TBAA access tag of the !tbaa metadata attachment of the
+// given instruction. The boolean return value indicates success, and is false
+// if the instruction has no !tbaa metadata attachment of tuple type.
+func GetTBAA(inst Instruction) (*metadata.Tuple, bool) {
+	md, ok := inst.(interface {
+		MDAttachments() []*metadata.Attachment
+	})
+	if !ok {
+		return nil, false
+	}
+	for _, attachment := range md.MDAttachments() {
+		if attachment.Name == "tbaa" {
+			tag, ok := attachment.Node.(*metadata.Tuple)
+			return tag, ok
+		}
+	}
+	return nil, false
+}
+
+// setTBAA sets the !tbaa metadata attachment of the given metadata attachments,
+// replacing any existing !tbaa metadata attachment.
+func setTBAA(mds *Metadata, tag *metadata.Tuple) error {
+	if err := verifyTBAATag(tag); err != nil {
+		return errors.WithStack(err)
+	}
+	mds.setAttachment("tbaa", tag)
+	return nil
+}
+
+// verifyTBAAMetadata reports an error if the given instruction has an invalid
+// !tbaa metadata attachment.
+func verifyTBAAMetadata(inst Instruction) error {
+	md, ok := inst.(interface {
+		MDAttachments() []*metadata.Attachment
+	})
+	if !ok {
+		return nil
+	}
+	for _, attachment := range md.MDAttachments() {
+		if attachment.Name != "tbaa" {
+			continue
+		}
+		tag, ok := attachment.Node.(*metadata.Tuple)
+		if !ok {
+			return errors.Errorf("invalid !tbaa metadata %s; expected metadata tuple", attachment.Node.Ident())
+		}
+		if err := verifyTBAATag(tag); err != nil {
+			return errors.Wrapf(err, "invalid !tbaa metadata %s", tag.Ident())
+		}
+	}
+	return nil
+}
+
+// verifyTBAATag reports an error if the given TBAA access tag is invalid. An
+// access tag consists of a base type node, an access type node, an i64 offset
+// and an optional i64 constant flag. The access type must be reachable from the
+// base type; i.e. it is the type of the field at the given offset within the
+// base type, or an ancestor thereof.
+//
+// Access tags of the old TBAA format (i.e. scalar type nodes) are accepted
+// without verification.
+//
+// ref: https://llvm.org/docs/LangRef.html#tbaa-metadata
+func verifyTBAATag(tag *metadata.Tuple) error {
+	if tag == nil {
+		return errors.New("invalid nil TBAA access tag")
+	}
+	if len(tag.Fields) > 0 {
+		if _, ok := tag.Fields[0].(*metadata.String); ok {
+			// Scalar TBAA type node used as access tag (old TBAA format), which is
+			// not verified.
+			return nil
+		}
+	}
+	if len(tag.Fields) != 3 && len(tag.Fields) != 4 {
+		return errors.Errorf("invalid number of fields in TBAA access tag; expected 3 or 4, got %d", len(tag.Fields))
+	}
+	base, ok := tag.Fields[0].(*metadata.Tuple)
+	if !ok {
+		return errors.Errorf("invalid base type of TBAA access tag; expected metadata tuple, got %s", tag.Fields[0])
+	}
+	access, ok := tag.Fields[1].(*metadata.Tuple)
+	if !ok {
+		return errors.Errorf("invalid access type of TBAA access tag; expected metadata tuple, got %s", tag.Fields[1])
+	}
+	for _, t := range []*metadata.Tuple{base, access} {
+		if err := verifyTBAAType(t, make(map[*metadata.Tuple]bool)); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if len(access.Fields) == 1 {
+		return errors.Errorf("invalid access type of TBAA access tag; expected scalar type node, got root node %s", access.Ident())
+	}
+	offset, ok := tbaaInt(tag.Fields[2])
+	if !ok {
+		return errors.Errorf("invalid offset of TBAA access tag; expected i64 constant, got %s", tag.Fields[2])
+	}
+	if len(tag.Fields) == 4 {
+		if c, ok := tbaaInt(tag.Fields[3]); !ok || c > 1 {
+			return errors.Errorf("invalid constant flag of TBAA access tag; expected i64 0 or 1, got %s", tag.Fields[3])
+		}
+	}
+	// Walk the fields of the base type at the given offset, and the ancestors
+	// of the field type, until the access type is found.
+	cur, off := base, offset
+	visited := make(map[*metadata.Tuple]bool)
+	for !visited[cur] {
+		visited[cur] = true
+		if cur == access && off == 0 {
+			return nil
+		}
+		next, fieldOffset, ok := tbaaFieldAt(cur, off)
+		if !ok {
+			break
+		}
+		cur = next
+		off -= fieldOffset
+	}
+	return errors.Errorf("access type %s not reachable from base type %s at offset %d of TBAA access tag", access.Ident(), base.Ident(), offset)
+}
+
+// verifyTBAAType reports an error if the given TBAA type node is invalid. A
+// type node is either a root node consisting of a name, or a scalar or struct
+// type node consisting of a name followed by pairs of type node and i64 offset.
+func verifyTBAAType(t *metadata.Tuple, visited map[*metadata.Tuple]bool) error {
+	if visited[t] {
+		return nil
+	}
+	visited[t] = true
+	if len(t.Fields) == 0 || len(t.Fields)%2 != 1 {
+		return errors.Errorf("invalid TBAA type node %s; expected name followed by pairs of type node and offset", t.Ident())
+	}
+	if _, ok := t.Fields[0].(*metadata.String); !ok {
+		return errors.Errorf("invalid name of TBAA type node %s; expected metadata string, got %s", t.Ident(), t.Fields[0])
+	}
+	for i := 1; i < len(t.Fields); i += 2 {
+		field, ok := t.Fields[i].(*metadata.Tuple)
+		if !ok {
+			return errors.Errorf("invalid field type of TBAA type node %s; expected metadata tuple, got %s", t.Ident(), t.Fields[i])
+		}
+		if _, ok := tbaaInt(t.Fields[i+1]); !ok {
+			return errors.Errorf("invalid field offset of TBAA type node %s; expected i64 constant, got %s", t.Ident(), t.Fields[i+1])
+		}
+		if err := verifyTBAAType(field, visited); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// tbaaFieldAt returns the type node and offset of the field of the given TBAA
+// type node containing the given offset; i.e. the last field with an offset
+// not greater than the given offset. The boolean return value indicates
+// success, and is false for root nodes.
+//
+// pre-condition: t is a valid TBAA type node.
+func tbaaFieldAt(t *metadata.Tuple, offset uint64) (*metadata.Tuple, uint64, bool) {
+	var field *metadata.Tuple
+	var fieldOffset uint64
+	for i := 1; i < len(t.Fields); i += 2 {
+		off, _ := tbaaInt(t.Fields[i+1])
+		if field != nil && off > offset {
+			break
+		}
+		field, fieldOffset = t.Fields[i].(*metadata.Tuple), off
+	}
+	if field == nil || fieldOffset > offset {
+		return nil, 0, false
+	}
+	return field, fieldOffset, true
+}
+
+// tbaaInt returns the value of the given i64 constant TBAA node field. The
+// boolean return value indicates success.
+func tbaaInt(field metadata.Field) (uint64, bool) {
+	c, ok := field.(*constant.Int)
+	if !ok || !c.Typ.Equal(types.I64) || !c.X.IsUint64() {
+		return 0, false
+	}
+	return c.X.Uint64(), true
+}
@@ -0,0 +1,92 @@
+package ir_test
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/asm"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestTBAA(t *testing.T) {
+	root := metadata.NewTBAARoot("Simple C/C++ TBAA")
+	char := metadata.NewTBAAScalarType("omnipotent char", root)
+	i32 := metadata.NewTBAAScalarType("int", char)
+	f32 := metadata.NewTBAAScalarType("float", char)
+	s := metadata.NewTBAAStructType("S", metadata.TBAAField{Type: i32, Offset: 0}, metadata.TBAAField{Type: f32, Offset: 4})
+	tag := metadata.NewTBAAAccessTag(s, f32, 4, false)
+
+	m := ir.NewModule()
+	for _, node := range []*metadata.Tuple{root, char, i32, f32, s, tag} {
+		m.MetadataDefs = append(m.MetadataDefs, node)
+	}
+	if err := m.AssignMetadataIDs(); err != nil {
+		t.Fatalf("unable to assign metadata IDs; %v", err)
+	}
+	p := ir.NewParam("p", types.NewPointer(types.Float))
+	f := m.NewFunc("f", types.Float, p)
+	entry := f.NewBlock("entry")
+	load := entry.NewLoad(p)
+	load.SetName("v")
+	entry.NewRet(load)
+	if err := load.SetTBAA(tag); err != nil {
+		t.Fatalf("unable to set !tbaa metadata; %v", err)
+	}
+	if want, got := "%v = load float, float* %p, !tbaa !5", load.LLString(); want != got {
+		t.Errorf("load mismatch; expected %q, got %q", want, got)
+	}
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	// Round-trip.
+	m2, err := asm.ParseString("", m.String())
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	if want, got := m.String(), m2.String(); want != got {
+		t.Errorf("module mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+	if err := m2.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	tag2, ok := ir.GetTBAA(m2.Funcs[0].Blocks[0].Insts[0])
+	if !ok {
+		t.Fatalf("missing !tbaa metadata of load")
+	}
+	if want, got := "!{!4, !3, i64 4}", tag2.LLString(); want != got {
+		t.Errorf("TBAA access tag mismatch; expected %q, got %q", want, got)
+	}
+
+	// Invalid access tags.
+	golden := []struct {
+		tag  *metadata.Tuple
+		want string
+	}{
+		// Access type not at offset of base type.
+		{tag: metadata.NewTBAAAccessTag(s, f32, 0, false), want: "access type !3 not reachable from base type !4 at offset 0 of TBAA access tag"},
+		// Access type not an ancestor of the field type.
+		{tag: metadata.NewTBAAAccessTag(char, i32, 0, false), want: "access type !2 not reachable from base type !1 at offset 0 of TBAA access tag"},
+		// Root node as access type.
+		{tag: metadata.NewTBAAAccessTag(s, root, 0, false), want: "invalid access type of TBAA access tag; expected scalar type node, got root node !0"},
+		// Missing offset.
+		{tag: &metadata.Tuple{MetadataID: -1, Fields: []metadata.Field{s, i32}}, want: "invalid number of fields in TBAA access tag; expected 3 or 4, got 2"},
+	}
+	for _, g := range golden {
+		err := load.SetTBAA(g.tag)
+		if err == nil {
+			t.Errorf("expected error for TBAA access tag %v, got nil", g.tag)
+			continue
+		}
+		if g.want != err.Error() {
+			t.Errorf("error mismatch for TBAA access tag %v; expected %q, got %q", g.tag, g.want, err.Error())
+		}
+	}
+	// Access tags with base type and access type of scalar type nodes, and of
+	// ancestors.
+	for _, tag := range []*metadata.Tuple{metadata.NewTBAAAccessTag(i32, i32, 0, true), metadata.NewTBAAAccessTag(s, char, 0, false)} {
+		if err := load.SetTBAA(tag); err != nil {
+			t.Errorf("unexpected error for TBAA access tag %v; %v", tag, err)
+		}
+	}
+}
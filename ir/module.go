@@ -1,10 +1,13 @@
 package ir
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/llir/llvm/internal/enc"
+	"github.com/llir/llvm/ir/constant"
 	"github.com/llir/llvm/ir/enum"
 	"github.com/llir/llvm/ir/metadata"
 	"github.com/llir/llvm/ir/types"
@@ -47,10 +50,18 @@ type Module struct {
 	NamedMetadataDefs map[string]*metadata.NamedDef
 	// (optional) Metadata definitions.
 	MetadataDefs []metadata.Definition
+	// (optional) Custom metadata kind names (without '!' prefix) of metadata
+	// attachments; see MetadataKindID.
+	MetadataKinds []string
 	// (optional) Use-list order directives.
 	UseListOrders []*UseListOrder
 	// (optional) Basic block specific use-list order directives.
 	UseListOrderBBs []*UseListOrderBB
+
+	// Name index of global values; built on first lookup.
+	symbols symbolIndex
+	// ID index of metadata definitions; built on first lookup.
+	mdIndex metadataIndex
 }
 
 // NewModule returns a new LLVM IR module.
@@ -64,9 +75,40 @@ func NewModule() *Module {
 // syntax.
 func (m *Module) String() string {
 	buf := &strings.Builder{}
-	// Assign metadata IDs.
+	if _, err := m.WriteTo(buf); err != nil {
+		panic(fmt.Errorf("unable to write module; %v", err))
+	}
+	return buf.String()
+}
+
+// WriteTo writes the LLVM IR assembly of the module to w, and returns the
+// number of bytes written. The output is identical to that of Module.String,
+// but written incrementally; buffered output is flushed to w after each
+// function.
+//
+// WriteTo implements the io.WriterTo interface.
+func (m *Module) WriteTo(w io.Writer) (int64, error) {
+	return m.writeTo(w, nil)
+}
+
+// writeTo writes the LLVM IR assembly of the module to w, with the given
+// comments printed before the associated entities of the module, and returns
+// the number of bytes written.
+func (m *Module) writeTo(w io.Writer, comments Comments) (int64, error) {
+	cw := &countWriter{w: w}
+	buf := bufio.NewWriter(cw)
+	// nonEmpty reports whether any output has been written.
+	nonEmpty := func() bool {
+		return cw.n+int64(buf.Buffered()) > 0
+	}
+	// Printing recurses on the operands of constants.
+	if err := m.checkConstDepth(); err != nil {
+		return 0, errors.WithStack(err)
+	}
+	// Assign global IDs and metadata IDs.
+	m.AssignGlobalIDs()
 	if err := m.AssignMetadataIDs(); err != nil {
-		panic(fmt.Errorf("unable to assign metadata IDs of module; %v", err))
+		return 0, errors.Wrap(err, "unable to assign metadata IDs of module")
 	}
 	// Source filename.
 	if len(m.SourceFilename) > 0 {
@@ -84,7 +126,7 @@ func (m *Module) String() string {
 		fmt.Fprintf(buf, "target triple = %s\n", quote(m.TargetTriple))
 	}
 	// Module-level inline assembly.
-	if len(m.ModuleAsms) > 0 && buf.Len() > 0 {
+	if len(m.ModuleAsms) > 0 && nonEmpty() {
 		buf.WriteString("\n")
 	}
 	for _, asm := range m.ModuleAsms {
@@ -92,7 +134,7 @@ func (m *Module) String() string {
 		fmt.Fprintf(buf, "module asm %s\n", quote(asm))
 	}
 	// Type definitions.
-	if len(m.TypeDefs) > 0 && buf.Len() > 0 {
+	if len(m.TypeDefs) > 0 && nonEmpty() {
 		buf.WriteString("\n")
 	}
 	for _, t := range m.TypeDefs {
@@ -102,45 +144,52 @@ func (m *Module) String() string {
 		fmt.Fprintf(buf, "%s = type %s\n", t, t.LLString())
 	}
 	// Comdat definitions.
-	if len(m.ComdatDefs) > 0 && buf.Len() > 0 {
+	if len(m.ComdatDefs) > 0 && nonEmpty() {
 		buf.WriteString("\n")
 	}
 	for _, def := range m.ComdatDefs {
 		fmt.Fprintln(buf, def.LLString())
 	}
 	// Global declarations and definitions.
-	if len(m.Globals) > 0 && buf.Len() > 0 {
+	if len(m.Globals) > 0 && nonEmpty() {
 		buf.WriteString("\n")
 	}
 	for _, g := range m.Globals {
+		writeComments(buf, comments, g, "")
 		fmt.Fprintln(buf, g.LLString())
 	}
 	// Aliases.
-	if len(m.Aliases) > 0 && buf.Len() > 0 {
+	if len(m.Aliases) > 0 && nonEmpty() {
 		buf.WriteString("\n")
 	}
 	for _, alias := range m.Aliases {
+		writeComments(buf, comments, alias, "")
 		fmt.Fprintln(buf, alias.LLString())
 	}
 	// IFuncs.
-	if len(m.IFuncs) > 0 && buf.Len() > 0 {
+	if len(m.IFuncs) > 0 && nonEmpty() {
 		buf.WriteString("\n")
 	}
 	for _, ifunc := range m.IFuncs {
+		writeComments(buf, comments, ifunc, "")
 		fmt.Fprintln(buf, ifunc.LLString())
 	}
 	// Function declarations and definitions.
-	if len(m.Funcs) > 0 && buf.Len() > 0 {
+	if len(m.Funcs) > 0 && nonEmpty() {
 		buf.WriteString("\n")
 	}
 	for i, f := range m.Funcs {
 		if i != 0 {
 			buf.WriteString("\n")
 		}
-		fmt.Fprintln(buf, f.LLString())
+		writeComments(buf, comments, f, "")
+		fmt.Fprintln(buf, f.llString(comments))
+		if err := buf.Flush(); err != nil {
+			return cw.n, errors.WithStack(err)
+		}
 	}
 	// Attribute group definitions.
-	if len(m.AttrGroupDefs) > 0 && buf.Len() > 0 {
+	if len(m.AttrGroupDefs) > 0 && nonEmpty() {
 		buf.WriteString("\n")
 	}
 	for _, a := range m.AttrGroupDefs {
@@ -152,7 +201,7 @@ func (m *Module) String() string {
 		mdNames = append(mdNames, mdName)
 	}
 	natsort.Strings(mdNames)
-	if len(m.NamedMetadataDefs) > 0 && buf.Len() > 0 {
+	if len(m.NamedMetadataDefs) > 0 && nonEmpty() {
 		buf.WriteString("\n")
 	}
 	for _, mdName := range mdNames {
@@ -161,7 +210,7 @@ func (m *Module) String() string {
 		fmt.Fprintf(buf, "%s = %s\n", md.Ident(), md.LLString())
 	}
 	// Metadata definitions.
-	if len(m.MetadataDefs) > 0 && buf.Len() > 0 {
+	if len(m.MetadataDefs) > 0 && nonEmpty() {
 		buf.WriteString("\n")
 	}
 	for _, md := range m.MetadataDefs {
@@ -171,20 +220,59 @@ func (m *Module) String() string {
 		fmt.Fprintf(buf, "%s = %s\n", md.Ident(), md.LLString())
 	}
 	// Use-list orders.
-	if len(m.UseListOrders) > 0 && buf.Len() > 0 {
+	if len(m.UseListOrders) > 0 && nonEmpty() {
 		buf.WriteString("\n")
 	}
 	for _, u := range m.UseListOrders {
 		fmt.Fprintln(buf, u)
 	}
 	// Basic block specific use-list orders.
-	if len(m.UseListOrderBBs) > 0 && buf.Len() > 0 {
+	if len(m.UseListOrderBBs) > 0 && nonEmpty() {
 		buf.WriteString("\n")
 	}
 	for _, u := range m.UseListOrderBBs {
 		fmt.Fprintln(buf, u)
 	}
-	return buf.String()
+	if err := buf.Flush(); err != nil {
+		return cw.n, errors.WithStack(err)
+	}
+	return cw.n, nil
+}
+
+// countWriter is an io.Writer which counts the number of bytes written to the
+// underlying writer.
+type countWriter struct {
+	// Underlying writer.
+	w io.Writer
+	// Number of bytes written.
+	n int64
+}
+
+// Write writes p to the underlying writer.
+func (cw *countWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// checkConstDepth reports an error if the nesting depth of a constant of the
+// module exceeds constant.MaxDepth.
+func (m *Module) checkConstDepth() error {
+	var err error
+	visit := func(c constant.Constant) {
+		if err == nil {
+			err = constant.CheckDepth(c)
+		}
+	}
+	// Only the outermost constants are visited; their operands are checked by
+	// constant.CheckDepth without recursion.
+	skipConst := func(c constant.Constant) bool {
+		return true
+	}
+	r := &constReplacer{visited: make(map[constant.Constant]bool), visit: visit, skipConst: skipConst}
+	r.replaceModule(m)
+	r.replaceMetadata(m)
+	return err
 }
 
 // ~~~ [ Comdat Definition ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
@@ -304,9 +392,49 @@ func (u *UseListOrderBB) String() string {
 
 // ### [ Helper functions ] ####################################################
 
+// AssignGlobalIDs assigns IDs to the unnamed global values of the module (e.g.
+// @0 of `@0 = global i32 5`). IDs are assigned in order of output; i.e. global
+// variables, aliases, IFuncs and functions.
+//
+// As in LLVM, the ID of an unnamed global value is determined by its position
+// among the unnamed global values of the module, and previously assigned IDs
+// are thus reassigned.
+func (m *Module) AssignGlobalIDs() {
+	id := int64(0)
+	setID := func(g value.Named) {
+		if isUnnamedGlobal(g) {
+			g.(globalIDer).SetID(id)
+			id++
+		}
+	}
+	for _, g := range m.Globals {
+		setID(g)
+	}
+	for _, alias := range m.Aliases {
+		setID(alias)
+	}
+	for _, ifunc := range m.IFuncs {
+		setID(ifunc)
+	}
+	for _, f := range m.Funcs {
+		setID(f)
+	}
+}
+
 // AssignMetadataIDs assigns metadata IDs to the unnamed metadata definitions of
-// the module.
+// the module. Metadata nodes without IDs which are part of a reference cycle
+// (e.g. !0 = distinct !{!0}) are first added to the metadata definitions of the
+// module.
 func (m *Module) AssignMetadataIDs() error {
+	m.invalidateMetadataIDs()
+	return m.assignMetadataIDs()
+}
+
+// assignMetadataIDs assigns metadata IDs to the unnamed metadata definitions of
+// the module, without invalidating the ID index of the metadata definitions.
+func (m *Module) assignMetadataIDs() error {
+	// Add metadata nodes part of reference cycles to the metadata definitions.
+	m.addCyclicMetadataDefs()
 	// Index used IDs.
 	used := make(map[int64]bool)
 	for _, md := range m.MetadataDefs {
@@ -340,3 +468,104 @@ func (m *Module) AssignMetadataIDs() error {
 	}
 	return nil
 }
+
+// ResolveParents back-fills the parent pointers of the module; the parent
+// module of each function, the parent function of each basic block and the
+// parent basic block of each instruction and terminator.
+//
+// ResolveParents must be re-run after structural edits which move basic blocks
+// or instructions between parents without using the builder methods.
+func (m *Module) ResolveParents() {
+	for _, f := range m.Funcs {
+		f.Parent = m
+		f.ResolveParents()
+	}
+}
+
+// ResolveParents back-fills the parent pointers of the function; the parent
+// function of each basic block and the parent basic block of each instruction
+// and terminator.
+//
+// ResolveParents must be re-run after structural edits which move basic blocks
+// or instructions between parents without using the builder methods.
+func (f *Func) ResolveParents() {
+	for _, block := range f.Blocks {
+		block.Parent = f
+		for _, inst := range block.Insts {
+			setParent(inst, block)
+		}
+		if block.Term != nil {
+			setParent(block.Term, block)
+		}
+	}
+}
+
+// ResolveBlockAddresses resolves the basic blocks of blockaddress constants in
+// the module, including blockaddress constants referenced by metadata, in a
+// single pass over the module. A blockaddress constant is resolved if its basic
+// block is a placeholder not part of the function (e.g. a basic block with only
+// a name or local ID, as created before the function body is translated); in
+// which case it is replaced by the basic block of the function with the same
+// name or local ID.
+//
+// An error is returned listing all blockaddress constants which could not be
+// resolved.
+//
+// pre-condition: local IDs assigned to the basic blocks of functions.
+func (m *Module) ResolveBlockAddresses() error {
+	// Basic blocks of functions, indexed by local identifier; computed on first
+	// use.
+	funcBlocks := make(map[*Func]map[LocalIdent]*Block)
+	// Basic blocks part of functions.
+	members := make(map[*Block]*Func)
+	var unresolved []string
+	seen := make(map[string]bool)
+	visit := func(c constant.Constant) {
+		ba, ok := c.(*constant.BlockAddress)
+		if !ok {
+			return
+		}
+		f, ok := ba.Func.(*Func)
+		if !ok {
+			return
+		}
+		blocks, ok := funcBlocks[f]
+		if !ok {
+			blocks = make(map[LocalIdent]*Block, len(f.Blocks))
+			for _, block := range f.Blocks {
+				blocks[block.LocalIdent] = block
+				members[block] = f
+			}
+			funcBlocks[f] = blocks
+		}
+		placeholder, ok := ba.Block.(*Block)
+		if !ok || members[placeholder] == f {
+			// Already resolved.
+			return
+		}
+		block, ok := blocks[placeholder.LocalIdent]
+		if !ok {
+			if s := ba.Ident(); !seen[s] {
+				seen[s] = true
+				unresolved = append(unresolved, s)
+			}
+			return
+		}
+		ba.Block = block
+	}
+	r := &constReplacer{visited: make(map[constant.Constant]bool), visit: visit}
+	r.replaceModule(m)
+	r.replaceMetadata(m)
+	if len(unresolved) > 0 {
+		return errors.Errorf("unable to locate basic blocks of blockaddress constants: %s", strings.Join(unresolved, ", "))
+	}
+	return nil
+}
+
+// setParent sets the parent basic block of the given instruction or
+// terminator.
+func setParent(inst interface{}, block *Block) {
+	if inst, ok := inst.(interface{ setParent(*Block) }); ok {
+		inst.setParent(block)
+	}
+}
@@ -0,0 +1,52 @@
+package ir
+
+import (
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/value"
+)
+
+// --- [ Address-taken functions ] ---------------------------------------------
+
+// AddressTakenFunctions returns the functions of the module which have their
+// address taken; i.e. functions which may be the target of an indirect call.
+//
+// The address of a function is taken if the function is used other than as the
+// direct callee of a call instruction or invoke terminator; e.g. as an argument
+// of a call, the operand of a store or constant expression, or in the
+// initializer of a global variable, the aliasee of an alias or the resolver of
+// an IFunc. References by blockaddress constants do not take the address of a
+// function.
+func (m *Module) AddressTakenFunctions() map[*Func]bool {
+	taken := make(map[*Func]bool)
+	visit := func(c constant.Constant) {
+		if f, ok := c.(*Func); ok {
+			taken[f] = true
+		}
+	}
+	skipConst := func(c constant.Constant) bool {
+		_, ok := c.(*constant.BlockAddress)
+		return ok
+	}
+	r := &constReplacer{visited: make(map[constant.Constant]bool), visit: visit, skipConst: skipConst, skipOperand: isDirectCallee}
+	r.replaceModule(m)
+	return taken
+}
+
+// isDirectCallee reports whether the given operand of the instruction is a
+// function directly called by the instruction.
+func isDirectCallee(inst Instruction, operand *value.Value) bool {
+	switch inst := inst.(type) {
+	case *InstCall:
+		if operand != &inst.Callee {
+			return false
+		}
+	case *TermInvoke:
+		if operand != &inst.Invokee {
+			return false
+		}
+	default:
+		return false
+	}
+	_, ok := (*operand).(*Func)
+	return ok
+}
@@ -0,0 +1,88 @@
+package ir_test
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/asm"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestAddressTakenFunctions(t *testing.T) {
+	const src = `
+@fp = global void ()* @in_init
+@table = global [1 x i8*] [i8* bitcast (void ()* @in_expr to i8*)]
+
+@alias = alias void (), void ()* @aliasee
+@ifunc = ifunc void (), void ()* ()* @resolver
+
+declare void @called()
+
+declare void @stored()
+
+declare void @arg()
+
+declare void @bitcast_callee()
+
+declare void @invoked()
+
+declare void @use(void ()*)
+
+declare void @in_init()
+
+declare void @in_expr()
+
+declare void @aliasee()
+
+declare void ()* @resolver()
+
+declare i32 @personality(...)
+
+define void @f(void ()** %p) personality i32 (...)* @personality {
+entry:
+	call void @called()
+	store void ()* @stored, void ()** %p
+	call void @use(void ()* @arg)
+	invoke void @invoked()
+		to label %exit unwind label %lpad
+
+lpad:
+	%0 = landingpad { i8*, i32 } cleanup
+	ret void
+
+exit:
+	ret void
+}
+
+define void @g() {
+entry:
+	br label %bb
+
+bb:
+	call void @f(void ()** null)
+	ret void
+}
+
+@ba = global i8* blockaddress(@g, %bb)
+`
+	m, err := asm.ParseString("", src)
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	// Call through bitcast of function.
+	callee, _ := m.Func("bitcast_callee")
+	f, _ := m.Func("f")
+	sig := types.NewPointer(types.NewFunc(types.Void, types.I32))
+	f.Blocks[0].NewCall(constant.NewBitCast(callee, sig), constant.NewInt(types.I32, 0))
+	var got []string
+	for f := range m.AddressTakenFunctions() {
+		got = append(got, f.Name())
+	}
+	sort.Strings(got)
+	want := []string{"aliasee", "arg", "bitcast_callee", "in_expr", "in_init", "personality", "resolver", "stored"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("address-taken functions mismatch; expected %v, got %v", want, got)
+	}
+}
@@ -7,5 +7,6 @@ import "github.com/llir/llvm/ir/constant"
 func (m *Module) NewAlias(name string, aliasee constant.Constant) *Alias {
 	alias := NewAlias(name, aliasee)
 	m.Aliases = append(m.Aliases, alias)
+	m.addSymbol(alias)
 	return alias
 }
@@ -0,0 +1,159 @@
+package ir
+
+import (
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+	"github.com/pkg/errors"
+)
+
+// --- [ Debug information ] ---------------------------------------------------
+
+const (
+	// Name of the named metadata definition listing the compile units of the
+	// module.
+	dbgCU = "llvm.dbg.cu"
+	// Name of the named metadata definition listing the module flags.
+	moduleFlags = "llvm.module.flags"
+	// Key of the module flag specifying the debug information metadata version.
+	debugInfoVersionKey = "Debug Info Version"
+	// Debug information metadata version supported by LLVM (i.e.
+	// DEBUG_METADATA_VERSION).
+	debugInfoVersion = 3
+	// Behaviour of the debug information version module flag on conflicting
+	// values when linking modules (i.e. emit a warning).
+	moduleFlagWarning = 2
+)
+
+// AddCompileUnit registers the given compile unit in the !llvm.dbg.cu named
+// metadata definition of the module, and adds the compile unit to the metadata
+// definitions of the module if not already present. Compile units are distinct
+// metadata nodes, and the compile unit is thus marked as distinct.
+//
+// The "Debug Info Version" module flag (in !llvm.module.flags) is added if not
+// already present, as required by LLVM for debug information to be emitted.
+//
+// Example:
+//
+//    !llvm.dbg.cu = !{!0}
+//    !llvm.module.flags = !{!2}
+//
+//    !0 = distinct !DICompileUnit(language: DW_LANG_C99, file: !1, producer: "foo", emissionKind: FullDebug)
+//    !1 = !DIFile(filename: "foo.c", directory: "/home/u")
+//    !2 = !{i32 2, !"Debug Info Version", i32 3}
+func (m *Module) AddCompileUnit(cu *metadata.DICompileUnit) {
+	cu.Distinct = true
+	m.addMetadataDef(cu)
+	m.addDebugInfoVersion()
+	def := m.namedMetadataDef(dbgCU)
+	for _, node := range def.Nodes {
+		if node == cu {
+			return
+		}
+	}
+	def.Nodes = append(def.Nodes, cu)
+}
+
+// CompileUnits returns the compile units of the !llvm.dbg.cu named metadata
+// definition of the module.
+func (m *Module) CompileUnits() []*metadata.DICompileUnit {
+	def, ok := m.NamedMetadataDefs[dbgCU]
+	if !ok {
+		return nil
+	}
+	var cus []*metadata.DICompileUnit
+	for _, node := range def.Nodes {
+		if cu, ok := node.(*metadata.DICompileUnit); ok {
+			cus = append(cus, cu)
+		}
+	}
+	return cus
+}
+
+// addDebugInfoVersion adds the "Debug Info Version" module flag to the
+// !llvm.module.flags named metadata definition of the module, if not already
+// present.
+//
+// Example:
+//
+//    !{i32 2, !"Debug Info Version", i32 3}
+func (m *Module) addDebugInfoVersion() {
+	def := m.namedMetadataDef(moduleFlags)
+	for _, node := range def.Nodes {
+		flag, ok := node.(*metadata.Tuple)
+		if !ok || len(flag.Fields) != 3 {
+			continue
+		}
+		if key, ok := flag.Fields[1].(*metadata.String); ok && key.Value == debugInfoVersionKey {
+			return
+		}
+	}
+	flag := &metadata.Tuple{
+		MetadataID: -1,
+		Fields: []metadata.Field{
+			constant.NewInt(types.I32, moduleFlagWarning),
+			&metadata.String{Value: debugInfoVersionKey},
+			constant.NewInt(types.I32, debugInfoVersion),
+		},
+	}
+	m.addMetadataDef(flag)
+	def.Nodes = append(def.Nodes, flag)
+}
+
+// verifyDebugInfo reports an error if the DISubprogram attached to a function
+// definition of the module is not reachable from a compile unit of the
+// !llvm.dbg.cu named metadata definition.
+func (m *Module) verifyDebugInfo() error {
+	cus := make(map[*metadata.DICompileUnit]bool)
+	for _, cu := range m.CompileUnits() {
+		cus[cu] = true
+	}
+	for _, f := range m.Funcs {
+		if len(f.Blocks) == 0 {
+			// Skip function declarations.
+			continue
+		}
+		node, ok := f.Metadata.attachment("dbg")
+		if !ok {
+			continue
+		}
+		sp, ok := node.(*metadata.DISubprogram)
+		if !ok {
+			return errors.Errorf("invalid !dbg metadata attachment of function %s; expected DISubprogram, got %s", f.Ident(), node.Ident())
+		}
+		if sp.Unit == nil {
+			return errors.Errorf("missing compile unit of DISubprogram %s attached to function %s", sp.Ident(), f.Ident())
+		}
+		if !cus[sp.Unit] {
+			return errors.Errorf("compile unit %s of DISubprogram %s attached to function %s not listed in !%s", sp.Unit.Ident(), sp.Ident(), f.Ident(), dbgCU)
+		}
+	}
+	return nil
+}
+
+// ### [ Helper functions ] ####################################################
+
+// namedMetadataDef returns the named metadata definition of the module with the
+// given name, creating it if not already present.
+func (m *Module) namedMetadataDef(name string) *metadata.NamedDef {
+	if m.NamedMetadataDefs == nil {
+		m.NamedMetadataDefs = make(map[string]*metadata.NamedDef)
+	}
+	def, ok := m.NamedMetadataDefs[name]
+	if !ok {
+		def = &metadata.NamedDef{Name: name}
+		m.NamedMetadataDefs[name] = def
+	}
+	return def
+}
+
+// addMetadataDef adds the given metadata definition to the module, if not
+// already present.
+func (m *Module) addMetadataDef(md metadata.Definition) {
+	for _, def := range m.MetadataDefs {
+		if def == md {
+			return
+		}
+	}
+	m.MetadataDefs = append(m.MetadataDefs, md)
+}
@@ -0,0 +1,73 @@
+package ir_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/asm"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestAddCompileUnit(t *testing.T) {
+	m := ir.NewModule()
+	file := &metadata.DIFile{MetadataID: -1, Filename: "foo.c", Directory: "/home/u"}
+	cu := &metadata.DICompileUnit{MetadataID: -1, Language: enum.DwarfLangC99, File: file, Producer: "foo", EmissionKind: enum.EmissionKindFullDebug}
+	subroutineType := &metadata.DISubroutineType{MetadataID: -1, Types: &metadata.Tuple{MetadataID: -1, Fields: []metadata.Field{metadata.Null}}}
+	sp := &metadata.DISubprogram{MetadataID: -1, Distinct: true, Name: "f", File: file, Line: 1, Type: subroutineType, ScopeLine: 1, SPFlags: enum.DISPFlagDefinition, Unit: cu}
+	m.MetadataDefs = append(m.MetadataDefs, file, subroutineType, sp)
+	f := m.NewFunc("f", types.Void)
+	f.Metadata = append(f.Metadata, &metadata.Attachment{Name: "dbg", Node: sp})
+	f.NewBlock("entry").NewRet(nil)
+
+	// Compile unit not listed in !llvm.dbg.cu.
+	want := "not listed in !llvm.dbg.cu"
+	if err := m.Verify(); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("error mismatch; expected %q, got %v", want, err)
+	}
+	m.AddCompileUnit(cu)
+	// Registering the compile unit again is a no-op.
+	m.AddCompileUnit(cu)
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	if got := m.CompileUnits(); len(got) != 1 || got[0] != cu {
+		t.Errorf("compile units mismatch; expected [%v], got %v", cu, got)
+	}
+	if !cu.Distinct {
+		t.Errorf("expected distinct compile unit")
+	}
+	if n := len(m.NamedMetadataDefs["llvm.module.flags"].Nodes); n != 1 {
+		t.Errorf("number of module flags mismatch; expected 1, got %d", n)
+	}
+	s := m.String()
+	for _, want := range []string{
+		"!llvm.dbg.cu = !{!3}",
+		"!llvm.module.flags = !{!4}",
+		`!3 = distinct !DICompileUnit(language: DW_LANG_C99, file: !0, producer: "foo", emissionKind: FullDebug)`,
+		`!4 = !{i32 2, !"Debug Info Version", i32 3}`,
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("missing %q in module:\n%s", want, s)
+		}
+	}
+	// Round-trip.
+	m2, err := asm.ParseString("", s)
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	if got := m2.String(); s != got {
+		t.Errorf("module mismatch; expected:\n%s\ngot:\n%s", s, got)
+	}
+	if err := m2.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	// Missing compile unit of DISubprogram.
+	sp.Unit = nil
+	want = "missing compile unit of DISubprogram"
+	if err := m.Verify(); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("error mismatch; expected %q, got %v", want, err)
+	}
+}
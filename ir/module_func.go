@@ -1,6 +1,11 @@
 package ir
 
-import "github.com/llir/llvm/ir/types"
+import (
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+	"github.com/pkg/errors"
+)
 
 // --- [ Functions ] -----------------------------------------------------------
 
@@ -12,5 +17,90 @@ func (m *Module) NewFunc(name string, retType types.Type, params ...*Param) *Fun
 	f := NewFunc(name, retType, params...)
 	f.Parent = m
 	m.Funcs = append(m.Funcs, f)
+	m.addSymbol(f)
 	return f
 }
+
+// GetOrInsertFunc returns the function of the given name in the module,
+// declaring it based on the given function signature if not already present.
+//
+// GetOrInsertFunc panics if the function is present with a different function
+// signature, or if a global variable, alias or IFunc of the given name is
+// present; use GetOrInsertFuncErr to handle such errors.
+func (m *Module) GetOrInsertFunc(name string, sig *types.FuncType) *Func {
+	f, err := m.GetOrInsertFuncErr(name, sig)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// GetOrInsertFuncErr returns the function of the given name in the module,
+// declaring it based on the given function signature if not already present.
+// An error is returned if the function is present with a different function
+// signature, or if a global variable, alias or IFunc of the given name is
+// present.
+func (m *Module) GetOrInsertFuncErr(name string, sig *types.FuncType) (*Func, error) {
+	if v := m.lookupGlobal(name); v != nil {
+		f, ok := v.(*Func)
+		if !ok {
+			return nil, errors.Errorf("unable to declare function %q; global %s of type %T already present", name, v.Ident(), v)
+		}
+		if !f.Sig.Equal(sig) {
+			return nil, errors.Errorf("function signature mismatch of %q; expected %v, got %v", name, sig, f.Sig)
+		}
+		return f, nil
+	}
+	params := make([]*Param, len(sig.Params))
+	for i, param := range sig.Params {
+		params[i] = NewParam("", param)
+	}
+	f := m.NewFunc(name, sig.RetType, params...)
+	f.Sig.Variadic = sig.Variadic
+	return f, nil
+}
+
+// NewThunk appends a new thunk function to the module, which forwards its
+// arguments to the target function using a musttail call. The thunk has the
+// function signature, calling convention, return attributes and parameter
+// attributes of the target function.
+//
+// An error is returned if the target function is variadic, as forwarding of
+// variable arguments (i.e. musttail call with '...') is not supported.
+//
+// Example:
+//
+//    define i32 @thunk(i32 %x, i8* %y) {
+//    ; <label>:0
+//       %1 = musttail call i32 @target(i32 %x, i8* %y)
+//       ret i32 %1
+//    }
+func (m *Module) NewThunk(name string, target *Func) (*Func, error) {
+	if target.Sig.Variadic {
+		return nil, errors.Errorf("unable to create thunk %q of variadic function %s", name, target.Ident())
+	}
+	params := make([]*Param, len(target.Params))
+	args := make([]value.Value, len(target.Params))
+	for i, param := range target.Params {
+		p := &Param{Typ: param.Typ}
+		if !param.IsUnnamed() {
+			p.LocalName = param.LocalName
+		}
+		p.Attrs = append(p.Attrs, param.Attrs...)
+		params[i] = p
+		args[i] = p
+	}
+	f := m.NewFunc(name, target.Sig.RetType, params...)
+	f.CallingConv = target.CallingConv
+	f.ReturnAttrs = append(f.ReturnAttrs, target.ReturnAttrs...)
+	entry := f.NewBlock("")
+	call := entry.NewCall(target, args...)
+	call.Tail = enum.TailMustTail
+	call.CallingConv = target.CallingConv
+	if types.IsVoid(target.Sig.RetType) {
+		entry.NewRet(nil)
+	} else {
+		entry.NewRet(call)
+	}
+	return f, nil
+}
@@ -0,0 +1,54 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestNewThunk(t *testing.T) {
+	m := NewModule()
+	x := NewParam("x", types.I32, enum.ParamAttrSignExt)
+	target := m.NewFunc("target", types.I32, x, NewParam("", types.I8Ptr))
+	target.CallingConv = enum.CallingConvFast
+	thunk, err := m.NewThunk("thunk", target)
+	if err != nil {
+		t.Fatalf("unable to create thunk; %v", err)
+	}
+	want := `define fastcc i32 @thunk(i32 signext %x, i8*) {
+; <label>:1
+	%2 = musttail call fastcc i32 @target(i32 %x, i8* %0)
+	ret i32 %2
+}`
+	if got := thunk.LLString(); want != got {
+		t.Errorf("thunk mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+	target.NewBlock("entry").NewRet(x)
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	// Thunk of function returning void.
+	g := m.NewFunc("g", types.Void)
+	want = `define void @g_thunk() {
+; <label>:0
+	musttail call void @g()
+	ret void
+}`
+	gThunk, err := m.NewThunk("g_thunk", g)
+	if err != nil {
+		t.Fatalf("unable to create thunk; %v", err)
+	}
+	if got := gThunk.LLString(); want != got {
+		t.Errorf("thunk mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+	// Thunks of variadic functions are not supported.
+	printf := m.GetOrInsertFunc("printf", types.NewFunc(types.I32, types.I8Ptr))
+	printf.Sig.Variadic = true
+	if f, err := m.NewThunk("printf_thunk", printf); err == nil {
+		t.Errorf("expected error for thunk of variadic function, got %v", f)
+	}
+	if _, ok := m.Func("printf_thunk"); ok {
+		t.Errorf("unexpected thunk of variadic function in module")
+	}
+}
@@ -0,0 +1,217 @@
+package ir
+
+import (
+	"strings"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+	"github.com/pkg/errors"
+)
+
+// --- [ Garbage collection statepoints ] --------------------------------------
+
+// Base names of the garbage collection statepoint intrinsics.
+const (
+	gcStatepoint = "llvm.experimental.gc.statepoint"
+	gcRelocate   = "llvm.experimental.gc.relocate"
+	gcResult     = "llvm.experimental.gc.result"
+)
+
+// GCStatepoint returns a new call instruction to the
+// llvm.experimental.gc.statepoint intrinsic, calling the given target function
+// with the given function arguments. The pointers of gcLive are recorded in a
+// "gc-live" operand bundle, and may be relocated by the garbage collector (see
+// Module.GCRelocate). The overloaded intrinsic (e.g.
+// llvm.experimental.gc.statepoint.p0f_isVoidf) is declared in the module if not
+// already present.
+//
+// The call instruction is not appended to any basic block.
+//
+// Example:
+//
+//    %token = call token (i64, i32, void (i8 addrspace(1)*)*, i32, i32, ...) @llvm.experimental.gc.statepoint.p0f_isVoidp1i8f(i64 0, i32 0, void (i8 addrspace(1)*)* @f, i32 1, i32 0, i8 addrspace(1)* %obj, i32 0, i32 0) [ "gc-live"(i8 addrspace(1)* %obj) ]
+//
+// ref: https://llvm.org/docs/Statepoints.html#llvm-experimental-gc-statepoint-intrinsic
+func (m *Module) GCStatepoint(id uint64, numPatchBytes uint32, target value.Value, args []value.Value, gcLive []value.Value) (*InstCall, error) {
+	sig, err := targetSig(target)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := verifyArgs("gc.statepoint", target, sig, args); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for _, ptr := range gcLive {
+		if !types.IsPointer(ptr.Type()) {
+			return nil, errors.Errorf("invalid gc-live value %s of gc.statepoint; expected pointer type, got %s", ptr.Ident(), ptr.Type())
+		}
+	}
+	statepointSig := types.NewFunc(types.Token, types.I64, types.I32, target.Type(), types.I32, types.I32)
+	statepointSig.Variadic = true
+	name := MangleIntrinsic(gcStatepoint, target.Type())
+	callee := m.GetOrInsertIntrinsic(name, statepointSig)
+	// Statepoint ID, number of patch bytes, target function, number of call
+	// arguments and flags, followed by the call arguments and the (deprecated)
+	// number of transition and deoptimization arguments.
+	statepointArgs := []value.Value{
+		constant.NewInt(types.I64, int64(id)),
+		constant.NewInt(types.I32, int64(numPatchBytes)),
+		target,
+		constant.NewInt(types.I32, int64(len(args))),
+		constant.NewInt(types.I32, 0),
+	}
+	statepointArgs = append(statepointArgs, args...)
+	statepointArgs = append(statepointArgs, constant.NewInt(types.I32, 0), constant.NewInt(types.I32, 0))
+	inst := NewCall(callee, statepointArgs...)
+	inst.OperandBundles = []*OperandBundle{NewOperandBundle("gc-live", gcLive...)}
+	return inst, nil
+}
+
+// GCRelocate returns a new call instruction to the llvm.experimental.gc.relocate
+// intrinsic, producing the relocated derived pointer at the given index of the
+// "gc-live" operand bundle of the given statepoint; which points into the object
+// of the base pointer at the given index. The overloaded intrinsic (e.g.
+// llvm.experimental.gc.relocate.p1i8) is declared in the module if not already
+// present.
+//
+// An error is returned if statepoint is not a call to the
+// llvm.experimental.gc.statepoint intrinsic, or if the base or derived index is
+// out of bounds.
+//
+// The call instruction is not appended to any basic block.
+//
+// ref: https://llvm.org/docs/Statepoints.html#llvm-experimental-gc-relocate
+func (m *Module) GCRelocate(statepoint *InstCall, base, derived int) (*InstCall, error) {
+	gcLive, err := statepointGCLive(statepoint)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := verifyGCLiveIndices(statepoint, gcLive, int64(base), int64(derived)); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	typ := gcLive[derived].Type()
+	sig := types.NewFunc(typ, types.Token, types.I32, types.I32)
+	name := MangleIntrinsic(gcRelocate, typ)
+	callee := m.GetOrInsertIntrinsic(name, sig)
+	return NewCall(callee, statepoint, constant.NewInt(types.I32, int64(base)), constant.NewInt(types.I32, int64(derived))), nil
+}
+
+// GCResult returns a new call instruction to the llvm.experimental.gc.result
+// intrinsic, producing the return value of the target function called by the
+// given statepoint. The overloaded intrinsic (e.g.
+// llvm.experimental.gc.result.i32) is declared in the module if not already
+// present.
+//
+// An error is returned if statepoint is not a call to the
+// llvm.experimental.gc.statepoint intrinsic, or if the target function has void
+// return type.
+//
+// The call instruction is not appended to any basic block.
+//
+// ref: https://llvm.org/docs/Statepoints.html#llvm-experimental-gc-result
+func (m *Module) GCResult(statepoint *InstCall) (*InstCall, error) {
+	if !isIntrinsicCall(statepoint, gcStatepoint) || len(statepoint.Args) < 3 {
+		return nil, errors.Errorf("invalid statepoint %s; expected call to %s", statepoint.Ident(), gcStatepoint)
+	}
+	sig, err := targetSig(statepoint.Args[2])
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if sig.RetType.Equal(types.Void) {
+		return nil, errors.Errorf("invalid statepoint %s of gc.result; expected target function with non-void return type", statepoint.Ident())
+	}
+	resultSig := types.NewFunc(sig.RetType, types.Token)
+	name := MangleIntrinsic(gcResult, sig.RetType)
+	callee := m.GetOrInsertIntrinsic(name, resultSig)
+	return NewCall(callee, statepoint), nil
+}
+
+// verifyGCRelocate reports an error if the given call instruction is an invalid
+// call to the llvm.experimental.gc.relocate intrinsic; i.e. if the base or
+// derived index is out of bounds of the "gc-live" operand bundle of its
+// statepoint.
+func verifyGCRelocate(inst *InstCall) error {
+	if !isIntrinsicCall(inst, gcRelocate) {
+		return nil
+	}
+	if len(inst.Args) != 3 {
+		return errors.Errorf("invalid number of arguments of gc.relocate %s; expected 3, got %d", inst.Ident(), len(inst.Args))
+	}
+	statepoint, ok := inst.Args[0].(*InstCall)
+	if !ok {
+		return errors.Errorf("invalid statepoint of gc.relocate %s; expected call to %s, got %s", inst.Ident(), gcStatepoint, inst.Args[0].Ident())
+	}
+	gcLive, err := statepointGCLive(statepoint)
+	if err != nil {
+		return errors.Wrapf(err, "invalid statepoint of gc.relocate %s", inst.Ident())
+	}
+	base, ok := inst.Args[1].(*constant.Int)
+	if !ok || !base.X.IsInt64() {
+		return errors.Errorf("invalid base index of gc.relocate %s; expected i32 constant, got %s", inst.Ident(), inst.Args[1].Ident())
+	}
+	derived, ok := inst.Args[2].(*constant.Int)
+	if !ok || !derived.X.IsInt64() {
+		return errors.Errorf("invalid derived index of gc.relocate %s; expected i32 constant, got %s", inst.Ident(), inst.Args[2].Ident())
+	}
+	if err := verifyGCLiveIndices(statepoint, gcLive, base.X.Int64(), derived.X.Int64()); err != nil {
+		return errors.Wrapf(err, "invalid gc.relocate %s", inst.Ident())
+	}
+	if typ := gcLive[derived.X.Int64()].Type(); !inst.Type().Equal(typ) {
+		return errors.Errorf("type mismatch between gc.relocate %s and derived pointer %s; expected %s, got %s", inst.Ident(), gcLive[derived.X.Int64()].Ident(), typ, inst.Type())
+	}
+	return nil
+}
+
+// ### [ Helper functions ] ####################################################
+
+// statepointGCLive returns the inputs of the "gc-live" operand bundle of the
+// given call to the llvm.experimental.gc.statepoint intrinsic.
+func statepointGCLive(statepoint *InstCall) ([]value.Value, error) {
+	if !isIntrinsicCall(statepoint, gcStatepoint) {
+		return nil, errors.Errorf("invalid statepoint %s; expected call to %s", statepoint.Ident(), gcStatepoint)
+	}
+	for _, bundle := range statepoint.OperandBundles {
+		if bundle.Tag == "gc-live" {
+			return bundle.Inputs, nil
+		}
+	}
+	return nil, errors.Errorf("missing gc-live operand bundle of statepoint %s", statepoint.Ident())
+}
+
+// verifyGCLiveIndices reports an error if the given base or derived index is
+// out of bounds of the "gc-live" operand bundle of the given statepoint, or if
+// the indexed values are not of pointer type.
+func verifyGCLiveIndices(statepoint *InstCall, gcLive []value.Value, base, derived int64) error {
+	for _, index := range []struct {
+		kind string
+		i    int64
+	}{{kind: "base", i: base}, {kind: "derived", i: derived}} {
+		if index.i < 0 || index.i >= int64(len(gcLive)) {
+			return errors.Errorf("%s pointer index %d out of bounds of gc-live operand bundle of statepoint %s with %d values", index.kind, index.i, statepoint.Ident(), len(gcLive))
+		}
+		if ptr := gcLive[index.i]; !types.IsPointer(ptr.Type()) {
+			return errors.Errorf("invalid %s pointer %s at index %d of gc-live operand bundle of statepoint %s; expected pointer type, got %s", index.kind, ptr.Ident(), index.i, statepoint.Ident(), ptr.Type())
+		}
+	}
+	return nil
+}
+
+// targetSig returns the function signature of the given call target.
+func targetSig(target value.Value) (*types.FuncType, error) {
+	t, ok := target.Type().(*types.PointerType)
+	if !ok {
+		return nil, errors.Errorf("invalid target type of statepoint; expected *types.PointerType, got %T", target.Type())
+	}
+	sig, ok := t.ElemType.(*types.FuncType)
+	if !ok {
+		return nil, errors.Errorf("invalid target type of statepoint; expected *types.FuncType, got %T", t.ElemType)
+	}
+	return sig, nil
+}
+
+// isIntrinsicCall reports whether the given call instruction calls an
+// overloaded intrinsic of the given base name.
+func isIntrinsicCall(inst *InstCall, base string) bool {
+	callee, ok := inst.Callee.(*Func)
+	return ok && strings.HasPrefix(callee.Name(), base+".")
+}
@@ -0,0 +1,102 @@
+package ir_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/asm"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+func TestGCStatepoint(t *testing.T) {
+	m := ir.NewModule()
+	objType := types.NewPointer(types.I8)
+	objType.AddrSpace = 1
+	callee := m.NewFunc("callee", types.I32, ir.NewParam("x", types.I32))
+	f := m.NewFunc("f", objType, ir.NewParam("obj", objType))
+	f.GC = "statepoint-example"
+	obj := f.Params[0]
+	entry := f.NewBlock("entry")
+	statepoint, err := m.GCStatepoint(0, 0, callee, []value.Value{constant.NewInt(types.I32, 42)}, []value.Value{obj})
+	if err != nil {
+		t.Fatalf("unable to create gc.statepoint; %v", err)
+	}
+	statepoint.SetName("token")
+	relocate, err := m.GCRelocate(statepoint, 0, 0)
+	if err != nil {
+		t.Fatalf("unable to create gc.relocate; %v", err)
+	}
+	relocate.SetName("obj.relocated")
+	result, err := m.GCResult(statepoint)
+	if err != nil {
+		t.Fatalf("unable to create gc.result; %v", err)
+	}
+	result.SetName("result")
+	entry.Insts = append(entry.Insts, statepoint, relocate, result)
+	entry.NewRet(relocate)
+	golden := []struct {
+		in   *ir.InstCall
+		want string
+	}{
+		{
+			in:   statepoint,
+			want: `%token = call token (i64, i32, i32 (i32)*, i32, i32, ...) @llvm.experimental.gc.statepoint.p0f_i32i32f(i64 0, i32 0, i32 (i32)* @callee, i32 1, i32 0, i32 42, i32 0, i32 0) [ "gc-live"(i8 addrspace(1)* %obj) ]`,
+		},
+		{
+			in:   relocate,
+			want: `%obj.relocated = call i8 addrspace(1)* @llvm.experimental.gc.relocate.p1i8(token %token, i32 0, i32 0)`,
+		},
+		{
+			in:   result,
+			want: `%result = call i32 @llvm.experimental.gc.result.i32(token %token)`,
+		},
+	}
+	for _, g := range golden {
+		if got := g.in.LLString(); g.want != got {
+			t.Errorf("intrinsic call mismatch; expected `%v`, got `%v`", g.want, got)
+		}
+	}
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	// Round-trip, including the garbage collector name.
+	m2, err := asm.ParseString("", m.String())
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	if want, got := m.String(), m2.String(); want != got {
+		t.Errorf("module mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+	if want, got := "statepoint-example", m2.Funcs[1].GC; want != got {
+		t.Errorf("garbage collector name mismatch; expected %q, got %q", want, got)
+	}
+	if err := m2.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+
+	// Invalid base and derived pointer indices.
+	if _, err := m.GCRelocate(statepoint, 0, 1); err == nil {
+		t.Errorf("expected error for out of bounds derived pointer index, got nil")
+	}
+	relocate.Args[1] = constant.NewInt(types.I32, 1)
+	want := "invalid gc.relocate %obj.relocated: base pointer index 1 out of bounds of gc-live operand bundle of statepoint %token with 1 values"
+	if err := m.Verify(); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("error mismatch; expected %q, got %v", want, err)
+	}
+	// gc.result of void target function.
+	void := m.NewFunc("void", types.Void)
+	sp, err := m.GCStatepoint(0, 0, void, nil, nil)
+	if err != nil {
+		t.Fatalf("unable to create gc.statepoint; %v", err)
+	}
+	if _, err := m.GCResult(sp); err == nil {
+		t.Errorf("expected error for gc.result of void target function, got nil")
+	}
+	// Argument mismatch of target function.
+	if _, err := m.GCStatepoint(0, 0, callee, nil, nil); err == nil {
+		t.Errorf("expected error for argument count mismatch of target function, got nil")
+	}
+}
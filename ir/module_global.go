@@ -12,6 +12,7 @@ import (
 func (m *Module) NewGlobal(name string, contentType types.Type) *Global {
 	g := NewGlobal(name, contentType)
 	m.Globals = append(m.Globals, g)
+	m.addSymbol(g)
 	return g
 }
 
@@ -20,5 +21,6 @@ func (m *Module) NewGlobal(name string, contentType types.Type) *Global {
 func (m *Module) NewGlobalDef(name string, init constant.Constant) *Global {
 	g := NewGlobalDef(name, init)
 	m.Globals = append(m.Globals, g)
+	m.addSymbol(g)
 	return g
 }
@@ -7,5 +7,6 @@ import "github.com/llir/llvm/ir/constant"
 func (m *Module) NewIFunc(name string, resolver constant.Constant) *IFunc {
 	ifunc := NewIFunc(name, resolver)
 	m.IFuncs = append(m.IFuncs, ifunc)
+	m.addSymbol(ifunc)
 	return ifunc
 }
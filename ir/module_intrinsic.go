@@ -0,0 +1,188 @@
+package ir
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// --- [ Intrinsics ] ----------------------------------------------------------
+
+// Memcpy returns a new call instruction to the llvm.memcpy intrinsic based on
+// the given destination address, source address, length in bytes and volatile
+// flag. The overloaded intrinsic (e.g. llvm.memcpy.p0i8.p0i8.i64) is declared
+// in the module if not already present.
+//
+// The call instruction is not appended to any basic block.
+func (m *Module) Memcpy(dst, src, len value.Value, isVolatile bool) *InstCall {
+	return m.memTransfer("llvm.memcpy", dst, src, len, isVolatile)
+}
+
+// Memmove returns a new call instruction to the llvm.memmove intrinsic based on
+// the given destination address, source address, length in bytes and volatile
+// flag. The overloaded intrinsic (e.g. llvm.memmove.p0i8.p0i8.i64) is declared
+// in the module if not already present.
+//
+// The call instruction is not appended to any basic block.
+func (m *Module) Memmove(dst, src, len value.Value, isVolatile bool) *InstCall {
+	return m.memTransfer("llvm.memmove", dst, src, len, isVolatile)
+}
+
+// Memset returns a new call instruction to the llvm.memset intrinsic based on
+// the given destination address, byte value, length in bytes and volatile flag.
+// The overloaded intrinsic (e.g. llvm.memset.p0i8.i64) is declared in the
+// module if not already present.
+//
+// The call instruction is not appended to any basic block.
+func (m *Module) Memset(dst, val, len value.Value, isVolatile bool) *InstCall {
+	sig := types.NewFunc(types.Void, dst.Type(), types.I8, len.Type(), types.I1)
+	name := MangleIntrinsic("llvm.memset", dst.Type(), len.Type())
+	callee := m.GetOrInsertIntrinsic(name, sig)
+	return NewCall(callee, dst, val, len, constant.NewBool(isVolatile))
+}
+
+// LifetimeStart returns a new call instruction to the llvm.lifetime.start
+// intrinsic based on the given object size in bytes and object address. The
+// overloaded intrinsic (e.g. llvm.lifetime.start.p0i8) is declared in the
+// module if not already present.
+//
+// The call instruction is not appended to any basic block.
+func (m *Module) LifetimeStart(size, ptr value.Value) *InstCall {
+	return m.lifetime("llvm.lifetime.start", size, ptr)
+}
+
+// LifetimeEnd returns a new call instruction to the llvm.lifetime.end intrinsic
+// based on the given object size in bytes and object address. The overloaded
+// intrinsic (e.g. llvm.lifetime.end.p0i8) is declared in the module if not
+// already present.
+//
+// The call instruction is not appended to any basic block.
+func (m *Module) LifetimeEnd(size, ptr value.Value) *InstCall {
+	return m.lifetime("llvm.lifetime.end", size, ptr)
+}
+
+// memTransfer returns a new call instruction to the given memory transfer
+// intrinsic (llvm.memcpy or llvm.memmove).
+func (m *Module) memTransfer(base string, dst, src, len value.Value, isVolatile bool) *InstCall {
+	sig := types.NewFunc(types.Void, dst.Type(), src.Type(), len.Type(), types.I1)
+	name := MangleIntrinsic(base, dst.Type(), src.Type(), len.Type())
+	callee := m.GetOrInsertIntrinsic(name, sig)
+	return NewCall(callee, dst, src, len, constant.NewBool(isVolatile))
+}
+
+// lifetime returns a new call instruction to the given lifetime intrinsic
+// (llvm.lifetime.start or llvm.lifetime.end).
+func (m *Module) lifetime(base string, size, ptr value.Value) *InstCall {
+	sig := types.NewFunc(types.Void, types.I64, ptr.Type())
+	name := MangleIntrinsic(base, ptr.Type())
+	callee := m.GetOrInsertIntrinsic(name, sig)
+	return NewCall(callee, size, ptr)
+}
+
+// GetOrInsertIntrinsic returns the intrinsic function of the given name (e.g.
+// as returned by MangleIntrinsic), declaring it in the module based on the given
+// function signature if not already present.
+//
+// GetOrInsertIntrinsic panics on function signature mismatch, as described by
+// Module.GetOrInsertFunc; use GetOrInsertIntrinsicErr to handle such errors.
+func (m *Module) GetOrInsertIntrinsic(name string, sig *types.FuncType) *Func {
+	f, err := m.GetOrInsertIntrinsicErr(name, sig)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// GetOrInsertIntrinsicErr returns the intrinsic function of the given name,
+// declaring it in the module based on the given function signature if not
+// already present. An error is returned on function signature mismatch, as
+// described by Module.GetOrInsertFuncErr.
+func (m *Module) GetOrInsertIntrinsicErr(name string, sig *types.FuncType) (*Func, error) {
+	return m.GetOrInsertFuncErr(name, sig)
+}
+
+// MangleIntrinsic returns the name of the overloaded intrinsic with the given
+// base name (e.g. llvm.memcpy), suffixed by the mangled names of the given
+// overload types (e.g. llvm.memcpy.p0i8.p0i8.i64).
+//
+// Types are mangled following the rules of LLVM; e.g. i32 for integer types,
+// f64 for floating-point types, v4f32 for vector types, a2i64 for array types
+// and p0i8 for pointer types, where pointer types are mangled by address space
+// and element type.
+func MangleIntrinsic(base string, overloadTypes ...types.Type) string {
+	buf := &strings.Builder{}
+	buf.WriteString(base)
+	for _, t := range overloadTypes {
+		fmt.Fprintf(buf, ".%s", mangleType(t))
+	}
+	return buf.String()
+}
+
+// mangleType returns the mangled name of the given type, as used in the names
+// of overloaded intrinsics.
+func mangleType(t types.Type) string {
+	switch t := t.(type) {
+	case *types.VoidType:
+		return "isVoid"
+	case *types.FuncType:
+		buf := &strings.Builder{}
+		fmt.Fprintf(buf, "f_%s", mangleType(t.RetType))
+		for _, param := range t.Params {
+			buf.WriteString(mangleType(param))
+		}
+		if t.Variadic {
+			buf.WriteString("vararg")
+		}
+		buf.WriteString("f")
+		return buf.String()
+	case *types.IntType:
+		return fmt.Sprintf("i%d", t.BitSize)
+	case *types.FloatType:
+		switch t.Kind {
+		case types.FloatKindHalf:
+			return "f16"
+		case types.FloatKindFloat:
+			return "f32"
+		case types.FloatKindDouble:
+			return "f64"
+		case types.FloatKindFP128:
+			return "f128"
+		case types.FloatKindX86_FP80:
+			return "f80"
+		case types.FloatKindPPC_FP128:
+			return "ppcf128"
+		default:
+			panic(fmt.Errorf("support for floating-point kind %v not yet implemented", t.Kind))
+		}
+	case *types.MMXType:
+		return "x86mmx"
+	case *types.PointerType:
+		return fmt.Sprintf("p%d%s", t.AddrSpace, mangleType(t.ElemType))
+	case *types.VectorType:
+		return fmt.Sprintf("v%d%s", t.Len, mangleType(t.ElemType))
+	case *types.LabelType:
+		return "label"
+	case *types.TokenType:
+		return "token"
+	case *types.MetadataType:
+		return "Metadata"
+	case *types.ArrayType:
+		return fmt.Sprintf("a%d%s", t.Len, mangleType(t.ElemType))
+	case *types.StructType:
+		if len(t.TypeName) > 0 {
+			return fmt.Sprintf("s_%s", t.TypeName)
+		}
+		buf := &strings.Builder{}
+		buf.WriteString("sl_")
+		for _, field := range t.Fields {
+			buf.WriteString(mangleType(field))
+		}
+		buf.WriteString("s")
+		return buf.String()
+	default:
+		panic(fmt.Errorf("support for type %T not yet implemented", t))
+	}
+}
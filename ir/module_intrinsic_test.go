@@ -0,0 +1,81 @@
+package ir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestModuleIntrinsics(t *testing.T) {
+	m := NewModule()
+	f := m.NewFunc("f", types.Void, NewParam("dst", types.I8Ptr), NewParam("src", types.I8Ptr))
+	dst, src := f.Params[0], f.Params[1]
+	n := constant.NewInt(types.I64, 16)
+	golden := []struct {
+		in   *InstCall
+		want string
+	}{
+		{
+			in:   m.Memcpy(dst, src, n, false),
+			want: "call void @llvm.memcpy.p0i8.p0i8.i64(i8* %dst, i8* %src, i64 16, i1 false)",
+		},
+		{
+			in:   m.Memmove(dst, src, n, true),
+			want: "call void @llvm.memmove.p0i8.p0i8.i64(i8* %dst, i8* %src, i64 16, i1 true)",
+		},
+		{
+			in:   m.Memset(dst, constant.NewInt(types.I8, 0), n, false),
+			want: "call void @llvm.memset.p0i8.i64(i8* %dst, i8 0, i64 16, i1 false)",
+		},
+		{
+			in:   m.LifetimeStart(n, dst),
+			want: "call void @llvm.lifetime.start.p0i8(i64 16, i8* %dst)",
+		},
+		{
+			in:   m.LifetimeEnd(n, dst),
+			want: "call void @llvm.lifetime.end.p0i8(i64 16, i8* %dst)",
+		},
+		// Reuse intrinsic declaration.
+		{
+			in:   m.Memcpy(src, dst, n, false),
+			want: "call void @llvm.memcpy.p0i8.p0i8.i64(i8* %src, i8* %dst, i64 16, i1 false)",
+		},
+	}
+	for _, g := range golden {
+		if got := g.in.LLString(); g.want != got {
+			t.Errorf("intrinsic call mismatch; expected `%v`, got `%v`", g.want, got)
+		}
+	}
+	var names []string
+	for _, f := range m.Funcs[1:] {
+		names = append(names, f.Name())
+	}
+	want := "llvm.memcpy.p0i8.p0i8.i64 llvm.memmove.p0i8.p0i8.i64 llvm.memset.p0i8.i64 llvm.lifetime.start.p0i8 llvm.lifetime.end.p0i8"
+	if got := strings.Join(names, " "); want != got {
+		t.Errorf("intrinsic declarations mismatch; expected `%v`, got `%v`", want, got)
+	}
+}
+
+func TestMangleIntrinsic(t *testing.T) {
+	i8PtrAS1 := types.NewPointer(types.I8)
+	i8PtrAS1.AddrSpace = 1
+	golden := []struct {
+		base          string
+		overloadTypes []types.Type
+		want          string
+	}{
+		{base: "llvm.smax", overloadTypes: []types.Type{types.I32}, want: "llvm.smax.i32"},
+		{base: "llvm.sqrt", overloadTypes: []types.Type{types.Double}, want: "llvm.sqrt.f64"},
+		{base: "llvm.fma", overloadTypes: []types.Type{types.NewVector(4, types.Float)}, want: "llvm.fma.v4f32"},
+		{base: "llvm.memcpy", overloadTypes: []types.Type{i8PtrAS1, types.I8Ptr, types.I32}, want: "llvm.memcpy.p1i8.p0i8.i32"},
+		{base: "llvm.foo", overloadTypes: []types.Type{types.NewArray(2, types.NewStruct(types.I64, types.FP128))}, want: "llvm.foo.a2sl_i64f128s"},
+		{base: "llvm.donothing", want: "llvm.donothing"},
+	}
+	for _, g := range golden {
+		if got := MangleIntrinsic(g.base, g.overloadTypes...); g.want != got {
+			t.Errorf("intrinsic name mismatch; expected %q, got %q", g.want, got)
+		}
+	}
+}
@@ -0,0 +1,748 @@
+package ir
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/llir/llvm/internal/enc"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+	"github.com/pkg/errors"
+)
+
+// --- [ Module linking ] ------------------------------------------------------
+
+// LinkModules links the top-level declarations and definitions of the module
+// src into the module dst, with semantics similar to those of llvm-link.
+//
+// Global identifiers are resolved as follows:
+//
+//    * declarations are resolved against definitions of the same name, and
+//      uses of the declaration are replaced by the definition (using a bitcast
+//      if the types differ);
+//    * weak and linkonce definitions are overridden by other definitions;
+//    * globals with private or internal linkage are renamed on conflict;
+//    * global variables with appending linkage are concatenated.
+//
+// Identified struct types of the same name are merged if they have the same
+// body, or if either is opaque. Attribute group IDs and metadata IDs of src are
+// renumbered, and named metadata definitions are merged.
+//
+// An error is returned if both modules define the same global identifier, if
+// identified struct types or comdats of the same name differ, or if the data
+// layouts or target triples differ. The module dst is left unmodified on error.
+// The module src should not be used after linking.
+func LinkModules(dst, src *Module) error {
+	l := &linker{dst: dst, src: src, repl: make(map[value.Value]value.Value)}
+	if err := l.resolve(); err != nil {
+		return errors.WithStack(err)
+	}
+	l.link()
+	return nil
+}
+
+// linker tracks the state of linking the module src into the module dst.
+type linker struct {
+	dst, src *Module
+	// Identified struct types of src with the same body as those of dst.
+	dupTypes map[types.Type]bool
+	// Opaque identified struct types of dst, mapped to the corresponding struct
+	// types of src defining their body.
+	opaqueTypes map[*types.StructType]*types.StructType
+	// Comdats of src already present in dst.
+	dupComdats map[*ComdatDef]bool
+	// Global values of dst and src removed by linking.
+	removed map[value.Named]bool
+	// Global values of dst and src to rename, mapped to their new name.
+	renames map[value.Named]string
+	// Offset of the global IDs of unnamed global values of src.
+	idOffset int64
+	// Global variables with appending linkage of dst, mapped to the global
+	// variables of src to append.
+	appends map[*Global]*Global
+	// Replacements of global values removed by linking.
+	repl map[value.Value]value.Value
+}
+
+// resolve resolves conflicts between the top-level entities of dst and src,
+// without modifying either module.
+func (l *linker) resolve() error {
+	props := []struct {
+		name     string
+		dst, src string
+	}{
+		{name: "data layout", dst: l.dst.DataLayout, src: l.src.DataLayout},
+		{name: "target triple", dst: l.dst.TargetTriple, src: l.src.TargetTriple},
+	}
+	for _, prop := range props {
+		if len(prop.dst) > 0 && len(prop.src) > 0 && prop.dst != prop.src {
+			return errors.Errorf("%s mismatch; expected %q, got %q", prop.name, prop.dst, prop.src)
+		}
+	}
+	if err := l.resolveTypes(); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := l.resolveComdats(); err != nil {
+		return errors.WithStack(err)
+	}
+	return l.resolveGlobals()
+}
+
+// resolveTypes resolves identified struct types of the same name in dst and
+// src.
+func (l *linker) resolveTypes() error {
+	l.dupTypes = make(map[types.Type]bool)
+	l.opaqueTypes = make(map[*types.StructType]*types.StructType)
+	dstTypes := make(map[string]types.Type)
+	for _, t := range l.dst.TypeDefs {
+		dstTypes[t.Name()] = t
+	}
+	for _, t := range l.src.TypeDefs {
+		prev, ok := dstTypes[t.Name()]
+		if !ok {
+			continue
+		}
+		l.dupTypes[t] = true
+		if prev.LLString() == t.LLString() || isOpaque(t) {
+			continue
+		}
+		if isOpaque(prev) {
+			if body, ok := t.(*types.StructType); ok {
+				l.opaqueTypes[prev.(*types.StructType)] = body
+				continue
+			}
+		}
+		return errors.Errorf("type definition mismatch of %s; expected %s, got %s", t, prev.LLString(), t.LLString())
+	}
+	return nil
+}
+
+// resolveComdats resolves comdats of the same name in dst and src.
+func (l *linker) resolveComdats() error {
+	l.dupComdats = make(map[*ComdatDef]bool)
+	dstComdats := make(map[string]*ComdatDef)
+	for _, def := range l.dst.ComdatDefs {
+		dstComdats[def.Name] = def
+	}
+	for _, def := range l.src.ComdatDefs {
+		prev, ok := dstComdats[def.Name]
+		if !ok {
+			continue
+		}
+		if prev.Kind != def.Kind {
+			return errors.Errorf("comdat selection kind mismatch of %s; expected %s, got %s", enc.Comdat(def.Name), prev.Kind, def.Kind)
+		}
+		l.dupComdats[def] = true
+	}
+	return nil
+}
+
+// resolveGlobals resolves global identifiers of the same name in dst and src.
+func (l *linker) resolveGlobals() error {
+	l.removed = make(map[value.Named]bool)
+	l.renames = make(map[value.Named]string)
+	l.appends = make(map[*Global]*Global)
+	names := make(map[string]bool)
+	dstGlobals := make(map[string]value.Named)
+	for _, g := range globalValues(l.dst) {
+		if isUnnamedGlobal(g) {
+			if id := g.(globalIDer).ID(); id >= l.idOffset {
+				l.idOffset = id + 1
+			}
+			continue
+		}
+		dstGlobals[g.Name()] = g
+		names[g.Name()] = true
+	}
+	srcGlobals := globalValues(l.src)
+	for _, g := range srcGlobals {
+		if !isUnnamedGlobal(g) {
+			names[g.Name()] = true
+		}
+	}
+	for _, s := range srcGlobals {
+		if isUnnamedGlobal(s) {
+			continue
+		}
+		d, ok := dstGlobals[s.Name()]
+		if !ok {
+			continue
+		}
+		dLinkage, sLinkage := globalLinkage(d), globalLinkage(s)
+		switch {
+		case isLocalLinkage(sLinkage):
+			l.renames[s] = uniqueGlobalName(s.Name(), names)
+		case isLocalLinkage(dLinkage):
+			l.renames[d] = uniqueGlobalName(d.Name(), names)
+		case dLinkage == enum.LinkageAppending && sLinkage == enum.LinkageAppending:
+			dg, dok := d.(*Global)
+			sg, sok := s.(*Global)
+			if !dok || !sok || !isArrayInit(dg) || !isArrayInit(sg) {
+				return errors.Errorf("invalid global variables with appending linkage %s; expected array definitions", d.Ident())
+			}
+			dt, st := dg.ContentType.(*types.ArrayType), sg.ContentType.(*types.ArrayType)
+			if !dt.ElemType.Equal(st.ElemType) {
+				return errors.Errorf("element type mismatch of global variables with appending linkage %s; expected %s, got %s", d.Ident(), dt.ElemType, st.ElemType)
+			}
+			l.appends[dg] = sg
+			l.removed[s] = true
+			l.repl[s] = d
+		case isDeclaration(s):
+			l.replace(s, d)
+		case isDeclaration(d):
+			l.replace(d, s)
+		case isWeakLinkage(sLinkage):
+			l.replace(s, d)
+		case isWeakLinkage(dLinkage):
+			l.replace(d, s)
+		default:
+			return errors.Errorf("duplicate definition of global identifier %s", d.Ident())
+		}
+	}
+	return nil
+}
+
+// replace records the replacement of the global value old by new, and the
+// removal of old.
+func (l *linker) replace(old, new value.Named) {
+	l.removed[old] = true
+	if old.Type().Equal(new.Type()) {
+		l.repl[old] = new
+		return
+	}
+	l.repl[old] = constant.NewBitCast(new.(constant.Constant), old.Type())
+}
+
+// link links the top-level entities of src into dst, based on the resolved
+// conflicts.
+func (l *linker) link() {
+	dst, src := l.dst, l.src
+	if len(dst.SourceFilename) == 0 {
+		dst.SourceFilename = src.SourceFilename
+	}
+	if len(dst.DataLayout) == 0 {
+		dst.DataLayout = src.DataLayout
+	}
+	if len(dst.TargetTriple) == 0 {
+		dst.TargetTriple = src.TargetTriple
+	}
+	dst.ModuleAsms = append(dst.ModuleAsms, src.ModuleAsms...)
+	// Type definitions.
+	for t, body := range l.opaqueTypes {
+		t.Packed = body.Packed
+		t.Fields = body.Fields
+		t.Opaque = false
+	}
+	for _, t := range src.TypeDefs {
+		if !l.dupTypes[t] {
+			dst.TypeDefs = append(dst.TypeDefs, t)
+		}
+	}
+	// Comdat definitions.
+	for _, def := range src.ComdatDefs {
+		if !l.dupComdats[def] {
+			dst.ComdatDefs = append(dst.ComdatDefs, def)
+		}
+	}
+	// Global values.
+	for g, name := range l.renames {
+		g.SetName(name)
+	}
+	for _, g := range globalValues(src) {
+		if isUnnamedGlobal(g) {
+			ident := g.(globalIDer)
+			ident.SetID(ident.ID() + l.idOffset)
+		}
+	}
+	for dg, sg := range l.appends {
+		dt := dg.ContentType.(*types.ArrayType)
+		elems := append(arrayElems(dg.Init, dt), arrayElems(sg.Init, sg.ContentType.(*types.ArrayType))...)
+		t := types.NewArray(uint64(len(elems)), dt.ElemType)
+		dg.ContentType = t
+		if dg.Typ != nil {
+			pt := types.NewPointer(t)
+			pt.AddrSpace = dg.Typ.AddrSpace
+			dg.Typ = pt
+		}
+		dg.Init = constant.NewArray(t, elems...)
+	}
+	dst.Globals = append(keepGlobals(dst.Globals, l.removed), keepGlobals(src.Globals, l.removed)...)
+	dst.Funcs = append(keepFuncs(dst.Funcs, l.removed), keepFuncs(src.Funcs, l.removed)...)
+	dst.Aliases = append(keepAliases(dst.Aliases, l.removed), keepAliases(src.Aliases, l.removed)...)
+	dst.IFuncs = append(keepIFuncs(dst.IFuncs, l.removed), keepIFuncs(src.IFuncs, l.removed)...)
+	dst.invalidateSymbols()
+	for _, f := range src.Funcs {
+		f.Parent = dst
+	}
+	// Attribute group definitions.
+	offset := int64(0)
+	for _, def := range dst.AttrGroupDefs {
+		if def.ID >= offset {
+			offset = def.ID + 1
+		}
+	}
+	for _, def := range src.AttrGroupDefs {
+		def.ID += offset
+		dst.AttrGroupDefs = append(dst.AttrGroupDefs, def)
+	}
+	// Metadata.
+	if dst.NamedMetadataDefs == nil && len(src.NamedMetadataDefs) > 0 {
+		dst.NamedMetadataDefs = make(map[string]*metadata.NamedDef)
+	}
+	for name, def := range src.NamedMetadataDefs {
+		if prev, ok := dst.NamedMetadataDefs[name]; ok {
+			prev.Nodes = append(prev.Nodes, def.Nodes...)
+			continue
+		}
+		dst.NamedMetadataDefs[name] = def
+	}
+	for _, def := range src.MetadataDefs {
+		def.SetID(-1)
+		dst.MetadataDefs = append(dst.MetadataDefs, def)
+	}
+	if err := dst.AssignMetadataIDs(); err != nil {
+		// Unreachable, as metadata IDs of src are reset.
+		panic(fmt.Errorf("unable to assign metadata IDs of linked module; %v", err))
+	}
+	for _, kind := range src.MetadataKinds {
+		dst.MetadataKindID(kind)
+	}
+	dst.UseListOrders = append(dst.UseListOrders, src.UseListOrders...)
+	dst.UseListOrderBBs = append(dst.UseListOrderBBs, src.UseListOrderBBs...)
+	// Replace uses of removed global values.
+	if len(l.repl) > 0 {
+		replaceGlobalUses(dst, l.repl)
+	}
+}
+
+// ### [ Helper functions ] ####################################################
+
+// globalValues returns the global variables, functions, aliases and IFuncs of
+// the given module.
+func globalValues(m *Module) []value.Named {
+	var gs []value.Named
+	for _, g := range m.Globals {
+		gs = append(gs, g)
+	}
+	for _, f := range m.Funcs {
+		gs = append(gs, f)
+	}
+	for _, alias := range m.Aliases {
+		gs = append(gs, alias)
+	}
+	for _, ifunc := range m.IFuncs {
+		gs = append(gs, ifunc)
+	}
+	return gs
+}
+
+// globalIDer is implemented by global values with a global ID.
+type globalIDer interface {
+	// ID returns the ID of the global identifier.
+	ID() int64
+	// SetID sets the ID of the global identifier.
+	SetID(id int64)
+}
+
+// isUnnamedGlobal reports whether the given global value is unnamed.
+func isUnnamedGlobal(g value.Named) bool {
+	if g, ok := g.(interface{ IsUnnamed() bool }); ok {
+		return g.IsUnnamed()
+	}
+	return false
+}
+
+// globalLinkage returns the linkage of the given global value.
+func globalLinkage(g value.Named) enum.Linkage {
+	switch g := g.(type) {
+	case *Global:
+		return g.Linkage
+	case *Func:
+		return g.Linkage
+	case *Alias:
+		return g.Linkage
+	case *IFunc:
+		return g.Linkage
+	default:
+		panic(fmt.Errorf("support for global value %T not yet implemented", g))
+	}
+}
+
+// isDeclaration reports whether the given global value is a global variable or
+// function declaration.
+func isDeclaration(g value.Named) bool {
+	switch g := g.(type) {
+	case *Global:
+		return g.Init == nil
+	case *Func:
+		return len(g.Blocks) == 0
+	}
+	return false
+}
+
+// isLocalLinkage reports whether the given linkage is private or internal.
+func isLocalLinkage(linkage enum.Linkage) bool {
+	return linkage == enum.LinkagePrivate || linkage == enum.LinkageInternal
+}
+
+// isWeakLinkage reports whether definitions of the given linkage may be
+// overridden by other definitions.
+func isWeakLinkage(linkage enum.Linkage) bool {
+	switch linkage {
+	case enum.LinkageAvailableExternally, enum.LinkageCommon, enum.LinkageLinkOnce, enum.LinkageLinkOnceODR, enum.LinkageWeak, enum.LinkageWeakODR:
+		return true
+	}
+	return false
+}
+
+// isOpaque reports whether the given type is an opaque struct type.
+func isOpaque(t types.Type) bool {
+	st, ok := t.(*types.StructType)
+	return ok && st.Opaque
+}
+
+// isArrayInit reports whether the given global variable is defined with an
+// array initializer.
+func isArrayInit(g *Global) bool {
+	if _, ok := g.ContentType.(*types.ArrayType); !ok {
+		return false
+	}
+	switch g.Init.(type) {
+	case *constant.Array, *constant.ZeroInitializer:
+		return true
+	}
+	return false
+}
+
+// arrayElems returns the elements of the given array initializer of type t.
+func arrayElems(init constant.Constant, t *types.ArrayType) []constant.Constant {
+	switch init := init.(type) {
+	case *constant.Array:
+		return init.Elems
+	case *constant.ZeroInitializer:
+		elems := make([]constant.Constant, t.Len)
+		for i := range elems {
+			elems[i] = constant.NewZeroInitializer(t.ElemType)
+		}
+		return elems
+	default:
+		panic(fmt.Errorf("support for array initializer %T not yet implemented", init))
+	}
+}
+
+// uniqueGlobalName returns a global name based on the given name which is not
+// present in names, and adds it to names.
+func uniqueGlobalName(name string, names map[string]bool) string {
+	for i := 1; ; i++ {
+		newName := fmt.Sprintf("%s.%d", name, i)
+		if !names[newName] {
+			names[newName] = true
+			return newName
+		}
+	}
+}
+
+// keepGlobals returns the global variables not present in removed.
+func keepGlobals(gs []*Global, removed map[value.Named]bool) []*Global {
+	var keep []*Global
+	for _, g := range gs {
+		if !removed[g] {
+			keep = append(keep, g)
+		}
+	}
+	return keep
+}
+
+// keepFuncs returns the functions not present in removed.
+func keepFuncs(fs []*Func, removed map[value.Named]bool) []*Func {
+	var keep []*Func
+	for _, f := range fs {
+		if !removed[f] {
+			keep = append(keep, f)
+		}
+	}
+	return keep
+}
+
+// keepAliases returns the aliases not present in removed.
+func keepAliases(aliases []*Alias, removed map[value.Named]bool) []*Alias {
+	var keep []*Alias
+	for _, alias := range aliases {
+		if !removed[alias] {
+			keep = append(keep, alias)
+		}
+	}
+	return keep
+}
+
+// keepIFuncs returns the IFuncs not present in removed.
+func keepIFuncs(ifuncs []*IFunc, removed map[value.Named]bool) []*IFunc {
+	var keep []*IFunc
+	for _, ifunc := range ifuncs {
+		if !removed[ifunc] {
+			keep = append(keep, ifunc)
+		}
+	}
+	return keep
+}
+
+// replaceGlobalUses replaces uses of global values in the given module, based
+// on the replacement map repl; in initializers, aliasees, resolvers, function
+// prefix, prologue and personality data and instruction operands, including
+// operands of constant expressions.
+func replaceGlobalUses(m *Module, repl map[value.Value]value.Value) {
+	r := &constReplacer{repl: repl, visited: make(map[constant.Constant]bool)}
+	r.replaceModule(m)
+}
+
+// blockAddresses returns the basic blocks of function f of which the address is
+// taken by a blockaddress constant in the given module.
+func blockAddresses(m *Module, f *Func) map[*Block]bool {
+	blocks := make(map[*Block]bool)
+	visit := func(c constant.Constant) {
+		if c, ok := c.(*constant.BlockAddress); ok && c.Func == f {
+			if block, ok := c.Block.(*Block); ok {
+				blocks[block] = true
+			}
+		}
+	}
+	r := &constReplacer{visited: make(map[constant.Constant]bool), visit: visit}
+	r.replaceModule(m)
+	return blocks
+}
+
+// replaceModule replaces uses of values in the given module; in initializers,
+// aliasees, resolvers, function prefix, prologue and personality data and
+// instruction operands, including operands of constant expressions.
+func (r *constReplacer) replaceModule(m *Module) {
+	for _, g := range m.Globals {
+		r.replaceConst(&g.Init)
+	}
+	for _, alias := range m.Aliases {
+		r.replaceConst(&alias.Aliasee)
+	}
+	for _, ifunc := range m.IFuncs {
+		r.replaceConst(&ifunc.Resolver)
+	}
+	for _, f := range m.Funcs {
+		r.replaceConst(&f.Prefix)
+		r.replaceConst(&f.Prologue)
+		r.replaceConst(&f.Personality)
+		for _, block := range f.Blocks {
+			for _, inst := range block.Insts {
+				r.replaceOperands(inst)
+			}
+			if term, ok := block.Term.(Instruction); ok {
+				r.replaceOperands(term)
+			}
+		}
+	}
+}
+
+// metadataPkgPath is the import path of the metadata package.
+var metadataPkgPath = reflect.TypeOf(metadata.Tuple{}).PkgPath()
+
+// replaceMetadata replaces uses of values in the metadata of the given module;
+// in metadata definitions, named metadata definitions, metadata attachments and
+// metadata arguments of instructions, including operands of constants referenced
+// by metadata nodes.
+func (r *constReplacer) replaceMetadata(m *Module) {
+	visited := make(map[interface{}]bool)
+	var walkNode func(node interface{})
+	// walkField replaces uses of values in the given struct field or slice
+	// element of a metadata node.
+	walkField := func(field reflect.Value) {
+		switch field.Kind() {
+		case reflect.Interface, reflect.Ptr:
+			if field.IsNil() || !field.CanInterface() {
+				return
+			}
+		default:
+			return
+		}
+		switch x := field.Interface().(type) {
+		case constant.Constant:
+			if field.Kind() == reflect.Interface {
+				r.walkField(field)
+			} else {
+				r.walkConst(x)
+			}
+		default:
+			walkNode(x)
+		}
+	}
+	walkNode = func(node interface{}) {
+		v := reflect.ValueOf(node)
+		if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct || v.Elem().Type().PkgPath() != metadataPkgPath {
+			return
+		}
+		if visited[node] {
+			return
+		}
+		visited[node] = true
+		s := v.Elem()
+		for i := 0; i < s.NumField(); i++ {
+			switch field := s.Field(i); field.Kind() {
+			case reflect.Slice:
+				for j := 0; j < field.Len(); j++ {
+					walkField(field.Index(j))
+				}
+			default:
+				walkField(field)
+			}
+		}
+	}
+	walkAttachments := func(mds []*metadata.Attachment) {
+		for _, md := range mds {
+			walkNode(md)
+		}
+	}
+	for _, md := range m.MetadataDefs {
+		walkNode(md)
+	}
+	for _, md := range m.NamedMetadataDefs {
+		walkNode(md)
+	}
+	for _, g := range m.Globals {
+		walkAttachments(g.Metadata)
+	}
+	for _, f := range m.Funcs {
+		walkAttachments(f.Metadata)
+		for _, block := range f.Blocks {
+			insts := block.Insts
+			if term, ok := block.Term.(Instruction); ok {
+				insts = append(insts[:len(insts):len(insts)], term)
+			}
+			for _, inst := range insts {
+				if md, ok := inst.(interface {
+					MDAttachments() []*metadata.Attachment
+				}); ok {
+					walkAttachments(md.MDAttachments())
+				}
+				for _, operand := range Operands(inst) {
+					if arg, ok := (*operand).(*metadata.Value); ok {
+						walkNode(arg)
+					}
+				}
+			}
+		}
+	}
+}
+
+// constReplacer replaces uses of values in instruction operands and constants.
+type constReplacer struct {
+	// Replacements of values.
+	repl map[value.Value]value.Value
+	// Constants already visited.
+	visited map[constant.Constant]bool
+	// (optional) Function invoked for each constant visited.
+	visit func(c constant.Constant)
+	// (optional) Function reporting whether to skip the operands of the given
+	// constant.
+	skipConst func(c constant.Constant) bool
+	// (optional) Function reporting whether to skip the given operand of an
+	// instruction.
+	skipOperand func(inst Instruction, operand *value.Value) bool
+	// Nesting depth of the constant currently walked.
+	depth int
+	// First error encountered while walking; constants nested deeper than
+	// constant.MaxDepth are not walked.
+	err error
+}
+
+// replaceOperands replaces uses of values in the operands of the given
+// instruction.
+func (r *constReplacer) replaceOperands(inst Instruction) {
+	for _, operand := range Operands(inst) {
+		if r.skipOperand != nil && r.skipOperand(inst, operand) {
+			continue
+		}
+		if new, ok := r.repl[*operand]; ok {
+			*operand = new
+			continue
+		}
+		if c, ok := (*operand).(constant.Constant); ok {
+			r.walkConst(c)
+		}
+	}
+}
+
+// replaceConst replaces the constant pointed to by c, or uses of values in its
+// operands.
+func (r *constReplacer) replaceConst(c *constant.Constant) {
+	if *c == nil {
+		return
+	}
+	if new, ok := r.repl[*c]; ok {
+		*c = new.(constant.Constant)
+		return
+	}
+	r.walkConst(*c)
+}
+
+// constantPkgPath is the import path of the constant package.
+var constantPkgPath = reflect.TypeOf(constant.Int{}).PkgPath()
+
+// walkConst replaces uses of values in the operands of the given constant,
+// recursively. Global values are not walked, and neither are constants nested
+// deeper than constant.MaxDepth, for which an error is recorded in r.err.
+func (r *constReplacer) walkConst(c constant.Constant) {
+	if r.visited[c] {
+		return
+	}
+	if r.depth >= constant.MaxDepth {
+		if r.err == nil {
+			r.err = errors.Errorf("nesting depth of constant exceeds limit %d", constant.MaxDepth)
+		}
+		return
+	}
+	r.depth++
+	defer func() { r.depth-- }()
+	r.visited[c] = true
+	if r.visit != nil {
+		r.visit(c)
+	}
+	if r.skipConst != nil && r.skipConst(c) {
+		return
+	}
+	v := reflect.ValueOf(c)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct || v.Elem().Type().PkgPath() != constantPkgPath {
+		return
+	}
+	s := v.Elem()
+	for i := 0; i < s.NumField(); i++ {
+		r.walkField(s.Field(i))
+	}
+}
+
+// walkField replaces uses of values in the given struct field or slice element
+// of a constant.
+func (r *constReplacer) walkField(field reflect.Value) {
+	switch field.Kind() {
+	case reflect.Interface:
+		if field.IsNil() || !field.CanSet() {
+			return
+		}
+		x, ok := field.Interface().(value.Value)
+		if !ok {
+			return
+		}
+		if new, ok := r.repl[x]; ok && reflect.TypeOf(new).AssignableTo(field.Type()) {
+			field.Set(reflect.ValueOf(new))
+			return
+		}
+		if c, ok := x.(constant.Constant); ok {
+			r.walkConst(c)
+		}
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.Interface {
+			return
+		}
+		for i := 0; i < field.Len(); i++ {
+			r.walkField(field.Index(i))
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package ir
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/llir/llvm/ir/value"
+	"github.com/rickypai/natsort"
+)
+
+// --- [ Symbol lookup ] -------------------------------------------------------
+
+// Func returns the function with the given name (without '@' prefix). The
+// boolean return value indicates success.
+func (m *Module) Func(name string) (*Func, bool) {
+	f, ok := m.lookupGlobal(name).(*Func)
+	return f, ok
+}
+
+// Global returns the global variable with the given name (without '@' prefix).
+// The boolean return value indicates success.
+func (m *Module) Global(name string) (*Global, bool) {
+	g, ok := m.lookupGlobal(name).(*Global)
+	return g, ok
+}
+
+// Alias returns the alias with the given name (without '@' prefix). The boolean
+// return value indicates success.
+func (m *Module) Alias(name string) (*Alias, bool) {
+	alias, ok := m.lookupGlobal(name).(*Alias)
+	return alias, ok
+}
+
+// GlobalsSorted returns the global variables of the module, sorted by name.
+func (m *Module) GlobalsSorted() []*Global {
+	globals := append([]*Global(nil), m.Globals...)
+	sort.SliceStable(globals, func(i, j int) bool {
+		return natsort.Less(globals[i].Name(), globals[j].Name())
+	})
+	return globals
+}
+
+// FuncsSorted returns the functions of the module, sorted by name.
+func (m *Module) FuncsSorted() []*Func {
+	funcs := append([]*Func(nil), m.Funcs...)
+	sort.SliceStable(funcs, func(i, j int) bool {
+		return natsort.Less(funcs[i].Name(), funcs[j].Name())
+	})
+	return funcs
+}
+
+// AliasesSorted returns the aliases of the module, sorted by name.
+func (m *Module) AliasesSorted() []*Alias {
+	aliases := append([]*Alias(nil), m.Aliases...)
+	sort.SliceStable(aliases, func(i, j int) bool {
+		return natsort.Less(aliases[i].Name(), aliases[j].Name())
+	})
+	return aliases
+}
+
+// symbolIndex is a name index of the global values of a module.
+type symbolIndex struct {
+	// mu prevents races on symbols.
+	mu sync.Mutex
+	// symbols maps from global name to the location of the global value in the
+	// module.
+	symbols map[string]symbol
+	// n is the number of global values of the module at the time of indexing.
+	n int
+}
+
+// symbol is the location of an indexed global value in a module.
+type symbol struct {
+	// Kind of global value.
+	kind symbolKind
+	// Index of the global value in the slice of global values of the given kind
+	// (e.g. m.Funcs).
+	index int
+}
+
+// symbolKind specifies the kind of an indexed global value.
+type symbolKind uint8
+
+// Kinds of indexed global values.
+const (
+	symbolGlobal symbolKind = iota
+	symbolFunc
+	symbolAlias
+	symbolIFunc
+)
+
+// lookupGlobal returns the global variable, function, alias or IFunc with the
+// given name (without '@' prefix), or nil if not present.
+//
+// Lookups are indexed by name. The index is rebuilt when global values have
+// been added to (or removed from) the module without use of the ir.Module
+// builder methods, or when the global value located by the index has since been
+// renamed or replaced. Names not present in the index are located by a linear
+// scan of the global values of the module, as global values may have been
+// renamed directly using SetName since indexing.
+func (m *Module) lookupGlobal(name string) value.Named {
+	idx := &m.symbols
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if idx.symbols == nil || idx.n != m.numSymbols() {
+		m.indexSymbols(idx)
+	}
+	if sym, ok := idx.symbols[name]; ok {
+		if v := m.symbolValue(sym); v != nil && v.Name() == name {
+			return v
+		}
+	}
+	// Index miss, or global value renamed or replaced since indexing.
+	v := m.findGlobal(name)
+	if v != nil {
+		m.indexSymbols(idx)
+	}
+	return v
+}
+
+// findGlobal returns the global variable, function, alias or IFunc with the
+// given name (without '@' prefix), or nil if not present. The global values of
+// the module are searched in the same order as they are indexed.
+func (m *Module) findGlobal(name string) value.Named {
+	for _, g := range m.Globals {
+		if g.Name() == name {
+			return g
+		}
+	}
+	for _, f := range m.Funcs {
+		if f.Name() == name {
+			return f
+		}
+	}
+	for _, alias := range m.Aliases {
+		if alias.Name() == name {
+			return alias
+		}
+	}
+	for _, ifunc := range m.IFuncs {
+		if ifunc.Name() == name {
+			return ifunc
+		}
+	}
+	return nil
+}
+
+// symbolValue returns the global value at the given location of the module, or
+// nil if the location is out of bounds.
+func (m *Module) symbolValue(sym symbol) value.Named {
+	switch sym.kind {
+	case symbolGlobal:
+		if sym.index < len(m.Globals) {
+			return m.Globals[sym.index]
+		}
+	case symbolFunc:
+		if sym.index < len(m.Funcs) {
+			return m.Funcs[sym.index]
+		}
+	case symbolAlias:
+		if sym.index < len(m.Aliases) {
+			return m.Aliases[sym.index]
+		}
+	case symbolIFunc:
+		if sym.index < len(m.IFuncs) {
+			return m.IFuncs[sym.index]
+		}
+	}
+	return nil
+}
+
+// indexSymbols indexes the global values of the module by name.
+//
+// pre-condition: idx.mu is locked.
+func (m *Module) indexSymbols(idx *symbolIndex) {
+	idx.symbols = make(map[string]symbol)
+	idx.n = 0
+	for i, g := range m.Globals {
+		idx.add(g.Name(), symbol{kind: symbolGlobal, index: i})
+	}
+	for i, f := range m.Funcs {
+		idx.add(f.Name(), symbol{kind: symbolFunc, index: i})
+	}
+	for i, alias := range m.Aliases {
+		idx.add(alias.Name(), symbol{kind: symbolAlias, index: i})
+	}
+	for i, ifunc := range m.IFuncs {
+		idx.add(ifunc.Name(), symbol{kind: symbolIFunc, index: i})
+	}
+}
+
+// add adds the given global value location to the index. The first global value
+// of a given name takes precedence.
+//
+// pre-condition: idx.mu is locked.
+func (idx *symbolIndex) add(name string, sym symbol) {
+	if _, ok := idx.symbols[name]; !ok {
+		idx.symbols[name] = sym
+	}
+	idx.n++
+}
+
+// addSymbol adds the given global value, which has been appended to the
+// module, to the name index of global values.
+func (m *Module) addSymbol(v value.Named) {
+	idx := &m.symbols
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if idx.symbols == nil {
+		// Index built lazily on first lookup.
+		return
+	}
+	var sym symbol
+	switch v.(type) {
+	case *Global:
+		sym = symbol{kind: symbolGlobal, index: len(m.Globals) - 1}
+	case *Func:
+		sym = symbol{kind: symbolFunc, index: len(m.Funcs) - 1}
+	case *Alias:
+		sym = symbol{kind: symbolAlias, index: len(m.Aliases) - 1}
+	case *IFunc:
+		sym = symbol{kind: symbolIFunc, index: len(m.IFuncs) - 1}
+	default:
+		panic(fmt.Errorf("support for global value %T not yet implemented", v))
+	}
+	idx.add(v.Name(), sym)
+}
+
+// invalidateSymbols invalidates the name index of global values of the module.
+func (m *Module) invalidateSymbols() {
+	idx := &m.symbols
+	idx.mu.Lock()
+	idx.symbols = nil
+	idx.mu.Unlock()
+}
+
+// numSymbols returns the number of global values of the module.
+func (m *Module) numSymbols() int {
+	return len(m.Globals) + len(m.Funcs) + len(m.Aliases) + len(m.IFuncs)
+}
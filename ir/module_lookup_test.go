@@ -0,0 +1,158 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestModuleLookup(t *testing.T) {
+	m := NewModule()
+	f10 := m.NewFunc("f10", types.Void)
+	f2 := m.NewFunc("f2", types.Void)
+	g := m.NewGlobalDef("g", constant.NewInt(types.I32, 0))
+	alias := m.NewAlias("a", g)
+	if got, ok := m.Func("f2"); !ok || got != f2 {
+		t.Errorf("function lookup mismatch; expected %v, got %v", f2, got)
+	}
+	if got, ok := m.Global("g"); !ok || got != g {
+		t.Errorf("global lookup mismatch; expected %v, got %v", g, got)
+	}
+	if got, ok := m.Alias("a"); !ok || got != alias {
+		t.Errorf("alias lookup mismatch; expected %v, got %v", alias, got)
+	}
+	// Lookups of a different kind of global value, or of missing names, fail.
+	if _, ok := m.Func("g"); ok {
+		t.Errorf("expected function lookup of global variable to fail")
+	}
+	if _, ok := m.Global("h"); ok {
+		t.Errorf("expected lookup of missing global to fail")
+	}
+	// Global values appended directly and renamed global values are resolved.
+	h := NewFunc("h", types.Void)
+	m.Funcs = append(m.Funcs, h)
+	if got, ok := m.Func("h"); !ok || got != h {
+		t.Errorf("function lookup mismatch; expected %v, got %v", h, got)
+	}
+	f2.SetName("f3")
+	if _, ok := m.Func("f2"); ok {
+		t.Errorf("expected lookup of renamed function to fail")
+	}
+	if got, ok := m.Func("f3"); !ok || got != f2 {
+		t.Errorf("function lookup mismatch; expected %v, got %v", f2, got)
+	}
+	// Global values replaced directly are resolved.
+	g2 := NewGlobalDef("g", constant.NewInt(types.I32, 1))
+	m.Globals[0] = g2
+	if got, ok := m.Global("g"); !ok || got != g2 {
+		t.Errorf("global lookup mismatch; expected %v, got %v", g2, got)
+	}
+	// Misses are not cached; global values added by the builder methods, by
+	// RenameGlobal, or renamed directly after a miss are resolved.
+	if _, ok := m.Global("x"); ok {
+		t.Errorf("expected lookup of missing global to fail")
+	}
+	x := m.NewGlobal("x", types.I32)
+	if got, ok := m.Global("x"); !ok || got != x {
+		t.Errorf("global lookup mismatch; expected %v, got %v", x, got)
+	}
+	if _, ok := m.Alias("b"); ok {
+		t.Errorf("expected lookup of missing alias to fail")
+	}
+	if err := m.RenameGlobal("a", "b"); err != nil {
+		t.Fatalf("unable to rename alias; %v", err)
+	}
+	if got, ok := m.Alias("b"); !ok || got != alias {
+		t.Errorf("alias lookup mismatch; expected %v, got %v", alias, got)
+	}
+	if _, ok := m.Func("baz"); ok {
+		t.Errorf("expected lookup of missing function to fail")
+	}
+	f10.SetName("baz")
+	if got, ok := m.Func("baz"); !ok || got != f10 {
+		t.Errorf("function lookup mismatch; expected %v, got %v", f10, got)
+	}
+	// Renaming to the name of a global value renamed directly after a miss
+	// fails.
+	if _, ok := m.Func("qux"); ok {
+		t.Errorf("expected lookup of missing function to fail")
+	}
+	h.SetName("qux")
+	if err := m.RenameGlobal("f3", "qux"); err == nil {
+		t.Errorf("expected error when renaming to name of existing function")
+	}
+	// Global values swapped directly, keeping the number of global values, are
+	// resolved.
+	y := NewGlobalDef("y", constant.NewInt(types.I32, 2))
+	m.Globals[0] = y
+	if got, ok := m.Global("y"); !ok || got != y {
+		t.Errorf("global lookup mismatch; expected %v, got %v", y, got)
+	}
+	if _, ok := m.Global("g"); ok {
+		t.Errorf("expected lookup of replaced global to fail")
+	}
+	h.SetName("h")
+	f10.SetName("f10")
+	// Sorted accessors.
+	funcs := m.FuncsSorted()
+	want := []*Func{f2, f10, h}
+	for i := range want {
+		if funcs[i] != want[i] {
+			t.Errorf("sorted function %d mismatch; expected %v, got %v", i, want[i], funcs[i])
+		}
+	}
+	if m.Funcs[0] != f10 || m.Funcs[1] != f2 {
+		t.Errorf("expected FuncsSorted to not modify module functions")
+	}
+}
+
+func TestGetOrInsertFunc(t *testing.T) {
+	m := NewModule()
+	sig := types.NewFunc(types.I8Ptr, types.I64)
+	malloc := m.GetOrInsertFunc("malloc", sig)
+	if want, got := "declare i8* @malloc(i64)", malloc.LLString(); want != got {
+		t.Errorf("function declaration mismatch; expected %q, got %q", want, got)
+	}
+	// Reuse function of the same name.
+	if got := m.GetOrInsertFunc("malloc", types.NewFunc(types.I8Ptr, types.I64)); got != malloc {
+		t.Errorf("function mismatch; expected %v, got %v", malloc.Ident(), got.Ident())
+	}
+	if len(m.Funcs) != 1 {
+		t.Errorf("function count mismatch; expected 1, got %d", len(m.Funcs))
+	}
+	printf := m.GetOrInsertFunc("printf", types.NewFunc(types.I32, types.I8Ptr))
+	printf.Sig.Variadic = true
+	if got := m.GetOrInsertFunc("printf", printf.Sig); got != printf {
+		t.Errorf("function mismatch; expected %v, got %v", printf.Ident(), got.Ident())
+	}
+	// Signature mismatch and name conflicts with other global values panic.
+	m.NewGlobalDef("g", constant.NewInt(types.I32, 0))
+	golden := []struct {
+		name string
+		sig  *types.FuncType
+	}{
+		{name: "malloc", sig: types.NewFunc(types.I8Ptr, types.I32)},
+		{name: "printf", sig: types.NewFunc(types.I32, types.I8Ptr)},
+		{name: "g", sig: sig},
+	}
+	for _, g := range golden {
+		func() {
+			defer func() {
+				if e := recover(); e == nil {
+					t.Errorf("expected panic for function %q of type %v", g.name, g.sig)
+				}
+			}()
+			m.GetOrInsertFunc(g.name, g.sig)
+		}()
+		if f, err := m.GetOrInsertFuncErr(g.name, g.sig); err == nil {
+			t.Errorf("expected error for function %q of type %v, got %v", g.name, g.sig, f)
+		}
+	}
+	if _, err := m.GetOrInsertIntrinsicErr("malloc", types.NewFunc(types.I8Ptr, types.I32)); err == nil {
+		t.Errorf("expected error for intrinsic signature mismatch")
+	}
+	if f, err := m.GetOrInsertFuncErr("malloc", sig); err != nil || f != malloc {
+		t.Errorf("GetOrInsertFuncErr mismatch; expected %v, got %v (%v)", malloc, f, err)
+	}
+}
@@ -0,0 +1,116 @@
+package ir
+
+import (
+	"fmt"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/value"
+)
+
+// --- [ Constant merging ] ----------------------------------------------------
+
+// MergeDuplicateConstants merges identical constant global variables of the
+// module, and returns the number of global variables merged (i.e. removed).
+//
+// Global variables are identical if they have the same type (including address
+// space), alignment, section and initializer; initializers are compared by
+// their LLVM syntax representation. Only private and internal constant global
+// variable definitions are merged, and only global variables with
+// unnamed_addr are replaced, as the addresses of merged global variables
+// compare equal. Global variables which are thread local, externally
+// initialized, part of a comdat, or have attributes or metadata attachments are
+// not merged, and neither are global variables referenced by llvm.used,
+// llvm.compiler.used or metadata tuples.
+//
+// Of each set of identical global variables, the first global variable without
+// unnamed_addr is kept, or the first global variable if all have unnamed_addr.
+// Uses of the other global variables of the set are replaced with the kept
+// global variable, and the replaced global variables are removed from the
+// module. Merging is repeated until no identical global variables remain, as
+// merging may make the initializers of other global variables identical.
+func (m *Module) MergeDuplicateConstants() int {
+	pinned := pinnedGlobals(m)
+	total := 0
+	for {
+		// Global variable to keep of each set of identical global variables,
+		// indexed by merge key.
+		keep := make(map[string]*Global)
+		keys := make(map[*Global]string)
+		var candidates []*Global
+		for _, g := range m.Globals {
+			if pinned[g] || !isMergeableConst(g) {
+				continue
+			}
+			key := constMergeKey(g)
+			keys[g] = key
+			candidates = append(candidates, g)
+			prev, ok := keep[key]
+			if !ok || (prev.UnnamedAddr == enum.UnnamedAddrUnnamedAddr && g.UnnamedAddr != enum.UnnamedAddrUnnamedAddr) {
+				keep[key] = g
+			}
+		}
+		repl := make(map[value.Value]value.Value)
+		removed := make(map[value.Named]bool)
+		for _, g := range candidates {
+			kept := keep[keys[g]]
+			if g == kept || g.UnnamedAddr != enum.UnnamedAddrUnnamedAddr {
+				continue
+			}
+			repl[g] = kept
+			removed[g] = true
+		}
+		if len(removed) == 0 {
+			break
+		}
+		replaceGlobalUses(m, repl)
+		m.Globals = keepGlobals(m.Globals, removed)
+		total += len(removed)
+	}
+	return total
+}
+
+// isMergeableConst reports whether the given global variable is a private or
+// internal constant global variable definition which may be merged with
+// identical global variables.
+func isMergeableConst(g *Global) bool {
+	if !g.Immutable || g.Init == nil || !isLocalLinkage(g.Linkage) {
+		return false
+	}
+	if g.TLSModel != enum.TLSModelNone || g.ExternallyInitialized || g.Comdat != nil {
+		return false
+	}
+	return len(g.FuncAttrs) == 0 && len(g.Metadata) == 0
+}
+
+// constMergeKey returns the key of the given global variable used to identify
+// identical global variables.
+func constMergeKey(g *Global) string {
+	return fmt.Sprintf("%s %s %s %d %s", g.Type(), g.ContentType, quote(g.Section), g.Align, g.Init)
+}
+
+// pinnedGlobals returns the global variables of the module which must not be
+// replaced; i.e. global variables referenced by llvm.used, llvm.compiler.used
+// or metadata (metadata definitions, named metadata definitions, metadata
+// attachments and metadata arguments).
+func pinnedGlobals(m *Module) map[*Global]bool {
+	pinned := make(map[*Global]bool)
+	for _, g := range m.Globals {
+		if g.Name() != "llvm.used" && g.Name() != "llvm.compiler.used" {
+			continue
+		}
+		for _, ref := range g.InitializerRelocations() {
+			if ref, ok := ref.(*Global); ok {
+				pinned[ref] = true
+			}
+		}
+	}
+	r := &constReplacer{visited: make(map[constant.Constant]bool)}
+	r.visit = func(c constant.Constant) {
+		if g, ok := c.(*Global); ok {
+			pinned[g] = true
+		}
+	}
+	r.replaceMetadata(m)
+	return pinned
+}
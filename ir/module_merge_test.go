@@ -0,0 +1,74 @@
+package ir_test
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/asm"
+)
+
+func TestMergeDuplicateConstants(t *testing.T) {
+	const content = `@.str = private unnamed_addr constant [4 x i8] c"foo\00"
+@.str.1 = private unnamed_addr constant [4 x i8] c"foo\00"
+@.str.2 = private constant [4 x i8] c"foo\00"
+@.str.3 = private unnamed_addr constant [4 x i8] c"bar\00"
+@.str.4 = internal unnamed_addr constant [4 x i8] c"foo\00", align 4
+@.str.5 = private unnamed_addr constant [4 x i8] c"foo\00", section "s"
+@.str.6 = private unnamed_addr global [4 x i8] c"foo\00"
+@.str.7 = unnamed_addr constant [4 x i8] c"foo\00"
+@.str.8 = private unnamed_addr constant [4 x i8] c"foo\00"
+@.str.9 = private unnamed_addr constant [4 x i8] c"foo\00"
+@p = private unnamed_addr constant i8* getelementptr ([4 x i8], [4 x i8]* @.str, i64 0, i64 0)
+@q = private unnamed_addr constant i8* getelementptr ([4 x i8], [4 x i8]* @.str.1, i64 0, i64 0)
+@llvm.used = appending global [1 x i8*] [i8* getelementptr ([4 x i8], [4 x i8]* @.str.8, i64 0, i64 0)], section "llvm.metadata"
+
+define i8* @f() {
+	%1 = getelementptr [4 x i8], [4 x i8]* @.str.1, i64 0, i64 0
+	%2 = load i8*, i8** @q
+	ret i8* %1
+}
+
+!foo = !{!0}
+
+!0 = !{[4 x i8]* @.str.9}
+`
+	const want = `@.str.2 = private constant [4 x i8] c"foo\00"
+@.str.3 = private unnamed_addr constant [4 x i8] c"bar\00"
+@.str.4 = internal unnamed_addr constant [4 x i8] c"foo\00", align 4
+@.str.5 = private unnamed_addr constant [4 x i8] c"foo\00", section "s"
+@.str.6 = private unnamed_addr global [4 x i8] c"foo\00"
+@.str.7 = unnamed_addr constant [4 x i8] c"foo\00"
+@.str.8 = private unnamed_addr constant [4 x i8] c"foo\00"
+@.str.9 = private unnamed_addr constant [4 x i8] c"foo\00"
+@p = private unnamed_addr constant i8* getelementptr ([4 x i8], [4 x i8]* @.str.2, i64 0, i64 0)
+@llvm.used = appending global [1 x i8*] [i8* getelementptr ([4 x i8], [4 x i8]* @.str.8, i64 0, i64 0)], section "llvm.metadata"
+
+define i8* @f() {
+; <label>:0
+	%1 = getelementptr [4 x i8], [4 x i8]* @.str.2, i64 0, i64 0
+	%2 = load i8*, i8** @p
+	ret i8* %1
+}
+
+!foo = !{!0}
+
+!0 = !{[4 x i8]* @.str.9}
+`
+	m, err := asm.ParseString("", content)
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	// @.str and @.str.1 are merged into @.str.2, after which @q is merged into
+	// @p.
+	if n := m.MergeDuplicateConstants(); n != 3 {
+		t.Errorf("number of merged global variables mismatch; expected 3, got %d", n)
+	}
+	if got := m.String(); got != want {
+		t.Errorf("module mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	if n := m.MergeDuplicateConstants(); n != 0 {
+		t.Errorf("number of merged global variables mismatch; expected 0, got %d", n)
+	}
+}
@@ -0,0 +1,355 @@
+package ir
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/rickypai/natsort"
+)
+
+// --- [ Metadata kinds ] ------------------------------------------------------
+
+// fixedMetadataKinds specifies the metadata kind names with fixed metadata kind
+// IDs, as pre-registered by LLVM.
+var fixedMetadataKinds = []string{
+	"dbg",                           // 0
+	"tbaa",                          // 1
+	"prof",                          // 2
+	"fpmath",                        // 3
+	"range",                         // 4
+	"tbaa.struct",                   // 5
+	"invariant.load",                // 6
+	"alias.scope",                   // 7
+	"noalias",                       // 8
+	"nontemporal",                   // 9
+	"llvm.mem.parallel_loop_access", // 10
+	"nonnull",                       // 11
+	"dereferenceable",               // 12
+	"dereferenceable_or_null",       // 13
+	"make.implicit",                 // 14
+	"unpredictable",                 // 15
+	"invariant.group",               // 16
+	"align",                         // 17
+	"llvm.loop",                     // 18
+	"type",                          // 19
+	"section_prefix",                // 20
+	"absolute_symbol",               // 21
+	"associated",                    // 22
+	"callees",                       // 23
+	"irr_loop",                      // 24
+	"llvm.access.group",             // 25
+	"callback",                      // 26
+	"preserve.access.index",         // 27
+	"vcall_visibility",              // 28
+	"noundef",                       // 29
+	"annotation",                    // 30
+}
+
+// MetadataKindID returns the metadata kind ID of the given metadata kind name
+// (without '!' prefix). Metadata kind names not already present in the
+// metadata kind table of the module are registered as custom metadata kinds
+// (stored in m.MetadataKinds), and are assigned IDs following the fixed
+// metadata kinds pre-registered by LLVM.
+func (m *Module) MetadataKindID(name string) int64 {
+	for id, kind := range fixedMetadataKinds {
+		if kind == name {
+			return int64(id)
+		}
+	}
+	for i, kind := range m.MetadataKinds {
+		if kind == name {
+			return int64(len(fixedMetadataKinds) + i)
+		}
+	}
+	m.MetadataKinds = append(m.MetadataKinds, name)
+	return int64(len(fixedMetadataKinds) + len(m.MetadataKinds) - 1)
+}
+
+// --- [ Metadata IDs ] --------------------------------------------------------
+
+// MetadataID returns the metadata ID (e.g. 3 of !3) of the given metadata node,
+// as printed by Module.String. Metadata IDs are assigned to metadata
+// definitions of the module without IDs (see Module.AssignMetadataIDs). The
+// boolean return value indicates success, and is false if the metadata node is
+// not a metadata definition of the module (e.g. a metadata node printed inline)
+// or if metadata IDs could not be assigned.
+func (m *Module) MetadataID(node metadata.Node) (int64, bool) {
+	def, ok := node.(metadata.Definition)
+	if !ok {
+		return -1, false
+	}
+	idx := &m.mdIndex
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	i, ok := m.lookupMetadata(idx, func() (int, bool) {
+		i, ok := idx.defs[def]
+		return i, ok
+	}, func(md metadata.Definition) bool {
+		return md == def
+	})
+	if !ok || m.MetadataDefs[i] != def {
+		return -1, false
+	}
+	return def.ID(), true
+}
+
+// MetadataByID returns the metadata definition of the module with the given
+// metadata ID (e.g. 3 of !3), as printed by Module.String. Metadata IDs are
+// assigned to metadata definitions of the module without IDs (see
+// Module.AssignMetadataIDs). The boolean return value indicates success.
+func (m *Module) MetadataByID(id int64) (metadata.Node, bool) {
+	idx := &m.mdIndex
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	i, ok := m.lookupMetadata(idx, func() (int, bool) {
+		i, ok := idx.ids[id]
+		return i, ok
+	}, func(md metadata.Definition) bool {
+		return md.ID() == id
+	})
+	if !ok || m.MetadataDefs[i].ID() != id {
+		return nil, false
+	}
+	return m.MetadataDefs[i], true
+}
+
+// metadataIndex is an ID index of the metadata definitions of a module.
+type metadataIndex struct {
+	// mu prevents races on the index.
+	mu sync.Mutex
+	// defs maps from metadata definition to its index in m.MetadataDefs.
+	defs map[metadata.Definition]int
+	// ids maps from metadata ID to the index of the metadata definition in
+	// m.MetadataDefs.
+	ids map[int64]int
+	// mds is m.MetadataDefs at the time of indexing.
+	mds []metadata.Definition
+}
+
+// lookupMetadata returns the index in m.MetadataDefs located by the given
+// lookup function in the ID index of the module. The boolean return value
+// indicates success. The match function reports whether the given metadata
+// definition is the one looked up, and is used to verify misses.
+//
+// The index is built once, after assigning metadata IDs, and rebuilt when
+// metadata definitions have been added to (or removed from) the module, when
+// metadata IDs have since been reassigned (see Module.AssignMetadataIDs), or
+// when the metadata definition located by the index has since been replaced
+// or its ID changed. Misses are verified against the metadata definitions of
+// the module, without reassigning metadata IDs, to locate metadata definitions
+// replaced in place (e.g. m.MetadataDefs[i] = def) since indexing.
+func (m *Module) lookupMetadata(idx *metadataIndex, lookup func() (int, bool), match func(md metadata.Definition) bool) (int, bool) {
+	if !idx.valid(m.MetadataDefs) {
+		if err := m.indexMetadata(idx); err != nil {
+			return 0, false
+		}
+	}
+	i, ok := lookup()
+	if ok && idx.located(m.MetadataDefs, i) {
+		return i, true
+	}
+	if !ok && !hasMetadataDef(m.MetadataDefs, match) {
+		// Cache miss.
+		return 0, false
+	}
+	// Metadata definition replaced or ID changed since indexing.
+	if err := m.indexMetadata(idx); err != nil {
+		return 0, false
+	}
+	return lookup()
+}
+
+// hasMetadataDef reports whether any of the given metadata definitions match.
+func hasMetadataDef(mds []metadata.Definition, match func(md metadata.Definition) bool) bool {
+	for _, md := range mds {
+		if match(md) {
+			return true
+		}
+	}
+	return false
+}
+
+// indexMetadata assigns metadata IDs to the metadata definitions of the module,
+// and indexes them by definition and by ID.
+func (m *Module) indexMetadata(idx *metadataIndex) error {
+	idx.mds = nil
+	if err := m.assignMetadataIDs(); err != nil {
+		return err
+	}
+	idx.defs = make(map[metadata.Definition]int, len(m.MetadataDefs))
+	idx.ids = make(map[int64]int, len(m.MetadataDefs))
+	for i, def := range m.MetadataDefs {
+		idx.defs[def] = i
+		idx.ids[def.ID()] = i
+	}
+	idx.mds = m.MetadataDefs
+	return nil
+}
+
+// valid reports whether the index is valid for the given metadata definitions;
+// i.e. whether they are of the same length and backing array as at the time of
+// indexing.
+func (idx *metadataIndex) valid(mds []metadata.Definition) bool {
+	if idx.mds == nil || len(idx.mds) != len(mds) {
+		return false
+	}
+	return len(mds) == 0 || &idx.mds[0] == &mds[0]
+}
+
+// located reports whether the i:th metadata definition of mds is unchanged
+// since indexing; i.e. that it has not been replaced and that its metadata ID
+// has not changed.
+func (idx *metadataIndex) located(mds []metadata.Definition, i int) bool {
+	def := mds[i]
+	j, ok := idx.defs[def]
+	if !ok || j != i {
+		return false
+	}
+	k, ok := idx.ids[def.ID()]
+	return ok && k == i
+}
+
+// invalidateMetadataIDs invalidates the ID index of the metadata definitions of
+// the module.
+func (m *Module) invalidateMetadataIDs() {
+	idx := &m.mdIndex
+	idx.mu.Lock()
+	idx.mds = nil
+	idx.mu.Unlock()
+}
+
+// --- [ Metadata cycles ] -----------------------------------------------------
+
+// addCyclicMetadataDefs adds the metadata nodes without IDs of the module which
+// are part of a reference cycle to the metadata definitions of the module, in
+// order of occurrence. A metadata node referencing itself (e.g. the loop ID of
+// !llvm.loop) cannot be printed inline, and is thus required to be a metadata
+// definition.
+//
+// Example:
+//
+//    !0 = distinct !{!0}
+func (m *Module) addCyclicMetadataDefs() {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[metadata.Definition]int)
+	var cyclic []metadata.Definition
+	var visit func(node metadata.Definition)
+	visit = func(node metadata.Definition) {
+		switch state[node] {
+		case visiting:
+			if node.ID() == -1 && !containsMetadataDef(cyclic, node) {
+				cyclic = append(cyclic, node)
+			}
+			return
+		case visited:
+			return
+		}
+		state[node] = visiting
+		for _, child := range metadataChildren(node) {
+			visit(child)
+		}
+		state[node] = visited
+	}
+	for _, md := range m.MetadataDefs {
+		visit(md)
+	}
+	// Named metadata definitions; visited in natural sorting order, as output.
+	var mdNames []string
+	for mdName := range m.NamedMetadataDefs {
+		mdNames = append(mdNames, mdName)
+	}
+	natsort.Strings(mdNames)
+	for _, mdName := range mdNames {
+		for _, node := range m.NamedMetadataDefs[mdName].Nodes {
+			if node, ok := node.(metadata.Definition); ok {
+				visit(node)
+			}
+		}
+	}
+	visitAttachments := func(v interface{}) {
+		md, ok := v.(interface {
+			MDAttachments() []*metadata.Attachment
+		})
+		if !ok {
+			return
+		}
+		for _, attachment := range md.MDAttachments() {
+			if node, ok := attachment.Node.(metadata.Definition); ok {
+				visit(node)
+			}
+		}
+	}
+	for _, g := range m.Globals {
+		visitAttachments(g)
+	}
+	for _, f := range m.Funcs {
+		visitAttachments(f)
+		for _, block := range f.Blocks {
+			for _, inst := range block.Insts {
+				visitAttachments(inst)
+			}
+			visitAttachments(block.Term)
+		}
+	}
+	for _, md := range cyclic {
+		m.addMetadataDef(md)
+	}
+}
+
+// containsMetadataDef reports whether the given metadata definitions contain
+// md.
+func containsMetadataDef(mds []metadata.Definition, md metadata.Definition) bool {
+	for _, def := range mds {
+		if def == md {
+			return true
+		}
+	}
+	return false
+}
+
+// metadataChildren returns the metadata nodes referenced by the fields of the
+// given metadata node (e.g. the fields of a metadata tuple, or the scope of a
+// DILocation).
+func metadataChildren(node metadata.Definition) []metadata.Definition {
+	v := reflect.ValueOf(node)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	var children []metadata.Definition
+	add := func(field reflect.Value) {
+		switch field.Kind() {
+		case reflect.Interface, reflect.Ptr:
+			if field.IsNil() || !field.CanInterface() {
+				return
+			}
+		default:
+			return
+		}
+		child, ok := field.Interface().(metadata.Definition)
+		if !ok {
+			return
+		}
+		// Skip typed nil pointers stored in interface fields.
+		if cv := reflect.ValueOf(child); cv.Kind() == reflect.Ptr && cv.IsNil() {
+			return
+		}
+		children = append(children, child)
+	}
+	s := v.Elem()
+	for i := 0; i < s.NumField(); i++ {
+		switch field := s.Field(i); field.Kind() {
+		case reflect.Slice:
+			for j := 0; j < field.Len(); j++ {
+				add(field.Index(j))
+			}
+		default:
+			add(field)
+		}
+	}
+	return children
+}
@@ -0,0 +1,56 @@
+package ir_test
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/asm"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestSelfReferentialMetadata(t *testing.T) {
+	m := ir.NewModule()
+	empty := &metadata.Tuple{MetadataID: -1}
+	// Loop ID; distinct self-referential node, not added to the metadata
+	// definitions of the module.
+	loop := &metadata.Tuple{MetadataID: -1, Distinct: true}
+	loop.Fields = []metadata.Field{loop, empty}
+	// Reference cycle of two nodes.
+	a := &metadata.Tuple{MetadataID: -1}
+	b := &metadata.Tuple{MetadataID: -1, Fields: []metadata.Field{a}}
+	a.Fields = []metadata.Field{b}
+	m.MetadataDefs = append(m.MetadataDefs, empty)
+	m.NamedMetadataDefs["foo"] = &metadata.NamedDef{Name: "foo", Nodes: []metadata.Node{a}}
+	f := m.NewFunc("f", types.Void)
+	ret := f.NewBlock("entry").NewRet(nil)
+	ret.Metadata = append(ret.Metadata, &metadata.Attachment{Name: "llvm.loop", Node: loop})
+	const want = `define void @f() {
+entry:
+	ret void, !llvm.loop !2
+}
+
+!foo = !{!1}
+
+!0 = !{}
+!1 = !{!{!1}}
+!2 = distinct !{!2, !0}
+`
+	s := m.String()
+	if s != want {
+		t.Errorf("module mismatch; expected:\n%s\ngot:\n%s", want, s)
+	}
+	// Round-trip.
+	m2, err := asm.ParseString("", s)
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	if got := m2.String(); s != got {
+		t.Errorf("module mismatch; expected:\n%s\ngot:\n%s", s, got)
+	}
+	term := m2.Funcs[0].Blocks[0].Term.(*ir.TermRet)
+	got, ok := term.Metadata[0].Node.(*metadata.Tuple)
+	if !ok || !got.Distinct || len(got.Fields) != 2 || got.Fields[0] != got {
+		t.Errorf("expected distinct self-referential loop ID, got %v", term.Metadata[0].Node)
+	}
+}
@@ -0,0 +1,48 @@
+package ir
+
+import (
+	"github.com/pkg/errors"
+)
+
+// --- [ Renaming ] ------------------------------------------------------------
+
+// RenameGlobal renames the global variable, function, alias or IFunc with the
+// given name (without '@' prefix) to the new name. An error is returned if no
+// global with the old name exists, or if a global with the new name already
+// exists.
+//
+// As global values are referenced by pointer, all uses of the global reflect
+// the new name. A comdat definition with the same name as the global (as used
+// by the `comdat` shorthand) is renamed with the global, unless a comdat with
+// the new name already exists.
+func (m *Module) RenameGlobal(oldName, newName string) error {
+	if len(newName) == 0 {
+		return errors.Errorf("invalid empty name when renaming global %q", oldName)
+	}
+	if oldName == newName {
+		return nil
+	}
+	old := m.lookupGlobal(oldName)
+	if old == nil {
+		return errors.Errorf("unable to locate global %q", oldName)
+	}
+	if m.lookupGlobal(newName) != nil {
+		return errors.Errorf("unable to rename global %q; global %q already exists", oldName, newName)
+	}
+	old.SetName(newName)
+	m.invalidateSymbols()
+	// Rename comdat definition of the same name.
+	var oldComdat, newComdat *ComdatDef
+	for _, def := range m.ComdatDefs {
+		switch def.Name {
+		case oldName:
+			oldComdat = def
+		case newName:
+			newComdat = def
+		}
+	}
+	if oldComdat != nil && newComdat == nil {
+		oldComdat.Name = newName
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package ir
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/value"
+)
+
+// --- [ Symbols ] -------------------------------------------------------------
+
+// UndefinedSymbols returns the names (without '@' prefix) of the external
+// symbols the module depends on; i.e. the global variable and function
+// declarations of the module, in order of occurrence. Intrinsic functions
+// (e.g. llvm.memcpy.p0i8.p0i8.i64) are not symbols, and are thus omitted.
+//
+// Declarations with extern_weak linkage are included, even though they may
+// remain undefined after linking.
+func (m *Module) UndefinedSymbols() []string {
+	var names []string
+	for _, g := range globalValues(m) {
+		if !isDeclaration(g) || isUnnamedGlobal(g) {
+			continue
+		}
+		if _, ok := g.(*Func); ok && strings.HasPrefix(g.Name(), "llvm.") {
+			// Skip intrinsic functions.
+			continue
+		}
+		names = append(names, g.Name())
+	}
+	return names
+}
+
+// ExportedSymbols returns the names (without '@' prefix) of the symbols defined
+// and exported by the module; i.e. the global variable and function
+// definitions, aliases and IFuncs of the module, in order of occurrence.
+//
+// Symbols of private or internal linkage are local to the module, and symbols
+// of hidden visibility are local to the linked object (e.g. shared object);
+// both are omitted. Definitions of available_externally linkage are not
+// emitted, and global variables of appending linkage (e.g. llvm.global_ctors)
+// are special variables of LLVM; both are omitted.
+func (m *Module) ExportedSymbols() []string {
+	var names []string
+	for _, g := range globalValues(m) {
+		if isDeclaration(g) || isUnnamedGlobal(g) {
+			continue
+		}
+		switch linkage := globalLinkage(g); {
+		case isLocalLinkage(linkage), linkage == enum.LinkageAvailableExternally, linkage == enum.LinkageAppending:
+			continue
+		}
+		if globalVisibility(g) == enum.VisibilityHidden {
+			continue
+		}
+		names = append(names, g.Name())
+	}
+	return names
+}
+
+// ### [ Helper functions ] ####################################################
+
+// globalVisibility returns the visibility of the given global value.
+func globalVisibility(g value.Named) enum.Visibility {
+	switch g := g.(type) {
+	case *Global:
+		return g.Visibility
+	case *Func:
+		return g.Visibility
+	case *Alias:
+		return g.Visibility
+	case *IFunc:
+		return g.Visibility
+	default:
+		panic(fmt.Errorf("support for global value %T not yet implemented", g))
+	}
+}
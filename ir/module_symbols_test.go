@@ -0,0 +1,54 @@
+package ir_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/llir/llvm/asm"
+)
+
+func TestModuleSymbols(t *testing.T) {
+	const src = `
+@llvm.global_ctors = appending global [0 x { i32, void ()*, i8* }] zeroinitializer
+@x = global i32 1
+@y = external global i32
+@z = internal global i32 2
+@h = hidden global i32 3
+@p = protected global i32 4
+@w = extern_weak global i32
+
+@a = alias i32, i32* @x
+@b = private alias i32, i32* @x
+
+declare void @f()
+
+declare void @llvm.trap()
+
+define void @g() {
+	call void @f()
+	ret void
+}
+
+define available_externally void @ae() {
+	ret void
+}
+
+define linkonce_odr void @l() {
+	ret void
+}
+
+define internal void @i() {
+	ret void
+}
+`
+	m, err := asm.ParseString("", src)
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	if want, got := []string{"y", "w", "f"}, m.UndefinedSymbols(); !reflect.DeepEqual(want, got) {
+		t.Errorf("undefined symbols mismatch; expected %q, got %q", want, got)
+	}
+	if want, got := []string{"x", "p", "g", "l", "a"}, m.ExportedSymbols(); !reflect.DeepEqual(want, got) {
+		t.Errorf("exported symbols mismatch; expected %q, got %q", want, got)
+	}
+}
@@ -11,3 +11,67 @@ func (m *Module) NewTypeDef(name string, typ types.Type) types.Type {
 	m.TypeDefs = append(m.TypeDefs, typ)
 	return typ
 }
+
+// NewOpaqueTypeDef appends a new opaque identified struct type definition to
+// the module based on the given type name. The body of the struct type may be
+// set later using SetBody, thus enabling the definition of recursive types.
+//
+// Example:
+//
+//    node := m.NewOpaqueTypeDef("Node")
+//    node.SetBody(types.I32, types.NewPointer(node))
+//
+// is emitted as
+//
+//    %Node = type { i32, %Node* }
+func (m *Module) NewOpaqueTypeDef(name string) *types.StructType {
+	t := &types.StructType{Opaque: true}
+	m.NewTypeDef(name, t)
+	return t
+}
+
+// SortedTypeDefs returns the struct type definitions of the module, ordered so
+// that no struct type is used before its definition. Struct types used through
+// pointer types do not need to be defined before use, and thereby break cycles
+// of recursive types; e.g. in
+//
+//    %Node = type { i32, %Node* }
+//
+// Struct types are otherwise ordered by their position in m.TypeDefs. Type
+// definitions of non-struct types are not included in the result, but struct
+// types used through them are ordered accordingly.
+func (m *Module) SortedTypeDefs() []*types.StructType {
+	defined := make(map[types.Type]bool)
+	for _, t := range m.TypeDefs {
+		defined[t] = true
+	}
+	var sorted []*types.StructType
+	visited := make(map[types.Type]bool)
+	var visit func(t types.Type)
+	visit = func(t types.Type) {
+		if visited[t] {
+			return
+		}
+		visited[t] = true
+		switch t := t.(type) {
+		case *types.PointerType:
+			// Pointer types break dependency cycles; the element type need not be
+			// defined before use.
+		case *types.VectorType:
+			visit(t.ElemType)
+		case *types.ArrayType:
+			visit(t.ElemType)
+		case *types.StructType:
+			for _, field := range t.Fields {
+				visit(field)
+			}
+			if defined[t] {
+				sorted = append(sorted, t)
+			}
+		}
+	}
+	for _, t := range m.TypeDefs {
+		visit(t)
+	}
+	return sorted
+}
@@ -0,0 +1,195 @@
+package ir
+
+import (
+	"fmt"
+
+	"github.com/llir/llvm/ir/value"
+)
+
+// === [ Operands ] ============================================================
+
+// Operands returns pointers to the value operands of the given instruction or
+// terminator, in operand order. Operands may be replaced in place by assigning
+// through the returned pointers.
+//
+// Incoming values of phi instructions are included, while basic block operands
+// (e.g. branch targets and incoming predecessors), switch case comparands and
+// exception pad operands of catchret and cleanupret are not. Constant operands
+// (e.g. the callee of a direct call) are included.
+func Operands(inst Instruction) []*value.Value {
+	switch inst := inst.(type) {
+	// Unary instructions
+	case *InstFNeg:
+		return []*value.Value{&inst.X}
+	// Binary and bitwise instructions
+	case *InstAdd:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstFAdd:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstSub:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstFSub:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstMul:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstFMul:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstUDiv:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstSDiv:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstFDiv:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstURem:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstSRem:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstFRem:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstShl:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstLShr:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstAShr:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstAnd:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstOr:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstXor:
+		return []*value.Value{&inst.X, &inst.Y}
+	// Vector instructions
+	case *InstExtractElement:
+		return []*value.Value{&inst.X, &inst.Index}
+	case *InstInsertElement:
+		return []*value.Value{&inst.X, &inst.Elem, &inst.Index}
+	case *InstShuffleVector:
+		return []*value.Value{&inst.X, &inst.Y}
+	// Aggregate instructions
+	case *InstExtractValue:
+		return []*value.Value{&inst.X}
+	case *InstInsertValue:
+		return []*value.Value{&inst.X, &inst.Elem}
+	// Memory instructions
+	case *InstAlloca:
+		if inst.NElems == nil {
+			return nil
+		}
+		return []*value.Value{&inst.NElems}
+	case *InstLoad:
+		return []*value.Value{&inst.Src}
+	case *InstStore:
+		return []*value.Value{&inst.Src, &inst.Dst}
+	case *InstFence:
+		return nil
+	case *InstCmpXchg:
+		return []*value.Value{&inst.Ptr, &inst.Cmp, &inst.New}
+	case *InstAtomicRMW:
+		return []*value.Value{&inst.Dst, &inst.X}
+	case *InstGetElementPtr:
+		ops := []*value.Value{&inst.Src}
+		for i := range inst.Indices {
+			ops = append(ops, &inst.Indices[i])
+		}
+		return ops
+	// Conversion instructions
+	case *InstTrunc:
+		return []*value.Value{&inst.From}
+	case *InstZExt:
+		return []*value.Value{&inst.From}
+	case *InstSExt:
+		return []*value.Value{&inst.From}
+	case *InstFPTrunc:
+		return []*value.Value{&inst.From}
+	case *InstFPExt:
+		return []*value.Value{&inst.From}
+	case *InstFPToUI:
+		return []*value.Value{&inst.From}
+	case *InstFPToSI:
+		return []*value.Value{&inst.From}
+	case *InstUIToFP:
+		return []*value.Value{&inst.From}
+	case *InstSIToFP:
+		return []*value.Value{&inst.From}
+	case *InstPtrToInt:
+		return []*value.Value{&inst.From}
+	case *InstIntToPtr:
+		return []*value.Value{&inst.From}
+	case *InstBitCast:
+		return []*value.Value{&inst.From}
+	case *InstAddrSpaceCast:
+		return []*value.Value{&inst.From}
+	// Other instructions
+	case *InstICmp:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstFCmp:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstPhi:
+		var ops []*value.Value
+		for _, inc := range inst.Incs {
+			ops = append(ops, &inc.X)
+		}
+		return ops
+	case *InstSelect:
+		return []*value.Value{&inst.Cond, &inst.X, &inst.Y}
+	case *InstCall:
+		ops := []*value.Value{&inst.Callee}
+		ops = appendArgs(ops, inst.Args)
+		return appendOperandBundles(ops, inst.OperandBundles)
+	case *InstVAArg:
+		return []*value.Value{&inst.ArgList}
+	case *InstLandingPad:
+		var ops []*value.Value
+		for _, clause := range inst.Clauses {
+			ops = append(ops, &clause.X)
+		}
+		return ops
+	case *InstCatchPad:
+		return appendArgs(nil, inst.Args)
+	case *InstCleanupPad:
+		return appendArgs(nil, inst.Args)
+	// Terminators
+	case *TermRet:
+		if inst.X == nil {
+			return nil
+		}
+		return []*value.Value{&inst.X}
+	case *TermBr:
+		return nil
+	case *TermCondBr:
+		return []*value.Value{&inst.Cond}
+	case *TermSwitch:
+		return []*value.Value{&inst.X}
+	case *TermIndirectBr:
+		return []*value.Value{&inst.Addr}
+	case *TermInvoke:
+		ops := []*value.Value{&inst.Invokee}
+		ops = appendArgs(ops, inst.Args)
+		return appendOperandBundles(ops, inst.OperandBundles)
+	case *TermResume:
+		return []*value.Value{&inst.X}
+	case *TermCatchSwitch, *TermCatchRet, *TermCleanupRet, *TermUnreachable:
+		return nil
+	default:
+		panic(fmt.Errorf("support for instruction %T not yet implemented", inst))
+	}
+}
+
+// ### [ Helper functions ] ####################################################
+
+// appendArgs appends pointers to the given function arguments to ops.
+func appendArgs(ops []*value.Value, args []value.Value) []*value.Value {
+	for i := range args {
+		ops = append(ops, &args[i])
+	}
+	return ops
+}
+
+// appendOperandBundles appends pointers to the inputs of the given operand
+// bundles to ops.
+func appendOperandBundles(ops []*value.Value, bundles []*OperandBundle) []*value.Value {
+	for _, bundle := range bundles {
+		ops = appendArgs(ops, bundle.Inputs)
+	}
+	return ops
+}
@@ -0,0 +1,1131 @@
+package ir
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/llir/llvm/internal/enc"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+	"github.com/pkg/errors"
+	"github.com/rickypai/natsort"
+)
+
+// === [ Printer ] =============================================================
+
+// Printer is a printer of LLVM IR modules in assembly syntax, with options to
+// control the output.
+//
+// The zero value of Printer prints modules as Module.String.
+type Printer struct {
+	// CanonicalMode specifies whether to print modules in the canonical form
+	// produced by `opt -S` of LLVM 14.0; as is useful for golden tests against
+	// the output of LLVM.
+	//
+	// In canonical mode, the output differs from Module.String as follows.
+	//
+	//    * the module ID is printed as a comment, based on the source filename.
+	//    * only type definitions and comdat definitions in use are printed, in
+	//      order of first use.
+	//    * instructions are indented by two spaces, and basic block labels are
+	//      printed as `N:` with a comment listing the predecessor basic blocks.
+	//    * dso_preemptable is omitted, as is dso_local if implied by the linkage
+	//      (private or internal) or visibility (hidden or protected).
+	//    * unnamed parameters of function definitions are printed with their
+	//      local ID, and parameters of function declarations without name.
+	//    * the explicit function type of call instructions is only printed for
+	//      variadic functions.
+	//    * function attributes of functions and call sites (both inline and from
+	//      attribute groups) are merged into attribute groups, which are numbered
+	//      in order of first use and printed with sorted attributes. Function
+	//      declarations and definitions are preceded by a comment listing their
+	//      function attributes. Parameter and return attributes are sorted.
+	//    * metadata definitions are numbered in order of first use, and unused
+	//      metadata definitions are omitted. Metadata attachments are sorted by
+	//      metadata kind ID. DIExpression metadata nodes are printed inline.
+	//    * float and double constants are printed in the decimal exponent
+	//      notation of LLVM (e.g. 1.000000e+00) if exactly representable, and in
+	//      hexadecimal notation otherwise.
+	//
+	// Canonical mode operates on the module as is, and does not infer properties
+	// which LLVM adds when reading a module (e.g. the alignment of load and store
+	// instructions, or attributes of intrinsic functions). The following
+	// differences from the output of LLVM remain.
+	//
+	//    * named metadata definitions are printed in natural sorting order, as
+	//      their order of definition is not recorded by the module.
+	//    * metadata nodes referenced by specialized metadata nodes are numbered
+	//      in field order, which may differ from the operand order of LLVM.
+	//    * attributes of global variables are not merged into attribute groups.
+	//    * use-list order directives are not printed.
+	//
+	// Canonical mode temporarily modifies the module while printing (e.g.
+	// metadata IDs and attribute lists), and must thus not be used concurrently
+	// with other uses of the module.
+	CanonicalMode bool
+	// ExplicitTypes specifies whether to print the explicit function type of
+	// every call instruction and invoke terminator, rather than relying on
+	// inference of the function type from the callee; as is useful for tools
+	// consuming the output without type inference.
+	//
+	// Operands are otherwise already printed as type-value pairs where permitted
+	// by the LLVM IR syntax (e.g. function arguments, and operands of store and
+	// select instructions). Incoming values of phi instructions and the second
+	// operand of binary and comparison instructions share the type of the
+	// instruction, and may not be printed as type-value pairs.
+	//
+	// ExplicitTypes temporarily modifies the module while printing, and must
+	// thus not be used concurrently with other uses of the module.
+	ExplicitTypes bool
+	// (optional) Comments specifies line comments to print before the
+	// associated global variables, aliases, IFuncs, functions, basic blocks and
+	// instructions of the module; as captured by asm.ParseStringComments.
+	Comments Comments
+}
+
+// Fprint writes the LLVM IR assembly of the given module to w.
+func (p *Printer) Fprint(w io.Writer, m *Module) error {
+	if p.CanonicalMode {
+		if _, err := io.WriteString(w, p.Sprint(m)); err != nil {
+			return errors.WithStack(err)
+		}
+		return nil
+	}
+	if p.ExplicitTypes {
+		restore := setExplicitTypes(m)
+		defer restore()
+	}
+	if _, err := m.writeTo(w, p.Comments); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// Sprint returns the LLVM IR assembly of the given module.
+func (p *Printer) Sprint(m *Module) string {
+	if p.ExplicitTypes {
+		restore := setExplicitTypes(m)
+		defer restore()
+	}
+	if !p.CanonicalMode {
+		buf := &strings.Builder{}
+		if _, err := m.writeTo(buf, p.Comments); err != nil {
+			panic(fmt.Errorf("unable to write module; %v", err))
+		}
+		return buf.String()
+	}
+	return canonicalString(m, p.ExplicitTypes, p.Comments)
+}
+
+// --- [ Explicit types ] ------------------------------------------------------
+
+// setExplicitTypes sets the explicit function type of every call instruction
+// and invoke terminator of the given module, and returns a function to restore
+// the module after printing.
+func setExplicitTypes(m *Module) (restore func()) {
+	var undo []func()
+	for _, f := range m.Funcs {
+		for _, block := range f.Blocks {
+			for _, inst := range block.Insts {
+				call, ok := inst.(*InstCall)
+				if !ok || call.FuncType != nil {
+					continue
+				}
+				// Cache type of call before setting function type.
+				call.Type()
+				call.FuncType = call.Sig()
+				undo = append(undo, func() {
+					call.FuncType = nil
+				})
+			}
+			invoke, ok := block.Term.(*TermInvoke)
+			if !ok {
+				continue
+			}
+			// Cache type of invoke before setting function type.
+			invoke.Type()
+			typ := invoke.Typ
+			if _, ok := typ.(*types.FuncType); ok {
+				continue
+			}
+			t, ok := invoke.Invokee.Type().(*types.PointerType)
+			if !ok {
+				panic(fmt.Errorf("invalid invokee type; expected *types.PointerType, got %T", invoke.Invokee.Type()))
+			}
+			sig, ok := t.ElemType.(*types.FuncType)
+			if !ok {
+				panic(fmt.Errorf("invalid invokee type; expected *types.FuncType, got %T", t.ElemType))
+			}
+			invoke.Typ = sig
+			undo = append(undo, func() {
+				invoke.Typ = typ
+			})
+		}
+	}
+	return func() {
+		for i := len(undo) - 1; i >= 0; i-- {
+			undo[i]()
+		}
+	}
+}
+
+// --- [ Canonical mode ] ------------------------------------------------------
+
+// canonicalString returns the LLVM IR assembly of the given module in the
+// canonical form produced by `opt -S` of LLVM 14.0. The explicit function type
+// of call instructions is retained if explicitTypes is set, and the given
+// comments are printed before the associated entities of the module.
+func canonicalString(m *Module, explicitTypes bool, comments Comments) string {
+	// Assign global IDs, metadata IDs and local IDs.
+	m.AssignGlobalIDs()
+	if err := m.AssignMetadataIDs(); err != nil {
+		panic(fmt.Errorf("unable to assign metadata IDs of module; %v", err))
+	}
+	for _, f := range m.Funcs {
+		if err := f.assignIDsIfDirty(); err != nil {
+			panic(fmt.Errorf("unable to assign IDs of function %q; %v", f.Ident(), err))
+		}
+	}
+	c := newCanonicalizer(m)
+	c.explicitTypes = explicitTypes
+	c.comments = comments
+	defer c.restore()
+	c.prepare()
+	buf := &strings.Builder{}
+	// Module ID and source filename.
+	if len(m.SourceFilename) > 0 {
+		fmt.Fprintf(buf, "; ModuleID = '%s'\n", m.SourceFilename)
+		fmt.Fprintf(buf, "source_filename = %s\n", quote(m.SourceFilename))
+	}
+	// Data layout.
+	if len(m.DataLayout) > 0 {
+		fmt.Fprintf(buf, "target datalayout = %s\n", quote(m.DataLayout))
+	}
+	// Target triple.
+	if len(m.TargetTriple) > 0 {
+		fmt.Fprintf(buf, "target triple = %s\n", quote(m.TargetTriple))
+	}
+	// Module-level inline assembly.
+	if len(m.ModuleAsms) > 0 && buf.Len() > 0 {
+		buf.WriteString("\n")
+	}
+	for _, asm := range m.ModuleAsms {
+		fmt.Fprintf(buf, "module asm %s\n", quote(asm))
+	}
+	// Type definitions.
+	if len(c.typeDefs) > 0 && buf.Len() > 0 {
+		buf.WriteString("\n")
+	}
+	for _, t := range c.typeDefs {
+		fmt.Fprintf(buf, "%s = type %s\n", t, t.LLString())
+	}
+	// Comdat definitions; separated by empty lines.
+	if len(c.comdatDefs) > 0 && buf.Len() > 0 {
+		buf.WriteString("\n")
+	}
+	for i, def := range c.comdatDefs {
+		if i != 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintln(buf, def.LLString())
+	}
+	// Global declarations and definitions.
+	if len(m.Globals) > 0 && buf.Len() > 0 {
+		buf.WriteString("\n")
+	}
+	for _, g := range m.Globals {
+		writeComments(buf, c.comments, g, "")
+		fmt.Fprintln(buf, g.LLString())
+	}
+	// Aliases.
+	if len(m.Aliases) > 0 && buf.Len() > 0 {
+		buf.WriteString("\n")
+	}
+	for _, alias := range m.Aliases {
+		writeComments(buf, c.comments, alias, "")
+		fmt.Fprintln(buf, alias.LLString())
+	}
+	// IFuncs.
+	if len(m.IFuncs) > 0 && buf.Len() > 0 {
+		buf.WriteString("\n")
+	}
+	for _, ifunc := range m.IFuncs {
+		writeComments(buf, c.comments, ifunc, "")
+		fmt.Fprintln(buf, ifunc.LLString())
+	}
+	// Function declarations and definitions; each preceded by an empty line.
+	for _, f := range m.Funcs {
+		if buf.Len() > 0 {
+			buf.WriteString("\n")
+		}
+		c.writeFunc(buf, f)
+	}
+	// Attribute group definitions.
+	if len(c.attrGroupDefs) > 0 && buf.Len() > 0 {
+		buf.WriteString("\n")
+	}
+	for _, def := range c.attrGroupDefs {
+		fmt.Fprintf(buf, "attributes %s = { %s }\n", def, canonicalAttrsString(def.FuncAttrs, true))
+	}
+	// Named metadata definitions; output in natural sorting order.
+	var mdNames []string
+	for mdName := range m.NamedMetadataDefs {
+		mdNames = append(mdNames, mdName)
+	}
+	natsort.Strings(mdNames)
+	if len(mdNames) > 0 && buf.Len() > 0 {
+		buf.WriteString("\n")
+	}
+	for _, mdName := range mdNames {
+		md := m.NamedMetadataDefs[mdName]
+		fmt.Fprintf(buf, "%s = %s\n", md.Ident(), md.LLString())
+	}
+	// Metadata definitions.
+	if len(c.mdDefs) > 0 && buf.Len() > 0 {
+		buf.WriteString("\n")
+	}
+	for _, md := range c.mdDefs {
+		fmt.Fprintf(buf, "%s = %s\n", md.Ident(), md.LLString())
+	}
+	return buf.String()
+}
+
+// canonicalizer tracks the state of printing a module in canonical form.
+type canonicalizer struct {
+	// Module being printed.
+	m *Module
+	// Named struct types in order of first use.
+	typeDefs []types.Type
+	// Comdat definitions in order of first use.
+	comdatDefs []*ComdatDef
+	// Attribute group definitions in order of first use.
+	attrGroupDefs []*AttrGroupDef
+	// Metadata definitions in order of first use.
+	mdDefs []metadata.Definition
+	// Functions to invoke (in reverse order) to restore the module after
+	// printing.
+	undo []func()
+	// Retain the explicit function type of call instructions.
+	explicitTypes bool
+	// Comments to print before the associated entities of the module.
+	comments Comments
+
+	// Visited types and values of the type finder.
+	visitedTypes  map[types.Type]bool
+	visitedValues map[value.Value]bool
+	// Attribute group definitions indexed by attribute group contents.
+	attrGroups map[string]*AttrGroupDef
+	// Metadata definitions of the module; mapped to true when numbered.
+	mdNumbered map[metadata.Definition]bool
+	// Visited metadata nodes which are not metadata definitions of the module.
+	mdVisited map[interface{}]bool
+}
+
+// newCanonicalizer returns a new canonicalizer for printing the given module.
+func newCanonicalizer(m *Module) *canonicalizer {
+	return &canonicalizer{
+		m:             m,
+		visitedTypes:  make(map[types.Type]bool),
+		visitedValues: make(map[value.Value]bool),
+		attrGroups:    make(map[string]*AttrGroupDef),
+		mdNumbered:    make(map[metadata.Definition]bool),
+		mdVisited:     make(map[interface{}]bool),
+	}
+}
+
+// restore restores the module after printing.
+func (c *canonicalizer) restore() {
+	for i := len(c.undo) - 1; i >= 0; i-- {
+		c.undo[i]()
+	}
+}
+
+// prepare prepares the module for printing in canonical form.
+func (c *canonicalizer) prepare() {
+	m := c.m
+	// Type definitions.
+	c.findTypes()
+	// Comdat definitions; functions before global variables, as in LLVM.
+	used := make(map[*ComdatDef]bool)
+	addComdat := func(def *ComdatDef) {
+		if def != nil && !used[def] {
+			used[def] = true
+			c.comdatDefs = append(c.comdatDefs, def)
+		}
+	}
+	for _, f := range m.Funcs {
+		addComdat(f.Comdat)
+	}
+	for _, g := range m.Globals {
+		addComdat(g.Comdat)
+	}
+	// Preemption specifiers.
+	for _, g := range m.Globals {
+		c.setPreemption(&g.Preemption, g.Linkage, g.Visibility)
+	}
+	for _, f := range m.Funcs {
+		c.setPreemption(&f.Preemption, f.Linkage, f.Visibility)
+	}
+	for _, a := range m.Aliases {
+		c.setPreemption(&a.Preemption, a.Linkage, a.Visibility)
+	}
+	for _, i := range m.IFuncs {
+		c.setPreemption(&i.Preemption, i.Linkage, i.Visibility)
+	}
+	// Attribute groups; functions before call sites.
+	for _, f := range m.Funcs {
+		c.setFuncAttrs(&f.FuncAttrs)
+		c.sortAttrs(f.ReturnAttrs)
+		for _, param := range f.Params {
+			c.sortAttrs(param.Attrs)
+		}
+	}
+	for _, f := range m.Funcs {
+		for _, block := range f.Blocks {
+			for _, inst := range block.Insts {
+				if call, ok := inst.(*InstCall); ok {
+					c.omitFuncType(call)
+					c.setFuncAttrs(&call.FuncAttrs)
+					c.sortAttrs(call.ReturnAttrs)
+					c.sortArgAttrs(call.Args)
+				}
+			}
+			if invoke, ok := block.Term.(*TermInvoke); ok {
+				c.setFuncAttrs(&invoke.FuncAttrs)
+				c.sortAttrs(invoke.ReturnAttrs)
+				c.sortArgAttrs(invoke.Args)
+			}
+		}
+	}
+	// Metadata; global variables, named metadata and functions, in order.
+	c.numberMetadata()
+}
+
+// ~~~ [ Functions ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// writeFunc writes the given function declaration or definition to buf.
+func (c *canonicalizer) writeFunc(buf *strings.Builder, f *Func) {
+	comments := c.comments
+	writeComments(buf, comments, f, "")
+	// Function attributes comment.
+	for _, attr := range f.FuncAttrs {
+		if def, ok := attr.(*AttrGroupDef); ok {
+			var attrs []FuncAttribute
+			for _, attr := range def.FuncAttrs {
+				switch attr.(type) {
+				case AttrString, AttrPair:
+					// skip string attributes.
+				default:
+					attrs = append(attrs, attr)
+				}
+			}
+			if len(attrs) > 0 {
+				fmt.Fprintf(buf, "; Function Attrs: %s\n", canonicalAttrsString(attrs, false))
+			}
+		}
+	}
+	if len(f.Blocks) == 0 {
+		buf.WriteString("declare")
+		for _, md := range f.Metadata {
+			fmt.Fprintf(buf, " %s", md)
+		}
+		if f.Linkage != enum.LinkageNone {
+			fmt.Fprintf(buf, " %s", f.Linkage)
+		}
+		fmt.Fprintf(buf, "%s\n", funcHeaderString(f, true))
+		return
+	}
+	buf.WriteString("define")
+	if f.Linkage != enum.LinkageNone {
+		fmt.Fprintf(buf, " %s", f.Linkage)
+	}
+	buf.WriteString(funcHeaderString(f, true))
+	for _, md := range f.Metadata {
+		fmt.Fprintf(buf, " %s", md)
+	}
+	buf.WriteString(" {")
+	preds := canonicalPreds(f)
+	for i, block := range f.Blocks {
+		// Basic block label; preceded by an empty line.
+		var label string
+		if !block.IsUnnamed() {
+			label = enc.Label(block.LocalName)
+		} else if i != 0 {
+			label = fmt.Sprintf("%d:", block.LocalID)
+		}
+		if len(label) > 0 {
+			buf.WriteString("\n")
+			writeComments(buf, comments, block, "")
+			buf.WriteString(label)
+		}
+		if i != 0 {
+			// Predecessor basic blocks comment, padded to column 50.
+			pad := 50 - len(label)
+			if pad < 1 {
+				pad = 1
+			}
+			buf.WriteString(strings.Repeat(" ", pad))
+			if len(preds[block]) == 0 {
+				buf.WriteString("; No predecessors!")
+			} else {
+				buf.WriteString("; preds = ")
+				for j, pred := range preds[block] {
+					if j != 0 {
+						buf.WriteString(", ")
+					}
+					buf.WriteString(pred.Ident())
+				}
+			}
+		}
+		buf.WriteString("\n")
+		if len(label) == 0 {
+			writeComments(buf, comments, block, "  ")
+		}
+		// Instructions and terminator.
+		for _, inst := range block.Insts {
+			writeComments(buf, comments, inst, "  ")
+			writeCanonicalInst(buf, inst.LLString())
+		}
+		writeComments(buf, comments, block.Term, "  ")
+		writeCanonicalInst(buf, block.Term.LLString())
+	}
+	buf.WriteString("}\n")
+}
+
+// writeCanonicalInst writes the given LLVM IR assembly of an instruction or
+// terminator to buf, indented as by LLVM.
+func writeCanonicalInst(buf *strings.Builder, s string) {
+	for i, line := range strings.Split(s, "\n") {
+		switch {
+		case i == 0:
+			// first line of instruction.
+		case strings.HasPrefix(line, "\t\t"):
+			line = strings.TrimLeft(line, "\t")
+			if strings.HasPrefix(line, "to ") || line == "cleanup" || strings.HasPrefix(line, "catch ") || strings.HasPrefix(line, "filter ") {
+				// Continuation of invoke and landingpad.
+				line = strings.Repeat(" ", 8) + line
+			} else {
+				// Cases of switch.
+				line = "  " + line
+			}
+		default:
+			line = strings.TrimLeft(line, "\t")
+		}
+		fmt.Fprintf(buf, "  %s\n", line)
+	}
+}
+
+// canonicalPreds returns the predecessor basic blocks of each basic block in the
+// given function, in the order listed by LLVM; i.e. in reverse order of
+// occurrence of branches to the basic block.
+func canonicalPreds(f *Func) map[*Block][]*Block {
+	preds := make(map[*Block][]*Block)
+	for _, block := range f.Blocks {
+		if block.Term == nil {
+			continue
+		}
+		for _, succ := range block.Term.Succs() {
+			preds[succ] = append(preds[succ], block)
+		}
+	}
+	for _, ps := range preds {
+		for i, j := 0, len(ps)-1; i < j; i, j = i+1, j-1 {
+			ps[i], ps[j] = ps[j], ps[i]
+		}
+	}
+	return preds
+}
+
+// omitFuncType omits the explicit function type of the given call instruction
+// if not variadic, as in LLVM.
+func (c *canonicalizer) omitFuncType(call *InstCall) {
+	if c.explicitTypes || call.FuncType == nil || call.FuncType.Variadic {
+		return
+	}
+	// Cache type of call before omitting function type.
+	call.Type()
+	funcType := call.FuncType
+	call.FuncType = nil
+	c.undo = append(c.undo, func() {
+		call.FuncType = funcType
+	})
+}
+
+// ~~~ [ Attributes ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// setFuncAttrs replaces the given function attributes (both inline and from
+// attribute groups) by a single attribute group with the same contents, as in
+// LLVM.
+func (c *canonicalizer) setFuncAttrs(funcAttrs *[]FuncAttribute) {
+	var attrs []FuncAttribute
+	seen := make(map[string]bool)
+	add := func(attr FuncAttribute) {
+		key := canonicalAttrString(attr, true)
+		if !seen[key] {
+			seen[key] = true
+			attrs = append(attrs, attr)
+		}
+	}
+	for _, attr := range *funcAttrs {
+		if def, ok := attr.(*AttrGroupDef); ok {
+			for _, attr := range def.FuncAttrs {
+				add(attr)
+			}
+			continue
+		}
+		add(attr)
+	}
+	old := *funcAttrs
+	c.undo = append(c.undo, func() {
+		*funcAttrs = old
+	})
+	if len(attrs) == 0 {
+		*funcAttrs = nil
+		return
+	}
+	sortAttrs(attrs)
+	key := canonicalAttrsString(attrs, true)
+	def, ok := c.attrGroups[key]
+	if !ok {
+		def = &AttrGroupDef{ID: int64(len(c.attrGroupDefs)), FuncAttrs: attrs}
+		c.attrGroups[key] = def
+		c.attrGroupDefs = append(c.attrGroupDefs, def)
+	}
+	*funcAttrs = []FuncAttribute{def}
+}
+
+// setPreemption omits the given preemption specifier of a global variable,
+// function, alias or IFunc with the given linkage and visibility if not printed
+// by LLVM; i.e. dso_preemptable, and dso_local if implied by the linkage or
+// visibility.
+func (c *canonicalizer) setPreemption(preemption *enum.Preemption, linkage enum.Linkage, visibility enum.Visibility) {
+	switch *preemption {
+	case enum.PreemptionDSOPreemptable:
+		// dso_preemptable is the default.
+	case enum.PreemptionDSOLocal:
+		local := linkage == enum.LinkagePrivate || linkage == enum.LinkageInternal
+		nonDefault := visibility == enum.VisibilityHidden || visibility == enum.VisibilityProtected
+		if !local && !(nonDefault && linkage != enum.LinkageExternWeak) {
+			return
+		}
+	default:
+		return
+	}
+	old := *preemption
+	c.undo = append(c.undo, func() {
+		*preemption = old
+	})
+	*preemption = enum.PreemptionNone
+}
+
+// sortAttrs sorts the given parameter or return attributes in place, in the
+// order of LLVM.
+func (c *canonicalizer) sortAttrs(attrs interface{}) {
+	v := reflect.ValueOf(attrs)
+	if v.Len() < 2 {
+		return
+	}
+	old := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+	reflect.Copy(old, v)
+	c.undo = append(c.undo, func() {
+		reflect.Copy(v, old)
+	})
+	sortAttrs(attrs)
+}
+
+// sortArgAttrs sorts the parameter attributes of the given function arguments
+// in place, in the order of LLVM.
+func (c *canonicalizer) sortArgAttrs(args []value.Value) {
+	for _, arg := range args {
+		if arg, ok := arg.(*Arg); ok {
+			c.sortAttrs(arg.Attrs)
+		}
+	}
+}
+
+// sortAttrs sorts the given slice of attributes in place, in the order of LLVM;
+// i.e. enum attributes, type attributes and integer attributes by attribute
+// kind, followed by string attributes sorted by key.
+func sortAttrs(attrs interface{}) {
+	v := reflect.ValueOf(attrs)
+	less := func(i, j int) bool {
+		a, b := v.Index(i).Interface(), v.Index(j).Interface()
+		ra, rb := attrRank(a), attrRank(b)
+		if ra != rb {
+			return ra < rb
+		}
+		return attrKey(a) < attrKey(b)
+	}
+	sort.SliceStable(attrs, less)
+}
+
+// llvmAttrKinds specifies the attribute keywords of LLVM 14.0 in order of
+// attribute kind; i.e. enum attributes, type attributes and integer attributes,
+// each sorted by the name of the attribute definition in LLVM.
+var llvmAttrKinds = []string{
+	// Enum attributes.
+	"alwaysinline",
+	"argmemonly",
+	"builtin",
+	"cold",
+	"convergent",
+	"disable_sanitizer_instrumentation",
+	"hot",
+	"immarg",
+	"inreg",
+	"inaccessiblememonly",
+	"inaccessiblemem_or_argmemonly",
+	"inlinehint",
+	"jumptable",
+	"minsize",
+	"mustprogress",
+	"naked",
+	"nest",
+	"noalias",
+	"nobuiltin",
+	"nocallback",
+	"nocapture",
+	"nocf_check",
+	"noduplicate",
+	"nofree",
+	"noimplicitfloat",
+	"noinline",
+	"nomerge",
+	"noprofile",
+	"norecurse",
+	"noredzone",
+	"noreturn",
+	"nosanitize_coverage",
+	"nosync",
+	"noundef",
+	"nounwind",
+	"nonlazybind",
+	"nonnull",
+	"null_pointer_is_valid",
+	"optforfuzzing",
+	"optsize",
+	"optnone",
+	"readnone",
+	"readonly",
+	"returned",
+	"returns_twice",
+	"signext",
+	"safestack",
+	"sanitize_address",
+	"sanitize_hwaddress",
+	"sanitize_memtag",
+	"sanitize_memory",
+	"sanitize_thread",
+	"shadowcallstack",
+	"speculatable",
+	"speculative_load_hardening",
+	"ssp",
+	"sspreq",
+	"sspstrong",
+	"strictfp",
+	"swiftasync",
+	"swifterror",
+	"swiftself",
+	"uwtable",
+	"willreturn",
+	"writeonly",
+	"zeroext",
+	// Type attributes.
+	"byref",
+	"byval",
+	"elementtype",
+	"inalloca",
+	"preallocated",
+	"sret",
+	// Integer attributes.
+	"align",
+	"allocsize",
+	"dereferenceable",
+	"dereferenceable_or_null",
+	"alignstack",
+	"vscale_range",
+}
+
+// attrRank returns the sort rank of the given attribute. Attributes unknown to
+// LLVM 14.0 are sorted after known attributes, and string attributes last.
+func attrRank(attr interface{}) int {
+	switch attr.(type) {
+	case AttrString, AttrPair:
+		return len(llvmAttrKinds) + 1
+	}
+	key := attrKey(attr)
+	for i, kind := range llvmAttrKinds {
+		if kind == key {
+			return i
+		}
+	}
+	return len(llvmAttrKinds)
+}
+
+// attrKey returns the sort key of the given attribute; i.e. the attribute
+// keyword, or the key of string attributes.
+func attrKey(attr interface{}) string {
+	switch attr := attr.(type) {
+	case AttrString:
+		return string(attr)
+	case AttrPair:
+		return attr.Key
+	}
+	s := fmt.Sprint(attr)
+	if pos := strings.IndexAny(s, " (="); pos != -1 {
+		return s[:pos]
+	}
+	return s
+}
+
+// canonicalAttrsString returns the string representation of the given function
+// attributes, as printed by LLVM within attribute groups or elsewhere.
+func canonicalAttrsString(attrs []FuncAttribute, inAttrGroup bool) string {
+	ss := make([]string, len(attrs))
+	for i, attr := range attrs {
+		ss[i] = canonicalAttrString(attr, inAttrGroup)
+	}
+	return strings.Join(ss, " ")
+}
+
+// canonicalAttrString returns the string representation of the given function
+// attribute, as printed by LLVM within attribute groups or elsewhere.
+func canonicalAttrString(attr FuncAttribute, inAttrGroup bool) string {
+	switch attr := attr.(type) {
+	case Align:
+		if inAttrGroup {
+			return fmt.Sprintf("align=%d", uint64(attr))
+		}
+	case AlignStack:
+		if inAttrGroup {
+			return fmt.Sprintf("alignstack=%d", uint64(attr))
+		}
+	case AllocSize:
+		if attr.NElemsIndex != -1 {
+			return fmt.Sprintf("allocsize(%d,%d)", attr.ElemSizeIndex, attr.NElemsIndex)
+		}
+	}
+	return attr.String()
+}
+
+// ~~~ [ Types ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// findTypes records the type definitions of the module in order of first use,
+// as located by the type finder of LLVM.
+func (c *canonicalizer) findTypes() {
+	m := c.m
+	for _, g := range m.Globals {
+		c.addType(g.Typ)
+		if g.Init != nil {
+			c.addValue(g.Init)
+		}
+	}
+	for _, alias := range m.Aliases {
+		c.addType(alias.Typ)
+		c.addValue(alias.Aliasee)
+	}
+	for _, ifunc := range m.IFuncs {
+		c.addType(ifunc.Typ)
+		c.addValue(ifunc.Resolver)
+	}
+	for _, f := range m.Funcs {
+		c.addType(f.Typ)
+		for _, v := range []constant.Constant{f.Prefix, f.Prologue, f.Personality} {
+			if v != nil {
+				c.addValue(v)
+			}
+		}
+		for _, block := range f.Blocks {
+			for _, inst := range block.Insts {
+				c.addInst(inst)
+			}
+			if term, ok := block.Term.(Instruction); ok {
+				c.addInst(term)
+			}
+		}
+	}
+}
+
+// addInst records the named struct types used by the given instruction or
+// terminator.
+func (c *canonicalizer) addInst(inst Instruction) {
+	if v, ok := inst.(value.Value); ok {
+		c.addType(v.Type())
+	}
+	for _, op := range Operands(inst) {
+		if _, ok := (*op).(Instruction); !ok {
+			c.addValue(*op)
+		}
+	}
+	switch inst := inst.(type) {
+	case *InstGetElementPtr:
+		c.addType(inst.ElemType)
+	case *InstAlloca:
+		c.addType(inst.ElemType)
+	case *InstCall:
+		c.addType(inst.Sig())
+	}
+}
+
+// addValue records the named struct types used by the given value, if constant.
+func (c *canonicalizer) addValue(v value.Value) {
+	if _, ok := v.(constant.Constant); !ok {
+		return
+	}
+	switch v.(type) {
+	case *Global, *Func, *Alias, *IFunc:
+		return
+	}
+	if !reflect.TypeOf(v).Comparable() {
+		return
+	}
+	if c.visitedValues[v] {
+		return
+	}
+	c.visitedValues[v] = true
+	c.addType(v.Type())
+	if x, ok := v.(*constant.Float); ok {
+		c.setFloatLit(x)
+	}
+	// Constant operands.
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Field(i)
+		if !field.CanInterface() {
+			continue
+		}
+		switch x := field.Interface().(type) {
+		case constant.Constant:
+			if x != nil && !(field.Kind() == reflect.Ptr && field.IsNil()) {
+				c.addValue(x)
+			}
+		case []constant.Constant:
+			for _, elem := range x {
+				c.addValue(elem)
+			}
+		}
+	}
+}
+
+// addType records the named struct types used by the given type, in depth-first
+// pre-order.
+func (c *canonicalizer) addType(t types.Type) {
+	if t == nil || c.visitedTypes[t] {
+		return
+	}
+	c.visitedTypes[t] = true
+	switch t := t.(type) {
+	case *types.PointerType:
+		c.addType(t.ElemType)
+	case *types.VectorType:
+		c.addType(t.ElemType)
+	case *types.ArrayType:
+		c.addType(t.ElemType)
+	case *types.StructType:
+		if len(t.Name()) > 0 {
+			c.typeDefs = append(c.typeDefs, t)
+		}
+		for _, field := range t.Fields {
+			c.addType(field)
+		}
+	case *types.FuncType:
+		c.addType(t.RetType)
+		for _, param := range t.Params {
+			c.addType(param)
+		}
+	}
+}
+
+// setFloatLit sets the textual representation of the given float or double
+// constant to the decimal exponent notation of LLVM (e.g. 1.000000e+00) if the
+// value is exactly representable, and to the hexadecimal notation otherwise.
+func (c *canonicalizer) setFloatLit(x *constant.Float) {
+	if x.Typ.Kind != types.FloatKindFloat && x.Typ.Kind != types.FloatKindDouble {
+		return
+	}
+	if x.IsNaN() || x.IsInf() {
+		return
+	}
+	f, _ := x.X.Float64()
+	if x.Typ.Kind == types.FloatKindFloat {
+		f32, _ := x.X.Float32()
+		f = float64(f32)
+	}
+	s := fmt.Sprintf("%e", f)
+	if g, err := strconv.ParseFloat(s, 64); err != nil || g != f {
+		s = fmt.Sprintf("0x%016X", math.Float64bits(f))
+	}
+	lit, err := constant.NewFloatFromString(x.Typ, s)
+	if err != nil {
+		panic(fmt.Errorf("unable to parse floating-point literal %q; %v", s, err))
+	}
+	orig := *x
+	*x = *lit
+	c.undo = append(c.undo, func() {
+		*x = orig
+	})
+}
+
+// ~~~ [ Metadata ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// numberMetadata numbers the metadata definitions of the module in order of
+// first use, as by LLVM, and sorts metadata attachments by metadata kind ID.
+func (c *canonicalizer) numberMetadata() {
+	m := c.m
+	for _, md := range m.MetadataDefs {
+		md := md
+		id := md.ID()
+		c.undo = append(c.undo, func() {
+			md.SetID(id)
+		})
+		if _, ok := md.(*metadata.DIExpression); ok {
+			// DIExpression metadata nodes are printed inline.
+			md.SetID(-1)
+			continue
+		}
+		c.mdNumbered[md] = false
+	}
+	for _, g := range m.Globals {
+		c.addAttachments(g.Metadata)
+	}
+	var mdNames []string
+	for mdName := range m.NamedMetadataDefs {
+		mdNames = append(mdNames, mdName)
+	}
+	natsort.Strings(mdNames)
+	for _, mdName := range mdNames {
+		for _, node := range m.NamedMetadataDefs[mdName].Nodes {
+			c.addMetadata(reflect.ValueOf(node))
+		}
+	}
+	for _, f := range m.Funcs {
+		c.addAttachments(f.Metadata)
+		for _, block := range f.Blocks {
+			for _, inst := range block.Insts {
+				if call, ok := inst.(*InstCall); ok && strings.HasPrefix(call.Callee.Ident(), "@llvm.") {
+					// Metadata arguments of intrinsic function calls.
+					for _, arg := range call.Args {
+						if md, ok := arg.(*metadata.Value); ok {
+							c.addMetadata(reflect.ValueOf(md.Value))
+						}
+					}
+				}
+				c.addInstAttachments(inst)
+			}
+			c.addInstAttachments(block.Term)
+		}
+	}
+}
+
+// addInstAttachments sorts the metadata attachments of the given instruction or
+// terminator in place by metadata kind ID, and numbers the metadata nodes
+// attached.
+func (c *canonicalizer) addInstAttachments(inst interface{}) {
+	md, ok := inst.(interface {
+		MDAttachments() []*metadata.Attachment
+	})
+	if ok {
+		c.addAttachments(md.MDAttachments())
+	}
+}
+
+// addAttachments sorts the given metadata attachments in place by metadata kind
+// ID, and numbers the metadata nodes attached.
+func (c *canonicalizer) addAttachments(mds []*metadata.Attachment) {
+	if len(mds) == 0 {
+		return
+	}
+	old := append([]*metadata.Attachment(nil), mds...)
+	c.undo = append(c.undo, func() {
+		copy(mds, old)
+	})
+	kindID := func(name string) int {
+		for id, kind := range fixedMetadataKinds {
+			if kind == name {
+				return id
+			}
+		}
+		for i, kind := range c.m.MetadataKinds {
+			if kind == name {
+				return len(fixedMetadataKinds) + i
+			}
+		}
+		return len(fixedMetadataKinds) + len(c.m.MetadataKinds)
+	}
+	less := func(i, j int) bool {
+		return kindID(mds[i].Name) < kindID(mds[j].Name)
+	}
+	sort.SliceStable(mds, less)
+	for _, md := range mds {
+		c.addMetadata(reflect.ValueOf(md.Node))
+	}
+}
+
+// addMetadata numbers the metadata definitions reachable from the given
+// metadata, in depth-first pre-order.
+func (c *canonicalizer) addMetadata(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if !v.IsNil() {
+			c.addMetadata(v.Elem())
+		}
+	case reflect.Ptr:
+		if v.IsNil() || !v.CanInterface() {
+			return
+		}
+		if x, ok := v.Interface().(*constant.Float); ok {
+			// floating-point constant operand of metadata node.
+			c.addValue(x)
+			return
+		}
+		if !isMetadataType(v.Type().Elem()) {
+			return
+		}
+		x := v.Interface()
+		if md, ok := x.(metadata.Definition); ok {
+			if numbered, ok := c.mdNumbered[md]; ok {
+				if numbered {
+					return
+				}
+				c.mdNumbered[md] = true
+				md.SetID(int64(len(c.mdDefs)))
+				c.mdDefs = append(c.mdDefs, md)
+				c.addMetadata(v.Elem())
+				return
+			}
+		}
+		if c.mdVisited[x] {
+			return
+		}
+		c.mdVisited[x] = true
+		c.addMetadata(v.Elem())
+	case reflect.Struct:
+		if !isMetadataType(v.Type()) {
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			c.addMetadata(v.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			c.addMetadata(v.Index(i))
+		}
+	}
+}
+
+// isMetadataType reports whether the given type is defined in the metadata
+// package.
+func isMetadataType(t reflect.Type) bool {
+	return t.PkgPath() == reflect.TypeOf(metadata.Tuple{}).PkgPath()
+}
@@ -0,0 +1,143 @@
+package ir_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/asm"
+	"github.com/llir/llvm/ir"
+)
+
+func TestPrinterCanonicalMode(t *testing.T) {
+	// The golden output of each test case is captured from `opt -S` of LLVM
+	// 14.0; e.g.
+	//
+	//    cd testdata && opt -S canonical_cfg.ll -o canonical_cfg.ll.golden
+	golden := []struct {
+		path string
+		want string
+	}{
+		// Control flow, unnamed basic blocks and parameters, type definitions and
+		// comdats.
+		{path: "testdata/canonical_cfg.ll", want: "testdata/canonical_cfg.ll.golden"},
+		// Function, call site, parameter and return attributes.
+		{path: "testdata/canonical_attrs.ll", want: "testdata/canonical_attrs.ll.golden"},
+		// Metadata numbering and metadata attachments.
+		{path: "testdata/canonical_metadata.ll", want: "testdata/canonical_metadata.ll.golden"},
+		// Floating-point constants.
+		{path: "testdata/canonical_float.ll", want: "testdata/canonical_float.ll.golden"},
+		// dso_local and dso_preemptable.
+		{path: "testdata/canonical_dso_local.ll", want: "testdata/canonical_dso_local.ll.golden"},
+	}
+	for _, g := range golden {
+		m, err := asm.ParseFile(g.path)
+		if err != nil {
+			t.Errorf("unable to parse %q into AST; %+v", g.path, err)
+			continue
+		}
+		// LLVM uses the file name as source filename of modules read from files
+		// without source_filename directive.
+		m.SourceFilename = filepath.Base(g.path)
+		buf, err := ioutil.ReadFile(g.want)
+		if err != nil {
+			t.Errorf("unable to read %q; %v", g.want, err)
+			continue
+		}
+		before := m.String()
+		p := &ir.Printer{CanonicalMode: true}
+		if got, want := p.Sprint(m), string(buf); got != want {
+			t.Errorf("canonical output mismatch of %q; expected:\n%s\ngot:\n%s", g.path, want, got)
+		}
+		// Printing in canonical mode must leave the module unmodified.
+		if after := m.String(); before != after {
+			t.Errorf("module %q modified by canonical printing; expected:\n%s\ngot:\n%s", g.path, before, after)
+		}
+		// The default printer prints as Module.String.
+		out := &strings.Builder{}
+		if err := (&ir.Printer{}).Fprint(out, m); err != nil {
+			t.Errorf("unable to print %q; %v", g.path, err)
+			continue
+		}
+		if before != out.String() {
+			t.Errorf("output mismatch of %q; expected:\n%s\ngot:\n%s", g.path, before, out.String())
+		}
+	}
+}
+
+func TestPrinterExplicitTypes(t *testing.T) {
+	const src = `
+declare i32 @f(i32)
+
+declare i32 @g(i32, ...)
+
+declare i32 @__gxx_personality_v0(...)
+
+define i32 @h(i32 %x) personality i32 (...)* @__gxx_personality_v0 {
+entry:
+	%a = call i32 @f(i32 %x)
+	%b = call i32 (i32, ...) @g(i32 %a, i32 %x)
+	%c = invoke i32 @f(i32 %b)
+		to label %normal unwind label %exception
+
+normal:
+	%d = phi i32 [ %c, %entry ]
+	ret i32 %d
+
+exception:
+	%e = landingpad { i8*, i32 }
+		cleanup
+	ret i32 0
+}
+`
+	m, err := asm.ParseString("", src)
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	before := m.String()
+	for _, canonical := range []bool{false, true} {
+		p := &ir.Printer{CanonicalMode: canonical, ExplicitTypes: true}
+		got := p.Sprint(m)
+		for _, want := range []string{
+			"%a = call i32 (i32) @f(i32 %x)",
+			"%b = call i32 (i32, ...) @g(i32 %a, i32 %x)",
+			"%c = invoke i32 (i32) @f(i32 %b)",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("missing %q in output (canonical mode %v):\n%s", want, canonical, got)
+			}
+		}
+		// The output with explicit types is valid LLVM IR.
+		if _, err := asm.ParseString("", got); err != nil {
+			t.Errorf("unable to parse output (canonical mode %v); %+v", canonical, err)
+		}
+		// Printing with explicit types must leave the module unmodified.
+		if after := m.String(); before != after {
+			t.Errorf("module modified by printing with explicit types (canonical mode %v); expected:\n%s\ngot:\n%s", canonical, before, after)
+		}
+	}
+}
+
+func TestNormalizeMemoryAttrsParse(t *testing.T) {
+	const src = `declare void @f() nounwind readonly argmemonly
+`
+	m, err := asm.ParseString("", src)
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	f := m.Funcs[0]
+	f.FuncAttrs = ir.NormalizeMemoryAttrs(f.FuncAttrs)
+	const want = "declare void @f() nounwind memory(argmem: read)"
+	if got := f.LLString(); got != want {
+		t.Errorf("function mismatch; expected %q, got %q", want, got)
+	}
+	// Normalized function attributes are parsed back unmodified.
+	m2, err := asm.ParseString("", m.String())
+	if err != nil {
+		t.Fatalf("unable to parse normalized module; %+v", err)
+	}
+	if got := m2.Funcs[0].LLString(); got != want {
+		t.Errorf("round-trip function mismatch; expected %q, got %q", want, got)
+	}
+}
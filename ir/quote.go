@@ -0,0 +1,80 @@
+package ir
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/llir/llvm/internal/enc"
+	"github.com/pkg/errors"
+)
+
+// --- [ Quoting ] -------------------------------------------------------------
+
+// QuoteIdent returns the LLVM IR assembly representation of the given global or
+// local identifier name, without '@' or '%' prefix. The name is enclosed in
+// double quotes if it contains characters not valid in unquoted identifiers,
+// with special characters replaced by hexadecimal escape sequences (\XX). Names
+// which are numbers or start with a decimal digit are quoted to distinguish
+// them from unnamed IDs.
+//
+// Examples:
+//
+//    "foo" -> "foo"
+//    "a b" -> `"a b"`
+//    "42"  -> `"42"`
+//    "1a"  -> `"1a"`
+//    "世"  -> `"\E4\B8\96"`
+func QuoteIdent(name string) string {
+	if len(name) == 0 {
+		return `""`
+	}
+	s := enc.EscapeIdent(name)
+	if s != name {
+		// Quoted identifier.
+		return s
+	}
+	if _, err := strconv.ParseInt(name, 10, 64); err == nil || isDigit(name[0]) {
+		return `"` + name + `"`
+	}
+	return name
+}
+
+// UnquoteIdent returns the global or local identifier name of the given LLVM IR
+// assembly representation, without '@' or '%' prefix; the inverse of
+// QuoteIdent.
+func UnquoteIdent(s string) (string, error) {
+	if strings.HasPrefix(s, `"`) {
+		return UnquoteString(s)
+	}
+	if len(s) == 0 || enc.EscapeIdent(s) != s || isDigit(s[0]) {
+		return "", errors.Errorf("invalid unquoted identifier %q", s)
+	}
+	return s, nil
+}
+
+// isDigit reports whether the given character is a decimal digit.
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// QuoteString returns s as a double-quoted LLVM IR string literal, with special
+// characters (e.g. '"', '\' and non-printable characters) replaced by
+// hexadecimal escape sequences (\XX).
+//
+// Examples:
+//
+//    "foo"      -> `"foo"`
+//    "a\"b"     -> `"a\22b"`
+//    "foo\x00"  -> `"foo\00"`
+func QuoteString(s string) string {
+	return quote(s)
+}
+
+// UnquoteString interprets s as a double-quoted LLVM IR string literal,
+// returning the string value that s quotes; the inverse of QuoteString.
+func UnquoteString(s string) (string, error) {
+	if len(s) < 2 || !strings.HasPrefix(s, `"`) || !strings.HasSuffix(s, `"`) {
+		return "", errors.Errorf("invalid quoted string %q; expected enclosing double quotes", s)
+	}
+	return unquote(s), nil
+}
@@ -0,0 +1,89 @@
+package ir_test
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestQuoteIdent(t *testing.T) {
+	golden := []struct {
+		name string
+		want string
+	}{
+		{name: "foo", want: "foo"},
+		{name: "foo.bar$-_", want: "foo.bar$-_"},
+		{name: "a b", want: `"a b"`},
+		{name: "42", want: `"42"`},
+		{name: "007", want: `"007"`},
+		{name: "-1", want: `"-1"`},
+		{name: "1a", want: `"1a"`},
+		{name: `a"b\c`, want: `"a\22b\5Cc"`},
+		{name: "世", want: `"\E4\B8\96"`},
+		{name: "", want: `""`},
+	}
+	m := ir.NewModule()
+	for _, g := range golden {
+		got := ir.QuoteIdent(g.name)
+		if g.want != got {
+			t.Errorf("quoted identifier mismatch of %q; expected `%s`, got `%s`", g.name, g.want, got)
+		}
+		name, err := ir.UnquoteIdent(got)
+		if err != nil {
+			t.Errorf("unable to unquote identifier `%s`; %v", got, err)
+		} else if name != g.name {
+			t.Errorf("unquoted identifier mismatch of `%s`; expected %q, got %q", got, g.name, name)
+		}
+		if len(g.name) == 0 {
+			continue
+		}
+		// Escaping matches that of the serializer.
+		param := ir.NewParam(g.name, types.I32)
+		if want := "%" + got; param.Ident() != want {
+			t.Errorf("local identifier mismatch of %q; expected `%s`, got `%s`", g.name, want, param.Ident())
+		}
+		global := m.NewGlobal(g.name, types.I32)
+		if want := "@" + got; global.Ident() != want {
+			t.Errorf("global identifier mismatch of %q; expected `%s`, got `%s`", g.name, want, global.Ident())
+		}
+	}
+	// Invalid identifiers.
+	for _, s := range []string{"", "a b", `"a`, "世", "42", "1a"} {
+		if _, err := ir.UnquoteIdent(s); err == nil {
+			t.Errorf("expected error for invalid identifier `%s`, got nil", s)
+		}
+	}
+}
+
+func TestQuoteString(t *testing.T) {
+	golden := []struct {
+		s    string
+		want string
+	}{
+		{s: "foo", want: `"foo"`},
+		{s: "", want: `""`},
+		{s: `a"b`, want: `"a\22b"`},
+		{s: `a\b`, want: `"a\5Cb"`},
+		{s: "foo\x00bar\n", want: `"foo\00bar\0A"`},
+		{s: "héllo", want: `"h\C3\A9llo"`},
+	}
+	for _, g := range golden {
+		got := ir.QuoteString(g.s)
+		if g.want != got {
+			t.Errorf("quoted string mismatch of %q; expected `%s`, got `%s`", g.s, g.want, got)
+		}
+		s, err := ir.UnquoteString(got)
+		if err != nil {
+			t.Errorf("unable to unquote string `%s`; %v", got, err)
+		} else if s != g.s {
+			t.Errorf("unquoted string mismatch of `%s`; expected %q, got %q", got, g.s, s)
+		}
+	}
+	// Invalid string literals.
+	for _, s := range []string{"", `"`, "foo", `"foo`} {
+		if _, err := ir.UnquoteString(s); err == nil {
+			t.Errorf("expected error for invalid string literal `%s`, got nil", s)
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package ir
 
+import "github.com/llir/llvm/ir/value"
+
 // === [ constant.Constant ] ===================================================
 
 // IsConstant ensures that only constants can be assigned to the
@@ -44,6 +46,10 @@ func (AlignStack) IsFuncAttribute() {}
 // ir.FuncAttribute interface.
 func (AllocSize) IsFuncAttribute() {}
 
+// IsFuncAttribute ensures that only function attributes can be assigned to the
+// ir.FuncAttribute interface.
+func (Memory) IsFuncAttribute() {}
+
 // === [ ir.Instruction ] ======================================================
 
 // Binary instructions.
@@ -115,6 +121,23 @@ func (*InstLandingPad) isInstruction() {}
 func (*InstCatchPad) isInstruction()   {}
 func (*InstCleanupPad) isInstruction() {}
 
+// Terminators.
+//
+// Terminators implement the ir.Instruction interface so that they may be
+// yielded alongside instructions (e.g. by ir.Block.All). Terminators must only
+// be stored in the Term field of basic blocks, never in Insts.
+func (*TermRet) isInstruction()         {}
+func (*TermBr) isInstruction()          {}
+func (*TermCondBr) isInstruction()      {}
+func (*TermSwitch) isInstruction()      {}
+func (*TermIndirectBr) isInstruction()  {}
+func (*TermInvoke) isInstruction()      {}
+func (*TermResume) isInstruction()      {}
+func (*TermCatchSwitch) isInstruction() {}
+func (*TermCatchRet) isInstruction()    {}
+func (*TermCleanupRet) isInstruction()  {}
+func (*TermUnreachable) isInstruction() {}
+
 // === [ ir.ParamAttribute ] ===================================================
 
 // IsParamAttribute ensures that only parameter attributes can be assigned to
@@ -160,3 +183,323 @@ func (*Block) isUnwindTarget() {}
 // isUnwindTarget ensures that only unwind targets can be assigned to the
 // ir.UnwindTarget interface.
 func (UnwindToCaller) isUnwindTarget() {}
+
+// === [ value.Value ] =========================================================
+
+// Kind returns the kind of the value.
+func (*Global) Kind() value.Kind {
+	return value.KindGlobal
+}
+
+// Kind returns the kind of the value.
+func (*Alias) Kind() value.Kind {
+	return value.KindGlobal
+}
+
+// Kind returns the kind of the value.
+func (*IFunc) Kind() value.Kind {
+	return value.KindGlobal
+}
+
+// Kind returns the kind of the value.
+func (*Func) Kind() value.Kind {
+	return value.KindFunc
+}
+
+// Kind returns the kind of the value.
+func (*Param) Kind() value.Kind {
+	return value.KindParam
+}
+
+// Kind returns the kind of the value.
+func (*Block) Kind() value.Kind {
+	return value.KindBasicBlock
+}
+
+// Kind returns the kind of the value.
+func (*InlineAsm) Kind() value.Kind {
+	return value.KindInlineAsm
+}
+
+// Unary instructions.
+
+// Kind returns the kind of the value.
+func (*InstFNeg) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Binary instructions.
+
+// Kind returns the kind of the value.
+func (*InstAdd) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstFAdd) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstSub) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstFSub) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstMul) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstFMul) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstUDiv) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstSDiv) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstFDiv) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstURem) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstSRem) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstFRem) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Bitwise instructions.
+
+// Kind returns the kind of the value.
+func (*InstShl) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstLShr) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstAShr) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstAnd) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstOr) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstXor) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Vector instructions.
+
+// Kind returns the kind of the value.
+func (*InstExtractElement) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstInsertElement) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstShuffleVector) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Aggregate instructions.
+
+// Kind returns the kind of the value.
+func (*InstExtractValue) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstInsertValue) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Memory instructions.
+
+// Kind returns the kind of the value.
+func (*InstAlloca) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstLoad) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstCmpXchg) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstAtomicRMW) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstGetElementPtr) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Conversion instructions.
+
+// Kind returns the kind of the value.
+func (*InstTrunc) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstZExt) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstSExt) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstFPTrunc) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstFPExt) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstFPToUI) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstFPToSI) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstUIToFP) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstSIToFP) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstPtrToInt) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstIntToPtr) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstBitCast) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstAddrSpaceCast) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Other instructions.
+
+// Kind returns the kind of the value.
+func (*InstICmp) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstFCmp) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstPhi) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstSelect) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstCall) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstVAArg) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstLandingPad) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstCatchPad) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*InstCleanupPad) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Value terminators.
+
+// Kind returns the kind of the value.
+func (*TermInvoke) Kind() value.Kind {
+	return value.KindInstruction
+}
+
+// Kind returns the kind of the value.
+func (*TermCatchSwitch) Kind() value.Kind {
+	return value.KindInstruction
+}
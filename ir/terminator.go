@@ -31,6 +31,13 @@ import (
 //    *ir.TermCatchRet      // https://godoc.org/github.com/llir/llvm/ir#TermCatchRet
 //    *ir.TermCleanupRet    // https://godoc.org/github.com/llir/llvm/ir#TermCleanupRet
 //    *ir.TermUnreachable   // https://godoc.org/github.com/llir/llvm/ir#TermUnreachable
+//
+// The terminators of the ir package also implement the Instruction interface,
+// through which the parent basic block of a terminator may be accessed; e.g.
+//
+//    if term, ok := block.Term.(ir.Instruction); ok {
+//        parent := term.GetParent()
+//    }
 type Terminator interface {
 	LLStringer
 	// Succs returns the successor basic blocks of the terminator.
@@ -48,6 +55,9 @@ type TermRet struct {
 
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewRet returns a new ret terminator based on the given return value. A nil
@@ -96,6 +106,9 @@ type TermBr struct {
 	Successors []*Block
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewBr returns a new unconditional br terminator based on the given target
@@ -141,6 +154,9 @@ type TermCondBr struct {
 	Successors []*Block
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewCondBr returns a new conditional br terminator based on the given
@@ -187,6 +203,9 @@ type TermSwitch struct {
 	Successors []*Block
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewSwitch returns a new switch terminator based on the given control
@@ -260,6 +279,9 @@ type TermIndirectBr struct {
 
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewIndirectBr returns a new indirectbr terminator based on the given target
@@ -274,6 +296,29 @@ func (term *TermIndirectBr) Succs() []*Block {
 	return term.ValidTargets
 }
 
+// AddDest adds the given basic block to the set of valid target basic blocks of
+// the terminator, if not already present.
+func (term *TermIndirectBr) AddDest(target *Block) {
+	for _, t := range term.ValidTargets {
+		if t == target {
+			return
+		}
+	}
+	term.ValidTargets = append(term.ValidTargets, target)
+}
+
+// RemoveDest removes the given basic block from the set of valid target basic
+// blocks of the terminator, if present.
+func (term *TermIndirectBr) RemoveDest(target *Block) {
+	targets := term.ValidTargets[:0]
+	for _, t := range term.ValidTargets {
+		if t != target {
+			targets = append(targets, t)
+		}
+	}
+	term.ValidTargets = targets
+}
+
 // LLString returns the LLVM syntax representation of the terminator.
 func (term *TermIndirectBr) LLString() string {
 	// 'indirectbr' Addr=TypeValue ',' '[' ValidTargets=(Label separator ',')+
@@ -316,7 +361,8 @@ type TermInvoke struct {
 	// extra.
 
 	// Type of result produced by the terminator, or function signature of the
-	// invokee (as used when invokee is variadic).
+	// invokee (as used when invokee is variadic, or for explicit function
+	// types).
 	Typ types.Type
 	// Successor basic blocks of the terminator.
 	Successors []*Block
@@ -332,6 +378,9 @@ type TermInvoke struct {
 	OperandBundles []*OperandBundle
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewInvoke returns a new invoke terminator based on the given invokee, function
@@ -402,12 +451,11 @@ func (term *TermInvoke) LLString() string {
 	for _, attr := range term.ReturnAttrs {
 		fmt.Fprintf(buf, " %s", attr)
 	}
-	// Use function signature instead of return type for variadic functions.
+	// Use function signature instead of return type for variadic functions and
+	// invokes with explicit function type.
 	typ := term.Type()
 	if t, ok := term.Typ.(*types.FuncType); ok {
-		if t.Variadic {
-			typ = t
-		}
+		typ = t
 	}
 	fmt.Fprintf(buf, " %s %s(", typ, term.Invokee.Ident())
 	for i, arg := range term.Args {
@@ -448,6 +496,9 @@ type TermResume struct {
 
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewResume returns a new resume terminator based on the given exception
@@ -492,6 +543,9 @@ type TermCatchSwitch struct {
 	Successors []*Block
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewCatchSwitch returns a new catchswitch terminator based on the given
@@ -560,6 +614,9 @@ type TermCatchRet struct {
 	Successors []*Block
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewCatchRet returns a new catchret terminator based on the given exit
@@ -604,6 +661,9 @@ type TermCleanupRet struct {
 	Successors []*Block
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewCleanupRet returns a new cleanupret terminator based on the given exit
@@ -645,6 +705,9 @@ type TermUnreachable struct {
 
 	// (optional) Metadata.
 	Metadata
+	// Parent basic block; field set by the ir.Block builder methods and
+	// ir.Module.ResolveParents.
+	InstParent
 }
 
 // NewUnreachable returns a new unreachable terminator.
@@ -0,0 +1,24 @@
+; Function attributes (inline and from attribute groups), call site attributes,
+; and parameter and return attributes, in non-canonical order.
+
+declare noalias nonnull i8* @alloc(i64 zeroext, i8* nocapture readonly noalias %hint) #3
+
+declare void @log(i8*) nounwind #1
+
+define i32 @f(i32* nonnull noalias %p, i32 signext inreg %x) #2 {
+entry:
+	%0 = call noalias i8* @alloc(i64 zeroext 8, i8* readonly nocapture null) #4
+	call void @log(i8* %0) "c" nounwind
+	call void @log(i8* %0) #1
+	ret i32 %x
+}
+
+define void @g() noinline #2 {
+	ret void
+}
+
+attributes #0 = { "unused" }
+attributes #1 = { "b"="2" "a"="1" cold }
+attributes #2 = { uwtable nounwind "frame-pointer"="all" ssp noinline }
+attributes #3 = { nounwind allocsize(0) }
+attributes #4 = { cold }
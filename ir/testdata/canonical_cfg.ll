@@ -0,0 +1,62 @@
+; Control flow with unnamed basic blocks and parameters, switch terminators with
+; duplicate successors, exception handling, unreachable basic blocks, unused
+; type definitions and comdats.
+
+%unused = type { i8 }
+%T = type { %S, %U* }
+%S = type { i32 }
+%U = type { i16 }
+
+$unused = comdat any
+$g = comdat any
+$h = comdat largest
+
+@g = global %T zeroinitializer, comdat, align 8
+@p = global i32 1, comdat($h), align 4
+
+declare i32 @printf(i8*, ...)
+
+declare i32 @__gxx_personality_v0(...)
+
+define i32 @h(i32, i32 %y) comdat personality i32 (...)* @__gxx_personality_v0 {
+	%2 = icmp sgt i32 %0, %y
+	br i1 %2, label %3, label %5
+
+; <label>:3
+	%4 = call i32 (i8*, ...) @printf(i8* null, i32 %0)
+	br label %5
+
+; <label>:5
+	%6 = phi i32 [ 1, %3 ], [ 0, %1 ]
+	switch i32 %6, label %exit [
+		i32 0, label %a
+		i32 1, label %b
+		i32 2, label %a
+	]
+
+a:
+	%7 = invoke i32 @f(i32 %6)
+		to label %exit unwind label %lpad
+
+b:
+	br label %exit
+
+lpad:
+	%8 = landingpad { i8*, i32 }
+		cleanup
+		catch i8* null
+	resume { i8*, i32 } %8
+
+exit:
+	%9 = phi i32 [ %6, %5 ], [ %7, %a ], [ 2, %b ]
+	ret i32 %9
+
+dead:
+	unreachable
+}
+
+define i32 @f(i32 %x) {
+	%1 = alloca %U, align 2
+	%2 = call i32 (i32) @f(i32 %x)
+	ret i32 %2
+}
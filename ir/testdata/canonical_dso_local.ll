@@ -0,0 +1,40 @@
+; dso_local and dso_preemptable, both explicit and implied by linkage and
+; visibility.
+
+@a = dso_local global i32 1
+@b = dso_preemptable global i32 2
+@c = internal dso_local global i32 3
+@d = private dso_local global i32 4
+@e = dso_local hidden global i32 5
+@f = dso_local protected global i32 6
+@g = extern_weak dso_local hidden global i32
+@h = external dso_preemptable global i32
+@i = dso_local alias i32, i32* @a
+@j = internal dso_local alias i32, i32* @a
+@k = dso_preemptable alias i32, i32* @b
+
+declare dso_local void @decl_local()
+
+declare dso_preemptable void @decl_preemptable()
+
+declare extern_weak dso_local hidden void @decl_weak_hidden()
+
+define dso_local void @def_local() {
+	ret void
+}
+
+define internal dso_local void @def_internal() {
+	ret void
+}
+
+define dso_local hidden void @def_hidden() {
+	ret void
+}
+
+define dso_preemptable void @def_preemptable() {
+	call void @decl_local()
+	call void @decl_preemptable()
+	call void @decl_weak_hidden()
+	call void @def_internal()
+	ret void
+}
@@ -0,0 +1,18 @@
+; Floating-point constants in decimal exponent notation if exactly
+; representable, and in hexadecimal notation otherwise.
+
+@d = global [6 x double] [double 1.0, double 0.1, double -0.0, double 3.141592653589793, double 0x7FF0000000000000, double 1.0e300]
+@fs = global [3 x float] [float 3.0, float 0x3FB99999A0000000, float 2.5e-1]
+@v = global <2 x double> <double 2.0, double 1.5>
+
+define double @f(double %x, float %y) {
+	%1 = fadd double %x, 4.0
+	%2 = fpext float %y to double
+	%3 = fmul double %2, 0x3FD5555555555555
+	%4 = fsub double %1, %3
+	%5 = select i1 true, double %4, double 1.0e-3
+	ret double %5
+}
+
+!consts = !{!0}
+!0 = !{float 0.5, double 1.0e10}
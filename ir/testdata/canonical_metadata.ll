@@ -0,0 +1,25 @@
+; Metadata definitions numbered out of order of first use, unused metadata
+; definitions and metadata attachments in non-canonical order.
+
+@x = global i32 0, !b !4
+
+define i32 @f(i32* %p) !a !7 {
+	%1 = load i32, i32* %p, align 4, !b !1, !range !3, !nonnull_custom !8, !a !2
+	ret i32 %1
+}
+
+!a.first = !{!6, !0}
+!b.second = !{!5}
+
+!0 = !{!"zero"}
+!1 = !{!"one"}
+!2 = !{!"two", !9}
+!3 = !{i32 0, i32 10}
+!4 = !{!"four", !1}
+!5 = !{!"five", !0}
+!6 = !{!"six"}
+!7 = distinct !{!1, !10}
+!8 = !{!"eight"}
+!9 = !{!"nine"}
+!10 = !{!"ten"}
+!11 = !{!"unused"}
@@ -0,0 +1,599 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// === [ Data layout ] =========================================================
+
+// DataLayout is a parsed LLVM IR data layout, which specifies how data is to be
+// laid out in memory.
+//
+// Sizes and alignments are specified in bits.
+//
+// ref: https://llvm.org/docs/LangRef.html#data-layout
+type DataLayout struct {
+	// Big-endian byte order; little-endian if false.
+	BigEndian bool
+	// Natural alignment of the stack; zero if not specified.
+	StackAlign uint64
+	// Address space of allocas.
+	AllocaAddrSpace AddrSpace
+	// Pointer layouts, indexed by address space.
+	Pointers map[AddrSpace]PointerLayout
+	// Integer type alignments, indexed by bit size.
+	IntAligns map[uint64]LayoutAlign
+	// Floating-point type alignments, indexed by bit size.
+	FloatAligns map[uint64]LayoutAlign
+	// Vector type alignments, indexed by bit size.
+	VectorAligns map[uint64]LayoutAlign
+	// Aggregate type alignment.
+	AggregateAlign LayoutAlign
+	// Native integer widths of the target CPU.
+	NativeIntWidths []uint64
+	// (optional) Name mangling style; empty if not present.
+	Mangling string
+}
+
+// PointerLayout specifies the size and alignment of pointers in a given address
+// space.
+type PointerLayout struct {
+	// Size of pointer in bits.
+	Size uint64
+	// Alignment of pointer in bits.
+	LayoutAlign
+	// Size of index used for address calculation in bits.
+	IndexSize uint64
+}
+
+// LayoutAlign specifies the ABI and preferred alignments of a type.
+type LayoutAlign struct {
+	// ABI alignment in bits.
+	ABI uint64
+	// Preferred alignment in bits.
+	Pref uint64
+}
+
+// NewDataLayout returns a new data layout based on the given data layout
+// string (e.g. "e-m:e-i64:64-f80:128-n8:16:32:64-S128"). Specifications not
+// present in the data layout string default to the values documented in the
+// LLVM language reference.
+func NewDataLayout(s string) (*DataLayout, error) {
+	dl := &DataLayout{
+		Pointers: map[AddrSpace]PointerLayout{
+			0: {Size: 64, LayoutAlign: LayoutAlign{ABI: 64, Pref: 64}, IndexSize: 64},
+		},
+		IntAligns: map[uint64]LayoutAlign{
+			1:  {ABI: 8, Pref: 8},
+			8:  {ABI: 8, Pref: 8},
+			16: {ABI: 16, Pref: 16},
+			32: {ABI: 32, Pref: 32},
+			64: {ABI: 32, Pref: 64},
+		},
+		FloatAligns: map[uint64]LayoutAlign{
+			16:  {ABI: 16, Pref: 16},
+			32:  {ABI: 32, Pref: 32},
+			64:  {ABI: 64, Pref: 64},
+			128: {ABI: 128, Pref: 128},
+		},
+		VectorAligns: map[uint64]LayoutAlign{
+			64:  {ABI: 64, Pref: 64},
+			128: {ABI: 128, Pref: 128},
+		},
+		AggregateAlign: LayoutAlign{ABI: 0, Pref: 64},
+	}
+	if len(s) == 0 {
+		return dl, nil
+	}
+	for _, spec := range strings.Split(s, "-") {
+		if len(spec) == 0 {
+			return nil, errors.Errorf("invalid empty specification in data layout %q", s)
+		}
+		if err := dl.parseSpec(spec); err != nil {
+			return nil, errors.Wrapf(err, "invalid specification %q in data layout %q", spec, s)
+		}
+	}
+	return dl, nil
+}
+
+// defaultDataLayouts specifies the default data layout strings of common
+// target triples, as used by LLVM 14.
+var defaultDataLayouts = []struct {
+	// Target architectures.
+	archs []string
+	// Target operating systems (prefix match); any operating system if empty.
+	oses []string
+	// Data layout string.
+	layout string
+}{
+	{archs: []string{"x86_64", "amd64"}, oses: []string{"linux"}, layout: "e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"},
+	{archs: []string{"x86_64", "amd64"}, oses: []string{"windows"}, layout: "e-m:w-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"},
+	{archs: []string{"x86_64", "amd64"}, oses: []string{"darwin", "macos"}, layout: "e-m:o-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"},
+	{archs: []string{"i386", "i486", "i586", "i686"}, oses: []string{"linux"}, layout: "e-m:e-p:32:32-p270:32:32-p271:32:32-p272:64:64-f64:32:64-f80:32-n8:16:32-S128"},
+	{archs: []string{"aarch64", "arm64"}, oses: []string{"linux"}, layout: "e-m:e-i8:8:32-i16:16:32-i64:64-i128:128-n32:64-S128"},
+	{archs: []string{"aarch64", "arm64"}, oses: []string{"darwin", "macos", "ios"}, layout: "e-m:o-i64:64-i128:128-n32:64-S128"},
+	{archs: []string{"riscv64"}, oses: []string{"linux"}, layout: "e-m:e-p:64:64-i64:64-i128:128-n64-S128"},
+	{archs: []string{"wasm32"}, layout: "e-m:e-p:32:32-p10:8:8-p20:8:8-i64:64-n32:64-S128-ni:1:10:20"},
+	{archs: []string{"wasm64"}, layout: "e-m:e-p:64:64-p10:8:8-p20:8:8-i64:64-n32:64-S128-ni:1:10:20"},
+}
+
+// DefaultDataLayout returns the default data layout of the given target triple
+// (e.g. "x86_64-unknown-linux-gnu"), as used by LLVM for the target. The
+// boolean return value indicates success, and is false if the target triple is
+// not known.
+//
+// Supported targets include x86_64 and aarch64 Linux, x86_64 Windows, macOS,
+// i686 Linux, riscv64 Linux, and wasm32 and wasm64.
+func DefaultDataLayout(triple string) (*DataLayout, bool) {
+	s, ok := DefaultDataLayoutString(triple)
+	if !ok {
+		return nil, false
+	}
+	dl, err := NewDataLayout(s)
+	if err != nil {
+		panic(fmt.Errorf("unable to parse default data layout %q of target triple %q; %v", s, triple, err))
+	}
+	return dl, true
+}
+
+// DefaultDataLayoutString returns the default data layout string of the given
+// target triple (e.g. "x86_64-unknown-linux-gnu"). The boolean return value
+// indicates success, and is false if the target triple is not known.
+func DefaultDataLayoutString(triple string) (string, bool) {
+	parts := strings.Split(triple, "-")
+	arch := parts[0]
+	for _, def := range defaultDataLayouts {
+		if !containsString(def.archs, arch) {
+			continue
+		}
+		if len(def.oses) == 0 {
+			return def.layout, true
+		}
+		for _, part := range parts[1:] {
+			for _, os := range def.oses {
+				if strings.HasPrefix(part, os) {
+					return def.layout, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// PointerSize returns the size in bits of pointers in the given address space.
+// The size of pointers in the default address space is used for address spaces
+// without an explicit pointer layout.
+func (dl *DataLayout) PointerSize(addrSpace AddrSpace) uint64 {
+	return dl.pointerLayout(addrSpace).Size
+}
+
+// IndexSize returns the size in bits of indices used for address calculation
+// of pointers in the given address space.
+func (dl *DataLayout) IndexSize(addrSpace AddrSpace) uint64 {
+	return dl.pointerLayout(addrSpace).IndexSize
+}
+
+// pointerLayout returns the pointer layout of the given address space.
+func (dl *DataLayout) pointerLayout(addrSpace AddrSpace) PointerLayout {
+	if p, ok := dl.Pointers[addrSpace]; ok {
+		return p
+	}
+	return dl.Pointers[0]
+}
+
+// TypeSize returns the size in bits of the given sized type (e.g. 1 for i1 and
+// 80 for x86_fp80). TypeSize panics if the type is unsized or its size in bits
+// overflows uint64; use SizeInBits to handle such errors.
+func (dl *DataLayout) TypeSize(t Type) uint64 {
+	size, err := dl.SizeInBits(t)
+	if err != nil {
+		panic(err)
+	}
+	return size
+}
+
+// SizeInBits returns the size in bits of the given sized type (e.g. 1 for i1
+// and 80 for x86_fp80). An error is returned if the type is unsized, or if its
+// size in bits overflows uint64 (e.g. [2305843009213693952 x i16]).
+func (dl *DataLayout) SizeInBits(t Type) (uint64, error) {
+	switch t := t.(type) {
+	case *IntType:
+		return t.BitSize, nil
+	case *FloatType:
+		return floatSize(t.Kind), nil
+	case *MMXType:
+		return 64, nil
+	case *PointerType:
+		return dl.PointerSize(t.AddrSpace), nil
+	case *VectorType:
+		elemSize, err := dl.SizeInBits(t.ElemType)
+		if err != nil {
+			return 0, errors.WithStack(err)
+		}
+		return mulSize(t, t.Len, elemSize)
+	case *ArrayType:
+		elemSize, err := dl.allocSizeInBits(t.ElemType)
+		if err != nil {
+			return 0, errors.WithStack(err)
+		}
+		return mulSize(t, t.Len, elemSize)
+	case *StructType:
+		layout, err := dl.structLayout(t)
+		if err != nil {
+			return 0, errors.WithStack(err)
+		}
+		return layout.Size, nil
+	default:
+		return 0, errors.Errorf("unable to compute size of unsized type %q", t)
+	}
+}
+
+// StoreSize returns the maximum number of bits that may be overwritten by
+// storing a value of the given sized type (i.e. the type size rounded up to a
+// whole number of bytes).
+func (dl *DataLayout) StoreSize(t Type) uint64 {
+	return alignTo(dl.TypeSize(t), 8)
+}
+
+// AllocSize returns the offset in bits between successive values of the given
+// sized type, including alignment padding (i.e. the stride of array elements).
+func (dl *DataLayout) AllocSize(t Type) uint64 {
+	size, err := dl.allocSizeInBits(t)
+	if err != nil {
+		panic(err)
+	}
+	return size
+}
+
+// allocSizeInBits returns the offset in bits between successive values of the
+// given sized type. An error is returned if the type is unsized, or if its
+// allocation size in bits overflows uint64.
+func (dl *DataLayout) allocSizeInBits(t Type) (uint64, error) {
+	size, err := dl.SizeInBits(t)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	storeSize, ok := alignToChecked(size, 8)
+	if !ok {
+		return 0, errors.Errorf("store size of type %q overflows", t)
+	}
+	allocSize, ok := alignToChecked(storeSize, dl.ABIAlign(t))
+	if !ok {
+		return 0, errors.Errorf("allocation size of type %q overflows", t)
+	}
+	return allocSize, nil
+}
+
+// ABIAlign returns the ABI alignment in bits of the given sized type.
+func (dl *DataLayout) ABIAlign(t Type) uint64 {
+	switch t := t.(type) {
+	case *IntType:
+		return intAlign(dl.IntAligns, t.BitSize)
+	case *FloatType:
+		size := floatSize(t.Kind)
+		if align, ok := dl.FloatAligns[size]; ok {
+			return align.ABI
+		}
+		return nextPowerOf2(alignTo(size, 8))
+	case *MMXType:
+		if align, ok := dl.VectorAligns[64]; ok {
+			return align.ABI
+		}
+		return 64
+	case *PointerType:
+		return dl.pointerLayout(t.AddrSpace).ABI
+	case *VectorType:
+		size := dl.TypeSize(t)
+		if align, ok := dl.VectorAligns[size]; ok {
+			return align.ABI
+		}
+		return nextPowerOf2(alignTo(size, 8))
+	case *ArrayType:
+		return dl.ABIAlign(t.ElemType)
+	case *StructType:
+		return dl.StructLayout(t).Align
+	default:
+		panic(fmt.Errorf("unable to compute alignment of unsized type %q", t))
+	}
+}
+
+// StructLayout specifies the memory layout of a struct type.
+type StructLayout struct {
+	// Size of struct in bits, including tail padding.
+	Size uint64
+	// ABI alignment of struct in bits.
+	Align uint64
+	// Field offsets in bits, indexed by field.
+	Offsets []uint64
+}
+
+// StructLayout returns the memory layout of the given non-opaque struct type.
+// Fields of packed struct types are laid out with byte alignment.
+func (dl *DataLayout) StructLayout(t *StructType) StructLayout {
+	if t.Opaque {
+		panic(fmt.Errorf("unable to compute layout of opaque struct type %q", t))
+	}
+	layout, err := dl.structLayout(t)
+	if err != nil {
+		panic(err)
+	}
+	return layout
+}
+
+// structLayout returns the memory layout of the given struct type. An error is
+// returned if the struct type is opaque, or if its size in bits overflows
+// uint64.
+func (dl *DataLayout) structLayout(t *StructType) (StructLayout, error) {
+	if t.Opaque {
+		return StructLayout{}, errors.Errorf("unable to compute layout of opaque struct type %q", t)
+	}
+	layout := StructLayout{Align: 8, Offsets: make([]uint64, len(t.Fields))}
+	if !t.Packed && dl.AggregateAlign.ABI > layout.Align {
+		layout.Align = dl.AggregateAlign.ABI
+	}
+	overflow := errors.Errorf("size of struct type %q overflows", t)
+	for i, field := range t.Fields {
+		fieldSize, err := dl.allocSizeInBits(field)
+		if err != nil {
+			return StructLayout{}, errors.WithStack(err)
+		}
+		if !t.Packed {
+			align := dl.ABIAlign(field)
+			size, ok := alignToChecked(layout.Size, align)
+			if !ok {
+				return StructLayout{}, overflow
+			}
+			layout.Size = size
+			if align > layout.Align {
+				layout.Align = align
+			}
+		}
+		layout.Offsets[i] = layout.Size
+		if layout.Size+fieldSize < layout.Size {
+			return StructLayout{}, overflow
+		}
+		layout.Size += fieldSize
+	}
+	size, ok := alignToChecked(layout.Size, layout.Align)
+	if !ok {
+		return StructLayout{}, overflow
+	}
+	layout.Size = size
+	return layout, nil
+}
+
+// parseSpec parses the given data layout specification into dl.
+func (dl *DataLayout) parseSpec(spec string) error {
+	switch spec[0] {
+	case 'E':
+		dl.BigEndian = true
+	case 'e':
+		dl.BigEndian = false
+	case 'S':
+		align, err := parseLayoutInt(spec[1:])
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		dl.StackAlign = align
+	case 'A':
+		addrSpace, err := parseLayoutInt(spec[1:])
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		dl.AllocaAddrSpace = AddrSpace(addrSpace)
+	case 'p':
+		// p[n]:<size>:<abi>[:<pref>][:<idx>]
+		parts := strings.Split(spec[1:], ":")
+		var addrSpace uint64
+		if len(parts[0]) > 0 {
+			var err error
+			if addrSpace, err = parseLayoutInt(parts[0]); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		nums, err := parseLayoutInts(parts[1:], 2, 4)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		p := PointerLayout{Size: nums[0], LayoutAlign: LayoutAlign{ABI: nums[1], Pref: nums[1]}, IndexSize: nums[0]}
+		if len(nums) >= 3 {
+			p.Pref = nums[2]
+		}
+		if len(nums) >= 4 {
+			p.IndexSize = nums[3]
+		}
+		dl.Pointers[AddrSpace(addrSpace)] = p
+	case 'i', 'f', 'v':
+		// i<size>:<abi>[:<pref>]
+		parts := strings.Split(spec[1:], ":")
+		size, err := parseLayoutInt(parts[0])
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		align, err := parseLayoutAlign(parts[1:])
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		switch spec[0] {
+		case 'i':
+			dl.IntAligns[size] = align
+		case 'f':
+			dl.FloatAligns[size] = align
+		case 'v':
+			dl.VectorAligns[size] = align
+		}
+	case 'a':
+		// a:<abi>[:<pref>]
+		parts := strings.Split(spec[1:], ":")
+		if len(parts[0]) > 0 {
+			return errors.Errorf("invalid size %q of aggregate alignment; expected empty", parts[0])
+		}
+		align, err := parseLayoutAlign(parts[1:])
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		dl.AggregateAlign = align
+	case 'n':
+		if strings.HasPrefix(spec, "ni:") {
+			// Ignore non-integral address spaces, which do not affect memory
+			// layout.
+			return nil
+		}
+		// n<size1>:<size2>:...
+		widths, err := parseLayoutInts(strings.Split(spec[1:], ":"), 1, -1)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		dl.NativeIntWidths = widths
+	case 'm':
+		// m:<mangling>
+		if !strings.HasPrefix(spec, "m:") {
+			return errors.Errorf("invalid mangling specification; expected 'm:' prefix")
+		}
+		dl.Mangling = spec[len("m:"):]
+	default:
+		// Ignore unknown specifications (e.g. function pointer alignment) which
+		// do not affect memory layout.
+	}
+	return nil
+}
+
+// ### [ Helper functions ] ####################################################
+
+// parseLayoutAlign parses the given ABI and optional preferred alignment.
+func parseLayoutAlign(parts []string) (LayoutAlign, error) {
+	nums, err := parseLayoutInts(parts, 1, 2)
+	if err != nil {
+		return LayoutAlign{}, errors.WithStack(err)
+	}
+	align := LayoutAlign{ABI: nums[0], Pref: nums[0]}
+	if len(nums) >= 2 {
+		align.Pref = nums[1]
+	}
+	return align, nil
+}
+
+// parseLayoutInts parses the given integers of a data layout specification,
+// ensuring that at least min and at most max integers are present. A negative
+// max indicates no upper bound.
+func parseLayoutInts(parts []string, min, max int) ([]uint64, error) {
+	if len(parts) < min || (max >= 0 && len(parts) > max) {
+		return nil, errors.Errorf("invalid number of fields; expected between %d and %d, got %d", min, max, len(parts))
+	}
+	nums := make([]uint64, len(parts))
+	for i, part := range parts {
+		x, err := parseLayoutInt(part)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		nums[i] = x
+	}
+	return nums, nil
+}
+
+// parseLayoutInt parses the given integer of a data layout specification.
+func parseLayoutInt(s string) (uint64, error) {
+	x, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, errors.Errorf("invalid integer %q", s)
+	}
+	return x, nil
+}
+
+// floatSize returns the size in bits of the given floating-point kind.
+func floatSize(kind FloatKind) uint64 {
+	switch kind {
+	case FloatKindHalf:
+		return 16
+	case FloatKindFloat:
+		return 32
+	case FloatKindDouble:
+		return 64
+	case FloatKindX86_FP80:
+		return 80
+	case FloatKindFP128, FloatKindPPC_FP128:
+		return 128
+	default:
+		panic(fmt.Errorf("support for floating-point kind %v not yet implemented", kind))
+	}
+}
+
+// intAlign returns the ABI alignment in bits of integers of the given bit size.
+// Integers without an exact alignment specification use the alignment of the
+// smallest larger specified integer width; or the largest specified integer
+// width if no larger one is present.
+func intAlign(aligns map[uint64]LayoutAlign, size uint64) uint64 {
+	if align, ok := aligns[size]; ok {
+		return align.ABI
+	}
+	var best, largest uint64
+	found := false
+	for width := range aligns {
+		if width > size && (!found || width < best) {
+			best = width
+			found = true
+		}
+		if width > largest {
+			largest = width
+		}
+	}
+	if found {
+		return aligns[best].ABI
+	}
+	return aligns[largest].ABI
+}
+
+// alignTo rounds x up to the nearest multiple of align; align of zero is
+// treated as one.
+func alignTo(x, align uint64) uint64 {
+	if align == 0 {
+		return x
+	}
+	return (x + align - 1) / align * align
+}
+
+// alignToChecked rounds x up to the nearest multiple of align, as alignTo. The
+// boolean return value is false if the result overflows uint64.
+func alignToChecked(x, align uint64) (uint64, bool) {
+	if align == 0 {
+		return x, true
+	}
+	if x > math.MaxUint64-(align-1) {
+		return 0, false
+	}
+	return alignTo(x, align), true
+}
+
+// mulSize returns the size in bits of the given vector or array type, with n
+// elements of the given size in bits. An error is returned if the size
+// overflows uint64.
+func mulSize(t Type, n, elemSize uint64) (uint64, error) {
+	if elemSize != 0 && n > math.MaxUint64/elemSize {
+		return 0, errors.Errorf("size of type %q overflows; %d elements of %d bits", t, n, elemSize)
+	}
+	return n * elemSize, nil
+}
+
+// nextPowerOf2 returns the smallest power of two greater than or equal to x.
+func nextPowerOf2(x uint64) uint64 {
+	p := uint64(1)
+	for p < x {
+		p <<= 1
+	}
+	return p
+}
+
+// containsString reports whether the given strings contain s.
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
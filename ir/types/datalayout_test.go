@@ -0,0 +1,182 @@
+package types
+
+import "testing"
+
+func TestNewDataLayout(t *testing.T) {
+	golden := []struct {
+		in string
+		// Address space of pointer.
+		addrSpace AddrSpace
+		// Expected pointer size in bits.
+		ptrSize uint64
+		// Expected index size in bits.
+		idxSize uint64
+		// Expected big-endian byte order.
+		bigEndian bool
+	}{
+		// Default data layout.
+		{in: "", ptrSize: 64, idxSize: 64},
+		// x86_64 Linux.
+		{in: "e-m:e-i64:64-f80:128-n8:16:32:64-S128", ptrSize: 64, idxSize: 64},
+		// i386 Linux.
+		{in: "e-m:e-p:32:32-f64:32:64-f80:32-n8:16:32-S128", ptrSize: 32, idxSize: 32},
+		// Big-endian with index size distinct from pointer size.
+		{in: "E-p:64:64:64:32", ptrSize: 64, idxSize: 32, bigEndian: true},
+		// Address space without explicit pointer layout.
+		{in: "e-p:32:32-p1:16:16", addrSpace: 2, ptrSize: 32, idxSize: 32},
+		// Address space with explicit pointer layout.
+		{in: "e-p:32:32-p1:16:16", addrSpace: 1, ptrSize: 16, idxSize: 16},
+	}
+	for _, g := range golden {
+		dl, err := NewDataLayout(g.in)
+		if err != nil {
+			t.Errorf("unable to parse data layout %q; %v", g.in, err)
+			continue
+		}
+		if got := dl.PointerSize(g.addrSpace); g.ptrSize != got {
+			t.Errorf("pointer size mismatch of data layout %q; expected %d, got %d", g.in, g.ptrSize, got)
+		}
+		if got := dl.IndexSize(g.addrSpace); g.idxSize != got {
+			t.Errorf("index size mismatch of data layout %q; expected %d, got %d", g.in, g.idxSize, got)
+		}
+		if g.bigEndian != dl.BigEndian {
+			t.Errorf("byte order mismatch of data layout %q; expected big-endian %t, got %t", g.in, g.bigEndian, dl.BigEndian)
+		}
+	}
+	// Invalid data layouts.
+	for _, in := range []string{"e--p:32:32", "p:32", "i32:x", "a8:8"} {
+		if _, err := NewDataLayout(in); err == nil {
+			t.Errorf("expected error for invalid data layout %q, got nil", in)
+		}
+	}
+}
+
+func TestDataLayoutTypeSize(t *testing.T) {
+	dl, err := NewDataLayout("e-m:e-i64:64-f80:128-n8:16:32:64-S128")
+	if err != nil {
+		t.Fatalf("unable to parse data layout; %v", err)
+	}
+	packed := NewStruct(I8, I32)
+	packed.Packed = true
+	golden := []struct {
+		typ Type
+		// Expected type size, store size, alloc size and ABI alignment in bits.
+		size, storeSize, allocSize, align uint64
+	}{
+		{typ: I1, size: 1, storeSize: 8, allocSize: 8, align: 8},
+		{typ: I32, size: 32, storeSize: 32, allocSize: 32, align: 32},
+		{typ: NewInt(48), size: 48, storeSize: 48, allocSize: 64, align: 64},
+		{typ: NewInt(128), size: 128, storeSize: 128, allocSize: 128, align: 64},
+		{typ: X86_FP80, size: 80, storeSize: 80, allocSize: 128, align: 128},
+		{typ: I8Ptr, size: 64, storeSize: 64, allocSize: 64, align: 64},
+		{typ: NewVector(4, I32), size: 128, storeSize: 128, allocSize: 128, align: 128},
+		{typ: NewArray(3, I16), size: 48, storeSize: 48, allocSize: 48, align: 16},
+		{typ: NewStruct(I8, I32, I8), size: 96, storeSize: 96, allocSize: 96, align: 32},
+		{typ: NewStruct(I8, I64), size: 128, storeSize: 128, allocSize: 128, align: 64},
+		{typ: packed, size: 40, storeSize: 40, allocSize: 40, align: 8},
+		{typ: NewStruct(), size: 0, storeSize: 0, allocSize: 0, align: 8},
+		// Multi-gigabyte arrays.
+		{typ: NewArray(4294967296, I8), size: 1 << 35, storeSize: 1 << 35, allocSize: 1 << 35, align: 8},
+		{typ: NewArray(4, NewArray(4294967296, I64)), size: 1 << 40, storeSize: 1 << 40, allocSize: 1 << 40, align: 64},
+		{typ: NewStruct(I8, NewArray(1<<40, I32)), size: 32 + 1<<45, storeSize: 32 + 1<<45, allocSize: 32 + 1<<45, align: 32},
+	}
+	for _, g := range golden {
+		if got := dl.TypeSize(g.typ); g.size != got {
+			t.Errorf("type size mismatch of %q; expected %d, got %d", g.typ, g.size, got)
+		}
+		if got := dl.StoreSize(g.typ); g.storeSize != got {
+			t.Errorf("store size mismatch of %q; expected %d, got %d", g.typ, g.storeSize, got)
+		}
+		if got := dl.AllocSize(g.typ); g.allocSize != got {
+			t.Errorf("alloc size mismatch of %q; expected %d, got %d", g.typ, g.allocSize, got)
+		}
+		if got := dl.ABIAlign(g.typ); g.align != got {
+			t.Errorf("alignment mismatch of %q; expected %d, got %d", g.typ, g.align, got)
+		}
+	}
+	// Field offsets.
+	layout := dl.StructLayout(NewStruct(I8, I32, I8, I64))
+	want := []uint64{0, 32, 64, 128}
+	for i := range want {
+		if want[i] != layout.Offsets[i] {
+			t.Errorf("offset mismatch of field %d; expected %d, got %d", i, want[i], layout.Offsets[i])
+		}
+	}
+}
+
+func TestDataLayoutSizeOverflow(t *testing.T) {
+	dl, err := NewDataLayout("e-m:e-i64:64-f80:128-n8:16:32:64-S128")
+	if err != nil {
+		t.Fatalf("unable to parse data layout; %v", err)
+	}
+	// Largest array with a size in bits representable as uint64.
+	if got, err := dl.SizeInBits(NewArray(1<<61-1, I8)); err != nil || got != 1<<64-8 {
+		t.Errorf("size mismatch; expected %d, got %d (%v)", uint64(1<<64-8), got, err)
+	}
+	golden := []Type{
+		NewArray(1<<61, I8),
+		NewArray(1<<63, I16),
+		NewArray(18446744073709551615, I8),
+		NewArray(1<<32, NewArray(1<<32, I8)),
+		NewVector(1<<62, I32),
+		NewStruct(NewArray(1<<60, I8), NewArray(1<<60, I8)),
+		NewStruct(I8, NewArray(1<<58, I64)),
+	}
+	for _, typ := range golden {
+		if got, err := dl.SizeInBits(typ); err == nil {
+			t.Errorf("expected overflow error for size of %q, got %d", typ, got)
+		}
+	}
+	// Unsized types.
+	if _, err := dl.SizeInBits(Void); err == nil {
+		t.Errorf("expected error for size of unsized type %q, got nil", Void)
+	}
+}
+
+func TestDefaultDataLayout(t *testing.T) {
+	golden := []struct {
+		triple string
+		// Expected pointer size in bits; or 0 if the target triple is not known.
+		ptrSize uint64
+		// Expected alignment of i64 in bits.
+		i64Align uint64
+		// Expected name mangling style.
+		mangling string
+	}{
+		{triple: "x86_64-unknown-linux-gnu", ptrSize: 64, i64Align: 64, mangling: "e"},
+		{triple: "x86_64-linux-gnu", ptrSize: 64, i64Align: 64, mangling: "e"},
+		{triple: "x86_64-pc-windows-msvc", ptrSize: 64, i64Align: 64, mangling: "w"},
+		{triple: "x86_64-apple-macosx10.15.0", ptrSize: 64, i64Align: 64, mangling: "o"},
+		{triple: "i686-pc-linux-gnu", ptrSize: 32, i64Align: 32, mangling: "e"},
+		{triple: "aarch64-unknown-linux-gnu", ptrSize: 64, i64Align: 64, mangling: "e"},
+		{triple: "arm64-apple-macosx11.0.0", ptrSize: 64, i64Align: 64, mangling: "o"},
+		{triple: "riscv64-unknown-linux-gnu", ptrSize: 64, i64Align: 64, mangling: "e"},
+		{triple: "wasm32-unknown-unknown", ptrSize: 32, i64Align: 64, mangling: "e"},
+		{triple: "wasm64-unknown-unknown", ptrSize: 64, i64Align: 64, mangling: "e"},
+		{triple: "x86_64-unknown-freebsd"},
+		{triple: "mips-unknown-linux-gnu"},
+		{triple: ""},
+	}
+	for _, g := range golden {
+		dl, ok := DefaultDataLayout(g.triple)
+		if g.ptrSize == 0 {
+			if ok {
+				t.Errorf("unexpected default data layout of target triple %q", g.triple)
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("missing default data layout of target triple %q", g.triple)
+			continue
+		}
+		if got := dl.PointerSize(0); g.ptrSize != got {
+			t.Errorf("pointer size mismatch of target triple %q; expected %d, got %d", g.triple, g.ptrSize, got)
+		}
+		if got := dl.ABIAlign(I64); g.i64Align != got {
+			t.Errorf("i64 alignment mismatch of target triple %q; expected %d, got %d", g.triple, g.i64Align, got)
+		}
+		if g.mangling != dl.Mangling {
+			t.Errorf("mangling mismatch of target triple %q; expected %q, got %q", g.triple, g.mangling, dl.Mangling)
+		}
+	}
+}
@@ -23,3 +23,6 @@
 %t23 = type { i8, i32 }
 %t24 = type <{ i8, i32 }>
 %t25 = type opaque
+%t26 = type [4294967296 x i8]
+%t27 = type <4294967296 x i64>
+%t28 = type [18446744073709551615 x i8]
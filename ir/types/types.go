@@ -43,6 +43,18 @@ var (
 
 // Convenience functions.
 
+// Ptr returns a new pointer type based on the given element type. Ptr is
+// shorthand for NewPointer.
+func Ptr(elemType Type) *PointerType {
+	return NewPointer(elemType)
+}
+
+// Arr returns a new array type based on the given array length and element
+// type. Arr is shorthand for NewArray.
+func Arr(len uint64, elemType Type) *ArrayType {
+	return NewArray(len, elemType)
+}
+
 // IsVoid reports whether the given type is a void type.
 func IsVoid(t Type) bool {
 	_, ok := t.(*VoidType)
@@ -61,6 +73,14 @@ func IsInt(t Type) bool {
 	return ok
 }
 
+// IsBool reports whether the given type is a boolean type (i.e. i1).
+func IsBool(t Type) bool {
+	if t, ok := t.(*IntType); ok {
+		return t.BitSize == 1
+	}
+	return false
+}
+
 // IsFloat reports whether the given type is a floating-point type.
 func IsFloat(t Type) bool {
 	_, ok := t.(*FloatType)
@@ -272,6 +292,22 @@ func (t *FuncType) SetName(name string) {
 	t.TypeName = name
 }
 
+// AppendParam appends a function parameter of the given type to the function
+// type.
+//
+// Note, the parameters of functions with the given function type are not
+// updated; when updating the signature of an ir.Func, a corresponding parameter
+// must be appended to its Params (see ir.Func.Verify).
+func (t *FuncType) AppendParam(param Type) {
+	t.Params = append(t.Params, param)
+}
+
+// SetVariadic sets whether the function type takes a variable number of
+// function arguments.
+func (t *FuncType) SetVariadic(variadic bool) {
+	t.Variadic = variadic
+}
+
 // --- [ Integer types ] -------------------------------------------------------
 
 // IntType is an LLVM IR integer type.
@@ -448,6 +484,9 @@ func NewPointer(elemType Type) *PointerType {
 
 // Equal reports whether t and u are of equal type.
 func (t *PointerType) Equal(u Type) bool {
+	if u, ok := u.(*PointerType); ok && t.AddrSpace != u.AddrSpace {
+		return false
+	}
 	// HACK: to prevent infinite loops (e.g. struct foo containing field of type
 	// pointer to foo).
 	return t.String() == u.String()
@@ -752,6 +791,15 @@ func NewStruct(fields ...Type) *StructType {
 	}
 }
 
+// SetBody sets the fields of the struct type, thus turning an opaque struct
+// type into a non-opaque struct type. The fields may refer to the struct type
+// itself (e.g. through pointer types), as identified struct types are uniqued
+// by type names.
+func (t *StructType) SetBody(fields ...Type) {
+	t.Fields = fields
+	t.Opaque = false
+}
+
 // Equal reports whether t and u are of equal type.
 func (t *StructType) Equal(u Type) bool {
 	if u, ok := u.(*StructType); ok {
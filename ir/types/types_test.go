@@ -106,6 +106,25 @@ func TestIsInt(t *testing.T) {
 	}
 }
 
+func TestIsBool(t *testing.T) {
+	golden := []struct {
+		t    Type
+		want bool
+	}{
+		{t: I1, want: true},
+		{t: NewInt(1), want: true},
+		{t: I8, want: false},
+		{t: NewVector(2, I1), want: false},
+		{t: Void, want: false},
+	}
+	for _, g := range golden {
+		got := IsBool(g.t)
+		if g.want != got {
+			t.Errorf("check if `%s` is a boolean type mismatch; expected %t, got %t", g.t, g.want, got)
+		}
+	}
+}
+
 func TestIsFloat(t *testing.T) {
 	golden := []struct {
 		t    Type
@@ -290,6 +309,10 @@ func TestEqual(t *testing.T) {
 		{t: NewPointer(I8), u: &PointerType{ElemType: I8}, want: true},
 		{t: NewPointer(I8), u: NewPointer(Double), want: false},
 		{t: NewPointer(I8), u: I8, want: false},
+		{t: &PointerType{ElemType: I8, AddrSpace: 1}, u: &PointerType{ElemType: I8, AddrSpace: 1}, want: true},
+		{t: &PointerType{ElemType: I8, AddrSpace: 1}, u: NewPointer(I8), want: false},
+		{t: &PointerType{ElemType: I8, AddrSpace: 1}, u: &PointerType{ElemType: I8, AddrSpace: 2}, want: false},
+		{t: NewPointer(&PointerType{ElemType: I8, AddrSpace: 1}), u: NewPointer(NewPointer(I8)), want: false},
 		{t: NewVector(5, I8), u: &VectorType{Len: 5, ElemType: I8}, want: true},
 		{t: NewVector(5, I8), u: NewVector(3, I8), want: false},
 		{t: NewVector(5, I8), u: I8, want: false},
@@ -380,6 +403,47 @@ func TestStructTypeEqual(t *testing.T) {
 	}
 }
 
+func TestFuncTypeMutation(t *testing.T) {
+	sig := NewFunc(I32)
+	ptr := NewPointer(sig)
+	sig.AppendParam(I8Ptr)
+	sig.AppendParam(I64)
+	if want, got := "i32 (i8*, i64)*", ptr.String(); want != got {
+		t.Errorf("function type mismatch; expected %q, got %q", want, got)
+	}
+	sig.SetVariadic(true)
+	if want, got := "i32 (i8*, i64, ...)*", ptr.String(); want != got {
+		t.Errorf("function type mismatch; expected %q, got %q", want, got)
+	}
+	if !sig.Equal(&FuncType{RetType: I32, Params: []Type{I8Ptr, I64}, Variadic: true}) {
+		t.Errorf("expected function type %q to be equal to incrementally constructed function type", sig)
+	}
+	sig.SetVariadic(false)
+	if want, got := "i32 (i8*, i64)", sig.String(); want != got {
+		t.Errorf("function type mismatch; expected %q, got %q", want, got)
+	}
+}
+
+func TestShorthands(t *testing.T) {
+	golden := []struct {
+		t    Type
+		want string
+	}{
+		{t: Ptr(I32), want: "i32*"},
+		{t: Ptr(Ptr(I8)), want: "i8**"},
+		{t: Arr(4, I64), want: "[4 x i64]"},
+		{t: Ptr(Arr(2, Double)), want: "[2 x double]*"},
+	}
+	for _, g := range golden {
+		if got := g.t.String(); g.want != got {
+			t.Errorf("type mismatch; expected %q, got %q", g.want, got)
+		}
+	}
+	if !Ptr(I8).Equal(I8Ptr) {
+		t.Errorf("expected %q to be equal to %q", Ptr(I8), I8Ptr)
+	}
+}
+
 // Assert that each type implements the types.Type interface.
 var (
 	_ Type = (*VoidType)(nil)
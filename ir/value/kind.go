@@ -0,0 +1,24 @@
+package value
+
+//go:generate stringer -linecomment -type Kind
+
+// Kind specifies the kind of a value, which may be used to dispatch on values
+// without type assertions; e.g. to switch on the kind of a value before type
+// asserting its underlying type within the given kind.
+//
+// Global variables, aliases and indirect functions are of kind KindGlobal,
+// while functions are of kind KindFunc. Value terminators (e.g. invoke) are of
+// kind KindInstruction.
+type Kind uint8
+
+// Value kinds.
+const (
+	KindInstruction Kind = iota // instruction
+	KindConstant                // constant
+	KindGlobal                  // global
+	KindFunc                    // func
+	KindParam                   // param
+	KindBasicBlock              // basic block
+	KindInlineAsm               // inline asm
+	KindMetadata                // metadata
+)
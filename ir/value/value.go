@@ -23,6 +23,8 @@ type Value interface {
 	Type() types.Type
 	// Ident returns the identifier associated with the value.
 	Ident() string
+	// Kind returns the kind of the value.
+	Kind() Kind
 }
 
 // Named is a named LLVM IR value.
@@ -31,12 +33,24 @@ type Value interface {
 //
 //    *ir.Global            // https://godoc.org/github.com/llir/llvm/ir#Global
 //    *ir.Func              // https://godoc.org/github.com/llir/llvm/ir#Func
+//    *ir.Alias             // https://godoc.org/github.com/llir/llvm/ir#Alias
+//    *ir.IFunc             // https://godoc.org/github.com/llir/llvm/ir#IFunc
 //    *ir.Param             // https://godoc.org/github.com/llir/llvm/ir#Param
 //    *ir.Block             // https://godoc.org/github.com/llir/llvm/ir#Block
 //    TODO: add named metadata value?
 //    ir.Instruction        // https://godoc.org/github.com/llir/llvm/ir#Instruction (except store and fence)
 //    *ir.TermInvoke        // https://godoc.org/github.com/llir/llvm/ir#TermInvoke
 //    *ir.TermCatchSwitch   // https://godoc.org/github.com/llir/llvm/ir#TermCatchSwitch (token result used by catchpad)
+//
+// Names are specified without '@' or '%' prefix. Values which are not named
+// (i.e. with an empty name) are identified by ID; e.g. %42. Name returns the
+// ID of such unnamed values, and quotes numeric names to distinguish them from
+// IDs; e.g. "42".
+//
+// SetName marks the value as named if name is non-empty, so that it is not
+// assigned an ID by ir.Func.AssignIDs, and as unnamed otherwise. After renaming
+// local variables, the parent function should be marked as modified (using
+// ir.Func.MarkDirty) so that IDs are reassigned the next time it is printed.
 type Named interface {
 	Value
 	// Name returns the name of the value.
@@ -0,0 +1,573 @@
+package ir
+
+import (
+	"strings"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+	"github.com/pkg/errors"
+)
+
+// === [ Verification ] ========================================================
+
+// Verify reports an error if the module is not well-formed.
+func (m *Module) Verify() error {
+	for _, g := range m.Globals {
+		if err := verifyAlign(g.Align); err != nil {
+			return errors.Wrapf(err, "invalid alignment of global variable %s", g.Ident())
+		}
+	}
+	for _, f := range m.Funcs {
+		if err := f.Verify(); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if err := m.verifyIndirectSymbols(); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := m.verifyDebugInfo(); err != nil {
+		return errors.WithStack(err)
+	}
+	// Verify constant expressions of the module.
+	var err error
+	visit := func(c constant.Constant) {
+		if err != nil {
+			return
+		}
+		switch e := c.(type) {
+		case *constant.ExprBitCast:
+			if e2 := verifyBitCast(e.From.Type(), e.To); e2 != nil {
+				err = errors.Wrapf(e2, "invalid bitcast expression %s", e.Ident())
+			}
+		case *constant.ExprShuffleVector:
+			if e2 := verifyShuffleMask(e.X.Type(), e.Mask); e2 != nil {
+				err = errors.Wrapf(e2, "invalid shufflevector expression %s", e.Ident())
+			}
+		}
+	}
+	r := &constReplacer{visited: make(map[constant.Constant]bool), visit: visit}
+	r.replaceModule(m)
+	if err == nil && r.err != nil {
+		return errors.WithStack(r.err)
+	}
+	return err
+}
+
+// Verify reports an error if the function is not well-formed.
+func (f *Func) Verify() error {
+	if err := f.verifyLocalNames(); err != nil {
+		return errors.WithStack(err)
+	}
+	if len(f.Params) != len(f.Sig.Params) {
+		return errors.Errorf("parameter count mismatch between function %s and its signature; expected %d, got %d", f.Ident(), len(f.Sig.Params), len(f.Params))
+	}
+	for i, param := range f.Params {
+		if !param.Typ.Equal(f.Sig.Params[i]) {
+			return errors.Errorf("parameter %d type mismatch between function %s and its signature; expected %s, got %s", i, f.Ident(), f.Sig.Params[i], param.Typ)
+		}
+	}
+	for _, param := range f.Params {
+		for _, attr := range param.Attrs {
+			if err := verifyParamAttr(param.Typ, attr); err != nil {
+				return errors.Wrapf(err, "invalid parameter attribute of %s in function %s", param.Ident(), f.Ident())
+			}
+		}
+	}
+	if err := verifyEntryCountMetadata(f); err != nil {
+		return errors.WithStack(err)
+	}
+	entry := f.Entry()
+	// Basic blocks of which the address is taken; computed on first use.
+	var addrTaken map[*Block]bool
+	for _, block := range f.Blocks {
+		for _, inst := range block.Insts {
+			if err := verifyInst(f, block, inst); err != nil {
+				return errors.Wrapf(err, "invalid instruction in basic block %s of function %s", block.Ident(), f.Ident())
+			}
+		}
+		if block.Term == nil {
+			return errors.Errorf("missing terminator in basic block %s of function %s", block.Ident(), f.Ident())
+		}
+		if err := verifyTerm(block.Term); err != nil {
+			return errors.Wrapf(err, "invalid terminator in basic block %s of function %s", block.Ident(), f.Ident())
+		}
+		// Each target basic block of indirectbr must have its address taken.
+		if term, ok := block.Term.(*TermIndirectBr); ok {
+			if addrTaken == nil {
+				m := f.Parent
+				if m == nil {
+					m = &Module{Funcs: []*Func{f}}
+				}
+				addrTaken = blockAddresses(m, f)
+			}
+			for _, target := range term.ValidTargets {
+				if !addrTaken[target] {
+					return errors.Errorf("invalid target basic block %s of indirectbr in basic block %s of function %s; expected blockaddress(%s, %s) in module", target.Ident(), block.Ident(), f.Ident(), f.Ident(), target.Ident())
+				}
+			}
+		}
+		// The entry basic block must not have any predecessors.
+		for _, succ := range block.Term.Succs() {
+			if succ == entry {
+				return errors.Errorf("invalid branch from basic block %s to entry basic block %s of function %s", block.Ident(), entry.Ident(), f.Ident())
+			}
+		}
+	}
+	return nil
+}
+
+// verifyInst reports an error if the given instruction of the basic block in
+// function f is not well-formed.
+func verifyInst(f *Func, block *Block, inst Instruction) error {
+	switch inst := inst.(type) {
+	case *InstCall:
+		if err := verifyCallArgs(inst); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := verifyRangeMetadata(inst); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := verifyTBAAMetadata(inst); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := verifyAliasScopeMetadata(inst); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := verifyGCRelocate(inst); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := verifyCalleesMetadata(inst); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := verifySrcLocMetadata(inst); err != nil {
+			return errors.WithStack(err)
+		}
+		if inst.Tail == enum.TailMustTail {
+			if err := verifyMustTail(f, inst); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		return verifyOperandBundles(inst.OperandBundles)
+	case *InstAlloca:
+		if err := verifyAlign(inst.Align); err != nil {
+			return errors.Wrapf(err, "invalid alignment of alloca %s", inst.Ident())
+		}
+		if inst.NElems != nil && !types.IsInt(inst.NElems.Type()) {
+			return errors.Errorf("invalid number of elements type of alloca %s; expected integer type, got %s", inst.Ident(), inst.NElems.Type())
+		}
+	case *InstLoad:
+		if err := verifyAlign(inst.Align); err != nil {
+			return errors.Wrapf(err, "invalid alignment of load %s", inst.Ident())
+		}
+		src, ok := inst.Src.Type().(*types.PointerType)
+		if !ok {
+			return errors.Errorf("invalid source address type of load %s; expected pointer type, got %s", inst.Ident(), inst.Src.Type())
+		}
+		if !inst.Type().Equal(src.ElemType) {
+			return errors.Errorf("type mismatch between load %s and source address %s; expected %s, got %s", inst.Ident(), inst.Src.Ident(), src.ElemType, inst.Type())
+		}
+		if err := verifyRangeMetadata(inst); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := verifyLoadMetadata(inst); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := verifyTBAAMetadata(inst); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := verifyAliasScopeMetadata(inst); err != nil {
+			return errors.WithStack(err)
+		}
+	case *InstStore:
+		if err := verifyAlign(inst.Align); err != nil {
+			return errors.Wrapf(err, "invalid alignment of store to %s", inst.Dst.Ident())
+		}
+		dst, ok := inst.Dst.Type().(*types.PointerType)
+		if !ok {
+			return errors.Errorf("invalid destination address type of store to %s; expected pointer type, got %s", inst.Dst.Ident(), inst.Dst.Type())
+		}
+		if !inst.Src.Type().Equal(dst.ElemType) {
+			return errors.Errorf("type mismatch between source value %s and destination address %s of store; expected %s, got %s", inst.Src.Ident(), inst.Dst.Ident(), dst.ElemType, inst.Src.Type())
+		}
+		if err := verifyTBAAMetadata(inst); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := verifyAliasScopeMetadata(inst); err != nil {
+			return errors.WithStack(err)
+		}
+	case *InstPhi:
+		if len(inst.Incs) == 0 {
+			return errors.Errorf("missing incoming values of phi %s", inst.Ident())
+		}
+	case *InstBitCast:
+		if err := verifyBitCast(inst.From.Type(), inst.To); err != nil {
+			return errors.Wrapf(err, "invalid bitcast %s", inst.Ident())
+		}
+	case *InstShuffleVector:
+		if err := verifyShuffleMask(inst.X.Type(), inst.Mask); err != nil {
+			return errors.Wrapf(err, "invalid shufflevector %s", inst.Ident())
+		}
+	case *InstGetElementPtr:
+		if got, want := srcAddrSpace(inst.Type()), srcAddrSpace(inst.Src.Type()); got != want {
+			return errors.Errorf("address space mismatch between getelementptr %s and source address %s; expected %s, got %s", inst.Ident(), inst.Src.Ident(), want, got)
+		}
+	case *InstCatchPad:
+		if inst.Scope == nil {
+			return errors.Errorf("missing catchswitch scope of catchpad %s", inst.Ident())
+		}
+		for _, handler := range inst.Scope.Handlers {
+			if handler == block {
+				return nil
+			}
+		}
+		return errors.Errorf("catchpad %s is not a handler of catchswitch %s", inst.Ident(), inst.Scope.Ident())
+	case *InstCleanupPad:
+		if err := verifyExceptionScope(inst.Scope); err != nil {
+			return errors.Wrapf(err, "invalid scope of cleanuppad %s", inst.Ident())
+		}
+	}
+	return nil
+}
+
+// verifyTerm reports an error if the given terminator is not well-formed.
+func verifyTerm(term Terminator) error {
+	switch term := term.(type) {
+	case *TermIndirectBr:
+		if _, ok := term.Addr.Type().(*types.PointerType); !ok {
+			return errors.Errorf("invalid target address type of indirectbr; expected pointer type, got %s", term.Addr.Type())
+		}
+	case *TermInvoke:
+		if err := verifyInvokeArgs(term); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := verifyCalleesMetadata(term); err != nil {
+			return errors.WithStack(err)
+		}
+		return verifyOperandBundles(term.OperandBundles)
+	case *TermCatchSwitch:
+		if err := verifyExceptionScope(term.Scope); err != nil {
+			return errors.Wrapf(err, "invalid scope of catchswitch %s", term.Ident())
+		}
+		if len(term.Handlers) == 0 {
+			return errors.Errorf("missing exception handlers of catchswitch %s", term.Ident())
+		}
+		for _, handler := range term.Handlers {
+			if len(handler.Insts) == 0 {
+				return errors.Errorf("invalid exception handler %s of catchswitch %s; expected catchpad as first instruction", handler.Ident(), term.Ident())
+			}
+			pad, ok := handler.Insts[0].(*InstCatchPad)
+			if !ok {
+				return errors.Errorf("invalid exception handler %s of catchswitch %s; expected catchpad as first instruction, got %T", handler.Ident(), term.Ident(), handler.Insts[0])
+			}
+			if pad.Scope != term {
+				return errors.Errorf("invalid exception handler %s of catchswitch %s; catchpad %s not within catchswitch", handler.Ident(), term.Ident(), pad.Ident())
+			}
+		}
+	case *TermCatchRet:
+		if term.From == nil {
+			return errors.New("missing catchpad of catchret")
+		}
+	case *TermCleanupRet:
+		if term.From == nil {
+			return errors.New("missing cleanuppad of cleanupret")
+		}
+	}
+	return nil
+}
+
+// ### [ Helper functions ] ####################################################
+
+// maxAlign is the maximum alignment in bytes supported by LLVM.
+const maxAlign = 1 << 29
+
+// verifyAlign reports an error if the given alignment is neither zero (default
+// alignment) nor a power of two not exceeding the maximum alignment.
+func verifyAlign(align Align) error {
+	if align == 0 {
+		return nil
+	}
+	if align&(align-1) != 0 {
+		return errors.Errorf("alignment %d is not a power of two", uint64(align))
+	}
+	if align > maxAlign {
+		return errors.Errorf("alignment %d exceeds maximum alignment %d", uint64(align), maxAlign)
+	}
+	return nil
+}
+
+// verifyIndirectSymbols reports an error if the aliases and IFuncs of the
+// module form a cycle, if the aliasee of an alias is not a definition, or if the
+// resolver of an IFunc is not a function definition.
+func (m *Module) verifyIndirectSymbols() error {
+	for _, alias := range m.Aliases {
+		target, err := indirectSymbolTarget(alias)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if isDeclaration(target) {
+			return errors.Errorf("invalid aliasee %s of alias %s; expected definition, got declaration", target.Ident(), alias.Ident())
+		}
+	}
+	for _, ifunc := range m.IFuncs {
+		if _, err := indirectSymbolTarget(ifunc); err != nil {
+			return errors.WithStack(err)
+		}
+		// Resolve aliases of resolver; terminates as the aliases and IFuncs have
+		// been checked for cycles above.
+		resolver := constantBase(ifunc.Resolver)
+		for {
+			alias, ok := resolver.(*Alias)
+			if !ok {
+				break
+			}
+			resolver = constantBase(alias.Aliasee)
+		}
+		if f, ok := resolver.(*Func); !ok || isDeclaration(f) {
+			return errors.Errorf("invalid resolver %s of IFunc %s; expected function definition", ifunc.Resolver.Ident(), ifunc.Ident())
+		}
+	}
+	return nil
+}
+
+// indirectSymbolTarget returns the target of the given alias or IFunc; i.e. the
+// global variable or function reached by following aliasees of aliases and
+// resolvers of IFuncs. An error is reported if the aliases and IFuncs form a
+// cycle, listing the members of the cycle.
+func indirectSymbolTarget(g value.Named) (value.Named, error) {
+	var path []value.Named
+	onPath := make(map[value.Named]bool)
+	for {
+		var c constant.Constant
+		switch sym := g.(type) {
+		case *Alias:
+			c = sym.Aliasee
+		case *IFunc:
+			c = sym.Resolver
+		default:
+			return g, nil
+		}
+		if onPath[g] {
+			var idents []string
+			start := 0
+			for path[start] != g {
+				start++
+			}
+			for _, member := range path[start:] {
+				idents = append(idents, member.Ident())
+			}
+			idents = append(idents, g.Ident())
+			return nil, errors.Errorf("cycle in aliases and IFuncs: %s", strings.Join(idents, " -> "))
+		}
+		onPath[g] = true
+		path = append(path, g)
+		next := constantBase(c)
+		if next == nil {
+			return nil, errors.Errorf("invalid aliasee or resolver %s of %s; expected global value", c.Ident(), g.Ident())
+		}
+		g = next
+	}
+}
+
+// constantBase returns the global value (i.e. global variable, function, alias
+// or IFunc) of the given constant, stripping pointer casts and getelementptr
+// expressions. The nil value is returned if the constant is not based on a
+// global value.
+func constantBase(c constant.Constant) value.Named {
+	switch c := c.(type) {
+	case *Global:
+		return c
+	case *Func:
+		return c
+	case *Alias:
+		return c
+	case *IFunc:
+		return c
+	case *constant.ExprBitCast:
+		return constantBase(c.From)
+	case *constant.ExprAddrSpaceCast:
+		return constantBase(c.From)
+	case *constant.ExprGetElementPtr:
+		return constantBase(c.Src)
+	default:
+		return nil
+	}
+}
+
+// verifyBitCast reports an error if a bitcast from type from to type to changes
+// the address space of a pointer (or vector of pointers), which requires
+// addrspacecast.
+func verifyBitCast(from, to types.Type) error {
+	if !isPtrOrPtrVector(from) || !isPtrOrPtrVector(to) {
+		return nil
+	}
+	if fromAddrSpace, toAddrSpace := srcAddrSpace(from), srcAddrSpace(to); fromAddrSpace != toAddrSpace {
+		return errors.Errorf("address space mismatch between %s and %s; use addrspacecast to cast between address spaces", from, to)
+	}
+	return nil
+}
+
+// verifyShuffleMask reports an error if a lane of the given shuffle mask of a
+// shufflevector with vector operands of type x is out of range. Undef and
+// poison lanes are valid.
+func verifyShuffleMask(x types.Type, mask []int) error {
+	t, ok := x.(*types.VectorType)
+	if !ok {
+		return errors.Errorf("invalid vector operand type of shufflevector; expected vector type, got %s", x)
+	}
+	for i, elem := range mask {
+		if elem == constant.UndefMaskElem || elem == constant.PoisonMaskElem {
+			continue
+		}
+		if elem < 0 || uint64(elem) >= 2*t.Len {
+			return errors.Errorf("shuffle mask element %d at lane %d out of range [0, %d)", elem, i, 2*t.Len)
+		}
+	}
+	return nil
+}
+
+// verifyParamAttr reports an error if the given parameter attribute is not
+// legal for parameters of type t; e.g. byval on a non-pointer parameter or
+// zeroext on a non-integer parameter.
+func verifyParamAttr(t types.Type, attr ParamAttribute) error {
+	switch attr := attr.(type) {
+	case Align:
+		if !isPtrOrPtrVector(t) {
+			return errors.Errorf("parameter attribute %s requires pointer type, got %s", attr, t)
+		}
+		return verifyAlign(attr)
+	case Dereferenceable:
+		if !isPtrOrPtrVector(t) {
+			return errors.Errorf("parameter attribute %s requires pointer type, got %s", attr, t)
+		}
+	case enum.ParamAttr:
+		switch attr {
+		case enum.ParamAttrByval, enum.ParamAttrInAlloca, enum.ParamAttrNest, enum.ParamAttrNoAlias, enum.ParamAttrNoCapture, enum.ParamAttrNonNull, enum.ParamAttrReadNone, enum.ParamAttrReadOnly, enum.ParamAttrSRet, enum.ParamAttrSwiftError, enum.ParamAttrWriteOnly:
+			if !isPtrOrPtrVector(t) {
+				return errors.Errorf("parameter attribute %s requires pointer type, got %s", attr, t)
+			}
+		case enum.ParamAttrSignExt, enum.ParamAttrZeroExt:
+			if !types.IsInt(t) {
+				return errors.Errorf("parameter attribute %s requires integer type, got %s", attr, t)
+			}
+		}
+	}
+	return nil
+}
+
+// isPtrOrPtrVector reports whether the given type is a pointer type or a vector
+// of pointers.
+func isPtrOrPtrVector(t types.Type) bool {
+	if vt, ok := t.(*types.VectorType); ok {
+		t = vt.ElemType
+	}
+	return types.IsPointer(t)
+}
+
+// verifyExceptionScope reports an error if the given exception scope is neither
+// the none token nor a funclet pad (catchpad or cleanuppad).
+func verifyExceptionScope(scope ExceptionScope) error {
+	switch scope := scope.(type) {
+	case *constant.NoneToken, *InstCatchPad, *InstCleanupPad:
+		return nil
+	case nil:
+		return errors.New("missing exception scope")
+	default:
+		return errors.Errorf("invalid exception scope %s; expected none, catchpad or cleanuppad, got %T", scope.Ident(), scope)
+	}
+}
+
+// verifyOperandBundles reports an error if a funclet operand bundle of the
+// given operand bundles does not reference exactly one funclet pad.
+func verifyOperandBundles(bundles []*OperandBundle) error {
+	for _, bundle := range bundles {
+		if bundle.Tag != "funclet" {
+			continue
+		}
+		if len(bundle.Inputs) != 1 {
+			return errors.Errorf("invalid number of inputs in funclet operand bundle; expected 1, got %d", len(bundle.Inputs))
+		}
+		switch input := bundle.Inputs[0].(type) {
+		case *InstCatchPad, *InstCleanupPad:
+			// valid funclet pad.
+		case nil:
+			return errors.New("missing input of funclet operand bundle")
+		default:
+			return errors.Errorf("invalid funclet operand bundle input %s; expected catchpad or cleanuppad, got %T", input.Ident(), input)
+		}
+	}
+	return nil
+}
+
+// verifyCallArgs reports an error if the return type or function arguments of
+// the given call instruction do not match its function signature (i.e. the
+// explicit function type if present, and the callee type otherwise).
+func verifyCallArgs(inst *InstCall) error {
+	sig := inst.Sig()
+	if !inst.Type().Equal(sig.RetType) {
+		return errors.Errorf("return type mismatch of call to %s; expected %s, got %s", inst.Callee.Ident(), sig.RetType, inst.Type())
+	}
+	return verifyArgs("call", inst.Callee, sig, inst.Args)
+}
+
+// verifyInvokeArgs reports an error if the function arguments of the given
+// invoke terminator do not match the function signature of its invokee.
+func verifyInvokeArgs(term *TermInvoke) error {
+	sig, ok := term.Typ.(*types.FuncType)
+	if !ok {
+		t, ok := term.Invokee.Type().(*types.PointerType)
+		if !ok {
+			return errors.Errorf("invalid invokee type of invoke; expected *types.PointerType, got %T", term.Invokee.Type())
+		}
+		if sig, ok = t.ElemType.(*types.FuncType); !ok {
+			return errors.Errorf("invalid invokee type of invoke; expected *types.FuncType, got %T", t.ElemType)
+		}
+	}
+	return verifyArgs("invoke", term.Invokee, sig, term.Args)
+}
+
+// verifyArgs reports an error if the number or types of the given function
+// arguments of a call-like instruction (e.g. call, invoke) to callee do not
+// match the function signature sig. Extra arguments are only allowed for
+// variadic functions.
+func verifyArgs(kind string, callee value.Value, sig *types.FuncType, args []value.Value) error {
+	if len(args) < len(sig.Params) || (!sig.Variadic && len(args) > len(sig.Params)) {
+		return errors.Errorf("argument count mismatch of %s to %s; expected %d, got %d", kind, callee.Ident(), len(sig.Params), len(args))
+	}
+	for i, param := range sig.Params {
+		if argType := args[i].Type(); !argType.Equal(param) {
+			return errors.Errorf("argument %d type mismatch of %s to %s; expected %s, got %s", i, kind, callee.Ident(), param, argType)
+		}
+	}
+	return nil
+}
+
+// verifyMustTail reports an error if the function signature of the callee of
+// the given musttail call instruction does not match the function signature of
+// the caller function f.
+func verifyMustTail(f *Func, inst *InstCall) error {
+	calleeType, ok := inst.Callee.Type().(*types.PointerType)
+	if !ok {
+		return errors.Errorf("invalid callee type of musttail call; expected *types.PointerType, got %T", inst.Callee.Type())
+	}
+	sig, ok := calleeType.ElemType.(*types.FuncType)
+	if !ok {
+		return errors.Errorf("invalid callee type of musttail call; expected *types.FuncType, got %T", calleeType.ElemType)
+	}
+	if !sig.RetType.Equal(f.Sig.RetType) {
+		return errors.Errorf("return type mismatch between callee %s and caller %s of musttail call; expected %s, got %s", inst.Callee.Ident(), f.Ident(), f.Sig.RetType, sig.RetType)
+	}
+	if sig.Variadic != f.Sig.Variadic {
+		return errors.Errorf("variadic mismatch between callee %s and caller %s of musttail call; expected %t, got %t", inst.Callee.Ident(), f.Ident(), f.Sig.Variadic, sig.Variadic)
+	}
+	if len(sig.Params) != len(f.Sig.Params) {
+		return errors.Errorf("parameter count mismatch between callee %s and caller %s of musttail call; expected %d, got %d", inst.Callee.Ident(), f.Ident(), len(f.Sig.Params), len(sig.Params))
+	}
+	for i := range sig.Params {
+		if !sig.Params[i].Equal(f.Sig.Params[i]) {
+			return errors.Errorf("parameter %d type mismatch between callee %s and caller %s of musttail call; expected %s, got %s", i, inst.Callee.Ident(), f.Ident(), f.Sig.Params[i], sig.Params[i])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,664 @@
+package ir
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+func TestVerifyExceptionPads(t *testing.T) {
+	golden := []struct {
+		// Exception scope of cleanuppad; a nil scope denotes the catchswitch.
+		scope ExceptionScope
+		want  bool
+	}{
+		{scope: constant.None, want: true},
+		{scope: nil, want: false},
+	}
+	for _, g := range golden {
+		m := NewModule()
+		f := m.NewFunc("f", types.Void)
+		entry := f.NewBlock("entry")
+		dispatch := f.NewBlock("dispatch")
+		handler := f.NewBlock("handler")
+		cleanup := f.NewBlock("cleanup")
+		exit := f.NewBlock("exit")
+		entry.NewBr(dispatch)
+		cs := dispatch.NewCatchSwitch(constant.None, []*Block{handler}, UnwindToCaller{})
+		cs.SetName("cs")
+		cp := handler.NewCatchPad(cs)
+		cp.SetName("cp")
+		handler.NewCatchRet(cp, exit)
+		scope := g.scope
+		if scope == nil {
+			scope = cs
+		}
+		cl := cleanup.NewCleanupPad(scope)
+		cl.SetName("cl")
+		cleanup.NewCleanupRet(cl, UnwindToCaller{})
+		exit.NewRet(nil)
+		err := m.Verify()
+		if got := err == nil; got != g.want {
+			t.Errorf("verification mismatch for cleanuppad scope %v; expected valid %v, got error %v", scope.Ident(), g.want, err)
+		}
+	}
+}
+
+func TestVerifyEntryBlock(t *testing.T) {
+	m := NewModule()
+	f := m.NewFunc("f", types.Void)
+	entry := f.NewBlock("entry")
+	loop := f.NewBlock("loop")
+	entry.NewBr(loop)
+	loop.NewBr(entry)
+	if got := f.Entry(); got != entry {
+		t.Errorf("entry block mismatch; expected %v, got %v", entry.Ident(), got.Ident())
+	}
+	if err := m.Verify(); err == nil {
+		t.Errorf("expected error for branch to entry basic block, got nil")
+	}
+	loop.Term = NewRet(nil)
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+}
+
+func TestVerifyMustTail(t *testing.T) {
+	golden := []struct {
+		// Function signature of callee.
+		sig  *types.FuncType
+		want bool
+	}{
+		{sig: types.NewFunc(types.I32, types.I8Ptr), want: true},
+		{sig: types.NewFunc(types.I64, types.I8Ptr), want: false},
+		{sig: types.NewFunc(types.I32, types.I8Ptr, types.I32), want: false},
+		{sig: types.NewFunc(types.I32, types.I32), want: false},
+		{sig: &types.FuncType{RetType: types.I32, Params: []types.Type{types.I8Ptr}, Variadic: true}, want: false},
+	}
+	for _, g := range golden {
+		m := NewModule()
+		var params []*Param
+		for _, param := range g.sig.Params {
+			params = append(params, NewParam("", param))
+		}
+		callee := m.NewFunc("callee", g.sig.RetType, params...)
+		callee.Sig.Variadic = g.sig.Variadic
+		f := m.NewFunc("f", types.I32, NewParam("x", types.I8Ptr))
+		entry := f.NewBlock("entry")
+		var args []value.Value
+		for _, param := range callee.Params {
+			args = append(args, constant.NewZeroInitializer(param.Type()))
+		}
+		call := entry.NewCall(callee, args...)
+		call.Tail = enum.TailMustTail
+		entry.NewRet(constant.NewInt(types.I32, 0))
+		err := m.Verify()
+		if got := err == nil; got != g.want {
+			t.Errorf("verification mismatch for musttail callee %v; expected valid %v, got error %v", g.sig, g.want, err)
+		}
+	}
+}
+
+func TestVerifyCallFuncType(t *testing.T) {
+	golden := []struct {
+		// Explicit function type of call.
+		sig *types.FuncType
+		// Function arguments.
+		args []value.Value
+		want bool
+	}{
+		{sig: types.NewFunc(types.I32, types.I32), args: []value.Value{constant.NewInt(types.I32, 1)}, want: true},
+		{sig: types.NewFunc(types.I32, types.I32), args: nil, want: false},
+		{sig: types.NewFunc(types.I32, types.I32), args: []value.Value{constant.NewInt(types.I64, 1)}, want: false},
+		{sig: &types.FuncType{RetType: types.I32, Variadic: true}, args: []value.Value{constant.NewInt(types.I64, 1)}, want: true},
+	}
+	for _, g := range golden {
+		m := NewModule()
+		fp := NewParam("fp", types.NewPointer(g.sig))
+		f := m.NewFunc("f", types.I32, fp)
+		entry := f.NewBlock("entry")
+		call := entry.NewCall(fp, g.args...)
+		call.FuncType = g.sig
+		entry.NewRet(call)
+		err := m.Verify()
+		if got := err == nil; got != g.want {
+			t.Errorf("verification mismatch for call %v; expected valid %v, got error %v", call.LLString(), g.want, err)
+		}
+		if want := fmt.Sprintf("call %s %%fp(", g.sig); !strings.Contains(call.LLString(), want) {
+			t.Errorf("explicit function type mismatch of call; expected %q in %q", want, call.LLString())
+		}
+	}
+}
+
+func TestVerifyCallArgs(t *testing.T) {
+	golden := []struct {
+		// Function signature of callee.
+		sig *types.FuncType
+		// Function arguments.
+		args []value.Value
+		// Expected error message suffix; or empty if valid.
+		want string
+	}{
+		{sig: types.NewFunc(types.Void, types.I32, types.I64), args: []value.Value{constant.NewInt(types.I32, 1), constant.NewInt(types.I64, 2)}},
+		{sig: types.NewFunc(types.Void, types.I32, types.I64), args: []value.Value{constant.NewInt(types.I32, 1)}, want: "argument count mismatch of %s to @g; expected 2, got 1"},
+		{sig: types.NewFunc(types.Void, types.I32), args: []value.Value{constant.NewInt(types.I32, 1), constant.NewInt(types.I32, 2)}, want: "argument count mismatch of %s to @g; expected 1, got 2"},
+		{sig: types.NewFunc(types.Void, types.I32, types.I64), args: []value.Value{constant.NewInt(types.I32, 1), constant.NewInt(types.I32, 2)}, want: "argument 1 type mismatch of %s to @g; expected i64, got i32"},
+		{sig: &types.FuncType{RetType: types.Void, Params: []types.Type{types.I32}, Variadic: true}, args: []value.Value{constant.NewInt(types.I32, 1), constant.NewInt(types.I8, 2)}},
+		{sig: &types.FuncType{RetType: types.Void, Params: []types.Type{types.I32}, Variadic: true}, args: nil, want: "argument count mismatch of %s to @g; expected 1, got 0"},
+	}
+	for _, g := range golden {
+		for _, kind := range []string{"call", "invoke"} {
+			m := NewModule()
+			callee := m.NewFunc("g", g.sig.RetType)
+			callee.Sig = g.sig
+			callee.Typ = types.NewPointer(g.sig)
+			for _, paramType := range g.sig.Params {
+				callee.Params = append(callee.Params, NewParam("", paramType))
+			}
+			f := m.NewFunc("f", types.Void)
+			entry := f.NewBlock("entry")
+			exit := f.NewBlock("exit")
+			exit.NewRet(nil)
+			switch kind {
+			case "call":
+				entry.NewCall(callee, g.args...)
+				entry.NewBr(exit)
+			case "invoke":
+				entry.NewInvoke(callee, g.args, exit, exit)
+			}
+			err := m.Verify()
+			if len(g.want) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error for %s with signature %v; %v", kind, g.sig, err)
+				}
+				continue
+			}
+			if want := fmt.Sprintf(g.want, kind); err == nil || !strings.HasSuffix(err.Error(), want) {
+				t.Errorf("error mismatch for %s with signature %v; expected %q, got %v", kind, g.sig, want, err)
+			}
+		}
+	}
+}
+
+func TestVerifyFuncParams(t *testing.T) {
+	m := NewModule()
+	f := m.NewFunc("f", types.Void, NewParam("x", types.I32))
+	f.NewBlock("").NewRet(nil)
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	// Updating the function signature does not update the function parameters.
+	f.Sig.AppendParam(types.I64)
+	want := "parameter count mismatch between function @f and its signature; expected 2, got 1"
+	if err := m.Verify(); err == nil || !strings.HasSuffix(err.Error(), want) {
+		t.Errorf("error mismatch; expected %q, got %v", want, err)
+	}
+	f.Params = append(f.Params, NewParam("y", types.I32))
+	want = "parameter 1 type mismatch between function @f and its signature; expected i64, got i32"
+	if err := m.Verify(); err == nil || !strings.HasSuffix(err.Error(), want) {
+		t.Errorf("error mismatch; expected %q, got %v", want, err)
+	}
+	f.Params[1].Typ = types.I64
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+}
+
+func TestVerifyAlign(t *testing.T) {
+	golden := []struct {
+		align Align
+		want  bool
+	}{
+		{align: 0, want: true},
+		{align: 1, want: true},
+		{align: 16, want: true},
+		{align: 1 << 29, want: true},
+		{align: 3, want: false},
+		{align: 12, want: false},
+		{align: 1 << 30, want: false},
+	}
+	for _, g := range golden {
+		m := NewModule()
+		f := m.NewFunc("f", types.Void)
+		entry := f.NewBlock("entry")
+		p := entry.NewAlloca(types.I32)
+		p.SetName("p")
+		store := entry.NewStore(constant.NewInt(types.I32, 1), p)
+		store.Align = g.align
+		load := entry.NewLoad(p)
+		load.SetName("v")
+		entry.NewRet(nil)
+		err := m.Verify()
+		if got := err == nil; got != g.want {
+			t.Errorf("verification mismatch for store alignment %d; expected valid %v, got error %v", g.align, g.want, err)
+		}
+		// Check alloca and load alignments.
+		store.Align = 0
+		p.Align, load.Align = g.align, 0
+		if got := m.Verify() == nil; got != g.want {
+			t.Errorf("verification mismatch for alloca alignment %d; expected valid %v", g.align, g.want)
+		}
+		p.Align, load.Align = 0, g.align
+		if got := m.Verify() == nil; got != g.want {
+			t.Errorf("verification mismatch for load alignment %d; expected valid %v", g.align, g.want)
+		}
+	}
+}
+
+func TestVerifyAddrSpace(t *testing.T) {
+	m := NewModule()
+	arrayType := types.NewArray(4, types.I32)
+	g := m.NewGlobalDef("g", constant.NewZeroInitializer(arrayType))
+	g.Typ = types.NewPointer(arrayType)
+	g.Typ.AddrSpace = 1
+	f := m.NewFunc("f", types.Void)
+	entry := f.NewBlock("entry")
+	gep := entry.NewGetElementPtr(g, constant.NewInt(types.I64, 0), constant.NewInt(types.I64, 1))
+	gep.SetName("elem")
+	load := entry.NewLoad(gep)
+	load.SetName("v")
+	x := entry.NewAlloca(types.I32)
+	x.SetName("x")
+	x.Typ = types.NewPointer(types.I32)
+	x.Typ.AddrSpace = 5
+	entry.NewStore(load, x)
+	entry.NewRet(nil)
+	if got, want := gep.Type().String(), "i32 addrspace(1)*"; got != want {
+		t.Errorf("getelementptr type mismatch; expected %v, got %v", want, got)
+	}
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	// Result type of getelementptr in default address space.
+	gep.Typ = types.NewPointer(types.I32)
+	if err := m.Verify(); err == nil {
+		t.Errorf("expected error for getelementptr address space mismatch, got nil")
+	}
+}
+
+func TestVerifyRange(t *testing.T) {
+	i8 := func(x int64) *constant.Int { return constant.NewInt(types.I8, x) }
+	golden := []struct {
+		pairs [][2]*constant.Int
+		want  bool
+	}{
+		{pairs: [][2]*constant.Int{{i8(0), i8(2)}}, want: true},
+		{pairs: [][2]*constant.Int{{i8(0), i8(2)}, {i8(4), i8(8)}}, want: true},
+		// Wrapped last range.
+		{pairs: [][2]*constant.Int{{i8(0), i8(2)}, {i8(100), i8(-10)}}, want: true},
+		{pairs: [][2]*constant.Int{{i8(-10), i8(2)}, {i8(100), i8(-10)}}, want: false},
+		// Empty range.
+		{pairs: nil, want: false},
+		{pairs: [][2]*constant.Int{{i8(1), i8(1)}}, want: false},
+		// Overlapping, contiguous and unsorted ranges.
+		{pairs: [][2]*constant.Int{{i8(0), i8(4)}, {i8(2), i8(8)}}, want: false},
+		{pairs: [][2]*constant.Int{{i8(0), i8(4)}, {i8(4), i8(8)}}, want: false},
+		{pairs: [][2]*constant.Int{{i8(4), i8(8)}, {i8(0), i8(2)}}, want: false},
+		// Wrapped range not last.
+		{pairs: [][2]*constant.Int{{i8(100), i8(-100)}, {i8(110), i8(120)}}, want: false},
+		// Type mismatch.
+		{pairs: [][2]*constant.Int{{constant.NewInt(types.I32, 0), constant.NewInt(types.I32, 2)}}, want: false},
+	}
+	for _, g := range golden {
+		m := NewModule()
+		p := NewParam("p", types.I8Ptr)
+		f := m.NewFunc("f", types.I8, p)
+		entry := f.NewBlock("entry")
+		load := entry.NewLoad(p)
+		load.SetName("v")
+		entry.NewRet(load)
+		err := load.SetRange(g.pairs...)
+		if got := err == nil; got != g.want {
+			t.Errorf("range validation mismatch for %v; expected valid %v, got error %v", g.pairs, g.want, err)
+			continue
+		}
+		if !g.want {
+			continue
+		}
+		pairs, ok := GetRange(load)
+		if !ok || len(pairs) != len(g.pairs) {
+			t.Errorf("range mismatch; expected %v, got %v", g.pairs, pairs)
+		}
+		if err := m.Verify(); err != nil {
+			t.Errorf("unexpected error; %v", err)
+		}
+	}
+}
+
+func TestVerifyLoadMetadata(t *testing.T) {
+	m := NewModule()
+	p := NewParam("p", types.NewPointer(types.I8Ptr))
+	f := m.NewFunc("f", types.I8Ptr, p)
+	entry := f.NewBlock("entry")
+	load := entry.NewLoad(p)
+	load.SetName("v")
+	entry.NewRet(load)
+	if err := load.SetNonNull(); err != nil {
+		t.Fatalf("unexpected error; %v", err)
+	}
+	if err := load.SetDereferenceable(16); err != nil {
+		t.Fatalf("unexpected error; %v", err)
+	}
+	if !load.IsNonNull() {
+		t.Errorf("expected !nonnull metadata of load")
+	}
+	if n, ok := load.Dereferenceable(); !ok || n != 16 {
+		t.Errorf("dereferenceable bytes mismatch; expected 16, got %d", n)
+	}
+	if want, got := "%v = load i8*, i8** %p, !nonnull !{}, !dereferenceable !{i64 16}", load.LLString(); want != got {
+		t.Errorf("load mismatch; expected %q, got %q", want, got)
+	}
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	// !nonnull and !dereferenceable only apply to loads of pointer type.
+	q := NewParam("q", types.I8Ptr)
+	g := m.NewFunc("g", types.I8, q)
+	entry = g.NewBlock("entry")
+	load = entry.NewLoad(q)
+	entry.NewRet(load)
+	if err := load.SetNonNull(); err == nil {
+		t.Errorf("expected error for !nonnull metadata of non-pointer load, got nil")
+	}
+	if err := load.SetDereferenceable(1); err == nil {
+		t.Errorf("expected error for !dereferenceable metadata of non-pointer load, got nil")
+	}
+	load.Metadata = append(load.Metadata, &metadata.Attachment{Name: "nonnull", Node: &metadata.Tuple{MetadataID: -1}})
+	if err := m.Verify(); err == nil {
+		t.Errorf("expected error for !nonnull metadata of non-pointer load, got nil")
+	}
+}
+
+func TestVerifyLocalNames(t *testing.T) {
+	golden := []struct {
+		// Names of the basic blocks and of the add instruction.
+		blocks []string
+		add    string
+		want   string
+	}{
+		{blocks: []string{"entry", "exit"}, add: "x", want: ""},
+		{blocks: []string{"entry", "entry"}, add: "x", want: "duplicate basic block label %entry in function @f"},
+		{blocks: []string{"entry", "exit"}, add: "p", want: "duplicate local variable name %p in function @f"},
+		{blocks: []string{"entry", "p"}, add: "x", want: "duplicate basic block label %p in function @f"},
+		// Unnamed locals are not subject to the check.
+		{blocks: []string{"", ""}, add: "", want: ""},
+	}
+	for _, g := range golden {
+		m := NewModule()
+		p := NewParam("p", types.I32)
+		f := m.NewFunc("f", types.I32, p)
+		entry := f.NewBlock(g.blocks[0])
+		exit := f.NewBlock(g.blocks[1])
+		add := entry.NewAdd(p, p)
+		add.SetName(g.add)
+		entry.NewBr(exit)
+		exit.NewRet(add)
+		for _, err := range []error{f.AssignIDs(), m.Verify()} {
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if g.want != got {
+				t.Errorf("local name verification mismatch for blocks %q and instruction %q; expected %q, got %q", g.blocks, g.add, g.want, got)
+			}
+		}
+	}
+}
+
+func TestVerifyIndirectBr(t *testing.T) {
+	m := NewModule()
+	f := m.NewFunc("f", types.Void, NewParam("addr", types.I8Ptr))
+	entry := f.NewBlock("entry")
+	a := f.NewBlock("a")
+	b := f.NewBlock("b")
+	a.NewRet(nil)
+	b.NewRet(nil)
+	ibr := entry.NewIndirectBr(nil)
+	ibr.Addr = f.Params[0]
+	ibr.AddDest(a)
+	ibr.AddDest(b)
+	ibr.AddDest(a)
+	if got, want := ibr.LLString(), "indirectbr i8* %addr, [label %a, label %b]"; got != want {
+		t.Errorf("indirectbr mismatch; expected %q, got %q", want, got)
+	}
+	// Targets without blockaddress.
+	want := "invalid target basic block %a of indirectbr in basic block %entry of function @f; expected blockaddress(@f, %a) in module"
+	if err := m.Verify(); err == nil || err.Error() != want {
+		t.Errorf("error mismatch for target basic block without blockaddress; expected %q, got %v", want, err)
+	}
+	// Block addresses in global initializer and in operand of other function.
+	m.NewGlobalDef("targets", constant.NewArray(types.NewArray(1, types.I8Ptr), constant.NewBlockAddress(f, a)))
+	g := m.NewFunc("g", types.I8Ptr)
+	g.NewBlock("").NewRet(constant.NewBitCast(constant.NewBlockAddress(f, b), types.I8Ptr))
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	// Removal of target.
+	ibr.RemoveDest(a)
+	if got, want := len(entry.Term.Succs()), 1; got != want {
+		t.Errorf("number of successors mismatch; expected %d, got %d", want, got)
+	}
+	// Target address of non-pointer type.
+	ibr.Addr = constant.NewInt(types.I64, 0)
+	if err := m.Verify(); err == nil {
+		t.Errorf("expected error for indirectbr target address of non-pointer type, got nil")
+	}
+}
+
+func TestParamAttrs(t *testing.T) {
+	ptr := types.NewPointer(types.I8)
+	golden := []struct {
+		typ  types.Type
+		attr ParamAttribute
+		want bool
+	}{
+		{typ: ptr, attr: enum.ParamAttrNoAlias, want: true},
+		{typ: ptr, attr: enum.ParamAttrByval, want: true},
+		{typ: types.NewVector(2, ptr), attr: enum.ParamAttrNoCapture, want: true},
+		{typ: ptr, attr: Dereferenceable{N: 8}, want: true},
+		{typ: ptr, attr: Align(16), want: true},
+		{typ: types.I8, attr: enum.ParamAttrSignExt, want: true},
+		{typ: types.I1, attr: enum.ParamAttrZeroExt, want: true},
+		{typ: types.I32, attr: enum.ParamAttrInReg, want: true},
+		{typ: types.I32, attr: AttrString("foo"), want: true},
+		{typ: types.I32, attr: enum.ParamAttrByval, want: false},
+		{typ: types.I32, attr: enum.ParamAttrNoAlias, want: false},
+		{typ: types.Double, attr: Dereferenceable{N: 8}, want: false},
+		{typ: ptr, attr: Align(3), want: false},
+		{typ: ptr, attr: enum.ParamAttrSignExt, want: false},
+		{typ: types.NewVector(2, types.I32), attr: enum.ParamAttrZeroExt, want: false},
+	}
+	for _, g := range golden {
+		p := NewParam("p", g.typ)
+		err := p.AddAttr(enum.ParamAttrInReg, g.attr)
+		if got := err == nil; got != g.want {
+			t.Errorf("validity mismatch of parameter attribute %s on %s; expected valid %v, got error %v", g.attr, g.typ, g.want, err)
+			continue
+		}
+		if !g.want {
+			if len(p.Attrs) != 0 {
+				t.Errorf("unexpected parameter attributes of %s after error; got %v", p.Ident(), p.Attrs)
+			}
+			// Invalid parameter attributes set directly are reported by Verify.
+			p.Attrs = []ParamAttribute{g.attr}
+		}
+		f := NewFunc("f", types.Void, p)
+		f.NewBlock("entry").NewRet(nil)
+		if got := f.Verify() == nil; got != g.want {
+			t.Errorf("verification mismatch of parameter attribute %s on %s; expected valid %v", g.attr, g.typ, g.want)
+		}
+	}
+	want := "i8* noalias nonnull %p"
+	if got := NewParam("p", ptr, enum.ParamAttrNoAlias, enum.ParamAttrNonNull).LLString(); got != want {
+		t.Errorf("parameter mismatch; expected %q, got %q", want, got)
+	}
+	if p, err := NewParamErr("p", ptr, enum.ParamAttrZeroExt); err == nil {
+		t.Errorf("expected error for zeroext pointer parameter, got %v", p)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for zeroext pointer parameter")
+		}
+	}()
+	NewParam("p", ptr, enum.ParamAttrZeroExt)
+}
+
+func TestVerifyBitCastAddrSpace(t *testing.T) {
+	m := NewModule()
+	g := m.NewGlobalDef("g", constant.NewInt(types.I32, 0))
+	g.Typ.AddrSpace = 1
+	ptr1 := types.NewPointer(types.I8)
+	ptr1.AddrSpace = 1
+	f := m.NewFunc("f", types.Void)
+	entry := f.NewBlock("entry")
+	cast := entry.NewBitCast(g, ptr1)
+	cast.SetName("p")
+	entry.NewRet(nil)
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	// Cast to default address space.
+	cast.To = types.NewPointer(types.I8)
+	want := "invalid bitcast %p: address space mismatch between i32 addrspace(1)* and i8*"
+	if err := m.Verify(); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("error mismatch; expected %q, got %v", want, err)
+	}
+	// Cast between address spaces using addrspacecast.
+	entry.Insts[0] = NewAddrSpaceCast(g, types.NewPointer(types.I8))
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	// Bitcast expressions of global initializers.
+	h := m.NewGlobalDef("h", constant.NewBitCast(g, ptr1))
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	h.Init = constant.NewBitCast(g, types.NewPointer(types.I8))
+	want = "invalid bitcast expression bitcast (i32 addrspace(1)* @g to i8*)"
+	if err := m.Verify(); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("error mismatch; expected %q, got %v", want, err)
+	}
+}
+
+func TestVerifyIndirectSymbols(t *testing.T) {
+	m := NewModule()
+	g := m.NewGlobalDef("g", constant.NewInt(types.I32, 0))
+	ext := m.NewGlobal("ext", types.I32)
+	resolver := m.NewFunc("resolver", types.I8Ptr)
+	resolverType := resolver.Type()
+	resolver.NewBlock("entry").NewRet(constant.NewNull(types.I8Ptr))
+	a := m.NewAlias("a", g)
+	b := m.NewAlias("b", constant.NewBitCast(a, types.I8Ptr))
+	ifunc := m.NewIFunc("ifunc", resolver)
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	// Aliasee of declaration.
+	a.Aliasee = ext
+	want := "invalid aliasee @ext of alias @a; expected definition, got declaration"
+	if err := m.Verify(); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("error mismatch; expected %q, got %v", want, err)
+	}
+	// Cycle of aliases.
+	a.Aliasee = constant.NewBitCast(b, types.NewPointer(types.I32))
+	want = "cycle in aliases and IFuncs: @a -> @b -> @a"
+	if err := m.Verify(); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("error mismatch; expected %q, got %v", want, err)
+	}
+	a.Aliasee = g
+	// Resolver of IFunc through alias.
+	r := m.NewAlias("r", resolver)
+	ifunc.Resolver = r
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	// Resolver of IFunc not a function definition.
+	ifunc.Resolver = constant.NewBitCast(g, resolverType)
+	want = "invalid resolver bitcast (i32* @g to i8* ()*) of IFunc @ifunc; expected function definition"
+	if err := m.Verify(); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("error mismatch; expected %q, got %v", want, err)
+	}
+	// Cycle through IFunc.
+	r.Aliasee = constant.NewBitCast(ifunc, resolverType)
+	ifunc.Resolver = r
+	want = "cycle in aliases and IFuncs: @r -> @ifunc -> @r"
+	if err := m.Verify(); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("error mismatch; expected %q, got %v", want, err)
+	}
+}
+
+func TestConstDepth(t *testing.T) {
+	defer func(maxDepth int) { constant.MaxDepth = maxDepth }(constant.MaxDepth)
+	constant.MaxDepth = 100
+	// Nested add expressions of nesting depth 200.
+	one := constant.NewInt(types.I32, 1)
+	var c constant.Constant = one
+	for i := 1; i < 200; i++ {
+		c = constant.NewAdd(c, one)
+	}
+	m := NewModule()
+	f := m.NewFunc("f", types.I32)
+	f.NewBlock("entry").NewRet(c)
+	const want = "nesting depth of constant exceeds limit 100"
+	if err := m.Verify(); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("error mismatch; expected %q, got %v", want, err)
+	}
+	if _, err := m.WriteTo(&strings.Builder{}); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("error mismatch; expected %q, got %v", want, err)
+	}
+	// Nested constants within the limit.
+	f.Blocks[0].Term = NewRet(constant.NewAdd(one, one))
+	m.NewGlobalDef("g", constant.NewArray(types.NewArray(1, types.I32), one))
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	if _, err := m.WriteTo(&strings.Builder{}); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+}
+
+func TestVerifyOperandBundles(t *testing.T) {
+	m := NewModule()
+	g := m.NewFunc("g", types.Void)
+	f := m.NewFunc("f", types.Void)
+	entry := f.NewBlock("entry")
+	call := entry.NewCall(g)
+	entry.NewRet(nil)
+	golden := []struct {
+		input value.Value
+		want  string
+	}{
+		{input: nil, want: "missing input of funclet operand bundle"},
+		{input: constant.NewInt(types.I32, 0), want: "invalid funclet operand bundle input 0; expected catchpad or cleanuppad, got *constant.Int"},
+	}
+	for _, g := range golden {
+		call.OperandBundles = []*OperandBundle{NewOperandBundle("funclet", g.input)}
+		if err := f.Verify(); err == nil || !strings.Contains(err.Error(), g.want) {
+			t.Errorf("error mismatch of funclet operand bundle input %v; expected %q, got %v", g.input, g.want, err)
+		}
+	}
+}
+
+func TestVerifyPhi(t *testing.T) {
+	f := NewFunc("f", types.I32)
+	entry := f.NewBlock("entry")
+	exit := f.NewBlock("exit")
+	entry.NewBr(exit)
+	phi := exit.NewPhi(NewIncoming(constant.NewInt(types.I32, 0), entry))
+	phi.SetName("p")
+	exit.NewRet(phi)
+	if err := f.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	phi.Incs = nil
+	want := "missing incoming values of phi %p"
+	if err := f.Verify(); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("error mismatch of phi without incoming values; expected %q, got %v", want, err)
+	}
+}
@@ -0,0 +1,132 @@
+package irutil
+
+import (
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/enum"
+)
+
+// === [ Instruction classification ] ==========================================
+
+// IsTerminator reports whether the given instruction is a terminator (e.g. ret,
+// br and invoke), as yielded by ir.Block.All.
+func IsTerminator(inst ir.Instruction) bool {
+	_, ok := inst.(ir.Terminator)
+	return ok
+}
+
+// IsBinaryOp reports whether the given instruction is a binary operation; i.e.
+// a binary instruction (e.g. add, fmul and srem) or a bitwise binary
+// instruction (e.g. shl, and and xor).
+//
+// ref: https://llvm.org/docs/LangRef.html#binary-operations
+//
+// ref: https://llvm.org/docs/LangRef.html#bitwise-binary-operations
+func IsBinaryOp(inst ir.Instruction) bool {
+	switch inst.(type) {
+	// Binary instructions.
+	case *ir.InstAdd, *ir.InstFAdd, *ir.InstSub, *ir.InstFSub, *ir.InstMul, *ir.InstFMul, *ir.InstUDiv, *ir.InstSDiv, *ir.InstFDiv, *ir.InstURem, *ir.InstSRem, *ir.InstFRem:
+		return true
+	// Bitwise instructions.
+	case *ir.InstShl, *ir.InstLShr, *ir.InstAShr, *ir.InstAnd, *ir.InstOr, *ir.InstXor:
+		return true
+	}
+	return false
+}
+
+// IsCast reports whether the given instruction is a conversion instruction
+// (e.g. trunc, bitcast and addrspacecast).
+//
+// ref: https://llvm.org/docs/LangRef.html#conversion-operations
+func IsCast(inst ir.Instruction) bool {
+	switch inst.(type) {
+	case *ir.InstTrunc, *ir.InstZExt, *ir.InstSExt, *ir.InstFPTrunc, *ir.InstFPExt, *ir.InstFPToUI, *ir.InstFPToSI, *ir.InstUIToFP, *ir.InstSIToFP, *ir.InstPtrToInt, *ir.InstIntToPtr, *ir.InstBitCast, *ir.InstAddrSpaceCast:
+		return true
+	}
+	return false
+}
+
+// IsMemoryAccess reports whether the given instruction reads or writes memory
+// through a pointer operand; i.e. a load, store, cmpxchg or atomicrmw
+// instruction.
+//
+// Note, fence instructions order memory accesses but do not access memory, and
+// memory accessed by call instructions and invoke terminators is not taken into
+// account.
+func IsMemoryAccess(inst ir.Instruction) bool {
+	switch inst.(type) {
+	case *ir.InstLoad, *ir.InstStore, *ir.InstCmpXchg, *ir.InstAtomicRMW:
+		return true
+	}
+	return false
+}
+
+// MayHaveSideEffects reports whether the given instruction may have side
+// effects, and may thus not be removed even if its result is unused.
+//
+// The following instructions may have side effects:
+//
+//    * store, fence, cmpxchg and atomicrmw instructions;
+//    * volatile and atomic load instructions;
+//    * va_arg instructions, which modify the va_list;
+//    * exception pads (landingpad, catchpad and cleanuppad);
+//    * terminators, which transfer control flow; and
+//    * call instructions, unless the call is known to only read memory (e.g.
+//      readonly, readnone or memory(read)), not throw (nounwind) and return
+//      (willreturn). Function attributes of both the call instruction and the
+//      called function (if called directly) are taken into account, including
+//      attributes of referenced attribute groups.
+//
+// All other instructions (e.g. binary, conversion and getelementptr
+// instructions and non-volatile loads) have no side effects.
+func MayHaveSideEffects(inst ir.Instruction) bool {
+	switch inst := inst.(type) {
+	case *ir.InstStore, *ir.InstFence, *ir.InstCmpXchg, *ir.InstAtomicRMW:
+		return true
+	case *ir.InstLoad:
+		return inst.Volatile || inst.Atomic
+	case *ir.InstVAArg:
+		return true
+	case *ir.InstLandingPad, *ir.InstCatchPad, *ir.InstCleanupPad:
+		return true
+	case *ir.InstCall:
+		return !isPureCall(inst)
+	case ir.Terminator:
+		return true
+	}
+	return false
+}
+
+// isPureCall reports whether the given call instruction only reads memory, does
+// not throw and returns, based on the function attributes of the call
+// instruction and the called function.
+func isPureCall(call *ir.InstCall) bool {
+	attrs := [][]ir.FuncAttribute{call.FuncAttrs}
+	if f, ok := call.Callee.(*ir.Func); ok {
+		attrs = append(attrs, f.FuncAttrs)
+	}
+	readOnly, noUnwind, willReturn := false, false, false
+	for _, a := range attrs {
+		readOnly = readOnly || ir.MemoryEffects(a).OnlyReadsMemory()
+		noUnwind = noUnwind || hasFuncAttr(a, enum.FuncAttrNoUnwind)
+		willReturn = willReturn || hasFuncAttr(a, enum.FuncAttrWillReturn)
+	}
+	return readOnly && noUnwind && willReturn
+}
+
+// hasFuncAttr reports whether the given function attributes, including
+// attributes of referenced attribute groups, contain attr.
+func hasFuncAttr(attrs []ir.FuncAttribute, attr enum.FuncAttr) bool {
+	for _, a := range attrs {
+		switch a := a.(type) {
+		case enum.FuncAttr:
+			if a == attr {
+				return true
+			}
+		case *ir.AttrGroupDef:
+			if hasFuncAttr(a.FuncAttrs, attr) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package irutil
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestClassify(t *testing.T) {
+	m := ir.NewModule()
+	g := m.NewFunc("g", types.I32)
+	pure := m.NewFunc("pure", types.I32)
+	pure.FuncAttrs = append(pure.FuncAttrs, enum.FuncAttrReadNone, enum.FuncAttrNoUnwind, enum.FuncAttrWillReturn)
+	group := &ir.AttrGroupDef{ID: 0, FuncAttrs: []ir.FuncAttribute{enum.FuncAttrNoUnwind, enum.FuncAttrWillReturn}}
+	m.AttrGroupDefs = append(m.AttrGroupDefs, group)
+	grouped := m.NewFunc("grouped", types.I32)
+	grouped.FuncAttrs = append(grouped.FuncAttrs, enum.FuncAttrReadOnly, group)
+	f := m.NewFunc("f", types.I32, ir.NewParam("x", types.I32))
+	entry := f.NewBlock("")
+	exit := f.NewBlock("")
+	x := f.Params[0]
+	one := constant.NewInt(types.I32, 1)
+	p := entry.NewAlloca(types.I32)
+	volatileLoad := entry.NewLoad(p)
+	volatileLoad.Volatile = true
+	atomicLoad := entry.NewLoad(p)
+	atomicLoad.Atomic = true
+	atomicLoad.Ordering = enum.AtomicOrderingAcquire
+	// Call without willreturn at call site only.
+	readOnlyCall := entry.NewCall(g)
+	readOnlyCall.FuncAttrs = append(readOnlyCall.FuncAttrs, enum.FuncAttrReadOnly, enum.FuncAttrNoUnwind)
+	// Call with attributes split between call site and callee.
+	siteCall := entry.NewCall(g)
+	siteCall.FuncAttrs = append(siteCall.FuncAttrs, enum.FuncAttrReadNone, enum.FuncAttrNoUnwind, enum.FuncAttrWillReturn)
+	golden := []struct {
+		inst                                           ir.Instruction
+		term, binary, cast, memory, mayHaveSideEffects bool
+	}{
+		{inst: entry.NewAdd(x, one), binary: true},
+		{inst: entry.NewFRem(constant.NewFloat(types.Double, 1), constant.NewFloat(types.Double, 2)), binary: true},
+		{inst: entry.NewXor(x, one), binary: true},
+		{inst: entry.NewICmp(enum.IPredEQ, x, one)},
+		{inst: entry.NewTrunc(x, types.I8), cast: true},
+		{inst: entry.NewBitCast(p, types.I8Ptr), cast: true},
+		{inst: entry.NewGetElementPtr(p, one)},
+		{inst: p},
+		{inst: entry.NewLoad(p), memory: true},
+		{inst: volatileLoad, memory: true, mayHaveSideEffects: true},
+		{inst: atomicLoad, memory: true, mayHaveSideEffects: true},
+		{inst: entry.NewStore(x, p), memory: true, mayHaveSideEffects: true},
+		{inst: entry.NewCmpXchg(p, x, one, enum.AtomicOrderingSeqCst, enum.AtomicOrderingSeqCst), memory: true, mayHaveSideEffects: true},
+		{inst: entry.NewAtomicRMW(enum.AtomicOpAdd, p, x, enum.AtomicOrderingSeqCst), memory: true, mayHaveSideEffects: true},
+		{inst: entry.NewFence(enum.AtomicOrderingSeqCst), mayHaveSideEffects: true},
+		{inst: entry.NewCall(g), mayHaveSideEffects: true},
+		{inst: entry.NewCall(pure)},
+		{inst: entry.NewCall(grouped)},
+		{inst: readOnlyCall, mayHaveSideEffects: true},
+		{inst: siteCall},
+		{inst: entry.NewBr(exit), term: true, mayHaveSideEffects: true},
+		{inst: exit.NewRet(x), term: true, mayHaveSideEffects: true},
+	}
+	for _, g := range golden {
+		if got := IsTerminator(g.inst); got != g.term {
+			t.Errorf("IsTerminator mismatch of %q; expected %v, got %v", g.inst.LLString(), g.term, got)
+		}
+		if got := IsBinaryOp(g.inst); got != g.binary {
+			t.Errorf("IsBinaryOp mismatch of %q; expected %v, got %v", g.inst.LLString(), g.binary, got)
+		}
+		if got := IsCast(g.inst); got != g.cast {
+			t.Errorf("IsCast mismatch of %q; expected %v, got %v", g.inst.LLString(), g.cast, got)
+		}
+		if got := IsMemoryAccess(g.inst); got != g.memory {
+			t.Errorf("IsMemoryAccess mismatch of %q; expected %v, got %v", g.inst.LLString(), g.memory, got)
+		}
+		if got := MayHaveSideEffects(g.inst); got != g.mayHaveSideEffects {
+			t.Errorf("MayHaveSideEffects mismatch of %q; expected %v, got %v", g.inst.LLString(), g.mayHaveSideEffects, got)
+		}
+	}
+}
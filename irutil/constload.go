@@ -0,0 +1,105 @@
+package irutil
+
+import (
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/value"
+)
+
+// FoldConstantLoads replaces load instructions of function f from immutable
+// global variables (i.e. marked `constant`) by the loaded constant of the
+// global initializer, and returns the number of loads folded.
+//
+// The source address of a folded load is either the global variable itself, or
+// a constant getelementptr expression into the global variable (see
+// ResolveConstGEPLoad). Loads are only folded if the loaded type matches the
+// type of the addressed constant. Volatile and atomic loads are not folded, nor
+// are loads from global variables with an initializer which may be replaced at
+// link time (e.g. weak linkage) or which are externally initialized.
+func FoldConstantLoads(f *ir.Func) int {
+	folded := make(map[value.Value]constant.Constant)
+	for _, block := range f.Blocks {
+		insts := block.Insts[:0]
+		for _, inst := range block.Insts {
+			if load, ok := inst.(*ir.InstLoad); ok {
+				if c, ok := constantLoad(load); ok {
+					folded[load] = c
+					continue
+				}
+			}
+			insts = append(insts, inst)
+		}
+		block.Insts = insts
+	}
+	if len(folded) == 0 {
+		return 0
+	}
+	// Replace uses of folded loads. Loaded constants never refer to loads, so a
+	// single pass suffices.
+	for _, block := range f.Blocks {
+		for _, inst := range block.Insts {
+			replaceFolded(inst, folded)
+		}
+		if term, ok := block.Term.(ir.Instruction); ok {
+			replaceFolded(term, folded)
+		}
+	}
+	f.ResetIDs()
+	return len(folded)
+}
+
+// constantLoad returns the constant loaded by the given load instruction from
+// an immutable global variable. The boolean return value indicates success.
+func constantLoad(load *ir.InstLoad) (constant.Constant, bool) {
+	if load.Volatile || load.Atomic {
+		return nil, false
+	}
+	var c constant.Constant
+	switch src := load.Src.(type) {
+	case *ir.Global:
+		if !hasDefinitiveInit(src) {
+			return nil, false
+		}
+		c = src.Init
+	case *constant.ExprGetElementPtr:
+		g, ok := src.Src.(*ir.Global)
+		if !ok || !hasDefinitiveInit(g) {
+			return nil, false
+		}
+		if c, ok = ResolveConstGEPLoad(src, g.Init); !ok {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+	if !c.Type().Equal(load.Type()) {
+		return nil, false
+	}
+	return c, true
+}
+
+// hasDefinitiveInit reports whether the given global variable is immutable and
+// has an initializer which is known to be the value of the global variable at
+// run time.
+func hasDefinitiveInit(g *ir.Global) bool {
+	if !g.Immutable || g.Init == nil || g.ExternallyInitialized {
+		return false
+	}
+	switch g.Linkage {
+	case enum.LinkageCommon, enum.LinkageExternWeak, enum.LinkageLinkOnce, enum.LinkageWeak:
+		// Initializer may be replaced at link time.
+		return false
+	}
+	return true
+}
+
+// replaceFolded replaces operands of the given instruction referring to folded
+// loads by the loaded constant.
+func replaceFolded(inst ir.Instruction, folded map[value.Value]constant.Constant) {
+	for _, operand := range ir.Operands(inst) {
+		if c, ok := folded[*operand]; ok {
+			*operand = c
+		}
+	}
+}
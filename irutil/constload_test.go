@@ -0,0 +1,59 @@
+package irutil
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestFoldConstantLoads(t *testing.T) {
+	m := ir.NewModule()
+	arrType := types.NewArray(3, types.I32)
+	table := m.NewGlobalDef("table", constant.NewArray(arrType, constant.NewInt(types.I32, 10), constant.NewInt(types.I32, 20), constant.NewInt(types.I32, 30)))
+	table.Immutable = true
+	x := m.NewGlobalDef("x", constant.NewInt(types.I32, 42))
+	x.Immutable = true
+	mutable := m.NewGlobalDef("mutable", constant.NewInt(types.I32, 1))
+	weak := m.NewGlobalDef("weak", constant.NewInt(types.I32, 2))
+	weak.Immutable = true
+	weak.Linkage = enum.LinkageWeak
+	f := m.NewFunc("f", types.I32)
+	entry := f.NewBlock("")
+	zero := constant.NewInt(types.I64, 0)
+	a := entry.NewLoad(constant.NewGetElementPtr(table, zero, constant.NewInt(types.I64, 1)))
+	b := entry.NewLoad(x)
+	c := entry.NewLoad(mutable)
+	d := entry.NewLoad(weak)
+	e := entry.NewLoad(x)
+	e.Volatile = true
+	// Type mismatch between load and addressed constant.
+	entry.NewLoad(constant.NewBitCast(x, types.I8Ptr))
+	sum := entry.NewAdd(a, b)
+	sum = entry.NewAdd(sum, c)
+	sum = entry.NewAdd(sum, d)
+	sum = entry.NewAdd(sum, e)
+	entry.NewRet(sum)
+	// Assign IDs before removal of unnamed instructions.
+	_ = f.LLString()
+	if n := FoldConstantLoads(f); n != 2 {
+		t.Errorf("number of folded loads mismatch; expected 2, got %d", n)
+	}
+	want := `define i32 @f() {
+; <label>:0
+	%1 = load i32, i32* @mutable
+	%2 = load i32, i32* @weak
+	%3 = load volatile i32, i32* @x
+	%4 = load i8, i8* bitcast (i32* @x to i8*)
+	%5 = add i32 20, 42
+	%6 = add i32 %5, %1
+	%7 = add i32 %6, %2
+	%8 = add i32 %7, %3
+	ret i32 %8
+}`
+	if got := f.LLString(); got != want {
+		t.Errorf("function mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+}
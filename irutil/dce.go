@@ -0,0 +1,61 @@
+package irutil
+
+import (
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/value"
+)
+
+// EliminateDeadCode removes instructions of function f which have no uses and
+// no side effects, and returns the number of instructions removed. Removal is
+// repeated until no further instructions become dead.
+//
+// Instructions with side effects are kept, as classified by MayHaveSideEffects;
+// these include stores, fences, atomic instructions, calls (other than calls
+// known to only read memory, not throw and return), va_arg instructions,
+// exception pads and volatile loads.
+func EliminateDeadCode(f *ir.Func) int {
+	// Record number of uses of each instruction.
+	uses := make(map[value.Value]int)
+	for _, block := range f.Blocks {
+		for _, inst := range block.Insts {
+			countUses(uses, inst, 1)
+		}
+		if term, ok := block.Term.(ir.Instruction); ok {
+			countUses(uses, term, 1)
+		}
+	}
+	total := 0
+	for {
+		n := 0
+		for _, block := range f.Blocks {
+			insts := block.Insts[:0]
+			for _, inst := range block.Insts {
+				if v, ok := inst.(value.Value); !ok || uses[v] > 0 || MayHaveSideEffects(inst) {
+					insts = append(insts, inst)
+					continue
+				}
+				countUses(uses, inst, -1)
+				n++
+			}
+			block.Insts = insts
+		}
+		if n == 0 {
+			break
+		}
+		total += n
+	}
+	if total > 0 {
+		f.ResetIDs()
+	}
+	return total
+}
+
+// countUses adds delta to the number of uses of each instruction used as an
+// operand of the given instruction.
+func countUses(uses map[value.Value]int, inst ir.Instruction, delta int) {
+	for _, operand := range ir.Operands(inst) {
+		if _, ok := (*operand).(ir.Instruction); ok {
+			uses[*operand] += delta
+		}
+	}
+}
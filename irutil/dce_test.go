@@ -0,0 +1,44 @@
+package irutil
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestEliminateDeadCode(t *testing.T) {
+	m := ir.NewModule()
+	g := m.NewFunc("g", types.I32)
+	f := m.NewFunc("f", types.I32, ir.NewParam("x", types.I32))
+	entry := f.NewBlock("")
+	x := f.Params[0]
+	// Chain of dead instructions.
+	a := entry.NewAdd(x, constant.NewInt(types.I32, 1))
+	entry.NewMul(a, a)
+	// Unused call, volatile load and store have side effects.
+	entry.NewCall(g)
+	p := entry.NewAlloca(types.I32)
+	entry.NewStore(x, p)
+	load := entry.NewLoad(p)
+	load.Volatile = true
+	entry.NewLoad(p)
+	entry.NewRet(x)
+	// Assign IDs before removal of unnamed instructions.
+	_ = f.LLString()
+	if n := EliminateDeadCode(f); n != 3 {
+		t.Errorf("number of removed instructions mismatch; expected 3, got %d", n)
+	}
+	want := `define i32 @f(i32 %x) {
+; <label>:0
+	%1 = call i32 @g()
+	%2 = alloca i32
+	store i32 %x, i32* %2
+	%3 = load volatile i32, i32* %2
+	ret i32 %x
+}`
+	if got := f.LLString(); got != want {
+		t.Errorf("function mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+}
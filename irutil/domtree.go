@@ -0,0 +1,129 @@
+package irutil
+
+import (
+	"github.com/llir/llvm/ir"
+)
+
+// --- [ Dominator tree ] ------------------------------------------------------
+
+// DomTree is the dominator tree of the basic blocks of a function reachable
+// from its entry basic block.
+type DomTree struct {
+	// Immediate dominator of each reachable basic block; the entry basic block
+	// is its own immediate dominator.
+	idom map[*ir.Block]*ir.Block
+}
+
+// NewDomTree returns the dominator tree of the given function. The dominator
+// tree of a function declaration is empty.
+//
+// ref: Cooper, K. D., Harvey, T. J. and Kennedy, K. (2001). A Simple, Fast
+// Dominance Algorithm.
+func NewDomTree(f *ir.Func) *DomTree {
+	t := &DomTree{idom: make(map[*ir.Block]*ir.Block)}
+	if len(f.Blocks) == 0 {
+		return t
+	}
+	// Compute reverse postorder of reachable basic blocks.
+	var post []*ir.Block
+	visited := make(map[*ir.Block]bool)
+	var visit func(block *ir.Block)
+	visit = func(block *ir.Block) {
+		visited[block] = true
+		if block.Term != nil {
+			for _, succ := range block.Term.Succs() {
+				if !visited[succ] {
+					visit(succ)
+				}
+			}
+		}
+		post = append(post, block)
+	}
+	entry := f.Blocks[0]
+	visit(entry)
+	order := make(map[*ir.Block]int)
+	for i, block := range post {
+		order[block] = i
+	}
+	preds := make(map[*ir.Block][]*ir.Block)
+	for _, block := range post {
+		if block.Term == nil {
+			continue
+		}
+		for _, succ := range block.Term.Succs() {
+			preds[succ] = append(preds[succ], block)
+		}
+	}
+	idom := t.idom
+	idom[entry] = entry
+	intersect := func(a, b *ir.Block) *ir.Block {
+		for a != b {
+			for order[a] < order[b] {
+				a = idom[a]
+			}
+			for order[b] < order[a] {
+				b = idom[b]
+			}
+		}
+		return a
+	}
+	for changed := true; changed; {
+		changed = false
+		for i := len(post) - 2; i >= 0; i-- {
+			block := post[i]
+			var newIdom *ir.Block
+			for _, pred := range preds[block] {
+				if _, ok := idom[pred]; !ok {
+					continue
+				}
+				if newIdom == nil {
+					newIdom = pred
+				} else {
+					newIdom = intersect(pred, newIdom)
+				}
+			}
+			if idom[block] != newIdom {
+				idom[block] = newIdom
+				changed = true
+			}
+		}
+	}
+	return t
+}
+
+// IDom returns the immediate dominator of the given basic block; or nil if the
+// basic block is the entry basic block or unreachable.
+func (t *DomTree) IDom(block *ir.Block) *ir.Block {
+	idom := t.idom[block]
+	if idom == block {
+		return nil
+	}
+	return idom
+}
+
+// Reachable reports whether the given basic block is reachable from the entry
+// basic block.
+func (t *DomTree) Reachable(block *ir.Block) bool {
+	_, ok := t.idom[block]
+	return ok
+}
+
+// Dominates reports whether basic block a dominates basic block b; i.e. whether
+// every path from the entry basic block to b passes through a. A reachable
+// basic block dominates itself. Unreachable basic blocks neither dominate nor
+// are dominated by other basic blocks.
+func (t *DomTree) Dominates(a, b *ir.Block) bool {
+	if !t.Reachable(a) || !t.Reachable(b) {
+		return false
+	}
+	for {
+		if b == a {
+			return true
+		}
+		idom := t.idom[b]
+		if idom == b {
+			return false
+		}
+		b = idom
+	}
+}
@@ -0,0 +1,76 @@
+package irutil
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/asm"
+	"github.com/llir/llvm/ir"
+)
+
+func TestDomTree(t *testing.T) {
+	const src = `
+define void @f(i1 %c) {
+entry:
+	br i1 %c, label %a, label %b
+
+a:
+	br label %exit
+
+b:
+	br label %exit
+
+exit:
+	ret void
+
+dead:
+	br label %exit
+}
+`
+	m, err := asm.ParseString("", src)
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	f := m.Funcs[0]
+	blocks := make(map[string]*ir.Block)
+	for _, block := range f.Blocks {
+		blocks[block.Name()] = block
+	}
+	dom := NewDomTree(f)
+	idoms := []struct {
+		block, want string
+	}{
+		{block: "entry", want: ""},
+		{block: "a", want: "entry"},
+		{block: "b", want: "entry"},
+		{block: "exit", want: "entry"},
+		{block: "dead", want: ""},
+	}
+	for _, g := range idoms {
+		got := ""
+		if idom := dom.IDom(blocks[g.block]); idom != nil {
+			got = idom.Name()
+		}
+		if got != g.want {
+			t.Errorf("immediate dominator mismatch of %q; expected %q, got %q", g.block, g.want, got)
+		}
+	}
+	golden := []struct {
+		a, b string
+		want bool
+	}{
+		{a: "entry", b: "exit", want: true},
+		{a: "exit", b: "exit", want: true},
+		{a: "a", b: "exit", want: false},
+		{a: "exit", b: "entry", want: false},
+		{a: "dead", b: "exit", want: false},
+		{a: "entry", b: "dead", want: false},
+	}
+	for _, g := range golden {
+		if got := dom.Dominates(blocks[g.a], blocks[g.b]); got != g.want {
+			t.Errorf("dominance mismatch of %q over %q; expected %v, got %v", g.a, g.b, g.want, got)
+		}
+	}
+	if dom.Reachable(blocks["dead"]) {
+		t.Errorf("expected basic block %q to be unreachable", "dead")
+	}
+}
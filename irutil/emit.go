@@ -0,0 +1,322 @@
+package irutil
+
+import (
+	"encoding/binary"
+	"math"
+	"math/big"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/mewmew/float/binary16"
+	"github.com/mewmew/float/float80x86"
+	"github.com/pkg/errors"
+)
+
+// Reloc is a relocation of a constant byte image, referring to the address of a
+// global symbol.
+type Reloc struct {
+	// Byte offset of the relocated address within the byte image.
+	Offset uint64
+	// Size in bytes of the relocated address.
+	Size uint64
+	// Global symbol of the relocated address; *ir.Global, *ir.Func, *ir.Alias
+	// or *ir.IFunc.
+	Sym constant.Constant
+	// Constant byte offset added to the address of the global symbol.
+	Addend int64
+}
+
+// EmitConstantBytes returns the byte image of the given constant, in the byte
+// order of the data layout dl, and a list of relocations for addresses of
+// global symbols referred to by the constant. The bytes of relocated addresses
+// are zero in the byte image; the offset to the global symbol is recorded in
+// the addend of the relocation.
+//
+// Padding bytes and undef constants are emitted as zero. Pointer leaves may be
+// global symbols, or bitcast and getelementptr expressions thereof with
+// constant indices; integer leaves may be ptrtoint expressions of such pointer
+// leaves, as long as the integer type is of pointer size. Integer conversion,
+// bitcast, comparison and select expressions are simplified before emission.
+//
+// An error is returned if the constant cannot be resolved to bytes and simple
+// relocations (e.g. blockaddress constants, expressions which cannot be folded
+// and bit-packed vectors), or if its nesting depth exceeds constant.MaxDepth.
+func EmitConstantBytes(dl *types.DataLayout, c constant.Constant) ([]byte, []Reloc, error) {
+	if err := constant.CheckDepth(c); err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	e := &emitter{
+		dl:  dl,
+		buf: make([]byte, dl.StoreSize(c.Type())/8),
+	}
+	if err := e.emit(c, 0); err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	return e.buf, e.relocs, nil
+}
+
+// emitter tracks the state of emitting the byte image of a constant.
+type emitter struct {
+	// Data layout.
+	dl *types.DataLayout
+	// Byte image.
+	buf []byte
+	// Relocations of the byte image.
+	relocs []Reloc
+}
+
+// emit emits the byte image of the given constant at the specified byte offset.
+func (e *emitter) emit(c constant.Constant, offset uint64) error {
+	for _, piece := range LayoutConstant(e.dl, c) {
+		if err := e.emitLeaf(piece.Const, offset+piece.Offset, piece.Size); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// emitLeaf emits the byte image of the given leaf constant at the specified
+// byte offset.
+func (e *emitter) emitLeaf(c constant.Constant, offset, size uint64) error {
+	dst := e.buf[offset : offset+size]
+	switch c := c.(type) {
+	case *constant.Int:
+		e.putInt(dst, c.X, c.Typ.BitSize)
+		return nil
+	case *constant.Float:
+		return e.putFloat(dst, c)
+	case *constant.CharArray:
+		copy(dst, c.X)
+		return nil
+	case *constant.Null, *constant.ZeroInitializer, *constant.Undef, *constant.Poison:
+		// Zero bytes.
+		return nil
+	case *constant.Vector:
+		return errors.Errorf("unable to emit bit-packed vector constant %q", c)
+	}
+	// Relocated addresses of global symbols.
+	if t, ok := c.Type().(*types.PointerType); ok {
+		if sym, addend, ok := e.resolveAddr(c); ok {
+			e.relocs = append(e.relocs, Reloc{Offset: offset, Size: e.dl.PointerSize(t.AddrSpace) / 8, Sym: sym, Addend: addend})
+			return nil
+		}
+	}
+	if expr, ok := c.(*constant.ExprIntToPtr); ok {
+		if x, ok := expr.From.(*constant.Int); ok {
+			t := expr.To.(*types.PointerType)
+			e.putInt(dst, x.X, e.dl.PointerSize(t.AddrSpace))
+			return nil
+		}
+	}
+	if expr, ok := c.(*constant.ExprPtrToInt); ok {
+		if t, ok := expr.From.Type().(*types.PointerType); ok && e.dl.TypeSize(expr.To) == e.dl.PointerSize(t.AddrSpace) {
+			if sym, addend, ok := e.resolveAddr(expr.From); ok {
+				e.relocs = append(e.relocs, Reloc{Offset: offset, Size: size, Sym: sym, Addend: addend})
+				return nil
+			}
+		}
+	}
+	// Simplified constant expressions.
+	if expr, ok := c.(constant.Expression); ok {
+		if simple := simplify(e.dl, expr); simple != c {
+			return e.emit(simple, offset)
+		}
+	}
+	return errors.Errorf("unable to resolve constant %q to bytes", c)
+}
+
+// resolveAddr resolves the given pointer constant to the address of a global
+// symbol plus a constant byte offset. The boolean return value indicates
+// success.
+func (e *emitter) resolveAddr(c constant.Constant) (sym constant.Constant, addend int64, ok bool) {
+	switch c := c.(type) {
+	case *ir.Global, *ir.Func, *ir.Alias, *ir.IFunc:
+		return c, 0, true
+	case *constant.ExprBitCast:
+		return e.resolveAddr(c.From)
+	case *constant.ExprGetElementPtr:
+		sym, addend, ok := e.resolveAddr(c.Src)
+		if !ok {
+			return nil, 0, false
+		}
+		offset, ok := e.gepOffset(c.ElemType, c.Indices)
+		if !ok {
+			return nil, 0, false
+		}
+		return sym, addend + offset, true
+	}
+	return nil, 0, false
+}
+
+// gepOffset returns the byte offset of the given constant getelementptr indices
+// into the source element type. The boolean return value indicates success.
+func (e *emitter) gepOffset(elemType types.Type, indices []constant.Constant) (int64, bool) {
+	var offset int64
+	t := elemType
+	for i, index := range indices {
+		if idx, ok := index.(*constant.Index); ok {
+			index = idx.Constant
+		}
+		x, ok := index.(*constant.Int)
+		if !ok || !x.X.IsInt64() {
+			return 0, false
+		}
+		n := x.X.Int64()
+		if i == 0 {
+			offset += n * int64(e.dl.AllocSize(t)/8)
+			continue
+		}
+		switch tt := t.(type) {
+		case *types.StructType:
+			layout := e.dl.StructLayout(tt)
+			if n < 0 || n >= int64(len(tt.Fields)) {
+				return 0, false
+			}
+			offset += int64(layout.Offsets[n] / 8)
+			t = tt.Fields[n]
+		case *types.ArrayType:
+			t = tt.ElemType
+			offset += n * int64(e.dl.AllocSize(t)/8)
+		case *types.VectorType:
+			t = tt.ElemType
+			offset += n * int64(e.dl.AllocSize(t)/8)
+		default:
+			return 0, false
+		}
+	}
+	return offset, true
+}
+
+// putInt stores the given integer value of the specified bit size into dst, in
+// the byte order of the data layout. Negative values are stored in two's
+// complement form.
+func (e *emitter) putInt(dst []byte, x *big.Int, bitSize uint64) {
+	if x.Sign() < 0 {
+		x = new(big.Int).Add(x, new(big.Int).Lsh(big.NewInt(1), uint(bitSize)))
+	}
+	// Big-endian bytes of x.
+	b := x.Bytes()
+	if len(b) > len(dst) {
+		b = b[len(b)-len(dst):]
+	}
+	for i := range dst {
+		dst[i] = 0
+	}
+	if e.dl.BigEndian {
+		copy(dst[len(dst)-len(b):], b)
+		return
+	}
+	for i, v := range b {
+		dst[len(b)-1-i] = v
+	}
+}
+
+// putFloat stores the given floating-point constant into dst, in the byte order
+// of the data layout.
+func (e *emitter) putFloat(dst []byte, c *constant.Float) error {
+	var order binary.ByteOrder = binary.LittleEndian
+	if e.dl.BigEndian {
+		order = binary.BigEndian
+	}
+	sign := c.X != nil && c.X.Signbit()
+	switch c.Typ.Kind {
+	case types.FloatKindHalf:
+		var bits uint16
+		if c.NaN {
+			bits = uint16(c.NaNBits(uint64(binary16.NaN.Bits()), 9))
+			if sign {
+				bits |= 0x8000
+			}
+		} else {
+			f, _ := binary16.NewFromBig(c.X)
+			bits = f.Bits()
+		}
+		order.PutUint16(dst, bits)
+	case types.FloatKindFloat:
+		var bits uint32
+		if c.NaN {
+			bits = uint32(c.NaNBits(0x7FC00000, 22))
+			if sign {
+				bits |= 0x80000000
+			}
+		} else {
+			f, _ := c.X.Float32()
+			bits = math.Float32bits(f)
+		}
+		order.PutUint32(dst, bits)
+	case types.FloatKindDouble:
+		var bits uint64
+		if c.NaN {
+			bits = c.NaNBits(0x7FF8000000000000, 51)
+			if sign {
+				bits |= 1 << 63
+			}
+		} else {
+			f, _ := c.X.Float64()
+			bits = math.Float64bits(f)
+		}
+		order.PutUint64(dst, bits)
+	case types.FloatKindX86_FP80:
+		var se uint16
+		var m uint64
+		if c.NaN {
+			se, m = 0x7FFF, c.NaNBits(0xC000000000000000, 62)
+			if sign {
+				se |= 0x8000
+			}
+		} else {
+			f, _ := float80x86.NewFromBig(c.X)
+			se, m = f.Bits()
+		}
+		// The 64-bit significand (including the explicit integer bit) and the
+		// 16-bit sign and exponent make up the 10 byte store size.
+		if e.dl.BigEndian {
+			order.PutUint16(dst, se)
+			order.PutUint64(dst[2:], m)
+		} else {
+			order.PutUint64(dst, m)
+			order.PutUint16(dst[8:], se)
+		}
+	case types.FloatKindFP128:
+		hi, lo := c.Bits128()
+		if e.dl.BigEndian {
+			order.PutUint64(dst, hi)
+			order.PutUint64(dst[8:], lo)
+		} else {
+			order.PutUint64(dst, lo)
+			order.PutUint64(dst[8:], hi)
+		}
+	case types.FloatKindPPC_FP128:
+		// The high-order double precedes the low-order double, regardless of
+		// byte order; as by LLVM.
+		hi, lo := c.Bits128()
+		order.PutUint64(dst, hi)
+		order.PutUint64(dst[8:], lo)
+	default:
+		return errors.Errorf("support for emitting floating-point constant of type %q not yet implemented", c.Typ)
+	}
+	return nil
+}
+
+// ### [ Helper functions ] ####################################################
+
+// simplify returns the simplified constant of the given constant expression,
+// based on the data layout. Expressions without support for simplification are
+// returned unmodified.
+func simplify(dl *types.DataLayout, expr constant.Expression) constant.Constant {
+	switch expr := expr.(type) {
+	case *constant.ExprPtrToInt:
+		return expr.SimplifyDataLayout(dl)
+	case *constant.ExprIntToPtr:
+		return expr.SimplifyDataLayout(dl)
+	case *constant.ExprTrunc, *constant.ExprZExt, *constant.ExprSExt, *constant.ExprBitCast, *constant.ExprICmp, *constant.ExprFCmp, *constant.ExprSelect:
+		return expr.Simplify()
+	// Integer binary and bitwise expressions.
+	case *constant.ExprAdd, *constant.ExprSub, *constant.ExprMul, *constant.ExprUDiv, *constant.ExprSDiv, *constant.ExprURem, *constant.ExprSRem:
+		return expr.Simplify()
+	case *constant.ExprShl, *constant.ExprLShr, *constant.ExprAShr, *constant.ExprAnd, *constant.ExprOr, *constant.ExprXor:
+		return expr.Simplify()
+	}
+	return expr
+}
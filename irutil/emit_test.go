@@ -0,0 +1,186 @@
+package irutil
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestEmitConstantBytes(t *testing.T) {
+	le, err := types.NewDataLayout("e-m:e-i64:64-f80:128-n8:16:32:64-S128")
+	if err != nil {
+		t.Fatalf("unable to parse data layout; %v", err)
+	}
+	be, err := types.NewDataLayout("E-p:32:32-i64:64")
+	if err != nil {
+		t.Fatalf("unable to parse data layout; %v", err)
+	}
+	arrType := types.NewArray(2, types.I32)
+	g := ir.NewGlobalDef("g", constant.NewZeroInitializer(arrType))
+	// { i8, i32, i16 }
+	typ := types.NewStruct(types.I8, types.I32, types.I16)
+	s := constant.NewStruct(typ, constant.NewInt(types.I8, -1), constant.NewInt(types.I32, 0x01020304), constant.NewInt(types.I16, 0x0506))
+	// { i8*, i64, float }
+	gep := constant.NewGetElementPtr(g, constant.NewInt(types.I64, 0), constant.NewInt(types.I64, 1))
+	ptrType := types.NewStruct(types.I8Ptr, types.I64, types.Float)
+	p := constant.NewStruct(ptrType, constant.NewBitCast(gep, types.I8Ptr), constant.NewPtrToInt(g, types.I64), constant.NewFloat(types.Float, 1))
+	golden := []struct {
+		dl     *types.DataLayout
+		c      constant.Constant
+		want   []byte
+		relocs []Reloc
+	}{
+		{
+			dl:   le,
+			c:    s,
+			want: []byte{0xFF, 0, 0, 0, 0x04, 0x03, 0x02, 0x01, 0x06, 0x05, 0, 0},
+		},
+		{
+			dl:   be,
+			c:    s,
+			want: []byte{0xFF, 0, 0, 0, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0, 0},
+		},
+		{
+			dl:   le,
+			c:    constant.NewCharArrayFromString("hi\x00"),
+			want: []byte("hi\x00"),
+		},
+		{
+			dl:   le,
+			c:    p,
+			want: []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x00, 0x00, 0x80, 0x3F, 0, 0, 0, 0},
+			relocs: []Reloc{
+				{Offset: 0, Size: 8, Sym: g, Addend: 4},
+				{Offset: 8, Size: 8, Sym: g, Addend: 0},
+			},
+		},
+		{
+			dl:   be,
+			c:    constant.NewIntToPtr(constant.NewInt(types.I32, 0x1234), types.I8Ptr),
+			want: []byte{0, 0, 0x12, 0x34},
+		},
+	}
+	for _, g := range golden {
+		got, relocs, err := EmitConstantBytes(g.dl, g.c)
+		if err != nil {
+			t.Errorf("unable to emit bytes of %q; %v", g.c, err)
+			continue
+		}
+		if !bytes.Equal(g.want, got) {
+			t.Errorf("byte image mismatch of %q; expected % X, got % X", g.c, g.want, got)
+		}
+		if len(g.relocs) != len(relocs) {
+			t.Errorf("number of relocations mismatch of %q; expected %d, got %d", g.c, len(g.relocs), len(relocs))
+			continue
+		}
+		for i := range g.relocs {
+			if g.relocs[i] != relocs[i] {
+				t.Errorf("relocation %d mismatch of %q; expected %+v, got %+v", i, g.c, g.relocs[i], relocs[i])
+			}
+		}
+	}
+	// Constants which cannot be resolved to bytes.
+	f := ir.NewFunc("f", types.Void)
+	block := f.NewBlock("entry")
+	block.NewRet(nil)
+	invalid := []constant.Constant{
+		constant.NewBlockAddress(f, block),
+		constant.NewAdd(constant.NewPtrToInt(g, types.I64), constant.NewInt(types.I64, 1)),
+	}
+	for _, c := range invalid {
+		if _, _, err := EmitConstantBytes(le, c); err == nil {
+			t.Errorf("expected error for constant %q, got nil", c)
+		}
+	}
+}
+
+func TestEmitFloatBytes(t *testing.T) {
+	dl, err := types.NewDataLayout("e-m:e-i64:64-f80:128-n8:16:32:64-S128")
+	if err != nil {
+		t.Fatalf("unable to parse data layout; %v", err)
+	}
+	golden := []struct {
+		typ *types.FloatType
+		s   string
+		// Little-endian byte image.
+		want []byte
+	}{
+		// half; signaling NaN with payload, and quiet NaN with payload.
+		{typ: types.Half, s: "0xH7C01", want: []byte{0x01, 0x7C}},
+		{typ: types.Half, s: "0xHFE05", want: []byte{0x05, 0xFE}},
+		// float
+		{typ: types.Float, s: "0x7FF0000020000000", want: []byte{0x01, 0x00, 0x80, 0x7F}},
+		{typ: types.Float, s: "0xFFF80000A0000000", want: []byte{0x05, 0x00, 0xC0, 0xFF}},
+		{typ: types.Float, s: "0x7FF4000000000000", want: []byte{0x00, 0x00, 0xA0, 0x7F}},
+		// double
+		{typ: types.Double, s: "0x7FF0000000000001", want: []byte{0x01, 0, 0, 0, 0, 0, 0xF0, 0x7F}},
+		{typ: types.Double, s: "0x7FF8000000000005", want: []byte{0x05, 0, 0, 0, 0, 0, 0xF8, 0x7F}},
+		{typ: types.Double, s: "0x7FF4000000000000", want: []byte{0, 0, 0, 0, 0, 0, 0xF4, 0x7F}},
+		// x86_fp80
+		{typ: types.X86_FP80, s: "0xK7FFF8000000000000001", want: []byte{0x01, 0, 0, 0, 0, 0, 0, 0x80, 0xFF, 0x7F}},
+		{typ: types.X86_FP80, s: "0xKFFFFC000000000000005", want: []byte{0x05, 0, 0, 0, 0, 0, 0, 0xC0, 0xFF, 0xFF}},
+	}
+	for _, g := range golden {
+		c, err := constant.NewFloatFromString(g.typ, g.s)
+		if err != nil {
+			t.Errorf("unable to parse %q; %v", g.s, err)
+			continue
+		}
+		got, _, err := EmitConstantBytes(dl, c)
+		if err != nil {
+			t.Errorf("unable to emit bytes of %q; %v", c, err)
+			continue
+		}
+		if !bytes.Equal(g.want, got) {
+			t.Errorf("byte image mismatch of %q; expected % X, got % X", c, g.want, got)
+		}
+	}
+	// 128-bit floating-point constants, in little-endian and big-endian byte
+	// order; as by llc.
+	be, err := types.NewDataLayout("E-m:e-i64:64-n32:64")
+	if err != nil {
+		t.Fatalf("unable to parse data layout; %v", err)
+	}
+	golden128 := []struct {
+		typ *types.FloatType
+		s   string
+		// Little-endian and big-endian byte images.
+		le, be []byte
+	}{
+		{
+			typ: types.FP128,
+			s:   "0xL0000000000000001C000921FB54442D1",
+			le:  []byte{0x01, 0, 0, 0, 0, 0, 0, 0, 0xD1, 0x42, 0x44, 0xB5, 0x1F, 0x92, 0x00, 0xC0},
+			be:  []byte{0xC0, 0x00, 0x92, 0x1F, 0xB5, 0x44, 0x42, 0xD1, 0, 0, 0, 0, 0, 0, 0, 0x01},
+		},
+		{
+			typ: types.PPC_FP128,
+			s:   "0xM400921FB54442D183CA1A62633145C07",
+			le:  []byte{0x18, 0x2D, 0x44, 0x54, 0xFB, 0x21, 0x09, 0x40, 0x07, 0x5C, 0x14, 0x33, 0x26, 0xA6, 0xA1, 0x3C},
+			be:  []byte{0x40, 0x09, 0x21, 0xFB, 0x54, 0x44, 0x2D, 0x18, 0x3C, 0xA1, 0xA6, 0x26, 0x33, 0x14, 0x5C, 0x07},
+		},
+	}
+	for _, g := range golden128 {
+		c, err := constant.NewFloatFromString(g.typ, g.s)
+		if err != nil {
+			t.Errorf("unable to parse %q; %v", g.s, err)
+			continue
+		}
+		for _, want := range []struct {
+			dl  *types.DataLayout
+			buf []byte
+		}{{dl: dl, buf: g.le}, {dl: be, buf: g.be}} {
+			got, _, err := EmitConstantBytes(want.dl, c)
+			if err != nil {
+				t.Errorf("unable to emit bytes of %q; %v", c, err)
+				continue
+			}
+			if !bytes.Equal(want.buf, got) {
+				t.Errorf("byte image mismatch of %q; expected % X, got % X", c, want.buf, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package irutil
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+// === [ Constant evaluation ] =================================================
+
+// EvalConstInt evaluates the given constant to a Go integer value. Constant
+// expressions are folded, operands first. The boolean return value indicates
+// success, and is false if the constant does not fold to an integer constant
+// representable as int64, or is nested deeper than constant.MaxDepth.
+//
+// Integer constants are interpreted as signed, except for i1 constants which
+// evaluate to 0 or 1.
+func EvalConstInt(c constant.Constant) (int64, bool) {
+	x, ok := foldConst(c).(*constant.Int)
+	if !ok || !x.X.IsInt64() {
+		return 0, false
+	}
+	return x.X.Int64(), true
+}
+
+// EvalConstFloat evaluates the given constant to a Go floating-point value.
+// Constant expressions are folded, operands first. The boolean return value
+// indicates success, and is false if the constant does not fold to a
+// floating-point constant exactly representable as float64, or is nested
+// deeper than constant.MaxDepth.
+func EvalConstFloat(c constant.Constant) (float64, bool) {
+	x, ok := foldConst(c).(*constant.Float)
+	if !ok {
+		return 0, false
+	}
+	if x.NaN {
+		return math.NaN(), true
+	}
+	f, acc := x.X.Float64()
+	if acc != big.Exact {
+		return 0, false
+	}
+	return f, true
+}
+
+// EvalConstBool evaluates the given constant to a Go boolean value. Constant
+// expressions are folded, operands first. The boolean return value indicates
+// success, and is false if the constant does not fold to an i1 constant, or is
+// nested deeper than constant.MaxDepth.
+func EvalConstBool(c constant.Constant) (bool, bool) {
+	x, ok := foldConst(c).(*constant.Int)
+	if !ok || !x.Typ.Equal(types.I1) {
+		return false, false
+	}
+	return x.X.Sign() != 0, true
+}
+
+// foldConst returns the folded constant of the given constant, or nil if the
+// nesting depth of the constant exceeds constant.MaxDepth.
+func foldConst(c constant.Constant) constant.Constant {
+	if constant.CheckDepth(c) != nil {
+		return nil
+	}
+	return evalConst(c)
+}
+
+// evalConst returns the folded constant of the given constant, folding the
+// operands of constant expressions before the expression itself. Constant
+// expressions without support for folding are returned unmodified.
+//
+// The given constant is not modified; folded operands are stored in copies of
+// the constant expressions.
+func evalConst(c constant.Constant) constant.Constant {
+	switch e := c.(type) {
+	case *constant.ExprTrunc:
+		e2 := *e
+		e2.From = evalConst(e.From)
+		return simplify(nil, &e2)
+	case *constant.ExprZExt:
+		e2 := *e
+		e2.From = evalConst(e.From)
+		return simplify(nil, &e2)
+	case *constant.ExprSExt:
+		e2 := *e
+		e2.From = evalConst(e.From)
+		return simplify(nil, &e2)
+	case *constant.ExprBitCast:
+		e2 := *e
+		e2.From = evalConst(e.From)
+		return simplify(nil, &e2)
+	case *constant.ExprPtrToInt:
+		e2 := *e
+		e2.From = evalConst(e.From)
+		return simplify(nil, &e2)
+	case *constant.ExprIntToPtr:
+		e2 := *e
+		e2.From = evalConst(e.From)
+		return simplify(nil, &e2)
+	case *constant.ExprAdd:
+		e2 := *e
+		e2.X, e2.Y = evalConst(e.X), evalConst(e.Y)
+		return simplify(nil, &e2)
+	case *constant.ExprSub:
+		e2 := *e
+		e2.X, e2.Y = evalConst(e.X), evalConst(e.Y)
+		return simplify(nil, &e2)
+	case *constant.ExprMul:
+		e2 := *e
+		e2.X, e2.Y = evalConst(e.X), evalConst(e.Y)
+		return simplify(nil, &e2)
+	case *constant.ExprUDiv:
+		e2 := *e
+		e2.X, e2.Y = evalConst(e.X), evalConst(e.Y)
+		return simplify(nil, &e2)
+	case *constant.ExprSDiv:
+		e2 := *e
+		e2.X, e2.Y = evalConst(e.X), evalConst(e.Y)
+		return simplify(nil, &e2)
+	case *constant.ExprURem:
+		e2 := *e
+		e2.X, e2.Y = evalConst(e.X), evalConst(e.Y)
+		return simplify(nil, &e2)
+	case *constant.ExprSRem:
+		e2 := *e
+		e2.X, e2.Y = evalConst(e.X), evalConst(e.Y)
+		return simplify(nil, &e2)
+	case *constant.ExprShl:
+		e2 := *e
+		e2.X, e2.Y = evalConst(e.X), evalConst(e.Y)
+		return simplify(nil, &e2)
+	case *constant.ExprLShr:
+		e2 := *e
+		e2.X, e2.Y = evalConst(e.X), evalConst(e.Y)
+		return simplify(nil, &e2)
+	case *constant.ExprAShr:
+		e2 := *e
+		e2.X, e2.Y = evalConst(e.X), evalConst(e.Y)
+		return simplify(nil, &e2)
+	case *constant.ExprAnd:
+		e2 := *e
+		e2.X, e2.Y = evalConst(e.X), evalConst(e.Y)
+		return simplify(nil, &e2)
+	case *constant.ExprOr:
+		e2 := *e
+		e2.X, e2.Y = evalConst(e.X), evalConst(e.Y)
+		return simplify(nil, &e2)
+	case *constant.ExprXor:
+		e2 := *e
+		e2.X, e2.Y = evalConst(e.X), evalConst(e.Y)
+		return simplify(nil, &e2)
+	case *constant.ExprICmp:
+		e2 := *e
+		e2.X, e2.Y = evalConst(e.X), evalConst(e.Y)
+		return simplify(nil, &e2)
+	case *constant.ExprFCmp:
+		e2 := *e
+		e2.X, e2.Y = evalConst(e.X), evalConst(e.Y)
+		return simplify(nil, &e2)
+	case *constant.ExprSelect:
+		e2 := *e
+		e2.Cond, e2.X, e2.Y = evalConst(e.Cond), evalConst(e.X), evalConst(e.Y)
+		return simplify(nil, &e2)
+	default:
+		return c
+	}
+}
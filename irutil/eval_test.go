@@ -0,0 +1,151 @@
+package irutil
+
+import (
+	"math"
+	"testing"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestEvalConst(t *testing.T) {
+	m := ir.NewModule()
+	g := m.NewGlobalDef("g", constant.NewInt(types.I32, 0))
+	minusOne := constant.NewInt(types.I8, -1)
+	cond := constant.NewICmp(enum.IPredSLT, constant.NewSExt(minusOne, types.I32), constant.NewInt(types.I32, 0))
+	ints := []struct {
+		in   constant.Constant
+		want int64
+		ok   bool
+	}{
+		{in: constant.NewInt(types.I64, -42), want: -42, ok: true},
+		{in: constant.NewZExt(minusOne, types.I32), want: 255, ok: true},
+		{in: constant.NewTrunc(constant.NewSExt(minusOne, types.I32), types.I16), want: -1, ok: true},
+		{in: constant.NewSelect(cond, constant.NewInt(types.I32, 1), constant.NewInt(types.I32, 2)), want: 1, ok: true},
+		{in: constant.NewPtrToInt(constant.NewNull(types.I8Ptr), types.I64), want: 0, ok: true},
+		// Not concrete.
+		{in: constant.NewPtrToInt(g, types.I64)},
+		{in: constant.NewUndef(types.I32)},
+		// Integer binary and bitwise expressions, wrapped to the bit size of the
+		// integer type.
+		{in: constant.NewAdd(i8(127), i8(1)), want: -128, ok: true},
+		{in: constant.NewAdd(constant.NewAdd(i8(1), i8(2)), i8(3)), want: 6, ok: true},
+		{in: constant.NewSub(i8(-128), i8(1)), want: 127, ok: true},
+		{in: constant.NewMul(i8(16), i8(17)), want: 16, ok: true},
+		{in: constant.NewUDiv(i8(-1), i8(2)), want: 127, ok: true},
+		{in: constant.NewSDiv(i8(-7), i8(2)), want: -3, ok: true},
+		{in: constant.NewURem(i8(-1), i8(10)), want: 5, ok: true},
+		{in: constant.NewSRem(i8(-7), i8(2)), want: -1, ok: true},
+		{in: constant.NewShl(i8(3), i8(7)), want: -128, ok: true},
+		{in: constant.NewLShr(i8(-128), i8(7)), want: 1, ok: true},
+		{in: constant.NewAShr(i8(-128), i8(7)), want: -1, ok: true},
+		{in: constant.NewAnd(i8(-1), i8(0x0F)), want: 15, ok: true},
+		{in: constant.NewOr(i8(0x70), i8(0x0F)), want: 127, ok: true},
+		{in: constant.NewXor(i8(-1), i8(0x0F)), want: -16, ok: true},
+		{in: constant.NewXor(constant.True, constant.True), want: 0, ok: true},
+		{in: withFlags(constant.NewAdd(i8(126), i8(1)), enum.OverflowFlagNSW), want: 127, ok: true},
+		{in: exactUDiv(i8(6), i8(3)), want: 2, ok: true},
+		// Undefined behaviour and poison.
+		{in: constant.NewUDiv(i8(1), i8(0))},
+		{in: constant.NewSDiv(i8(-128), i8(-1))},
+		{in: constant.NewURem(i8(1), i8(0))},
+		{in: constant.NewSRem(i8(-128), i8(-1))},
+		{in: constant.NewShl(i8(1), i8(8))},
+		{in: constant.NewLShr(i8(1), i8(-1))},
+		{in: constant.NewAShr(i8(1), i8(8))},
+		{in: withFlags(constant.NewAdd(i8(127), i8(1)), enum.OverflowFlagNSW)},
+		{in: withFlags(constant.NewSub(i8(0), i8(1)), enum.OverflowFlagNUW)},
+		{in: withFlags(constant.NewMul(i8(64), i8(2)), enum.OverflowFlagNSW)},
+		{in: exactUDiv(i8(7), i8(2))},
+		// Folding not supported.
+		{in: constant.NewAdd(constant.NewPtrToInt(g, types.I32), i32(4))},
+	}
+	for _, g := range ints {
+		got, ok := EvalConstInt(g.in)
+		if got != g.want || ok != g.ok {
+			t.Errorf("%v: integer mismatch; expected %d (%v), got %d (%v)", g.in, g.want, g.ok, got, ok)
+		}
+	}
+	// Vectors are folded element-wise.
+	v := constant.NewVector(types.NewVector(2, types.I8), i8(100), i8(-1))
+	sum := constant.NewAdd(v, v)
+	if want, got := "<2 x i8> <i8 -56, i8 -2>", evalConst(sum).String(); want != got {
+		t.Errorf("%v: vector mismatch; expected %q, got %q", sum, want, got)
+	}
+	// Constants nested deeper than constant.MaxDepth are not folded.
+	func() {
+		defer func(maxDepth int) { constant.MaxDepth = maxDepth }(constant.MaxDepth)
+		constant.MaxDepth = 100
+		var c constant.Constant = i32(0)
+		for i := 1; i < 100; i++ {
+			c = constant.NewAdd(c, i32(1))
+		}
+		if got, ok := EvalConstInt(c); !ok || got != 99 {
+			t.Errorf("integer mismatch; expected 99, got %d (%v)", got, ok)
+		}
+		c = constant.NewAdd(c, i32(1))
+		if _, ok := EvalConstInt(c); ok {
+			t.Errorf("unexpected integer value of constant nested deeper than %d", constant.MaxDepth)
+		}
+		dl, err := types.NewDataLayout("e")
+		if err != nil {
+			t.Fatalf("unable to parse data layout; %v", err)
+		}
+		if _, _, err := EmitConstantBytes(dl, c); err == nil {
+			t.Errorf("expected error for constant nested deeper than %d", constant.MaxDepth)
+		}
+	}()
+	if got, ok := EvalConstBool(cond); !ok || !got {
+		t.Errorf("%v: boolean mismatch; expected true, got %v (%v)", cond, got, ok)
+	}
+	if _, ok := EvalConstBool(constant.NewInt(types.I32, 1)); ok {
+		t.Errorf("unexpected boolean value of i32 constant")
+	}
+	// Original constant expression is not modified.
+	if want, got := "i1 icmp slt (i32 sext (i8 -1 to i32), i32 0)", cond.String(); want != got {
+		t.Errorf("constant expression mismatch; expected %q, got %q", want, got)
+	}
+	fcond := constant.NewFCmp(enum.FPredOLT, constant.NewFloat(types.Double, 1), constant.NewFloat(types.Double, 2))
+	f := constant.NewSelect(fcond, constant.NewFloat(types.Double, 1.5), constant.NewFloat(types.Double, 2.5))
+	if got, ok := EvalConstFloat(f); !ok || got != 1.5 {
+		t.Errorf("%v: float mismatch; expected 1.5, got %v (%v)", f, got, ok)
+	}
+	if got, ok := EvalConstFloat(constant.NewFloat(types.Float, math.NaN())); !ok || !math.IsNaN(got) {
+		t.Errorf("float mismatch; expected NaN, got %v (%v)", got, ok)
+	}
+	if _, ok := EvalConstFloat(constant.NewInt(types.I32, 1)); ok {
+		t.Errorf("unexpected float value of i32 constant")
+	}
+}
+
+// i8 returns a new i8 integer constant of the given value.
+func i8(x int64) *constant.Int {
+	return constant.NewInt(types.I8, x)
+}
+
+// i32 returns a new i32 integer constant of the given value.
+func i32(x int64) *constant.Int {
+	return constant.NewInt(types.I32, x)
+}
+
+// withFlags sets the overflow flags of the given constant expression.
+func withFlags(e constant.Expression, flags ...enum.OverflowFlag) constant.Expression {
+	switch e := e.(type) {
+	case *constant.ExprAdd:
+		e.OverflowFlags = flags
+	case *constant.ExprSub:
+		e.OverflowFlags = flags
+	case *constant.ExprMul:
+		e.OverflowFlags = flags
+	}
+	return e
+}
+
+// exactUDiv returns a new udiv expression with the exact flag set.
+func exactUDiv(x, y constant.Constant) *constant.ExprUDiv {
+	e := constant.NewUDiv(x, y)
+	e.Exact = true
+	return e
+}
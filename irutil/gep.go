@@ -0,0 +1,156 @@
+package irutil
+
+import (
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// SimplifyGEP returns a value equivalent to the given getelementptr
+// instruction if all of its indices are zero; i.e. the source address, or a
+// bitcast of the source address to the result type if the types differ. The
+// boolean return value indicates success.
+//
+// The bitcast is a constant expression if the source address is a constant,
+// and a bitcast instruction otherwise. The bitcast instruction is not inserted
+// into a basic block; callers should insert it in place of the getelementptr
+// instruction before replacing its uses (e.g. using ReplaceAllUsesWith). The
+// address space of the source address is preserved. Vector getelementptr
+// instructions are only simplified if the source address is of the result
+// type.
+func SimplifyGEP(inst *ir.InstGetElementPtr) (value.Value, bool) {
+	for _, index := range inst.Indices {
+		c, ok := index.(constant.Constant)
+		if !ok || !constant.IsZeroIndex(c) {
+			return nil, false
+		}
+	}
+	typ := inst.Type()
+	if inst.Src.Type().Equal(typ) {
+		return inst.Src, true
+	}
+	if types.IsVector(typ) {
+		return nil, false
+	}
+	if src, ok := inst.Src.(constant.Constant); ok {
+		return constant.NewBitCast(src, typ), true
+	}
+	return ir.NewBitCast(inst.Src, typ), true
+}
+
+// ResolveConstGEPLoad returns the element constant of the given initializer
+// addressed by the indices of the constant getelementptr expression, where the
+// source address of the expression refers to a global variable with the given
+// initializer. The boolean return value indicates success.
+//
+// The indices must be integer constants, the first of which must be zero, and
+// the source element type of the expression must be the type of the
+// initializer. Elements of zeroinitializer and undef aggregates resolve to
+// zero and undef constants of the element type respectively.
+func ResolveConstGEPLoad(ce *constant.ExprGetElementPtr, initializer constant.Constant) (constant.Constant, bool) {
+	if len(ce.Indices) == 0 || !ce.ElemType.Equal(initializer.Type()) {
+		return nil, false
+	}
+	c := initializer
+	for i, index := range ce.Indices {
+		if idx, ok := index.(*constant.Index); ok {
+			index = idx.Constant
+		}
+		x, ok := index.(*constant.Int)
+		if !ok || !x.X.IsInt64() {
+			return nil, false
+		}
+		n := x.X.Int64()
+		if i == 0 {
+			if n != 0 {
+				return nil, false
+			}
+			continue
+		}
+		if c, ok = elemConstant(c, n); !ok {
+			return nil, false
+		}
+	}
+	return c, true
+}
+
+// elemConstant returns the element constant at index n of the given aggregate
+// constant. The boolean return value indicates success.
+func elemConstant(c constant.Constant, n int64) (constant.Constant, bool) {
+	if n < 0 {
+		return nil, false
+	}
+	switch c := c.(type) {
+	case *constant.Array:
+		if n >= int64(len(c.Elems)) {
+			return nil, false
+		}
+		return c.Elems[n], true
+	case *constant.CharArray:
+		if n >= int64(len(c.X)) {
+			return nil, false
+		}
+		return constant.NewInt(types.I8, int64(c.X[n])), true
+	case *constant.Vector:
+		if n >= int64(len(c.Elems)) {
+			return nil, false
+		}
+		return c.Elems[n], true
+	case *constant.Struct:
+		if n >= int64(len(c.Fields)) {
+			return nil, false
+		}
+		return c.Fields[n], true
+	case *constant.ZeroInitializer:
+		elemType, ok := elemTypeAt(c.Typ, n)
+		if !ok {
+			return nil, false
+		}
+		return zeroValue(elemType), true
+	case *constant.Undef:
+		elemType, ok := elemTypeAt(c.Typ, n)
+		if !ok {
+			return nil, false
+		}
+		return constant.NewUndef(elemType), true
+	}
+	return nil, false
+}
+
+// elemTypeAt returns the element type at index n of the given aggregate type.
+// The boolean return value indicates success.
+func elemTypeAt(t types.Type, n int64) (types.Type, bool) {
+	switch t := t.(type) {
+	case *types.ArrayType:
+		if uint64(n) >= t.Len {
+			return nil, false
+		}
+		return t.ElemType, true
+	case *types.VectorType:
+		if uint64(n) >= t.Len {
+			return nil, false
+		}
+		return t.ElemType, true
+	case *types.StructType:
+		if n >= int64(len(t.Fields)) {
+			return nil, false
+		}
+		return t.Fields[n], true
+	}
+	return nil, false
+}
+
+// zeroValue returns the zero constant of the given type; i.e. an integer or
+// floating-point zero, a null pointer or a zeroinitializer.
+func zeroValue(t types.Type) constant.Constant {
+	switch t := t.(type) {
+	case *types.IntType:
+		return constant.NewInt(t, 0)
+	case *types.FloatType:
+		return constant.NewFloat(t, 0)
+	case *types.PointerType:
+		return constant.NewNull(t)
+	}
+	return constant.NewZeroInitializer(t)
+}
@@ -0,0 +1,103 @@
+package irutil
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestSimplifyGEP(t *testing.T) {
+	arrType := types.NewArray(4, types.I32)
+	arrPtrAS1 := types.NewPointer(arrType)
+	arrPtrAS1.AddrSpace = 1
+	g := ir.NewGlobalDef("g", constant.NewZeroInitializer(arrType))
+	f := ir.NewFunc("f", types.Void, ir.NewParam("p", types.NewPointer(arrType)), ir.NewParam("q", arrPtrAS1), ir.NewParam("i", types.I64))
+	p, q, i := f.Params[0], f.Params[1], f.Params[2]
+	entry := f.NewBlock("entry")
+	zero := constant.NewInt(types.I64, 0)
+	golden := []struct {
+		in   *ir.InstGetElementPtr
+		want string // empty if not simplified
+	}{
+		// Zero index to source address of same type.
+		{in: entry.NewGetElementPtr(p, zero), want: "[4 x i32]* %p"},
+		// Zero index chain to first element.
+		{in: entry.NewGetElementPtr(p, zero, zero), want: "%0 = bitcast [4 x i32]* %p to i32*"},
+		// Address space is preserved.
+		{in: entry.NewGetElementPtr(q, zero, constant.NewInt(types.I32, 0)), want: "%0 = bitcast [4 x i32] addrspace(1)* %q to i32 addrspace(1)*"},
+		// Constant source address.
+		{in: entry.NewGetElementPtr(g, zero, zero), want: "i32* bitcast ([4 x i32]* @g to i32*)"},
+		// Non-zero and non-constant indices.
+		{in: entry.NewGetElementPtr(p, zero, constant.NewInt(types.I64, 1))},
+		{in: entry.NewGetElementPtr(p, zero, i)},
+	}
+	for _, g := range golden {
+		v, ok := SimplifyGEP(g.in)
+		if len(g.want) == 0 {
+			if ok {
+				t.Errorf("expected %q to not be simplified, got %v", g.in.LLString(), v)
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("unable to simplify %q", g.in.LLString())
+			continue
+		}
+		got := v.String()
+		if inst, ok := v.(*ir.InstBitCast); ok {
+			got = inst.LLString()
+		}
+		if g.want != got {
+			t.Errorf("simplified value mismatch of %q; expected %q, got %q", g.in.LLString(), g.want, got)
+		}
+	}
+}
+
+func TestResolveConstGEPLoad(t *testing.T) {
+	i32 := func(x int64) *constant.Int { return constant.NewInt(types.I32, x) }
+	i64 := func(x int64) *constant.Int { return constant.NewInt(types.I64, x) }
+	// { [3 x i32], [2 x i8], { i32, i8* } }
+	arrType := types.NewArray(3, types.I32)
+	pairType := types.NewStruct(types.I32, types.I8Ptr)
+	typ := types.NewStruct(arrType, types.NewArray(3, types.I8), pairType)
+	init := constant.NewStruct(typ, constant.NewArray(arrType, i32(10), i32(20), i32(30)), constant.NewCharArrayFromString("hi\x00"), constant.NewZeroInitializer(pairType))
+	g := ir.NewGlobalDef("g", init)
+	golden := []struct {
+		in   *constant.ExprGetElementPtr
+		want string // empty if not resolved
+	}{
+		{in: constant.NewGetElementPtr(g, i64(0), i32(0), i64(2)), want: "i32 30"},
+		{in: constant.NewGetElementPtr(g, i64(0), i32(1), i64(1)), want: "i8 105"},
+		{in: constant.NewGetElementPtr(g, i64(0), i32(2), i32(0)), want: "i32 0"},
+		{in: constant.NewGetElementPtr(g, i64(0), i32(2), i32(1)), want: "i8* null"},
+		{in: constant.NewGetElementPtr(g, i64(0), i32(0)), want: "[3 x i32] [i32 10, i32 20, i32 30]"},
+		{in: constant.NewGetElementPtr(g, i64(0)), want: init.String()},
+		// Out of bounds indices.
+		{in: constant.NewGetElementPtr(g, i64(1), i32(0), i64(0))},
+		{in: constant.NewGetElementPtr(g, i64(0), i32(0), i64(3))},
+		{in: constant.NewGetElementPtr(g, i64(0), i32(0), i64(-1))},
+	}
+	for _, g := range golden {
+		c, ok := ResolveConstGEPLoad(g.in, init)
+		if len(g.want) == 0 {
+			if ok {
+				t.Errorf("expected %q to not be resolved, got %q", g.in, c)
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("unable to resolve %q", g.in)
+			continue
+		}
+		if got := c.String(); g.want != got {
+			t.Errorf("resolved constant mismatch of %q; expected %q, got %q", g.in, g.want, got)
+		}
+	}
+	// Element type mismatch between expression and initializer.
+	other := constant.NewGetElementPtr(constant.NewNull(types.NewPointer(arrType)), i64(0), i64(0))
+	if c, ok := ResolveConstGEPLoad(other, init); ok {
+		t.Errorf("expected element type mismatch to not be resolved, got %q", c)
+	}
+}
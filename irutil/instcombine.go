@@ -0,0 +1,151 @@
+package irutil
+
+import (
+	"math/big"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+	"github.com/llir/llvm/irutil/match"
+)
+
+// InstCombine simplifies instructions of function f by a set of peephole
+// optimizations, and returns the number of instructions simplified.
+// Simplification is repeated until no further instructions can be simplified.
+//
+// Uses of simplified instructions are replaced by the simplified value, and the
+// simplified instructions are removed. Instructions made dead by simplification
+// are removed by EliminateDeadCode.
+//
+// The following simplifications are performed, with operands of commutative
+// instructions in either order.
+//
+//    x + 0              -> x
+//    x * 1              -> x
+//    x * 0              -> 0
+//    x & x              -> x
+//    x & -1             -> x
+//    x | 0              -> x
+//    x - x              -> 0
+//    select true, a, b  -> a
+//    select false, a, b -> b
+func InstCombine(f *ir.Func) int {
+	// Simplified instructions mapped to their replacement value.
+	repl := make(map[value.Value]value.Value)
+	total := 0
+	for {
+		n := 0
+		for _, block := range f.Blocks {
+			insts := block.Insts[:0]
+			for _, inst := range block.Insts {
+				replaceSimplified(inst, repl)
+				if v, ok := inst.(value.Value); ok {
+					if new, ok := simplifyInst(v); ok {
+						repl[v] = resolveSimplified(new, repl)
+						n++
+						continue
+					}
+				}
+				insts = append(insts, inst)
+			}
+			block.Insts = insts
+		}
+		// Replace uses by phi instructions of later basic blocks and terminators.
+		for _, block := range f.Blocks {
+			for _, inst := range block.Insts {
+				replaceSimplified(inst, repl)
+			}
+			if term, ok := block.Term.(ir.Instruction); ok {
+				replaceSimplified(term, repl)
+			}
+		}
+		if n == 0 {
+			break
+		}
+		total += n
+	}
+	if total > 0 {
+		EliminateDeadCode(f)
+		f.ResetIDs()
+	}
+	return total
+}
+
+// simplifyInst returns the simplified value of the given instruction. The
+// boolean return value indicates success.
+func simplifyInst(inst value.Value) (value.Value, bool) {
+	var x, y value.Value
+	var c *constant.Int
+	switch {
+	// x + 0 -> x
+	case match.Commutative(match.Add(match.Value(&x), match.Int(0))).Match(inst):
+		return x, true
+	// x * 1 -> x
+	case match.Commutative(match.Mul(match.Value(&x), match.Int(1))).Match(inst):
+		return x, true
+	// x * 0 -> 0
+	case match.Commutative(match.Mul(match.Value(&x), match.Int(0))).Match(inst):
+		return zeroValue(x.Type()), true
+	// x & x -> x
+	case match.And(match.Value(&x), match.Value(&y)).Match(inst) && x == y:
+		return x, true
+	// x & -1 -> x
+	case match.And(match.Value(&x), match.ConstInt(&c)).Match(inst) && isAllOnes(c):
+		return x, true
+	case match.And(match.ConstInt(&c), match.Value(&x)).Match(inst) && isAllOnes(c):
+		return x, true
+	// x | 0 -> x
+	case match.Commutative(match.Or(match.Value(&x), match.Int(0))).Match(inst):
+		return x, true
+	// x - x -> 0
+	case match.Sub(match.Value(&x), match.Value(&y)).Match(inst) && x == y:
+		return zeroValue(x.Type()), true
+	// select true, a, b -> a
+	// select false, a, b -> b
+	case match.Select(match.ConstInt(&c), match.Value(&x), match.Value(&y)).Match(inst):
+		if c.X.Sign() != 0 {
+			return x, true
+		}
+		return y, true
+	}
+	return nil, false
+}
+
+// isAllOnes reports whether the given integer constant has all bits set.
+func isAllOnes(c *constant.Int) bool {
+	if c.X.Cmp(big.NewInt(-1)) == 0 {
+		return true
+	}
+	t, ok := c.Type().(*types.IntType)
+	if !ok {
+		return false
+	}
+	mask := new(big.Int).Lsh(big.NewInt(1), uint(t.BitSize))
+	mask.Sub(mask, big.NewInt(1))
+	return c.X.Cmp(mask) == 0
+}
+
+// replaceSimplified replaces operands of the given instruction referring to
+// simplified instructions by their replacement value.
+func replaceSimplified(inst ir.Instruction, repl map[value.Value]value.Value) {
+	for _, operand := range ir.Operands(inst) {
+		if _, ok := repl[*operand]; ok {
+			*operand = resolveSimplified(*operand, repl)
+		}
+	}
+}
+
+// resolveSimplified returns the replacement value of the given value, following
+// chains of simplified instructions. Cycles of simplified instructions (only
+// possible in unreachable code) are broken after visiting each instruction once.
+func resolveSimplified(v value.Value, repl map[value.Value]value.Value) value.Value {
+	for i := 0; i < len(repl); i++ {
+		new, ok := repl[v]
+		if !ok {
+			break
+		}
+		v = new
+	}
+	return v
+}
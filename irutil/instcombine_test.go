@@ -0,0 +1,62 @@
+package irutil
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/asm"
+)
+
+func TestInstCombine(t *testing.T) {
+	const src = `
+define i32 @f(i32 %x, i32 %y, i1 %c) {
+entry:
+	%a = add i32 0, %x
+	%b = mul i32 %a, 1
+	%d = and i32 %b, %b
+	%e = and i32 -1, %d
+	%f = or i32 %e, 0
+	%g = sub i32 %f, %y
+	%h = mul i32 %g, 0
+	%i = select i1 true, i32 %f, i32 %h
+	%j = sub i32 %i, %x
+	%k = add i32 %j, %y
+	%l = and i8 255, 7
+	%m = select i1 %c, i32 %k, i32 %y
+	br i1 %c, label %exit, label %entry.1
+
+entry.1:
+	%n = add i32 %m, 0
+	br label %exit
+
+exit:
+	%r = phi i32 [ %k, %entry ], [ %n, %entry.1 ]
+	ret i32 %r
+}
+`
+	m, err := asm.ParseString("", src)
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	f := m.Funcs[0]
+	if n := InstCombine(f); n != 11 {
+		t.Errorf("number of simplified instructions mismatch; expected 11, got %d", n)
+	}
+	want := `define i32 @f(i32 %x, i32 %y, i1 %c) {
+entry:
+	%m = select i1 %c, i32 %y, i32 %y
+	br i1 %c, label %exit, label %entry.1
+
+entry.1:
+	br label %exit
+
+exit:
+	%r = phi i32 [ %y, %entry ], [ %m, %entry.1 ]
+	ret i32 %r
+}`
+	if got := f.LLString(); got != want {
+		t.Errorf("function mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+}
@@ -0,0 +1,8 @@
+// Package irutil implements analyses and transformations of LLVM IR modules.
+//
+// The ir package is concerned with the construction and serialization of LLVM
+// IR modules, while the irutil package holds higher-level algorithms operating
+// on the data types of the ir package (e.g. replacing all uses of a value and
+// eliminating dead code). This keeps the core types of the ir package lean, as
+// analyses and transformations are added.
+package irutil
@@ -0,0 +1,66 @@
+package irutil
+
+import (
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+// ConstantPiece is a leaf constant of an aggregate constant, located at a given
+// byte offset within the memory image of the aggregate.
+type ConstantPiece struct {
+	// Byte offset of the leaf constant, relative to the start of the aggregate.
+	Offset uint64
+	// Size in bytes of the leaf constant (i.e. its store size).
+	Size uint64
+	// Leaf constant.
+	Const constant.Constant
+}
+
+// LayoutConstant returns the leaf constants of the given constant, in order of
+// byte offset, with nested aggregate constants (arrays, structs and vectors)
+// flattened based on the memory layout of the data layout dl. Bytes not covered
+// by any piece are alignment padding.
+//
+// Leaf constants include scalars (integers, floating-point values and
+// pointers), constant expressions, character arrays, zeroinitializer and undef
+// constants. In particular, zeroinitializer and undef constants of aggregate
+// type are not expanded, but are returned as a single piece spanning the
+// aggregate. Vectors with elements which are not a whole number of bytes in
+// size (e.g. <8 x i1>) are bit-packed, and returned as a single piece.
+func LayoutConstant(dl *types.DataLayout, c constant.Constant) []ConstantPiece {
+	return layoutConstant(nil, dl, c, 0)
+}
+
+// layoutConstant appends the leaf constants of c, located at the given byte
+// offset, to pieces.
+func layoutConstant(pieces []ConstantPiece, dl *types.DataLayout, c constant.Constant, offset uint64) []ConstantPiece {
+	switch c := c.(type) {
+	case *constant.Array:
+		stride := dl.AllocSize(c.Typ.ElemType) / 8
+		for i, elem := range c.Elems {
+			pieces = layoutConstant(pieces, dl, elem, offset+uint64(i)*stride)
+		}
+		return pieces
+	case *constant.Struct:
+		layout := dl.StructLayout(c.Typ)
+		for i, field := range c.Fields {
+			pieces = layoutConstant(pieces, dl, field, offset+layout.Offsets[i]/8)
+		}
+		return pieces
+	case *constant.Vector:
+		elemSize := dl.TypeSize(c.Typ.ElemType)
+		if elemSize%8 != 0 {
+			break
+		}
+		for i, elem := range c.Elems {
+			pieces = layoutConstant(pieces, dl, elem, offset+uint64(i)*elemSize/8)
+		}
+		return pieces
+	}
+	size := dl.StoreSize(c.Type()) / 8
+	if size == 0 {
+		// Skip empty aggregates (e.g. zeroinitializer of type {}).
+		return pieces
+	}
+	return append(pieces, ConstantPiece{Offset: offset, Size: size, Const: c})
+}
@@ -0,0 +1,51 @@
+package irutil
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestLayoutConstant(t *testing.T) {
+	dl, err := types.NewDataLayout("e-m:e-i64:64-f80:128-n8:16:32:64-S128")
+	if err != nil {
+		t.Fatalf("unable to parse data layout; %v", err)
+	}
+	g := ir.NewGlobalDef("g", constant.NewInt(types.I32, 0))
+	// { i8, i32, [2 x { i16, i8 }], i8*, [1000000 x i64] }
+	elemType := types.NewStruct(types.I16, types.I8)
+	bigType := types.NewArray(1000000, types.I64)
+	typ := types.NewStruct(types.I8, types.I32, types.NewArray(2, elemType), types.I8Ptr, bigType)
+	one := constant.NewInt(types.I8, 1)
+	two := constant.NewInt(types.I32, 2)
+	elem := constant.NewStruct(elemType, constant.NewInt(types.I16, 3), constant.NewInt(types.I8, 4))
+	zero := constant.NewZeroInitializer(elemType)
+	ptr := constant.NewBitCast(g, types.I8Ptr)
+	big := constant.NewZeroInitializer(bigType)
+	c := constant.NewStruct(typ, one, two, constant.NewArray(types.NewArray(2, elemType), elem, zero), ptr, big)
+	want := []ConstantPiece{
+		{Offset: 0, Size: 1, Const: one},
+		{Offset: 4, Size: 4, Const: two},
+		{Offset: 8, Size: 2, Const: elem.Fields[0]},
+		{Offset: 10, Size: 1, Const: elem.Fields[1]},
+		{Offset: 12, Size: 4, Const: zero},
+		{Offset: 16, Size: 8, Const: ptr},
+		{Offset: 24, Size: 8000000, Const: big},
+	}
+	got := LayoutConstant(dl, c)
+	if len(want) != len(got) {
+		t.Fatalf("number of pieces mismatch; expected %d, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if want[i] != got[i] {
+			t.Errorf("piece %d mismatch; expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+	// Bit-packed vector.
+	vec := constant.NewVector(types.NewVector(8, types.I1), constant.True, constant.False, constant.True, constant.False, constant.True, constant.False, constant.True, constant.False)
+	if got := LayoutConstant(dl, vec); len(got) != 1 || got[0].Size != 1 || got[0].Const != vec {
+		t.Errorf("bit-packed vector layout mismatch; expected single piece of size 1, got %+v", got)
+	}
+}
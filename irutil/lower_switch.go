@@ -0,0 +1,159 @@
+package irutil
+
+import (
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+	"github.com/pkg/errors"
+)
+
+// --- [ Switch lowering ] -----------------------------------------------------
+
+// LowerSwitch replaces the given switch terminator of a basic block in function
+// f with a chain of icmp instructions and conditional br terminators, for use
+// with backends which do not support switch.
+//
+// The first comparison is placed in the basic block of the switch terminator;
+// subsequent comparisons are placed in new basic blocks inserted directly after
+// it. Cases are compared in order, after which control is transferred to the
+// default target. Phi instructions of the targets are updated to reference the
+// new predecessor basic blocks.
+//
+// An error is returned if the switch terminator is not the terminator of a
+// basic block in f, if the control variable or case comparands are not of
+// integer type, or if a phi instruction of a target lacks an incoming value for
+// the basic block of the switch terminator. The function is left unmodified on
+// error.
+func LowerSwitch(f *ir.Func, sw *ir.TermSwitch) error {
+	// Validate block structure before rewriting.
+	block := switchBlock(f, sw)
+	if block == nil {
+		return errors.Errorf("unable to locate switch terminator in function %s", f.Ident())
+	}
+	if !types.IsInt(sw.X.Type()) {
+		return errors.Errorf("invalid switch control variable type in basic block %s; expected integer type, got %s", block.Ident(), sw.X.Type())
+	}
+	for _, c := range sw.Cases {
+		if !c.X.Type().Equal(sw.X.Type()) {
+			return errors.Errorf("invalid switch case comparand type in basic block %s; expected %s, got %s", block.Ident(), sw.X.Type(), c.X.Type())
+		}
+	}
+	targets := uniqueBlocks(sw.Succs())
+	for _, target := range targets {
+		for _, inst := range target.Insts {
+			phi, ok := inst.(*ir.InstPhi)
+			if !ok {
+				break
+			}
+			if incomingFrom(phi, block) == nil {
+				return errors.Errorf("missing incoming value from basic block %s in phi instruction %s of basic block %s", block.Ident(), phi.Ident(), target.Ident())
+			}
+		}
+	}
+	// Create comparison chain; the edges of the chain are recorded to update
+	// phi instructions of targets.
+	type edge struct {
+		from, to *ir.Block
+	}
+	var edges []edge
+	var chain []*ir.Block
+	cur := block
+	for i, c := range sw.Cases {
+		cond := ir.NewICmp(enum.IPredEQ, sw.X, c.X)
+		cond.Metadata = switchMetadata(sw)
+		cond.Parent = cur
+		cur.Insts = append(cur.Insts, cond)
+		next := sw.TargetDefault
+		if i < len(sw.Cases)-1 {
+			next = ir.NewBlock("")
+			next.Parent = f
+			chain = append(chain, next)
+		}
+		term := ir.NewCondBr(cond, c.Target, next)
+		term.Metadata = switchMetadata(sw)
+		term.Parent = cur
+		cur.Term = term
+		edges = append(edges, edge{from: cur, to: c.Target}, edge{from: cur, to: next})
+		cur = next
+	}
+	if len(sw.Cases) == 0 {
+		term := ir.NewBr(sw.TargetDefault)
+		term.Metadata = switchMetadata(sw)
+		term.Parent = block
+		block.Term = term
+		edges = append(edges, edge{from: block, to: sw.TargetDefault})
+	}
+	// Insert new basic blocks after the basic block of the switch terminator.
+	for i, b := range f.Blocks {
+		if b == block {
+			blocks := make([]*ir.Block, 0, len(f.Blocks)+len(chain))
+			blocks = append(blocks, f.Blocks[:i+1]...)
+			blocks = append(blocks, chain...)
+			blocks = append(blocks, f.Blocks[i+1:]...)
+			f.Blocks = blocks
+			break
+		}
+	}
+	// Update phi instructions of targets, with one incoming value per edge.
+	for _, target := range targets {
+		for _, inst := range target.Insts {
+			phi, ok := inst.(*ir.InstPhi)
+			if !ok {
+				break
+			}
+			x := incomingFrom(phi, block).X
+			var incs []*ir.Incoming
+			for _, inc := range phi.Incs {
+				if inc.Pred != block {
+					incs = append(incs, inc)
+				}
+			}
+			for _, e := range edges {
+				if e.to == target {
+					incs = append(incs, ir.NewIncoming(x, e.from))
+				}
+			}
+			phi.Incs = incs
+		}
+	}
+	f.ResetIDs()
+	return nil
+}
+
+// ### [ Helper functions ] ####################################################
+
+// switchMetadata returns a copy of the metadata attachments of the given switch
+// terminator (e.g. !dbg) to attach to the instructions and terminators of the
+// lowered comparison chain. Branch weights (!prof) are omitted, as they do not
+// apply to the conditional branches of the chain.
+func switchMetadata(sw *ir.TermSwitch) ir.Metadata {
+	var mds ir.Metadata
+	for _, md := range sw.Metadata {
+		if md.Name != "prof" {
+			mds = append(mds, md)
+		}
+	}
+	return mds
+}
+
+// switchBlock returns the basic block of function f terminated by the given
+// switch terminator, or nil if not present.
+func switchBlock(f *ir.Func, sw *ir.TermSwitch) *ir.Block {
+	for _, block := range f.Blocks {
+		if block.Term == sw {
+			return block
+		}
+	}
+	return nil
+}
+
+// incomingFrom returns the first incoming value of the given phi instruction
+// from the predecessor basic block, or nil if not present.
+func incomingFrom(phi *ir.InstPhi, pred *ir.Block) *ir.Incoming {
+	for _, inc := range phi.Incs {
+		if inc.Pred == pred {
+			return inc
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package irutil
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/llir/llvm/asm"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestLowerSwitch(t *testing.T) {
+	golden := []struct {
+		path string
+		want string
+	}{
+		{path: "testdata/lower_switch.ll", want: "testdata/lower_switch.ll.golden"},
+	}
+	for _, g := range golden {
+		m, err := asm.ParseFile(g.path)
+		if err != nil {
+			t.Errorf("unable to parse %q into AST; %+v", g.path, err)
+			continue
+		}
+		for _, f := range m.Funcs {
+			for _, block := range f.Blocks {
+				if sw, ok := block.Term.(*ir.TermSwitch); ok {
+					if err := LowerSwitch(f, sw); err != nil {
+						t.Errorf("unable to lower switch of %q; %v", g.path, err)
+					}
+				}
+			}
+		}
+		if err := m.Verify(); err != nil {
+			t.Errorf("invalid module after lowering switch of %q; %v", g.path, err)
+		}
+		buf, err := ioutil.ReadFile(g.want)
+		if err != nil {
+			t.Errorf("unable to read %q; %v", g.want, err)
+			continue
+		}
+		if got, want := m.String(), string(buf); got != want {
+			t.Errorf("module mismatch after lowering switch of %q; expected:\n%s\ngot:\n%s", g.path, want, got)
+		}
+	}
+	// Switch terminator not within function.
+	m := ir.NewModule()
+	f := m.NewFunc("f", types.Void, ir.NewParam("x", types.I32))
+	entry := f.NewBlock("entry")
+	entry.NewRet(nil)
+	sw := ir.NewSwitch(f.Params[0], entry, ir.NewCase(constant.NewInt(types.I32, 1), entry))
+	if err := LowerSwitch(f, sw); err == nil {
+		t.Errorf("expected error for switch terminator not within function, got nil")
+	}
+}
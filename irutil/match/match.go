@@ -0,0 +1,514 @@
+// Package match implements composable pattern matchers for LLVM IR values, as
+// useful for writing peephole optimizations.
+//
+// Patterns are composed of leaf patterns (e.g. Value, ConstInt, Specific)
+// and instruction patterns (e.g. Add, ICmp, ZExt) matching the operands of an
+// instruction against nested patterns. Captures are bound only if the entire
+// pattern matches.
+//
+// Example:
+//
+//    var a value.Value
+//    var c *constant.Int
+//    if match.Add(match.Value(&a), match.ConstInt(&c)).Match(inst) {
+//       // inst is `add a, c`
+//    }
+//
+// Binary instruction patterns match their operands in order. Use Commutative
+// to also match the operands in swapped order.
+//
+// Inspired by the PatternMatch helpers of LLVM.
+//
+// ref: https://github.com/llvm/llvm-project/blob/main/llvm/include/llvm/IR/PatternMatch.h
+package match
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/value"
+)
+
+// Pattern is a pattern matching LLVM IR values.
+type Pattern struct {
+	// Reports whether the given value matches the pattern, and records captures
+	// to bind on success.
+	match func(v value.Value, b *binder) bool
+	// Returns the pattern with operands swapped; nil if the pattern cannot be
+	// commuted.
+	commute func() Pattern
+}
+
+// Match reports whether the given value matches the pattern. Captures of the
+// pattern are bound if the value matches, and left unmodified otherwise.
+func (p Pattern) Match(v value.Value) bool {
+	var b binder
+	if !p.match(v, &b) {
+		return false
+	}
+	for _, bind := range b {
+		bind()
+	}
+	return true
+}
+
+// binder records the captures of a pattern match, to bind on success.
+type binder []func()
+
+// add records the given capture.
+func (b *binder) add(bind func()) {
+	*b = append(*b, bind)
+}
+
+// reset discards captures recorded after the first n captures.
+func (b *binder) reset(n int) {
+	*b = (*b)[:n]
+}
+
+// --- [ Leaf patterns ] -------------------------------------------------------
+
+// Any returns a pattern matching any value.
+func Any() Pattern {
+	return Pattern{match: func(v value.Value, b *binder) bool {
+		return true
+	}}
+}
+
+// Value returns a pattern matching any value, and capturing it in v.
+func Value(v *value.Value) Pattern {
+	return Bind(v, Any())
+}
+
+// Bind returns a pattern matching the given pattern, and capturing the matched
+// value in v. Bind is used to capture nested instructions.
+func Bind(v *value.Value, p Pattern) Pattern {
+	return Pattern{match: func(x value.Value, b *binder) bool {
+		if !p.match(x, b) {
+			return false
+		}
+		b.add(func() { *v = x })
+		return true
+	}}
+}
+
+// Specific returns a pattern matching only the given value.
+func Specific(v value.Value) Pattern {
+	return Pattern{match: func(x value.Value, b *binder) bool {
+		return x == v
+	}}
+}
+
+// Const returns a pattern matching any constant, and capturing it in c.
+func Const(c *constant.Constant) Pattern {
+	return Pattern{match: func(v value.Value, b *binder) bool {
+		x, ok := v.(constant.Constant)
+		if !ok {
+			return false
+		}
+		b.add(func() { *c = x })
+		return true
+	}}
+}
+
+// ConstInt returns a pattern matching any integer constant, and capturing it in
+// c.
+func ConstInt(c **constant.Int) Pattern {
+	return Pattern{match: func(v value.Value, b *binder) bool {
+		x, ok := v.(*constant.Int)
+		if !ok {
+			return false
+		}
+		b.add(func() { *c = x })
+		return true
+	}}
+}
+
+// Int returns a pattern matching integer constants of the given value.
+func Int(x int64) Pattern {
+	want := big.NewInt(x)
+	return Pattern{match: func(v value.Value, b *binder) bool {
+		c, ok := v.(*constant.Int)
+		return ok && c.X.Cmp(want) == 0
+	}}
+}
+
+// --- [ Binary instructions ] -------------------------------------------------
+
+// Add returns a pattern matching add instructions with operands x and y.
+func Add(x, y Pattern) Pattern {
+	return binary(x, y, func(v value.Value) (value.Value, value.Value, bool) {
+		if inst, ok := v.(*ir.InstAdd); ok {
+			return inst.X, inst.Y, true
+		}
+		return nil, nil, false
+	})
+}
+
+// FAdd returns a pattern matching fadd instructions with operands x and y.
+func FAdd(x, y Pattern) Pattern {
+	return binary(x, y, func(v value.Value) (value.Value, value.Value, bool) {
+		if inst, ok := v.(*ir.InstFAdd); ok {
+			return inst.X, inst.Y, true
+		}
+		return nil, nil, false
+	})
+}
+
+// Sub returns a pattern matching sub instructions with operands x and y.
+func Sub(x, y Pattern) Pattern {
+	return binary(x, y, func(v value.Value) (value.Value, value.Value, bool) {
+		if inst, ok := v.(*ir.InstSub); ok {
+			return inst.X, inst.Y, true
+		}
+		return nil, nil, false
+	})
+}
+
+// FSub returns a pattern matching fsub instructions with operands x and y.
+func FSub(x, y Pattern) Pattern {
+	return binary(x, y, func(v value.Value) (value.Value, value.Value, bool) {
+		if inst, ok := v.(*ir.InstFSub); ok {
+			return inst.X, inst.Y, true
+		}
+		return nil, nil, false
+	})
+}
+
+// Mul returns a pattern matching mul instructions with operands x and y.
+func Mul(x, y Pattern) Pattern {
+	return binary(x, y, func(v value.Value) (value.Value, value.Value, bool) {
+		if inst, ok := v.(*ir.InstMul); ok {
+			return inst.X, inst.Y, true
+		}
+		return nil, nil, false
+	})
+}
+
+// FMul returns a pattern matching fmul instructions with operands x and y.
+func FMul(x, y Pattern) Pattern {
+	return binary(x, y, func(v value.Value) (value.Value, value.Value, bool) {
+		if inst, ok := v.(*ir.InstFMul); ok {
+			return inst.X, inst.Y, true
+		}
+		return nil, nil, false
+	})
+}
+
+// UDiv returns a pattern matching udiv instructions with operands x and y.
+func UDiv(x, y Pattern) Pattern {
+	return binary(x, y, func(v value.Value) (value.Value, value.Value, bool) {
+		if inst, ok := v.(*ir.InstUDiv); ok {
+			return inst.X, inst.Y, true
+		}
+		return nil, nil, false
+	})
+}
+
+// SDiv returns a pattern matching sdiv instructions with operands x and y.
+func SDiv(x, y Pattern) Pattern {
+	return binary(x, y, func(v value.Value) (value.Value, value.Value, bool) {
+		if inst, ok := v.(*ir.InstSDiv); ok {
+			return inst.X, inst.Y, true
+		}
+		return nil, nil, false
+	})
+}
+
+// FDiv returns a pattern matching fdiv instructions with operands x and y.
+func FDiv(x, y Pattern) Pattern {
+	return binary(x, y, func(v value.Value) (value.Value, value.Value, bool) {
+		if inst, ok := v.(*ir.InstFDiv); ok {
+			return inst.X, inst.Y, true
+		}
+		return nil, nil, false
+	})
+}
+
+// URem returns a pattern matching urem instructions with operands x and y.
+func URem(x, y Pattern) Pattern {
+	return binary(x, y, func(v value.Value) (value.Value, value.Value, bool) {
+		if inst, ok := v.(*ir.InstURem); ok {
+			return inst.X, inst.Y, true
+		}
+		return nil, nil, false
+	})
+}
+
+// SRem returns a pattern matching srem instructions with operands x and y.
+func SRem(x, y Pattern) Pattern {
+	return binary(x, y, func(v value.Value) (value.Value, value.Value, bool) {
+		if inst, ok := v.(*ir.InstSRem); ok {
+			return inst.X, inst.Y, true
+		}
+		return nil, nil, false
+	})
+}
+
+// FRem returns a pattern matching frem instructions with operands x and y.
+func FRem(x, y Pattern) Pattern {
+	return binary(x, y, func(v value.Value) (value.Value, value.Value, bool) {
+		if inst, ok := v.(*ir.InstFRem); ok {
+			return inst.X, inst.Y, true
+		}
+		return nil, nil, false
+	})
+}
+
+// --- [ Bitwise instructions ] ------------------------------------------------
+
+// Shl returns a pattern matching shl instructions with operands x and y.
+func Shl(x, y Pattern) Pattern {
+	return binary(x, y, func(v value.Value) (value.Value, value.Value, bool) {
+		if inst, ok := v.(*ir.InstShl); ok {
+			return inst.X, inst.Y, true
+		}
+		return nil, nil, false
+	})
+}
+
+// LShr returns a pattern matching lshr instructions with operands x and y.
+func LShr(x, y Pattern) Pattern {
+	return binary(x, y, func(v value.Value) (value.Value, value.Value, bool) {
+		if inst, ok := v.(*ir.InstLShr); ok {
+			return inst.X, inst.Y, true
+		}
+		return nil, nil, false
+	})
+}
+
+// AShr returns a pattern matching ashr instructions with operands x and y.
+func AShr(x, y Pattern) Pattern {
+	return binary(x, y, func(v value.Value) (value.Value, value.Value, bool) {
+		if inst, ok := v.(*ir.InstAShr); ok {
+			return inst.X, inst.Y, true
+		}
+		return nil, nil, false
+	})
+}
+
+// And returns a pattern matching and instructions with operands x and y.
+func And(x, y Pattern) Pattern {
+	return binary(x, y, func(v value.Value) (value.Value, value.Value, bool) {
+		if inst, ok := v.(*ir.InstAnd); ok {
+			return inst.X, inst.Y, true
+		}
+		return nil, nil, false
+	})
+}
+
+// Or returns a pattern matching or instructions with operands x and y.
+func Or(x, y Pattern) Pattern {
+	return binary(x, y, func(v value.Value) (value.Value, value.Value, bool) {
+		if inst, ok := v.(*ir.InstOr); ok {
+			return inst.X, inst.Y, true
+		}
+		return nil, nil, false
+	})
+}
+
+// Xor returns a pattern matching xor instructions with operands x and y.
+func Xor(x, y Pattern) Pattern {
+	return binary(x, y, func(v value.Value) (value.Value, value.Value, bool) {
+		if inst, ok := v.(*ir.InstXor); ok {
+			return inst.X, inst.Y, true
+		}
+		return nil, nil, false
+	})
+}
+
+// binary returns a pattern matching binary instructions with operands x and y,
+// as located by the given operands function.
+func binary(x, y Pattern, operands func(v value.Value) (value.Value, value.Value, bool)) Pattern {
+	return Pattern{
+		match: func(v value.Value, b *binder) bool {
+			vx, vy, ok := operands(v)
+			return ok && x.match(vx, b) && y.match(vy, b)
+		},
+		commute: func() Pattern {
+			return binary(y, x, operands)
+		},
+	}
+}
+
+// --- [ Comparison instructions ] ---------------------------------------------
+
+// ICmp returns a pattern matching icmp instructions with operands x and y, and
+// capturing the integer comparison predicate in pred (if non-nil).
+func ICmp(pred *enum.IPred, x, y Pattern) Pattern {
+	return icmp(pred, x, y, false)
+}
+
+// icmp returns a pattern matching icmp instructions with operands x and y, and
+// capturing the integer comparison predicate in pred (if non-nil). The
+// predicate is captured with operands swapped if swapped is set.
+func icmp(pred *enum.IPred, x, y Pattern, swapped bool) Pattern {
+	return Pattern{
+		match: func(v value.Value, b *binder) bool {
+			inst, ok := v.(*ir.InstICmp)
+			if !ok || !x.match(inst.X, b) || !y.match(inst.Y, b) {
+				return false
+			}
+			if pred != nil {
+				p := inst.Pred
+				if swapped {
+					p = swappedIPred(p)
+				}
+				b.add(func() { *pred = p })
+			}
+			return true
+		},
+		commute: func() Pattern {
+			return icmp(pred, y, x, !swapped)
+		},
+	}
+}
+
+// FCmp returns a pattern matching fcmp instructions with operands x and y, and
+// capturing the floating-point comparison predicate in pred (if non-nil).
+func FCmp(pred *enum.FPred, x, y Pattern) Pattern {
+	return Pattern{match: func(v value.Value, b *binder) bool {
+		inst, ok := v.(*ir.InstFCmp)
+		if !ok || !x.match(inst.X, b) || !y.match(inst.Y, b) {
+			return false
+		}
+		if pred != nil {
+			p := inst.Pred
+			b.add(func() { *pred = p })
+		}
+		return true
+	}}
+}
+
+// swappedIPred returns the integer comparison predicate of equivalent
+// comparison with operands swapped.
+func swappedIPred(pred enum.IPred) enum.IPred {
+	switch pred {
+	case enum.IPredSGE:
+		return enum.IPredSLE
+	case enum.IPredSGT:
+		return enum.IPredSLT
+	case enum.IPredSLE:
+		return enum.IPredSGE
+	case enum.IPredSLT:
+		return enum.IPredSGT
+	case enum.IPredUGE:
+		return enum.IPredULE
+	case enum.IPredUGT:
+		return enum.IPredULT
+	case enum.IPredULE:
+		return enum.IPredUGE
+	case enum.IPredULT:
+		return enum.IPredUGT
+	}
+	// eq and ne are symmetric.
+	return pred
+}
+
+// --- [ Conversion instructions ] ---------------------------------------------
+
+// Trunc returns a pattern matching trunc instructions with operand x.
+func Trunc(x Pattern) Pattern {
+	return unary(x, func(v value.Value) (value.Value, bool) {
+		if inst, ok := v.(*ir.InstTrunc); ok {
+			return inst.From, true
+		}
+		return nil, false
+	})
+}
+
+// ZExt returns a pattern matching zext instructions with operand x.
+func ZExt(x Pattern) Pattern {
+	return unary(x, func(v value.Value) (value.Value, bool) {
+		if inst, ok := v.(*ir.InstZExt); ok {
+			return inst.From, true
+		}
+		return nil, false
+	})
+}
+
+// SExt returns a pattern matching sext instructions with operand x.
+func SExt(x Pattern) Pattern {
+	return unary(x, func(v value.Value) (value.Value, bool) {
+		if inst, ok := v.(*ir.InstSExt); ok {
+			return inst.From, true
+		}
+		return nil, false
+	})
+}
+
+// PtrToInt returns a pattern matching ptrtoint instructions with operand x.
+func PtrToInt(x Pattern) Pattern {
+	return unary(x, func(v value.Value) (value.Value, bool) {
+		if inst, ok := v.(*ir.InstPtrToInt); ok {
+			return inst.From, true
+		}
+		return nil, false
+	})
+}
+
+// IntToPtr returns a pattern matching inttoptr instructions with operand x.
+func IntToPtr(x Pattern) Pattern {
+	return unary(x, func(v value.Value) (value.Value, bool) {
+		if inst, ok := v.(*ir.InstIntToPtr); ok {
+			return inst.From, true
+		}
+		return nil, false
+	})
+}
+
+// BitCast returns a pattern matching bitcast instructions with operand x.
+func BitCast(x Pattern) Pattern {
+	return unary(x, func(v value.Value) (value.Value, bool) {
+		if inst, ok := v.(*ir.InstBitCast); ok {
+			return inst.From, true
+		}
+		return nil, false
+	})
+}
+
+// unary returns a pattern matching instructions with a single operand x, as
+// located by the given operand function.
+func unary(x Pattern, operand func(v value.Value) (value.Value, bool)) Pattern {
+	return Pattern{match: func(v value.Value, b *binder) bool {
+		vx, ok := operand(v)
+		return ok && x.match(vx, b)
+	}}
+}
+
+// --- [ Other instructions ] --------------------------------------------------
+
+// Select returns a pattern matching select instructions with condition cond
+// and operands x and y.
+func Select(cond, x, y Pattern) Pattern {
+	return Pattern{match: func(v value.Value, b *binder) bool {
+		inst, ok := v.(*ir.InstSelect)
+		return ok && cond.match(inst.Cond, b) && x.match(inst.X, b) && y.match(inst.Y, b)
+	}}
+}
+
+// --- [ Commutative patterns ] ------------------------------------------------
+
+// Commutative returns a pattern matching the given binary instruction or icmp
+// pattern with operands in either order. The predicate captured by commuted
+// icmp patterns is adjusted for the swapped operands (e.g. sgt instead of slt).
+//
+// Commutative panics if the given pattern is not a binary instruction or icmp
+// pattern. Patterns of non-commutative instructions (e.g. sub) are commuted as
+// well, which is useful to match a given operand on either side.
+func Commutative(p Pattern) Pattern {
+	if p.commute == nil {
+		panic(fmt.Errorf("invalid pattern of Commutative; expected binary instruction or icmp pattern"))
+	}
+	swapped := p.commute()
+	return Pattern{match: func(v value.Value, b *binder) bool {
+		n := len(*b)
+		if p.match(v, b) {
+			return true
+		}
+		b.reset(n)
+		return swapped.match(v, b)
+	}}
+}
@@ -0,0 +1,91 @@
+package match
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+func TestMatch(t *testing.T) {
+	x := ir.NewParam("x", types.I32)
+	y := ir.NewParam("y", types.I32)
+	f := ir.NewFunc("f", types.I32, x, y)
+	entry := f.NewBlock("entry")
+	one := constant.NewInt(types.I32, 1)
+	add := entry.NewAdd(x, one)           // add x, 1
+	addC := entry.NewAdd(one, x)          // add 1, x
+	mul := entry.NewMul(add, y)           // mul (add x, 1), y
+	zext := entry.NewZExt(add, types.I64) // zext (add x, 1)
+	cmp := entry.NewICmp(enum.IPredSLT, one, x)
+
+	// Leaf patterns and captures.
+	var a value.Value
+	var c *constant.Int
+	if !Add(Value(&a), ConstInt(&c)).Match(add) {
+		t.Errorf("expected match of %q", add.LLString())
+	}
+	if a != x || c != one {
+		t.Errorf("capture mismatch; expected %v and %v, got %v and %v", x, one, a, c)
+	}
+	if !Add(Specific(x), Int(1)).Match(add) {
+		t.Errorf("expected match of %q", add.LLString())
+	}
+	if Add(Specific(x), Int(2)).Match(add) {
+		t.Errorf("unexpected match of %q", add.LLString())
+	}
+
+	// Captures are left unmodified if the pattern does not match.
+	a, c = nil, nil
+	if Add(Value(&a), ConstInt(&c)).Match(addC) {
+		t.Errorf("unexpected match of %q", addC.LLString())
+	}
+	if a != nil || c != nil {
+		t.Errorf("unexpected captures of failed match; got %v and %v", a, c)
+	}
+
+	// Commutative patterns.
+	if !Commutative(Add(Value(&a), ConstInt(&c))).Match(addC) {
+		t.Errorf("expected commutative match of %q", addC.LLString())
+	}
+	if a != x || c != one {
+		t.Errorf("capture mismatch; expected %v and %v, got %v and %v", x, one, a, c)
+	}
+	var pred enum.IPred
+	if !Commutative(ICmp(&pred, Specific(x), ConstInt(&c))).Match(cmp) {
+		t.Errorf("expected commutative match of %q", cmp.LLString())
+	}
+	if pred != enum.IPredSGT {
+		t.Errorf("predicate mismatch; expected %v, got %v", enum.IPredSGT, pred)
+	}
+	if !ICmp(&pred, ConstInt(&c), Specific(x)).Match(cmp) || pred != enum.IPredSLT {
+		t.Errorf("expected match of %q with predicate %v, got %v", cmp.LLString(), enum.IPredSLT, pred)
+	}
+
+	// Nested patterns.
+	var inner value.Value
+	if !Mul(Bind(&inner, Add(Specific(x), Int(1))), Specific(y)).Match(mul) {
+		t.Errorf("expected match of %q", mul.LLString())
+	}
+	if inner != add {
+		t.Errorf("capture mismatch; expected %v, got %v", add, inner)
+	}
+	if !ZExt(Add(Any(), Any())).Match(zext) {
+		t.Errorf("expected match of %q", zext.LLString())
+	}
+	if SExt(Any()).Match(zext) || Sub(Any(), Any()).Match(add) {
+		t.Errorf("unexpected match of instruction kind")
+	}
+}
+
+func TestCommutativeInvalidPattern(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for commutative zext pattern")
+		}
+	}()
+	Commutative(ZExt(Any()))
+}
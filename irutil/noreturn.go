@@ -0,0 +1,125 @@
+package irutil
+
+import (
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// TruncateAfterNoReturn truncates the basic blocks of function f after calls
+// which do not return, and returns the number of basic blocks modified.
+//
+// A call does not return if the call instruction or the directly called
+// function has the noreturn function attribute (including attributes of
+// referenced attribute groups). The instructions following the first such call
+// of a basic block are removed, and the terminator of the basic block is
+// replaced by an unreachable terminator. Incoming values of the basic block
+// are removed from phi instructions of its former successors, and uses of the
+// removed instructions elsewhere (e.g. in basic blocks which are now
+// unreachable) are replaced by undefined values. Phi instructions left without
+// incoming values are removed, and their uses replaced by undefined values.
+//
+// Basic blocks already terminated by an unreachable terminator directly after
+// the call are left unmodified.
+func TruncateAfterNoReturn(f *ir.Func) int {
+	n := 0
+	for _, block := range f.Blocks {
+		if truncateAfterNoReturn(f, block) {
+			n++
+		}
+	}
+	if n > 0 {
+		f.ResetIDs()
+	}
+	return n
+}
+
+// truncateAfterNoReturn truncates the given basic block of function f after the
+// first call which does not return, and reports whether the basic block was
+// modified.
+func truncateAfterNoReturn(f *ir.Func, block *ir.Block) bool {
+	for i, inst := range block.Insts {
+		call, ok := inst.(*ir.InstCall)
+		if !ok || !doesNotReturn(call) {
+			continue
+		}
+		dead := block.Insts[i+1:]
+		if _, ok := block.Term.(*ir.TermUnreachable); ok && len(dead) == 0 {
+			return false
+		}
+		removed := make([]ir.Instruction, len(dead), len(dead)+1)
+		copy(removed, dead)
+		if term, ok := block.Term.(ir.Instruction); ok {
+			removed = append(removed, term)
+		}
+		var succs []*ir.Block
+		if block.Term != nil {
+			succs = block.Term.Succs()
+		}
+		block.Insts = block.Insts[:i+1]
+		block.NewUnreachable()
+		for _, succ := range succs {
+			removeIncoming(f, succ, block)
+		}
+		for _, inst := range removed {
+			v, ok := inst.(value.Value)
+			if !ok || types.Equal(v.Type(), types.Void) || types.Equal(v.Type(), types.Token) {
+				continue
+			}
+			ReplaceAllUsesWith(f, v, constant.NewUndef(v.Type()))
+		}
+		return true
+	}
+	return false
+}
+
+// doesNotReturn reports whether the given call instruction is known not to
+// return, based on the noreturn function attribute of the call instruction and
+// the directly called function.
+func doesNotReturn(call *ir.InstCall) bool {
+	if hasFuncAttr(call.FuncAttrs, enum.FuncAttrNoReturn) {
+		return true
+	}
+	callee, ok := call.Callee.(*ir.Func)
+	return ok && hasFuncAttr(callee.FuncAttrs, enum.FuncAttrNoReturn)
+}
+
+// removeIncoming removes the incoming values of predecessor basic block pred
+// from the phi instructions of the given basic block in function f. Phi
+// instructions without remaining incoming values are removed, and their uses
+// replaced by undefined values.
+func removeIncoming(f *ir.Func, block, pred *ir.Block) {
+	var empty []*ir.InstPhi
+	for _, inst := range block.Insts {
+		phi, ok := inst.(*ir.InstPhi)
+		if !ok {
+			continue
+		}
+		incs := phi.Incs[:0]
+		for _, inc := range phi.Incs {
+			if inc.Pred != pred {
+				incs = append(incs, inc)
+			}
+		}
+		phi.Incs = incs
+		if len(incs) == 0 {
+			empty = append(empty, phi)
+		}
+	}
+	if len(empty) == 0 {
+		return
+	}
+	insts := block.Insts[:0]
+	for _, inst := range block.Insts {
+		if phi, ok := inst.(*ir.InstPhi); ok && len(phi.Incs) == 0 {
+			continue
+		}
+		insts = append(insts, inst)
+	}
+	block.Insts = insts
+	for _, phi := range empty {
+		ReplaceAllUsesWith(f, phi, constant.NewUndef(phi.Type()))
+	}
+}
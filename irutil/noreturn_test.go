@@ -0,0 +1,136 @@
+package irutil
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/asm"
+)
+
+func TestTruncateAfterNoReturn(t *testing.T) {
+	const src = `
+declare void @abort() noreturn
+
+declare void @exit(i32) #0
+
+declare void @g()
+
+define i32 @f(i32 %x) {
+entry:
+	switch i32 %x, label %join [
+		i32 1, label %a
+		i32 2, label %b
+		i32 3, label %c
+	]
+
+a:
+	call void @abort()
+	%y = add i32 %x, 1
+	call void @g()
+	br label %a.1
+
+a.1:
+	%w = mul i32 %y, 2
+	br label %join
+
+b:
+	call void @g() noreturn
+	br label %join
+
+c:
+	%z = mul i32 %x, 2
+	call void @exit(i32 %z)
+	br label %join
+
+join:
+	%r = phi i32 [ %x, %entry ], [ %w, %a.1 ], [ 0, %b ], [ %z, %c ]
+	ret i32 %r
+}
+
+define i32 @h(i32 %x) {
+entry:
+	call void @abort()
+	br label %next
+
+next:
+	%p = phi i32 [ %x, %entry ]
+	%q = add i32 %p, 1
+	ret i32 %q
+}
+
+attributes #0 = { noreturn nounwind }
+`
+	m, err := asm.ParseString("", src)
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	f := m.Funcs[3]
+	if n := TruncateAfterNoReturn(f); n != 3 {
+		t.Errorf("number of modified basic blocks mismatch; expected 3, got %d", n)
+	}
+	want := `define i32 @f(i32 %x) {
+entry:
+	switch i32 %x, label %join [
+		i32 1, label %a
+		i32 2, label %b
+		i32 3, label %c
+	]
+
+a:
+	call void @abort()
+	unreachable
+
+a.1:
+	%w = mul i32 undef, 2
+	br label %join
+
+b:
+	call void @g() noreturn
+	unreachable
+
+c:
+	%z = mul i32 %x, 2
+	call void @exit(i32 %z)
+	unreachable
+
+join:
+	%r = phi i32 [ %x, %entry ], [ %w, %a.1 ]
+	ret i32 %r
+}`
+	if got := f.LLString(); got != want {
+		t.Errorf("function mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	// The transformed module is valid LLVM IR assembly.
+	if _, err := asm.ParseString("", m.String()); err != nil {
+		t.Errorf("unable to parse transformed module; %+v", err)
+	}
+	if n := TruncateAfterNoReturn(f); n != 0 {
+		t.Errorf("number of modified basic blocks mismatch; expected 0, got %d", n)
+	}
+	// Phi instructions of basic blocks whose only predecessor is truncated are
+	// removed.
+	h := m.Funcs[4]
+	if n := TruncateAfterNoReturn(h); n != 1 {
+		t.Errorf("number of modified basic blocks mismatch; expected 1, got %d", n)
+	}
+	want = `define i32 @h(i32 %x) {
+entry:
+	call void @abort()
+	unreachable
+
+next:
+	%q = add i32 undef, 1
+	ret i32 %q
+}`
+	if got := h.LLString(); got != want {
+		t.Errorf("function mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+	if _, err := asm.ParseString("", m.String()); err != nil {
+		t.Errorf("unable to parse transformed module; %+v", err)
+	}
+}
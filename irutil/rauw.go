@@ -0,0 +1,44 @@
+package irutil
+
+import (
+	"fmt"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/value"
+)
+
+// ReplaceAllUsesWith replaces all uses of the old value by the new value in the
+// instructions and terminators of function f, and returns the number of uses
+// replaced. The old and new values must be of the same type.
+func ReplaceAllUsesWith(f *ir.Func, old, new value.Value) int {
+	if !old.Type().Equal(new.Type()) {
+		panic(fmt.Errorf("type mismatch between old value %s and new value %s; expected %s, got %s", old.Ident(), new.Ident(), old.Type(), new.Type()))
+	}
+	n := 0
+	for _, block := range f.Blocks {
+		for _, inst := range block.Insts {
+			n += replaceOperands(inst, old, new)
+		}
+		if term, ok := block.Term.(ir.Instruction); ok {
+			n += replaceOperands(term, old, new)
+		}
+	}
+	return n
+}
+
+// replaceOperands replaces operands of the given instruction referring to the
+// old value by the new value, and returns the number of operands replaced. The
+// cached type of the instruction is reset if any operand is replaced.
+func replaceOperands(inst ir.Instruction, old, new value.Value) int {
+	n := 0
+	for _, operand := range ir.Operands(inst) {
+		if *operand == old {
+			*operand = new
+			n++
+		}
+	}
+	if n > 0 {
+		ir.ResetType(inst)
+	}
+	return n
+}
@@ -0,0 +1,35 @@
+package irutil
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestReplaceAllUsesWith(t *testing.T) {
+	f := ir.NewFunc("f", types.I32, ir.NewParam("x", types.I32), ir.NewParam("y", types.I32))
+	entry := f.NewBlock("entry")
+	x, y := f.Params[0], f.Params[1]
+	a := entry.NewAdd(x, x)
+	a.SetName("a")
+	b := entry.NewMul(a, x)
+	b.SetName("b")
+	entry.NewRet(b)
+	if n := ReplaceAllUsesWith(f, x, y); n != 3 {
+		t.Errorf("number of replaced uses mismatch; expected 3, got %d", n)
+	}
+	if n := ReplaceAllUsesWith(f, b, constant.NewInt(types.I32, 42)); n != 1 {
+		t.Errorf("number of replaced uses mismatch; expected 1, got %d", n)
+	}
+	want := `define i32 @f(i32 %x, i32 %y) {
+entry:
+	%a = add i32 %y, %y
+	%b = mul i32 %a, %y
+	ret i32 42
+}`
+	if got := f.LLString(); got != want {
+		t.Errorf("function mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+}
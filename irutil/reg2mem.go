@@ -0,0 +1,335 @@
+package irutil
+
+import (
+	"fmt"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// --- [ Register demotion ] ---------------------------------------------------
+
+// DemoteRegToMem demotes values of function f to stack slots (reg2mem), for use
+// with backends which do not support phi instructions. It is the inverse of
+// mem2reg, and returns the number of values demoted.
+//
+// Each phi instruction is replaced by a load from a stack slot, which is stored
+// to at the end of each predecessor basic block. Each instruction with a use
+// outside of its basic block, or a use by a phi instruction, is stored to a
+// stack slot directly after its definition, and each use is replaced by a load
+// from the stack slot. Stack slots are allocated at the beginning of the entry
+// basic block.
+//
+// The result of an invoke terminator is only defined in its normal destination,
+// and is thus stored at the start of the normal destination when used by a phi
+// instruction; splitting the edge to the normal destination if it has other
+// predecessors.
+//
+// Allocas of the entry basic block, values of token type and results of invoke
+// terminators are not demoted. Neither are phi instructions of basic blocks
+// terminated by a catchswitch terminator, as such basic blocks cannot contain
+// non-phi instructions.
+func DemoteRegToMem(f *ir.Func) int {
+	if len(f.Blocks) == 0 {
+		return 0
+	}
+	d := &demoter{
+		f:      f,
+		entry:  f.Blocks[0],
+		uses:   make(map[value.Value][]use),
+		before: make(map[ir.Instruction][]ir.Instruction),
+		after:  make(map[ir.Instruction][]ir.Instruction),
+		stores: make(map[*ir.Block][]ir.Instruction),
+		phis:   make(map[*ir.Block][]ir.Instruction),
+		dests:  make(map[*ir.TermInvoke]*ir.Block),
+	}
+	// Record uses of instructions.
+	for _, block := range f.Blocks {
+		for _, inst := range block.Insts {
+			d.recordUses(block, inst)
+		}
+		if term, ok := block.Term.(ir.Instruction); ok {
+			d.recordUses(block, term)
+		}
+	}
+	// Demote instructions with uses outside of their basic block.
+	n := 0
+	for _, block := range f.Blocks {
+		for _, inst := range block.Insts {
+			if d.escapes(block, inst) {
+				d.demoteInst(inst.(value.Named))
+				n++
+			}
+		}
+	}
+	// Demote phi instructions.
+	for _, block := range f.Blocks {
+		if _, ok := block.Term.(*ir.TermCatchSwitch); ok {
+			continue
+		}
+		for _, inst := range block.Insts {
+			phi, ok := inst.(*ir.InstPhi)
+			if !ok {
+				break
+			}
+			if types.Equal(phi.Type(), types.Token) {
+				continue
+			}
+			d.demotePhi(block, phi)
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	d.storeIncs()
+	d.rewrite(f)
+	return n
+}
+
+// demoter tracks the state of register demotion in a function.
+type demoter struct {
+	// Function in which to demote values.
+	f *ir.Func
+	// Entry basic block of the function.
+	entry *ir.Block
+	// Uses of instructions, indexed by instruction.
+	uses map[value.Value][]use
+	// Stack slots of demoted values.
+	allocas []ir.Instruction
+	// Instructions to insert before a given instruction or terminator.
+	before map[ir.Instruction][]ir.Instruction
+	// Instructions to insert after a given instruction.
+	after map[ir.Instruction][]ir.Instruction
+	// Stores of invoke results to insert at the start of a given basic block.
+	stores map[*ir.Block][]ir.Instruction
+	// Loads replacing the demoted phi instructions of a basic block.
+	phis map[*ir.Block][]ir.Instruction
+	// Demoted phi instructions and their corresponding stack slots.
+	demoted []*ir.InstPhi
+	slots   []*ir.InstAlloca
+	// Basic blocks in which to store the results of invoke terminators.
+	dests map[*ir.TermInvoke]*ir.Block
+}
+
+// use is a use of a value by an instruction or terminator.
+type use struct {
+	// Basic block of the user.
+	block *ir.Block
+	// User of the value.
+	user ir.Instruction
+	// Operand of the user referring to the value.
+	operand *value.Value
+	// Incoming predecessor basic block of the value if used by a phi
+	// instruction; or nil otherwise.
+	pred *ir.Block
+}
+
+// recordUses records the uses of instructions by the given instruction or
+// terminator of the basic block.
+func (d *demoter) recordUses(block *ir.Block, inst ir.Instruction) {
+	if phi, ok := inst.(*ir.InstPhi); ok {
+		for _, inc := range phi.Incs {
+			if _, ok := inc.X.(ir.Instruction); ok {
+				d.uses[inc.X] = append(d.uses[inc.X], use{block: block, user: phi, operand: &inc.X, pred: inc.Pred})
+			}
+		}
+		return
+	}
+	for _, operand := range ir.Operands(inst) {
+		if _, ok := (*operand).(ir.Instruction); ok {
+			d.uses[*operand] = append(d.uses[*operand], use{block: block, user: inst, operand: operand})
+		}
+	}
+}
+
+// escapes reports whether the given non-phi instruction of the basic block
+// should be demoted, as it is used outside of its basic block or by a phi
+// instruction.
+func (d *demoter) escapes(block *ir.Block, inst ir.Instruction) bool {
+	if _, ok := inst.(*ir.InstPhi); ok {
+		return false
+	}
+	if _, ok := inst.(*ir.InstAlloca); ok && block == d.entry {
+		return false
+	}
+	v, ok := inst.(value.Named)
+	if !ok || types.Equal(v.Type(), types.Void) || types.Equal(v.Type(), types.Token) {
+		return false
+	}
+	for _, u := range d.uses[v] {
+		if u.block != block || u.pred != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// demoteInst demotes the given non-phi instruction to a stack slot.
+func (d *demoter) demoteInst(v value.Named) {
+	slot := d.newSlot(v)
+	inst := v.(ir.Instruction)
+	d.after[inst] = append(d.after[inst], ir.NewStore(v, slot))
+	// Reuse loads for multiple uses by the same user, and for multiple incoming
+	// values of phi instructions from the same predecessor basic block.
+	loads := make(map[ir.Instruction]*ir.InstLoad)
+	for _, u := range d.uses[v] {
+		at := u.user
+		if u.pred != nil {
+			at = u.pred.Term.(ir.Instruction)
+		}
+		load, ok := loads[at]
+		if !ok {
+			load = ir.NewLoad(slot)
+			loads[at] = load
+			d.before[at] = append(d.before[at], load)
+		}
+		*u.operand = load
+	}
+}
+
+// demotePhi demotes the given phi instruction of the basic block to a stack
+// slot. Uses of the phi instruction are replaced by a load from the stack slot,
+// while stores to the stack slot are inserted by storeIncs once all phi
+// instructions have been demoted.
+func (d *demoter) demotePhi(block *ir.Block, phi *ir.InstPhi) {
+	slot := d.newSlot(phi)
+	load := ir.NewLoad(slot)
+	d.phis[block] = append(d.phis[block], load)
+	for _, u := range d.uses[phi] {
+		*u.operand = load
+	}
+	d.demoted = append(d.demoted, phi)
+	d.slots = append(d.slots, slot)
+}
+
+// storeIncs inserts stores of the incoming values of demoted phi instructions
+// at the end of each predecessor basic block, or at the start of the normal
+// destination of invoke terminators for results of invoke terminators.
+func (d *demoter) storeIncs() {
+	for i, phi := range d.demoted {
+		stored := make(map[*ir.Block]bool)
+		for _, inc := range phi.Incs {
+			if stored[inc.Pred] {
+				continue
+			}
+			stored[inc.Pred] = true
+			store := ir.NewStore(inc.X, d.slots[i])
+			if invoke, ok := inc.X.(*ir.TermInvoke); ok && inc.Pred.Term == invoke {
+				dest := d.invokeDest(inc.Pred, invoke)
+				d.stores[dest] = append(d.stores[dest], store)
+				continue
+			}
+			term := inc.Pred.Term.(ir.Instruction)
+			d.before[term] = append(d.before[term], store)
+		}
+	}
+}
+
+// invokeDest returns the basic block at the start of which to store the result
+// of the given invoke terminator of the basic block; the normal destination of
+// the invoke terminator, or a new basic block on the edge to the normal
+// destination if the normal destination has other predecessors.
+func (d *demoter) invokeDest(block *ir.Block, invoke *ir.TermInvoke) *ir.Block {
+	if dest, ok := d.dests[invoke]; ok {
+		return dest
+	}
+	dest := invoke.Normal
+	split := false
+	for _, pred := range d.f.Blocks {
+		if pred == block || pred.Term == nil {
+			continue
+		}
+		for _, succ := range pred.Term.Succs() {
+			if succ == dest {
+				split = true
+			}
+		}
+	}
+	if split {
+		name := ""
+		if len(block.LocalName) > 0 && len(dest.LocalName) > 0 {
+			name = fmt.Sprintf("%s.%s_crit_edge", block.LocalName, dest.LocalName)
+		}
+		edge := ir.NewBlock(name)
+		edge.Parent = d.f
+		term := ir.NewBr(dest)
+		term.Parent = edge
+		edge.Term = term
+		retarget(invoke, dest, edge)
+		for _, inst := range dest.Insts {
+			phi, ok := inst.(*ir.InstPhi)
+			if !ok {
+				break
+			}
+			for _, inc := range phi.Incs {
+				if inc.Pred == block {
+					inc.Pred = edge
+				}
+			}
+		}
+		// Insert new basic block after the basic block of the invoke terminator.
+		blocks := make([]*ir.Block, 0, len(d.f.Blocks)+1)
+		for _, b := range d.f.Blocks {
+			blocks = append(blocks, b)
+			if b == block {
+				blocks = append(blocks, edge)
+			}
+		}
+		d.f.Blocks = blocks
+		dest = edge
+	}
+	d.dests[invoke] = dest
+	return dest
+}
+
+// newSlot returns a new stack slot for the given value, to be allocated in the
+// entry basic block.
+func (d *demoter) newSlot(v value.Named) *ir.InstAlloca {
+	slot := ir.NewAlloca(v.Type())
+	if n, ok := v.(interface{ IsUnnamed() bool }); ok && !n.IsUnnamed() {
+		slot.SetName(v.Name() + ".reg2mem")
+	}
+	d.allocas = append(d.allocas, slot)
+	return slot
+}
+
+// rewrite inserts the instructions of the register demotion into the basic
+// blocks of function f, and removes demoted phi instructions.
+func (d *demoter) rewrite(f *ir.Func) {
+	demoted := make(map[ir.Instruction]bool)
+	for _, phi := range d.demoted {
+		demoted[phi] = true
+	}
+	for _, block := range f.Blocks {
+		var insts []ir.Instruction
+		if block == d.entry {
+			insts = append(insts, d.allocas...)
+		}
+		// Stores of invoke results precede loads of demoted phi instructions.
+		loads := append(d.stores[block], d.phis[block]...)
+		for _, inst := range block.Insts {
+			if demoted[inst] {
+				continue
+			}
+			// Insert stores of invoke results and loads of demoted phi
+			// instructions after any remaining phi instructions and exception
+			// pad.
+			if len(loads) > 0 && !isPhiOrPad(inst) {
+				insts = append(insts, loads...)
+				loads = nil
+			}
+			insts = append(insts, d.before[inst]...)
+			insts = append(insts, inst)
+			insts = append(insts, d.after[inst]...)
+		}
+		insts = append(insts, loads...)
+		if term, ok := block.Term.(ir.Instruction); ok {
+			insts = append(insts, d.before[term]...)
+		}
+		block.Insts = insts
+	}
+	f.ResolveParents()
+	f.ResetIDs()
+}
@@ -0,0 +1,42 @@
+package irutil
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/llir/llvm/asm"
+)
+
+func TestDemoteRegToMem(t *testing.T) {
+	golden := []struct {
+		path string
+		want string
+		// Number of demoted values of each function.
+		ns []int
+	}{
+		{path: "testdata/reg2mem.ll", want: "testdata/reg2mem.ll.golden", ns: []int{4, 4, 0, 0, 0, 1, 1}},
+	}
+	for _, g := range golden {
+		m, err := asm.ParseFile(g.path)
+		if err != nil {
+			t.Errorf("unable to parse %q into AST; %+v", g.path, err)
+			continue
+		}
+		for i, f := range m.Funcs {
+			if n := DemoteRegToMem(f); n != g.ns[i] {
+				t.Errorf("number of demoted values mismatch of function %s in %q; expected %d, got %d", f.Ident(), g.path, g.ns[i], n)
+			}
+		}
+		if err := m.Verify(); err != nil {
+			t.Errorf("invalid module after demoting values of %q; %v", g.path, err)
+		}
+		buf, err := ioutil.ReadFile(g.want)
+		if err != nil {
+			t.Errorf("unable to read %q; %v", g.want, err)
+			continue
+		}
+		if got, want := m.String(), string(buf); got != want {
+			t.Errorf("module mismatch after demoting values of %q; expected:\n%s\ngot:\n%s", g.path, want, got)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package irutil
+
+import (
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// --- [ Instruction sinking ] -------------------------------------------------
+
+// SinkInstructions moves instructions of function f without side effects into
+// the basic block of their users, and returns the number of instructions sunk.
+// Sinking an instruction into the basic block in which it is used shortens its
+// live range, and avoids computing the value on paths where it is not used.
+//
+// An instruction is sunk if all of its users are located in a single basic
+// block, other than its own, which is dominated by the basic block of the
+// instruction. The instruction is inserted before its first user of the basic
+// block. Instructions are not sunk into loops not containing the instruction,
+// and neither into basic blocks terminated by a catchswitch terminator.
+//
+// Phi instructions, instructions used by phi instructions, instructions which
+// may have side effects (see MayHaveSideEffects) and instructions which may
+// read memory (loads and calls) or allocate memory (allocas) are not sunk.
+func SinkInstructions(f *ir.Func) int {
+	if len(f.Blocks) == 0 {
+		return 0
+	}
+	s := &sinker{dom: NewDomTree(f)}
+	total := 0
+	for {
+		s.recordUses(f)
+		n := 0
+		// Visit instructions in reverse order, as sinking an instruction may
+		// enable the sinking of its operands.
+		for i := len(f.Blocks) - 1; i >= 0; i-- {
+			block := f.Blocks[i]
+			for j := len(block.Insts) - 1; j >= 0; j-- {
+				if s.sink(block, j) {
+					n++
+				}
+			}
+		}
+		if n == 0 {
+			break
+		}
+		total += n
+	}
+	if total > 0 {
+		f.ResolveParents()
+		f.ResetIDs()
+	}
+	return total
+}
+
+// sinker tracks the state of instruction sinking in a function.
+type sinker struct {
+	// Dominator tree of the function.
+	dom *DomTree
+	// Basic blocks of the users of each instruction, and whether the
+	// instruction is used by a phi instruction.
+	userBlocks map[value.Value][]*ir.Block
+	phiUsed    map[value.Value]bool
+}
+
+// recordUses records the basic blocks of the users of instructions in function
+// f.
+func (s *sinker) recordUses(f *ir.Func) {
+	s.userBlocks = make(map[value.Value][]*ir.Block)
+	s.phiUsed = make(map[value.Value]bool)
+	record := func(block *ir.Block, inst ir.Instruction) {
+		_, isPhi := inst.(*ir.InstPhi)
+		for _, operand := range ir.Operands(inst) {
+			if _, ok := (*operand).(ir.Instruction); !ok {
+				continue
+			}
+			if isPhi {
+				s.phiUsed[*operand] = true
+			}
+			s.userBlocks[*operand] = append(s.userBlocks[*operand], block)
+		}
+	}
+	for _, block := range f.Blocks {
+		for _, inst := range block.Insts {
+			record(block, inst)
+		}
+		if term, ok := block.Term.(ir.Instruction); ok {
+			record(block, term)
+		}
+	}
+}
+
+// sink sinks the i:th instruction of the basic block into the basic block of
+// its users, and reports whether the instruction was sunk.
+func (s *sinker) sink(block *ir.Block, i int) bool {
+	inst := block.Insts[i]
+	if MayHaveSideEffects(inst) {
+		return false
+	}
+	switch inst.(type) {
+	case *ir.InstPhi, *ir.InstAlloca, *ir.InstLoad, *ir.InstCall:
+		return false
+	}
+	v, ok := inst.(value.Value)
+	if !ok || types.Equal(v.Type(), types.Token) || s.phiUsed[v] {
+		return false
+	}
+	users := uniqueBlocks(s.userBlocks[v])
+	if len(users) != 1 || users[0] == block {
+		return false
+	}
+	target := users[0]
+	if _, ok := target.Term.(*ir.TermCatchSwitch); ok {
+		return false
+	}
+	if !s.dom.Dominates(block, target) || inLoopWithout(target, block) {
+		return false
+	}
+	// Move instruction before its first user in the target basic block, or
+	// before the terminator if only used by the terminator.
+	block.Insts = append(block.Insts[:i:i], block.Insts[i+1:]...)
+	pos := len(target.Insts)
+	for j, user := range target.Insts {
+		if isPhiOrPad(user) {
+			continue
+		}
+		if usesValue(user, v) {
+			pos = j
+			break
+		}
+	}
+	insts := make([]ir.Instruction, 0, len(target.Insts)+1)
+	insts = append(insts, target.Insts[:pos]...)
+	insts = append(insts, inst)
+	insts = append(insts, target.Insts[pos:]...)
+	target.Insts = insts
+	return true
+}
+
+// usesValue reports whether the given instruction uses v as an operand.
+func usesValue(inst ir.Instruction, v value.Value) bool {
+	for _, operand := range ir.Operands(inst) {
+		if *operand == v {
+			return true
+		}
+	}
+	return false
+}
+
+// inLoopWithout reports whether the given basic block is part of a cycle of the
+// control flow graph which does not pass through basic block exclude.
+func inLoopWithout(block, exclude *ir.Block) bool {
+	visited := map[*ir.Block]bool{exclude: true}
+	queue := []*ir.Block{block}
+	for len(queue) > 0 {
+		b := queue[0]
+		queue = queue[1:]
+		if b.Term == nil {
+			continue
+		}
+		for _, succ := range b.Term.Succs() {
+			if succ == block {
+				return true
+			}
+			if !visited[succ] {
+				visited[succ] = true
+				queue = append(queue, succ)
+			}
+		}
+	}
+	return false
+}
+
+// isPhiOrPad reports whether the given instruction is a phi instruction or an
+// exception pad, which must precede other instructions of a basic block.
+func isPhiOrPad(inst ir.Instruction) bool {
+	switch inst.(type) {
+	case *ir.InstPhi, *ir.InstLandingPad, *ir.InstCatchPad, *ir.InstCleanupPad:
+		return true
+	}
+	return false
+}
+
+// uniqueBlocks returns the given basic blocks with duplicates removed, in order
+// of first occurrence.
+func uniqueBlocks(blocks []*ir.Block) []*ir.Block {
+	var unique []*ir.Block
+	seen := make(map[*ir.Block]bool)
+	for _, block := range blocks {
+		if !seen[block] {
+			seen[block] = true
+			unique = append(unique, block)
+		}
+	}
+	return unique
+}
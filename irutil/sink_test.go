@@ -0,0 +1,42 @@
+package irutil
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/llir/llvm/asm"
+)
+
+func TestSinkInstructions(t *testing.T) {
+	golden := []struct {
+		path string
+		want string
+		// Number of sunk instructions of each function.
+		ns []int
+	}{
+		{path: "testdata/sink.ll", want: "testdata/sink.ll.golden", ns: []int{3, 0, 0}},
+	}
+	for _, g := range golden {
+		m, err := asm.ParseFile(g.path)
+		if err != nil {
+			t.Errorf("unable to parse %q into AST; %+v", g.path, err)
+			continue
+		}
+		for i, f := range m.Funcs {
+			if n := SinkInstructions(f); n != g.ns[i] {
+				t.Errorf("number of sunk instructions mismatch of function %s in %q; expected %d, got %d", f.Ident(), g.path, g.ns[i], n)
+			}
+		}
+		if err := m.Verify(); err != nil {
+			t.Errorf("invalid module after sinking instructions of %q; %v", g.path, err)
+		}
+		buf, err := ioutil.ReadFile(g.want)
+		if err != nil {
+			t.Errorf("unable to read %q; %v", g.want, err)
+			continue
+		}
+		if got, want := m.String(), string(buf); got != want {
+			t.Errorf("module mismatch after sinking instructions of %q; expected:\n%s\ngot:\n%s", g.path, want, got)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package irutil
+
+import (
+	"fmt"
+
+	"github.com/llir/llvm/ir"
+)
+
+// --- [ Critical edge splitting ] ---------------------------------------------
+
+// SplitCriticalEdges splits the critical edges of function f, and returns the
+// number of edges split. An edge is critical if its source basic block has
+// multiple distinct successors and its target basic block has multiple distinct
+// predecessors.
+//
+// Each critical edge is split by inserting a new basic block, containing only an
+// unconditional br terminator to the target, directly after the source basic
+// block. All edges from the source to the target are redirected to the new
+// basic block, and the incoming values of phi instructions in the target from
+// the source are replaced by a single incoming value from the new basic block.
+//
+// Edges which cannot be split are left unmodified; these include edges of
+// indirectbr terminators and edges to exception handling basic blocks (e.g.
+// the unwind target of an invoke terminator).
+func SplitCriticalEdges(f *ir.Func) int {
+	// Record predecessors of basic blocks.
+	preds := make(map[*ir.Block][]*ir.Block)
+	for _, block := range f.Blocks {
+		if block.Term == nil {
+			continue
+		}
+		for _, succ := range uniqueBlocks(block.Term.Succs()) {
+			preds[succ] = append(preds[succ], block)
+		}
+	}
+	// Locate critical edges, in order of source basic block.
+	type edge struct {
+		from, to *ir.Block
+	}
+	var edges []edge
+	for _, block := range f.Blocks {
+		if block.Term == nil {
+			continue
+		}
+		if _, ok := block.Term.(*ir.TermIndirectBr); ok {
+			continue
+		}
+		succs := uniqueBlocks(block.Term.Succs())
+		if len(succs) < 2 {
+			continue
+		}
+		for _, succ := range succs {
+			if len(preds[succ]) < 2 || isEHPadBlock(succ) {
+				continue
+			}
+			edges = append(edges, edge{from: block, to: succ})
+		}
+	}
+	if len(edges) == 0 {
+		return 0
+	}
+	// Split critical edges.
+	split := make(map[*ir.Block][]*ir.Block)
+	for _, e := range edges {
+		name := ""
+		if len(e.from.LocalName) > 0 && len(e.to.LocalName) > 0 {
+			name = fmt.Sprintf("%s.%s_crit_edge", e.from.LocalName, e.to.LocalName)
+		}
+		block := ir.NewBlock(name)
+		block.Parent = f
+		term := ir.NewBr(e.to)
+		term.Parent = block
+		block.Term = term
+		retarget(e.from.Term, e.to, block)
+		for _, inst := range e.to.Insts {
+			phi, ok := inst.(*ir.InstPhi)
+			if !ok {
+				break
+			}
+			// Multiple edges from the source are merged into a single edge from
+			// the new basic block.
+			var incs []*ir.Incoming
+			redirected := false
+			for _, inc := range phi.Incs {
+				if inc.Pred == e.from {
+					if redirected {
+						continue
+					}
+					inc.Pred = block
+					redirected = true
+				}
+				incs = append(incs, inc)
+			}
+			phi.Incs = incs
+		}
+		split[e.from] = append(split[e.from], block)
+	}
+	// Insert new basic blocks after the source basic block of each edge.
+	blocks := make([]*ir.Block, 0, len(f.Blocks)+len(edges))
+	for _, block := range f.Blocks {
+		blocks = append(blocks, block)
+		blocks = append(blocks, split[block]...)
+	}
+	f.Blocks = blocks
+	f.ResetIDs()
+	return len(edges)
+}
+
+// retarget redirects the edges of the given terminator from the old to the new
+// target basic block. Only the normal edge of invoke terminators is redirected.
+func retarget(term ir.Terminator, old, new *ir.Block) {
+	switch term := term.(type) {
+	case *ir.TermBr:
+		if term.Target == old {
+			term.Target = new
+		}
+		term.Successors = nil
+	case *ir.TermCondBr:
+		if term.TargetTrue == old {
+			term.TargetTrue = new
+		}
+		if term.TargetFalse == old {
+			term.TargetFalse = new
+		}
+		term.Successors = nil
+	case *ir.TermSwitch:
+		if term.TargetDefault == old {
+			term.TargetDefault = new
+		}
+		for _, c := range term.Cases {
+			if c.Target == old {
+				c.Target = new
+			}
+		}
+		term.Successors = nil
+	case *ir.TermInvoke:
+		if term.Normal == old {
+			term.Normal = new
+		}
+		term.Successors = nil
+	default:
+		panic(fmt.Errorf("support for retargeting terminator %T not yet implemented", term))
+	}
+}
+
+// isEHPadBlock reports whether the given basic block is an exception handling
+// basic block, the first non-phi instruction of which is an exception pad.
+func isEHPadBlock(block *ir.Block) bool {
+	for _, inst := range block.Insts {
+		if _, ok := inst.(*ir.InstPhi); ok {
+			continue
+		}
+		return isPhiOrPad(inst)
+	}
+	_, ok := block.Term.(*ir.TermCatchSwitch)
+	return ok
+}
@@ -0,0 +1,46 @@
+package irutil
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/llir/llvm/asm"
+)
+
+func TestSplitCriticalEdges(t *testing.T) {
+	golden := []struct {
+		path string
+		want string
+		// Number of critical edges of each function.
+		ns []int
+	}{
+		{path: "testdata/split_critical_edges.ll", want: "testdata/split_critical_edges.ll.golden", ns: []int{1, 1, 4}},
+	}
+	for _, g := range golden {
+		m, err := asm.ParseFile(g.path)
+		if err != nil {
+			t.Errorf("unable to parse %q into AST; %+v", g.path, err)
+			continue
+		}
+		for i, f := range m.Funcs {
+			if n := SplitCriticalEdges(f); n != g.ns[i] {
+				t.Errorf("number of split critical edges mismatch of function %s in %q; expected %d, got %d", f.Ident(), g.path, g.ns[i], n)
+			}
+			// Splitting is idempotent.
+			if n := SplitCriticalEdges(f); n != 0 {
+				t.Errorf("unexpected critical edges of function %s in %q after splitting; got %d", f.Ident(), g.path, n)
+			}
+		}
+		if err := m.Verify(); err != nil {
+			t.Errorf("invalid module after splitting critical edges of %q; %v", g.path, err)
+		}
+		buf, err := ioutil.ReadFile(g.want)
+		if err != nil {
+			t.Errorf("unable to read %q; %v", g.want, err)
+			continue
+		}
+		if got, want := m.String(), string(buf); got != want {
+			t.Errorf("module mismatch after splitting critical edges of %q; expected:\n%s\ngot:\n%s", g.path, want, got)
+		}
+	}
+}
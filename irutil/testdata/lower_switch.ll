@@ -0,0 +1,49 @@
+define i32 @f(i32 %x) {
+entry:
+	switch i32 %x, label %def [
+		i32 1, label %one
+		i32 2, label %two
+		i32 3, label %one
+	]
+
+one:
+	br label %exit
+
+two:
+	br label %exit
+
+def:
+	br label %exit
+
+exit:
+	%r = phi i32 [ 10, %one ], [ 20, %two ], [ 30, %def ]
+	ret i32 %r
+}
+
+define i32 @g(i32 %x) {
+; <label>:0
+	switch i32 %x, label %2 [
+		i32 0, label %1
+		i32 5, label %2
+	]
+
+; <label>:1
+	br label %2
+
+; <label>:2
+	%3 = phi i32 [ 1, %1 ], [ 0, %0 ], [ 0, %0 ]
+	ret i32 %3
+}
+
+define void @h(i32 %x) {
+entry:
+	switch i32 %x, label %exit [
+		i32 1, label %exit
+	], !prof !0, !dbg !1
+
+exit:
+	ret void
+}
+
+!0 = !{!"branch_weights", i32 1, i32 2}
+!1 = !{}
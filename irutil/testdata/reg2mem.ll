@@ -0,0 +1,74 @@
+define i32 @f(i1 %c, i32 %x) {
+entry:
+	%a = add i32 %x, 1
+	br i1 %c, label %then, label %exit
+
+then:
+	%b = mul i32 %a, %a
+	br label %exit
+
+exit:
+	%r = phi i32 [ %a, %entry ], [ %b, %then ]
+	%s = phi i32 [ 0, %entry ], [ %b, %then ]
+	%t = add i32 %r, %s
+	ret i32 %t
+}
+
+define i32 @swap(i32 %n) {
+; <label>:0
+	br label %1
+
+; <label>:1
+	%2 = phi i32 [ 0, %0 ], [ %3, %1 ]
+	%3 = phi i32 [ 1, %0 ], [ %2, %1 ]
+	%4 = phi i32 [ 0, %0 ], [ %5, %1 ]
+	%5 = add i32 %4, 1
+	%6 = icmp slt i32 %5, %n
+	br i1 %6, label %1, label %7
+
+; <label>:7
+	ret i32 %2
+}
+
+define i32 @local(i32 %x) {
+entry:
+	%y = add i32 %x, %x
+	ret i32 %y
+}
+
+declare i32 @g()
+
+declare i32 @__gxx_personality_v0(...)
+
+define i32 @invoke(i1 %c) personality i32 (...)* @__gxx_personality_v0 {
+entry:
+	br i1 %c, label %call, label %exit
+
+call:
+	%x = invoke i32 @g()
+		to label %exit unwind label %lpad
+
+exit:
+	%r = phi i32 [ 0, %entry ], [ %x, %call ]
+	ret i32 %r
+
+lpad:
+	%lp = landingpad { i8*, i32 }
+		cleanup
+	ret i32 -1
+}
+
+define i32 @invoke_single() personality i32 (...)* @__gxx_personality_v0 {
+entry:
+	%x = invoke i32 @g()
+		to label %cont unwind label %lpad
+
+cont:
+	%r = phi i32 [ %x, %entry ]
+	ret i32 %r
+
+lpad:
+	%lp = landingpad { i8*, i32 }
+		cleanup
+	ret i32 -1
+}
@@ -0,0 +1,53 @@
+define i32 @f(i32 %x, i1 %c) {
+entry:
+	%a = add i32 %x, 1
+	%b = mul i32 %a, 2
+	%d = sub i32 %x, 3
+	br i1 %c, label %then, label %else
+
+then:
+	%y = add i32 %b, %d
+	ret i32 %y
+
+else:
+	ret i32 0
+}
+
+define i32 @g(i32 %x, i1 %c, i32* %p) {
+entry:
+	; Used in multiple basic blocks.
+	%a = add i32 %x, 1
+	; Used by phi instruction.
+	%b = add i32 %x, 2
+	; Load; not sunk.
+	%v = load i32, i32* %p
+	br i1 %c, label %then, label %else
+
+then:
+	store i32 %a, i32* %p
+	br label %exit
+
+else:
+	%y = add i32 %a, %v
+	br label %exit
+
+exit:
+	%z = phi i32 [ %b, %then ], [ %y, %else ]
+	ret i32 %z
+}
+
+define i32 @h(i32 %x, i32 %n) {
+entry:
+	; Not sunk into loop.
+	%a = mul i32 %x, %x
+	br label %loop
+
+loop:
+	%i = phi i32 [ 0, %entry ], [ %j, %loop ]
+	%j = add i32 %i, %a
+	%cond = icmp slt i32 %j, %n
+	br i1 %cond, label %loop, label %exit
+
+exit:
+	ret i32 %j
+}
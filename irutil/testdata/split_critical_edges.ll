@@ -0,0 +1,41 @@
+define i32 @f(i1 %c, i32 %x) {
+entry:
+	br i1 %c, label %then, label %exit
+
+then:
+	br label %exit
+
+exit:
+	%r = phi i32 [ 0, %entry ], [ 1, %then ]
+	ret i32 %r
+}
+
+define i32 @g(i32 %x) {
+; <label>:0
+	switch i32 %x, label %2 [
+		i32 0, label %1
+		i32 1, label %2
+		i32 2, label %4
+	]
+
+; <label>:1
+	br label %2
+
+; <label>:2
+	%3 = phi i32 [ 0, %0 ], [ 0, %0 ], [ 1, %1 ]
+	ret i32 %3
+
+; <label>:4
+	ret i32 2
+}
+
+define void @h(i1 %c) {
+entry:
+	br i1 %c, label %loop, label %exit
+
+loop:
+	br i1 %c, label %loop, label %exit
+
+exit:
+	ret void
+}
@@ -0,0 +1,65 @@
+package irutil
+
+import (
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/value"
+)
+
+// === [ Type assertions ] =====================================================
+
+// AsConstant returns the given value as a constant. The boolean return value
+// indicates success.
+func AsConstant(v value.Value) (constant.Constant, bool) {
+	c, ok := v.(constant.Constant)
+	return c, ok
+}
+
+// AsConstInt returns the given value as an integer constant. The boolean return
+// value indicates success.
+func AsConstInt(v value.Value) (*constant.Int, bool) {
+	c, ok := v.(*constant.Int)
+	return c, ok
+}
+
+// AsConstFloat returns the given value as a floating-point constant. The
+// boolean return value indicates success.
+func AsConstFloat(v value.Value) (*constant.Float, bool) {
+	c, ok := v.(*constant.Float)
+	return c, ok
+}
+
+// AsFunc returns the given value as a function. The boolean return value
+// indicates success.
+func AsFunc(v value.Value) (*ir.Func, bool) {
+	f, ok := v.(*ir.Func)
+	return f, ok
+}
+
+// AsGlobal returns the given value as a global variable. The boolean return
+// value indicates success.
+func AsGlobal(v value.Value) (*ir.Global, bool) {
+	g, ok := v.(*ir.Global)
+	return g, ok
+}
+
+// AsParam returns the given value as a function parameter. The boolean return
+// value indicates success.
+func AsParam(v value.Value) (*ir.Param, bool) {
+	p, ok := v.(*ir.Param)
+	return p, ok
+}
+
+// AsBlock returns the given value as a basic block. The boolean return value
+// indicates success.
+func AsBlock(v value.Value) (*ir.Block, bool) {
+	b, ok := v.(*ir.Block)
+	return b, ok
+}
+
+// AsInstruction returns the given value as an instruction or terminator. The
+// boolean return value indicates success.
+func AsInstruction(v value.Value) (ir.Instruction, bool) {
+	inst, ok := v.(ir.Instruction)
+	return inst, ok
+}
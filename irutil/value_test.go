@@ -0,0 +1,47 @@
+package irutil
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+func TestAs(t *testing.T) {
+	m := ir.NewModule()
+	g := m.NewGlobalDef("g", constant.NewInt(types.I32, 0))
+	f := m.NewFunc("f", types.I32, ir.NewParam("x", types.I32))
+	entry := f.NewBlock("entry")
+	add := entry.NewAdd(f.Params[0], constant.NewInt(types.I32, 1))
+	entry.NewRet(add)
+	golden := []struct {
+		v    value.Value
+		want []bool // constant, int, float, func, global, param, block, instruction
+	}{
+		{v: constant.NewInt(types.I32, 1), want: []bool{true, true, false, false, false, false, false, false}},
+		{v: constant.NewFloat(types.Double, 1), want: []bool{true, false, true, false, false, false, false, false}},
+		{v: f, want: []bool{true, false, false, true, false, false, false, false}},
+		{v: g, want: []bool{true, false, false, false, true, false, false, false}},
+		{v: f.Params[0], want: []bool{false, false, false, false, false, true, false, false}},
+		{v: entry, want: []bool{false, false, false, false, false, false, true, false}},
+		{v: add, want: []bool{false, false, false, false, false, false, false, true}},
+	}
+	for _, g := range golden {
+		_, isConst := AsConstant(g.v)
+		_, isInt := AsConstInt(g.v)
+		_, isFloat := AsConstFloat(g.v)
+		_, isFunc := AsFunc(g.v)
+		_, isGlobal := AsGlobal(g.v)
+		_, isParam := AsParam(g.v)
+		_, isBlock := AsBlock(g.v)
+		_, isInst := AsInstruction(g.v)
+		got := []bool{isConst, isInt, isFloat, isFunc, isGlobal, isParam, isBlock, isInst}
+		for i := range got {
+			if got[i] != g.want[i] {
+				t.Errorf("type assertion %d mismatch of %v; expected %v, got %v", i, g.v.Ident(), g.want[i], got[i])
+			}
+		}
+	}
+}